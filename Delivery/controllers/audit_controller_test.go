@@ -0,0 +1,119 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuditRouter(usecase domain.AuditUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auditController := controllers.NewAuditController(usecase)
+
+	router.GET("/admin/audit", auditController.ListEvents)
+	return router
+}
+
+func TestAuditController_ListEvents(t *testing.T) {
+	mockUsecase := mocks.NewMockAuditUsecase(t)
+	router := setupAuditRouter(mockUsecase)
+
+	eventTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []domain.AuditEvent{{
+		Time:       eventTime,
+		Action:     domain.AuditActionTaskDelete,
+		Outcome:    domain.AuditOutcomeSuccess,
+		Actor:      "alice",
+		ClientIP:   "203.0.113.7",
+		UserAgent:  "=HYPERLINK(\"http://evil\")",
+		RequestID:  "req-1",
+		TargetType: domain.AuditTargetTask,
+		TargetID:   "64b7f0c2a1b2c3d4e5f60718",
+		Details:    map[string]string{"b": "2", "a": "1"},
+	}}
+
+	t.Run("JSON_WithFilters", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ListEvents(mock.AnythingOfType("*context.timerCtx"), domain.AuditFilter{
+				Actor:   "alice",
+				Action:  domain.AuditActionTaskDelete,
+				Outcome: domain.AuditOutcomeSuccess,
+				From:    eventTime.Add(-time.Hour),
+				Limit:   50,
+			}).
+			Return(events, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit?actor=alice&action=task.delete&outcome=success&from=2025-03-01T11:00:00Z&limit=50", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var body struct {
+			Events []domain.AuditEvent `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		require.Len(t, body.Events, 1)
+		assert.Equal(t, "alice", body.Events[0].Actor)
+	})
+
+	t.Run("CSVExport", func(t *testing.T) {
+		mockUsecase.EXPECT().ListEvents(mock.AnythingOfType("*context.timerCtx"), domain.AuditFilter{}).Return(events, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit?format=csv", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), "audit-log.csv")
+		lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, "time,action,outcome,actor,client_ip,user_agent,request_id,target_type,target_id,details", lines[0])
+		assert.Equal(t, `2025-03-01T12:00:00Z,task.delete,success,alice,203.0.113.7,"'=HYPERLINK(""http://evil"")",req-1,task,64b7f0c2a1b2c3d4e5f60718,a=1;b=2`, lines[1])
+	})
+
+	t.Run("NDJSONExport", func(t *testing.T) {
+		mockUsecase.EXPECT().ListEvents(mock.AnythingOfType("*context.timerCtx"), domain.AuditFilter{}).Return(append(events, events...), nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit?format=ndjson", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+		assert.Len(t, strings.Split(strings.TrimSpace(rr.Body.String()), "\n"), 2)
+	})
+
+	t.Run("BadRequest_InvalidQuery", func(t *testing.T) {
+		for _, query := range []string{"outcome=maybe", "format=xml", "limit=-5", "limit=20000", "from=yesterday"} {
+			req, _ := http.NewRequest(http.MethodGet, "/admin/audit?"+query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		}
+	})
+
+	t.Run("InternalServerError", func(t *testing.T) {
+		mockUsecase.EXPECT().ListEvents(mock.AnythingOfType("*context.timerCtx"), domain.AuditFilter{}).Return(nil, errors.New("db down")).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -2,13 +2,15 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UserController struct {
@@ -41,15 +43,9 @@ func (userControl *UserController) Register(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 	defer cancel()
 
-	insertResult, err := userControl.userUsecase.Register(ctx, req.Username, req.Password)
+	user, err := userControl.userUsecase.Register(ctx, req.Username, req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	insertedID, ok := insertResult.InsertedID.(primitive.ObjectID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get inserted ID"})
+		userError(c, err)
 		return
 	}
 
@@ -58,9 +54,11 @@ func (userControl *UserController) Register(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User registered successfully",
 		"user": gin.H{
-			"id":       insertedID.Hex(),
-			"username": req.Username,
-			"role":     domain.RoleUser,
+			"id":             user.ID.String(),
+			"username":       user.Username,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"role":           user.Role,
 		}})
 }
 
@@ -78,14 +76,238 @@ func (userControl *UserController) Login(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 	defer cancel()
 
-	tokenString, err := userControl.userUsecase.Login(ctx, req.Username, req.Password)
+	result, err := userControl.userUsecase.Login(ctx, req.Username, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()}) // 401 Unauthorized
+		loginError(c, err)
+		return
+	}
+
+	// The client has to send a TOTP or recovery code to POST /users/login/mfa next
+	if result.MFARequired {
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": result.MFAToken})
 		return
 	}
 
 	// Return the token to the client
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	c.JSON(http.StatusOK, gin.H{"token": result.Token})
+}
+
+// Responds to a failed login step: 429 with Retry-After while locked out, 401 otherwise
+func loginError(c *gin.Context, err error) {
+	var tooManyAttempts *domain.TooManyAttemptsError
+	if errors.As(err, &tooManyAttempts) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(tooManyAttempts.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()}) // 429 Too Many Requests
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()}) // 401 Unauthorized
+}
+
+// Returns the authenticated user's profile
+func (userControl *UserController) GetProfile(c *gin.Context) {
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	user, err := userControl.userUsecase.GetProfile(ctx, username)
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Updates the authenticated user's profile fields
+func (userControl *UserController) UpdateProfile(c *gin.Context) {
+	var req domain.UpdateProfileRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	user, err := userControl.userUsecase.UpdateProfile(ctx, username, req)
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Changes the authenticated user's password and returns a fresh token.
+// Tokens issued before the change stop working.
+func (userControl *UserController) ChangePassword(c *gin.Context) {
+	var req domain.ChangePasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	tokenString, err := userControl.userUsecase.ChangePassword(ctx, username, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully", "token": tokenString})
+}
+
+// Deletes the authenticated user's account.
+// The request must repeat the password, if the account has one, and the username as confirmation.
+func (userControl *UserController) DeleteAccount(c *gin.Context) {
+	var req domain.DeleteAccountRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Confirm != username {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "confirm must match your username to delete the account"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := userControl.userUsecase.DeleteAccount(ctx, username, req.Password, c.GetTime("signed_in_at")); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+}
+
+// Sends a new verification email to the authenticated user
+func (userControl *UserController) RequestEmailVerification(c *gin.Context) {
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := userControl.userUsecase.RequestEmailVerification(ctx, username); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+// Verifies an email address using the token from the verification email
+func (userControl *UserController) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := userControl.userUsecase.VerifyEmail(ctx, req.Token); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email address verified"})
+}
+
+// Admin only: lifts the login lockout of the user in the path
+func (userControl *UserController) UnlockUser(c *gin.Context) {
+	username := c.Param("username")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := userControl.userUsecase.UnlockUser(ctx, username); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked"})
+}
+
+// Responds to a failed user operation. Passwords rejected by the policy list every rule they break.
+func userError(c *gin.Context, err error) {
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "violations": policyErr.Violations})
+		return
+	}
+
+	c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+// Maps errors from the user usecase to an HTTP status code
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrTokenNotFound),
+		errors.Is(err, domain.ErrRoleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrIncorrectPassword):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrReauthRequired):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrNoFieldsToUpdate),
+		errors.Is(err, domain.ErrInvalidToken),
+		errors.Is(err, domain.ErrNoEmailAddress),
+		errors.Is(err, domain.ErrWeakPassword),
+		errors.Is(err, domain.ErrInvalidMFACode),
+		errors.Is(err, domain.ErrMFANotEnabled),
+		errors.Is(err, domain.ErrMFANotEnrolling),
+		errors.Is(err, domain.ErrNoScopes):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrMFARequired),
+		errors.Is(err, domain.ErrScopeNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrUserAlreadyExists),
+		errors.Is(err, domain.ErrEmailTaken),
+		errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrEmailAlreadyVerified),
+		errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 // ------------------------- Task Handlers -------------------------
@@ -135,7 +357,12 @@ func (taskControl *TaskController) UpdateTask(c *gin.Context) {
 	// Request Context
 	ctx := c.Request.Context()
 
-	err := taskControl.taskUsecase.UpdateTask(ctx, id, task)
+	err := taskControl.taskUsecase.UpdateTask(ctx, id, task, infrastructure.GetPrincipalFromContext(c))
+	if errors.Is(err, domain.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -151,7 +378,12 @@ func (taskControl *TaskController) DeleteTask(c *gin.Context) {
 	// Request Context
 	ctx := c.Request.Context()
 
-	err := taskControl.taskUsecase.DeleteTask(ctx, id)
+	err := taskControl.taskUsecase.DeleteTask(ctx, id, infrastructure.GetPrincipalFromContext(c))
+
+	if errors.Is(err, domain.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -183,16 +415,50 @@ func (taskControl *TaskController) NewTask(c *gin.Context) {
 	// Request Context
 	ctx := c.Request.Context()
 
-	insertResult, err := taskControl.taskUsecase.NewTask(ctx, newTask)
+	createdTask, err := taskControl.taskUsecase.NewTask(ctx, newTask)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Get the inserted ID
-	insertedID, _ := insertResult.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusCreated, createdTask)
+}
+
+// Apply many task changes at once. Answers 200 when every operation was applied, otherwise 207
+// Multi-Status; the outcome of each operation is in the results either way.
+func (taskControl *TaskController) BulkTasks(c *gin.Context) {
+	var request domain.BulkTaskRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
 
-	newTask.ID = insertedID
+	results, err := taskControl.taskUsecase.BulkTasks(ctx, request, infrastructure.GetPrincipalFromContext(c))
+	switch {
+	case errors.Is(err, domain.ErrInvalidBulkRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrTooManyBulkTasks):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "max_operations": domain.MaxBulkTaskOperations})
+		return
+	case errors.Is(err, domain.ErrNoTransactions):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "atomic bulk requests need a database with transactions"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	for _, result := range results {
+		if result.Status != domain.BulkStatusApplied {
+			status = http.StatusMultiStatus
+			break
+		}
+	}
 
-	c.JSON(http.StatusCreated, newTask)
+	c.JSON(status, gin.H{"results": results})
 }
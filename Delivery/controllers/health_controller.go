@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	healthChecker domain.HealthChecker
+}
+
+func NewHealthController(healthChecker domain.HealthChecker) *HealthController {
+	return &HealthController{healthChecker: healthChecker}
+}
+
+// Liveness probe: answers as long as the process is serving requests, without touching dependencies
+func (healthControl *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.HealthReport{Status: domain.HealthStatusOK})
+}
+
+// Readiness probe: 200 when every registered check passes, otherwise 503 with the failing checks
+func (healthControl *HealthController) Readiness(c *gin.Context) {
+	report := healthControl.healthChecker.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != domain.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
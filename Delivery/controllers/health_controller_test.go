@@ -0,0 +1,85 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupHealthRouter(healthChecker domain.HealthChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	healthController := controllers.NewHealthController(healthChecker)
+
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
+	return router
+}
+
+func TestHealthController(t *testing.T) {
+	mockHealthChecker := mocks.NewMockHealthChecker(t)
+	router := setupHealthRouter(mockHealthChecker)
+
+	t.Run("Liveness_DoesNotRunChecks", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+		mockHealthChecker.AssertNotCalled(t, "Readiness", mock.Anything)
+	})
+
+	t.Run("Readiness_Ready", func(t *testing.T) {
+		mockHealthChecker.EXPECT().
+			Readiness(mock.Anything).
+			Return(domain.HealthReport{Status: domain.HealthStatusOK, Checks: map[string]domain.HealthCheckResult{
+				"mongo": {Status: domain.HealthStatusOK, LatencyMS: 1.5},
+			}}).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"status":"ok","checks":{"mongo":{"status":"ok","latency_ms":1.5}}}`, rr.Body.String())
+	})
+
+	t.Run("Readiness_CheckFailed", func(t *testing.T) {
+		mockHealthChecker.EXPECT().
+			Readiness(mock.Anything).
+			Return(domain.HealthReport{Status: domain.HealthStatusError, Checks: map[string]domain.HealthCheckResult{
+				"mongo": {Status: domain.HealthStatusError, LatencyMS: 2000, Error: "timed out"},
+			}}).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"error":"timed out"`)
+	})
+
+	t.Run("Readiness_ShuttingDown", func(t *testing.T) {
+		mockHealthChecker.EXPECT().
+			Readiness(mock.Anything).
+			Return(domain.HealthReport{Status: domain.HealthStatusShuttingDown}).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.JSONEq(t, `{"status":"shutting_down"}`, rr.Body.String())
+	})
+}
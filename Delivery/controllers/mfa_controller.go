@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAController struct {
+	mfaUsecase domain.MFAUsecase
+}
+
+func NewMFAController(mfaUsecase domain.MFAUsecase) *MFAController {
+	return &MFAController{mfaUsecase: mfaUsecase}
+}
+
+// Starts enrolling an authenticator app for the authenticated user
+func (mfaControl *MFAController) BeginEnrollment(c *gin.Context) {
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	enrollment, err := mfaControl.mfaUsecase.BeginEnrollment(ctx, username)
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// Enables two-factor authentication with a code from the newly enrolled app
+func (mfaControl *MFAController) ConfirmEnrollment(c *gin.Context) {
+	var req domain.MFACodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	recoveryCodes, err := mfaControl.mfaUsecase.ConfirmEnrollment(ctx, username, req.Code)
+	if err != nil {
+		mfaCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled. Store the recovery codes somewhere safe, they will not be shown again.",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// Turns two-factor authentication off for the authenticated user
+func (mfaControl *MFAController) Disable(c *gin.Context) {
+	var req domain.DisableMFARequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := mfaControl.mfaUsecase.Disable(ctx, username, req.Password, req.Code); err != nil {
+		mfaCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// Replaces the authenticated user's recovery codes
+func (mfaControl *MFAController) RegenerateRecoveryCodes(c *gin.Context) {
+	var req domain.MFACodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	recoveryCodes, err := mfaControl.mfaUsecase.RegenerateRecoveryCodes(ctx, username, req.Code)
+	if err != nil {
+		mfaCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
+
+// Second step of a login for users with two-factor authentication
+func (mfaControl *MFAController) CompleteLogin(c *gin.Context) {
+	var req domain.MFALoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	tokenString, err := mfaControl.mfaUsecase.CompleteLogin(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+}
+
+// Admin only: lists the roles that must use two-factor authentication
+func (mfaControl *MFAController) GetPolicy(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	policy, err := mfaControl.mfaUsecase.GetPolicy(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// Admin only: sets the roles that must use two-factor authentication
+func (mfaControl *MFAController) SetPolicy(c *gin.Context) {
+	var req domain.MFAPolicyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	policy := &domain.MFAPolicy{RequiredRoles: req.RequiredRoles}
+	if err := mfaControl.mfaUsecase.SetPolicy(ctx, policy); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// Responds to a failed operation that needed a code. Codes share the login lockout, so it is
+// answered the same way: 429 with Retry-After.
+func mfaCodeError(c *gin.Context, err error) {
+	var tooManyAttempts *domain.TooManyAttemptsError
+	if errors.As(err, &tooManyAttempts) {
+		loginError(c, err)
+		return
+	}
+
+	c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+}
@@ -0,0 +1,275 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupMFARouter(usecase domain.MFAUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mfaController := controllers.NewMFAController(usecase)
+
+	withAuth := func(c *gin.Context) { // Simulate Auth middleware
+		c.Set("username", "testuser")
+		c.Set("role", domain.RoleUser)
+	}
+
+	router.POST("/users/login/mfa", mfaController.CompleteLogin)
+	router.POST("/users/me/mfa/totp", withAuth, mfaController.BeginEnrollment)
+	router.POST("/users/me/mfa/totp/confirm", withAuth, mfaController.ConfirmEnrollment)
+	router.DELETE("/users/me/mfa", withAuth, mfaController.Disable)
+	router.POST("/users/me/mfa/recovery-codes", withAuth, mfaController.RegenerateRecoveryCodes)
+	router.PUT("/admin/mfa/policy", mfaController.SetPolicy)
+	return router
+}
+
+func TestMFAController_Enrollment(t *testing.T) {
+	mockUsecase := mocks.NewMockMFAUsecase(t)
+	router := setupMFARouter(mockUsecase)
+
+	t.Run("BeginEnrollment_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			BeginEnrollment(mock.AnythingOfType("*context.timerCtx"), "testuser").
+			Return(&domain.TOTPEnrollment{Secret: "SECRET", OTPAuthURI: "otpauth://totp/x", QRCode: "cG5n"}, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/totp", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var respBody map[string]string
+		json.Unmarshal(rr.Body.Bytes(), &respBody)
+		assert.Equal(t, "SECRET", respBody["secret"])
+		assert.Equal(t, "otpauth://totp/x", respBody["otpauth_uri"])
+		assert.Equal(t, "cG5n", respBody["qr_code"])
+	})
+
+	t.Run("BeginEnrollment_AlreadyEnabled", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			BeginEnrollment(mock.AnythingOfType("*context.timerCtx"), "testuser").
+			Return(nil, domain.ErrMFAAlreadyEnabled).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/totp", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("ConfirmEnrollment_ReturnsRecoveryCodes", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ConfirmEnrollment(mock.AnythingOfType("*context.timerCtx"), "testuser", "123456").
+			Return([]string{"aaaa-bbbb-cccc-dddd"}, nil).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.MFACodeRequest{Code: "123456"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/totp/confirm", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var respBody struct {
+			RecoveryCodes []string `json:"recovery_codes"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &respBody)
+		assert.Equal(t, []string{"aaaa-bbbb-cccc-dddd"}, respBody.RecoveryCodes)
+	})
+
+	t.Run("ConfirmEnrollment_InvalidCode", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ConfirmEnrollment(mock.AnythingOfType("*context.timerCtx"), "testuser", "000000").
+			Return(nil, domain.ErrInvalidMFACode).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.MFACodeRequest{Code: "000000"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/totp/confirm", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("ConfirmEnrollment_TooManyRequests", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ConfirmEnrollment(mock.AnythingOfType("*context.timerCtx"), "testuser", "123456").
+			Return(nil, &domain.TooManyAttemptsError{RetryAfter: time.Minute}).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.MFACodeRequest{Code: "123456"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/totp/confirm", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	})
+
+	t.Run("Disable_RequiredByPolicy", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			Disable(mock.AnythingOfType("*context.timerCtx"), "testuser", "password123", "123456").
+			Return(domain.ErrMFARequired).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.DisableMFARequest{Password: "password123", Code: "123456"})
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me/mfa", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Disable_TooManyRequests", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			Disable(mock.AnythingOfType("*context.timerCtx"), "testuser", "password123", "123456").
+			Return(&domain.TooManyAttemptsError{RetryAfter: time.Minute}).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.DisableMFARequest{Password: "password123", Code: "123456"})
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me/mfa", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	})
+
+	t.Run("RegenerateRecoveryCodes_TooManyRequests", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			RegenerateRecoveryCodes(mock.AnythingOfType("*context.timerCtx"), "testuser", "123456").
+			Return(nil, &domain.TooManyAttemptsError{RetryAfter: time.Minute}).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.MFACodeRequest{Code: "123456"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/recovery-codes", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	})
+
+	t.Run("RegenerateRecoveryCodes_InvalidCode", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			RegenerateRecoveryCodes(mock.AnythingOfType("*context.timerCtx"), "testuser", "000000").
+			Return(nil, domain.ErrInvalidMFACode).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.MFACodeRequest{Code: "000000"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/recovery-codes", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestMFAController_CompleteLogin(t *testing.T) {
+	mockUsecase := mocks.NewMockMFAUsecase(t)
+	router := setupMFARouter(mockUsecase)
+
+	send := func(body interface{}) *httptest.ResponseRecorder {
+		reqBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "mfa.token", "123456").
+			Return("access.token", nil).
+			Once()
+
+		rr := send(domain.MFALoginRequest{MFAToken: "mfa.token", Code: "123456"})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var respBody map[string]string
+		json.Unmarshal(rr.Body.Bytes(), &respBody)
+		assert.Equal(t, "access.token", respBody["token"])
+	})
+
+	t.Run("Unauthorized_InvalidCode", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "mfa.token", "000000").
+			Return("", domain.ErrInvalidMFACode).
+			Once()
+
+		rr := send(domain.MFALoginRequest{MFAToken: "mfa.token", Code: "000000"})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("TooManyRequests", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "mfa.token", "123456").
+			Return("", &domain.TooManyAttemptsError{RetryAfter: time.Minute}).
+			Once()
+
+		rr := send(domain.MFALoginRequest{MFAToken: "mfa.token", Code: "123456"})
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	})
+
+	t.Run("BadRequest_MissingCode", func(t *testing.T) {
+		rr := send(map[string]string{"mfa_token": "mfa.token"})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestMFAController_SetPolicy(t *testing.T) {
+	mockUsecase := mocks.NewMockMFAUsecase(t)
+	router := setupMFARouter(mockUsecase)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			SetPolicy(mock.AnythingOfType("*context.timerCtx"), &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin}}).
+			Return(nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/admin/mfa/policy", bytes.NewBufferString(`{"required_roles": ["admin"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("BadRequest_UnknownRole", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			SetPolicy(mock.AnythingOfType("*context.timerCtx"), &domain.MFAPolicy{RequiredRoles: []string{"superuser"}}).
+			Return(domain.ErrRoleNotFound).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/admin/mfa/policy", bytes.NewBufferString(`{"required_roles": ["superuser"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
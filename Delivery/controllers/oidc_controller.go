@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCController struct {
+	oidcUsecase domain.OIDCUsecase
+}
+
+func NewOIDCController(oidcUsecase domain.OIDCUsecase) *OIDCController {
+	return &OIDCController{oidcUsecase: oidcUsecase}
+}
+
+// Redirects the browser to the identity provider's login page
+func (oidcControl *OIDCController) Login(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	authURL, err := oidcControl.oidcUsecase.BeginLogin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// The identity provider redirects here with an authorization code, or an error if the user cancelled
+func (oidcControl *OIDCController) Callback(c *gin.Context) {
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": domain.ErrSSOFailed.Error() + ": " + providerError})
+		return
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state and code are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	result, err := oidcControl.oidcUsecase.CompleteLogin(ctx, state, code)
+	if errors.Is(err, domain.ErrSSOFailed) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Same response as a password login
+	if result.MFARequired {
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": result.MFAToken})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": result.Token})
+}
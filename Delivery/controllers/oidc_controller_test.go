@@ -0,0 +1,127 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupOIDCRouter(usecase domain.OIDCUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	oidcController := controllers.NewOIDCController(usecase)
+
+	router.GET("/users/oidc/login", oidcController.Login)
+	router.GET("/users/oidc/callback", oidcController.Callback)
+	return router
+}
+
+func TestOIDCController(t *testing.T) {
+	mockUsecase := mocks.NewMockOIDCUsecase(t)
+	router := setupOIDCRouter(mockUsecase)
+
+	t.Run("Login_RedirectsToProvider", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			BeginLogin(mock.AnythingOfType("*context.timerCtx")).
+			Return("https://idp.example.com/authorize?state=abc", nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/login", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusFound, rr.Code)
+		assert.Equal(t, "https://idp.example.com/authorize?state=abc", rr.Header().Get("Location"))
+	})
+
+	t.Run("Callback_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "abc", "code-1").
+			Return(&domain.LoginResult{Token: "jwt.token"}, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?state=abc&code=code-1", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"token": "jwt.token"}`, rr.Body.String())
+	})
+
+	t.Run("Callback_MFARequired", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "abc", "code-1").
+			Return(&domain.LoginResult{MFARequired: true, MFAToken: "mfa.token"}, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?state=abc&code=code-1", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"mfa_required": true, "mfa_token": "mfa.token"}`, rr.Body.String())
+	})
+
+	t.Run("Callback_ProviderError", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?error=access_denied&state=abc", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), "access_denied")
+	})
+
+	t.Run("Callback_MissingCode", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?state=abc", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Callback_InvalidState", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "replayed", "code-1").
+			Return(nil, domain.ErrInvalidToken).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?state=replayed&code=code-1", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Callback_VerificationFailed", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "abc", "code-1").
+			Return(nil, domain.ErrSSOFailed).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?state=abc&code=code-1", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Callback_EmailBelongsToUnverifiedAccount", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			CompleteLogin(mock.AnythingOfType("*context.timerCtx"), "abc", "code-1").
+			Return(nil, domain.ErrEmailTaken).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/oidc/callback?state=abc&code=code-1", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetController struct {
+	passwordResetUsecase domain.PasswordResetUsecase
+}
+
+func NewPasswordResetController(passwordResetUsecase domain.PasswordResetUsecase) *PasswordResetController {
+	return &PasswordResetController{passwordResetUsecase: passwordResetUsecase}
+}
+
+// Starts a password reset. The response is identical whether or not the username exists.
+func (resetControl *PasswordResetController) ForgotPassword(c *gin.Context) {
+	var req domain.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resetControl.passwordResetUsecase.RequestReset(c.Request.Context(), req.Username)
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists and has an email address, a password reset token has been sent"})
+}
+
+// Sets a new password using a token from the reset email
+func (resetControl *PasswordResetController) ResetPassword(c *gin.Context) {
+	var req domain.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := resetControl.passwordResetUsecase.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset. Please log in again."})
+}
@@ -0,0 +1,135 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupPasswordResetRouter(usecase domain.PasswordResetUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	resetController := controllers.NewPasswordResetController(usecase)
+	router.POST("/users/password/forgot", resetController.ForgotPassword)
+	router.POST("/users/password/reset", resetController.ResetPassword)
+	return router
+}
+
+func TestPasswordResetController_ForgotPassword(t *testing.T) {
+	mockUsecase := mocks.NewMockPasswordResetUsecase(t)
+	router := setupPasswordResetRouter(mockUsecase)
+
+	t.Run("Accepted_SameResponseForAnyUsername", func(t *testing.T) {
+		var bodies []string
+		for _, username := range []string{"existinguser", "ghost"} {
+			mockUsecase.EXPECT().
+				RequestReset(mock.Anything, username).
+				Return().
+				Once()
+
+			reqBody, _ := json.Marshal(domain.ForgotPasswordRequest{Username: username})
+			req, _ := http.NewRequest(http.MethodPost, "/users/password/forgot", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusAccepted, rr.Code)
+			bodies = append(bodies, rr.Body.String())
+		}
+		assert.Equal(t, bodies[0], bodies[1], "Responses must not reveal whether the username exists")
+	})
+
+	t.Run("BadRequest_MissingUsername", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/users/password/forgot", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestPasswordResetController_ResetPassword(t *testing.T) {
+	mockUsecase := mocks.NewMockPasswordResetUsecase(t)
+	router := setupPasswordResetRouter(mockUsecase)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ResetPassword(mock.AnythingOfType("*context.timerCtx"), "valid-token", "newpassword").
+			Return(nil).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.ResetPasswordRequest{Token: "valid-token", NewPassword: "newpassword"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("BadRequest_InvalidToken", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ResetPassword(mock.AnythingOfType("*context.timerCtx"), "expired-token", "newpassword").
+			Return(domain.ErrInvalidToken).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.ResetPasswordRequest{Token: "expired-token", NewPassword: "newpassword"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("BadRequest_WeakPassword_ListsViolations", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ResetPassword(mock.AnythingOfType("*context.timerCtx"), "valid-token", "password").
+			Return(&domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+				{Rule: domain.PasswordRuleCommonPassword, Message: "is too common"},
+				{Rule: domain.PasswordRuleBreachedPassword, Message: "has appeared in a data breach"},
+			}}).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.ResetPasswordRequest{Token: "valid-token", NewPassword: "password"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{
+			"error": "password does not meet the password policy",
+			"violations": [
+				{"rule": "common_password", "message": "is too common"},
+				{"rule": "breached_password", "message": "has appeared in a data breach"}
+			]
+		}`, rr.Body.String())
+	})
+
+	t.Run("InternalServerError_UsecaseError", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ResetPassword(mock.AnythingOfType("*context.timerCtx"), "valid-token", "newpassword").
+			Return(errors.New("db down")).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.ResetPasswordRequest{Token: "valid-token", NewPassword: "newpassword"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
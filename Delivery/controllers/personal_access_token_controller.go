@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenController struct {
+	tokenUsecase domain.PersonalAccessTokenUsecase
+}
+
+func NewPersonalAccessTokenController(tokenUsecase domain.PersonalAccessTokenUsecase) *PersonalAccessTokenController {
+	return &PersonalAccessTokenController{tokenUsecase: tokenUsecase}
+}
+
+// Creates a personal access token. The token is only ever returned in this response.
+func (tokenControl *PersonalAccessTokenController) CreateToken(c *gin.Context) {
+	var req domain.CreatePersonalAccessTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	accessToken, plaintext, err := tokenControl.tokenUsecase.CreateToken(ctx, username, req)
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":               "Token created. Copy it now, it will not be shown again.",
+		"token":                 plaintext,
+		"personal_access_token": accessToken,
+	})
+}
+
+// Lists the authenticated user's tokens, without the tokens themselves
+func (tokenControl *PersonalAccessTokenController) ListTokens(c *gin.Context) {
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	tokens, err := tokenControl.tokenUsecase.ListTokens(ctx, username)
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Revokes one of the authenticated user's tokens
+func (tokenControl *PersonalAccessTokenController) RevokeToken(c *gin.Context) {
+	username, _, err := infrastructure.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := tokenControl.tokenUsecase.RevokeToken(ctx, username, c.Param("id")); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked"})
+}
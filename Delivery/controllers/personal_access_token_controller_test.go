@@ -0,0 +1,113 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupPersonalAccessTokenRouter(usecase domain.PersonalAccessTokenUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	tokenController := controllers.NewPersonalAccessTokenController(usecase)
+
+	withAuth := func(c *gin.Context) { // Simulate Auth middleware
+		c.Set("username", "testuser")
+		c.Set("role", domain.RoleUser)
+	}
+
+	router.POST("/users/me/tokens", withAuth, tokenController.CreateToken)
+	router.GET("/users/me/tokens", withAuth, tokenController.ListTokens)
+	router.DELETE("/users/me/tokens/:id", withAuth, tokenController.RevokeToken)
+	return router
+}
+
+func TestPersonalAccessTokenController(t *testing.T) {
+	mockUsecase := mocks.NewMockPersonalAccessTokenUsecase(t)
+	router := setupPersonalAccessTokenRouter(mockUsecase)
+
+	t.Run("CreateToken_Success", func(t *testing.T) {
+		request := domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{domain.PermissionTasksRead}}
+		created := &domain.PersonalAccessToken{ID: domain.NewID(), Name: "ci", Prefix: "tm_pat_abcd1234", TokenHash: "secret-hash", Scopes: request.Scopes, ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockUsecase.EXPECT().
+			CreateToken(mock.AnythingOfType("*context.timerCtx"), "testuser", request).
+			Return(created, "tm_pat_abcd1234rest", nil).
+			Once()
+
+		reqBody, _ := json.Marshal(request)
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/tokens", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"token":"tm_pat_abcd1234rest"`)
+		assert.Contains(t, rr.Body.String(), `"prefix":"tm_pat_abcd1234"`)
+		assert.NotContains(t, rr.Body.String(), "secret-hash", "The stored hash must not be returned")
+	})
+
+	t.Run("CreateToken_BadRequest_UnknownScope", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/tokens", bytes.NewBufferString(`{"name": "ci", "scopes": ["everything"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("CreateToken_Forbidden_AdminScope", func(t *testing.T) {
+		request := domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{domain.PermissionUsersAdmin}}
+		mockUsecase.EXPECT().
+			CreateToken(mock.AnythingOfType("*context.timerCtx"), "testuser", request).
+			Return(nil, "", domain.ErrScopeNotAllowed).
+			Once()
+
+		reqBody, _ := json.Marshal(request)
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/tokens", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("ListTokens_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ListTokens(mock.AnythingOfType("*context.timerCtx"), "testuser").
+			Return([]domain.PersonalAccessToken{{Name: "ci"}, {Name: "deploy"}}, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/me/tokens", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var tokens []map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &tokens)
+		assert.Len(t, tokens, 2)
+	})
+
+	t.Run("RevokeToken_NotFound", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			RevokeToken(mock.AnythingOfType("*context.timerCtx"), "testuser", "abc").
+			Return(domain.ErrTokenNotFound).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me/tokens/abc", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleController struct {
+	roleUsecase domain.RoleUsecase
+}
+
+func NewRoleController(roleUsecase domain.RoleUsecase) *RoleController {
+	return &RoleController{roleUsecase: roleUsecase}
+}
+
+// Lists every role with its permissions
+func (roleControl *RoleController) ListRoles(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	roles, err := roleControl.roleUsecase.ListRoles(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles, "permissions": domain.AllPermissions})
+}
+
+// Creates the role named in the URL or replaces its permissions
+func (roleControl *RoleController) SaveRole(c *gin.Context) {
+	var req domain.SaveRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	role := &domain.Role{Name: c.Param("name"), Permissions: req.Permissions}
+	if err := roleControl.roleUsecase.SaveRole(ctx, role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+// Gives the user named in the URL another role
+func (roleControl *RoleController) AssignRole(c *gin.Context) {
+	var req domain.AssignRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := roleControl.roleUsecase.AssignRole(ctx, c.Param("username"), req.Role); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated"})
+}
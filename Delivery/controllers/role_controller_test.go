@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupRoleRouter(usecase domain.RoleUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	roleController := controllers.NewRoleController(usecase)
+
+	router.GET("/admin/roles", roleController.ListRoles)
+	router.PUT("/admin/roles/:name", roleController.SaveRole)
+	router.PUT("/admin/users/:username/role", roleController.AssignRole)
+	return router
+}
+
+func TestRoleController(t *testing.T) {
+	mockUsecase := mocks.NewMockRoleUsecase(t)
+	router := setupRoleRouter(mockUsecase)
+
+	t.Run("ListRoles_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ListRoles(mock.AnythingOfType("*context.timerCtx")).
+			Return(domain.DefaultRoles(), nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/roles", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"name":"admin"`)
+		assert.Contains(t, rr.Body.String(), `"permissions":["tasks:read","tasks:write","tasks:delete:any","users:admin"]`)
+	})
+
+	t.Run("SaveRole_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			SaveRole(mock.AnythingOfType("*context.timerCtx"), &domain.Role{Name: "viewer", Permissions: []string{domain.PermissionTasksRead}}).
+			Return(nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/admin/roles/viewer", bytes.NewBufferString(`{"permissions": ["tasks:read"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("SaveRole_UnknownPermission", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, "/admin/roles/viewer", bytes.NewBufferString(`{"permissions": ["tasks:everything"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("AssignRole_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			AssignRole(mock.AnythingOfType("*context.timerCtx"), "testuser", domain.RoleAdmin).
+			Return(nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/admin/users/testuser/role", bytes.NewBufferString(`{"role": "admin"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("AssignRole_UnknownRole", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			AssignRole(mock.AnythingOfType("*context.timerCtx"), "testuser", "superuser").
+			Return(domain.ErrRoleNotFound).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/admin/users/testuser/role", bytes.NewBufferString(`{"role": "superuser"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
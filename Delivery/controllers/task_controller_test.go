@@ -16,8 +16,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Helper to set up a Gin router with the TaskController for testing
@@ -46,8 +44,8 @@ func TestTaskController_GetAllTask(t *testing.T) {
 	t.Run("Success_ReturnsTasks", func(t *testing.T) {
 		// Arrange
 		expectedTasks := []domain.Task{
-			{ID: primitive.NewObjectID(), Title: "Task 1", CreatedBy: "testuser"},
-			{ID: primitive.NewObjectID(), Title: "Task 2", CreatedBy: "testuser"},
+			{ID: domain.NewID(), Title: "Task 1", CreatedBy: "testuser"},
+			{ID: domain.NewID(), Title: "Task 2", CreatedBy: "testuser"},
 		}
 		mockUsecase.EXPECT().
 			GetAllTask(mock.Anything). // Controller passes c.Request.Context()
@@ -103,15 +101,15 @@ func TestTaskController_GetTaskByID(t *testing.T) {
 
 	t.Run("Success_ReturnsTask", func(t *testing.T) {
 		// Arrange
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		expectedTask := domain.Task{ID: taskID, Title: "Specific Task", CreatedBy: "testuser"}
 
 		mockUsecase.EXPECT().
-			GetTaskByID(mock.Anything, taskID.Hex()).
+			GetTaskByID(mock.Anything, taskID.String()).
 			Return(expectedTask, nil).
 			Once()
 
-		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", taskID.Hex()), nil)
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", taskID.String()), nil)
 		rr := httptest.NewRecorder()
 
 		// Act
@@ -127,16 +125,16 @@ func TestTaskController_GetTaskByID(t *testing.T) {
 
 	t.Run("NotFound_UsecaseReturnsError", func(t *testing.T) {
 		// Arrange
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		// Your GetTaskByID controller maps usecase errors to http.StatusNotFound
 		usecaseError := errors.New("task not found in db") // Usecase returns this
 
 		mockUsecase.EXPECT().
-			GetTaskByID(mock.Anything, taskID.Hex()).
+			GetTaskByID(mock.Anything, taskID.String()).
 			Return(domain.Task{}, usecaseError). // Return empty task and error
 			Once()
 
-		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", taskID.Hex()), nil)
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", taskID.String()), nil)
 		rr := httptest.NewRecorder()
 
 		// Act
@@ -164,17 +162,19 @@ func TestTaskController_NewTask(t *testing.T) {
 		newTaskReq := domain.Task{Title: "A New Task", Description: "Description here", Status: "Pending"}
 		reqBodyBytes, _ := json.Marshal(newTaskReq)
 
-		mockObjectID := primitive.NewObjectID()
-		// Your controller expects *mongo.InsertOneResult from usecase.NewTask
-		mockInsertResult := &mongo.InsertOneResult{InsertedID: mockObjectID}
+		mockID := domain.NewID()
 
 		// The controller will set CreatedBy from context, then call usecase
 		expectedTaskToUsecase := newTaskReq
 		expectedTaskToUsecase.CreatedBy = "newtaskuser" // This is what controller passes
 
+		// The usecase returns the task as stored, with its ID
+		storedTask := expectedTaskToUsecase
+		storedTask.ID = mockID
+
 		mockUsecase.EXPECT().
 			NewTask(mock.Anything, expectedTaskToUsecase).
-			Return(mockInsertResult, nil).
+			Return(storedTask, nil).
 			Once()
 
 		req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(reqBodyBytes))
@@ -189,8 +189,8 @@ func TestTaskController_NewTask(t *testing.T) {
 		var createdTask domain.Task
 		json.Unmarshal(rr.Body.Bytes(), &createdTask)
 
-		// The controller sets the ID on the newTask object after successful insertion
-		assert.Equal(t, mockObjectID, createdTask.ID)
+		// The controller responds with the stored task
+		assert.Equal(t, mockID, createdTask.ID)
 		assert.Equal(t, newTaskReq.Title, createdTask.Title)
 		assert.Equal(t, "newtaskuser", createdTask.CreatedBy) // Check if CreatedBy is correctly set in response
 		mockUsecase.AssertExpectations(t)
@@ -241,7 +241,7 @@ func TestTaskController_NewTask(t *testing.T) {
 
 		mockUsecase.EXPECT().
 			NewTask(mock.Anything, expectedTaskToUsecase).
-			Return(nil, usecaseError).
+			Return(domain.Task{}, usecaseError).
 			Once()
 
 		req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(reqBodyBytes))
@@ -270,16 +270,16 @@ func TestTaskController_UpdateTask(t *testing.T) {
 
 	t.Run("Success_UpdatesTask", func(t *testing.T) {
 		// Arrange
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		updateReq := domain.Task{Title: "Updated Title", Status: "Completed"} // Only send fields to update
 		reqBodyBytes, _ := json.Marshal(updateReq)
 
 		mockUsecase.EXPECT().
-			UpdateTask(mock.Anything, taskID.Hex(), updateReq).
+			UpdateTask(mock.Anything, taskID.String(), updateReq, domain.Principal{Username: "taskupdater"}).
 			Return(nil). // Successful update returns nil error
 			Once()
 
-		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.Hex()), bytes.NewBuffer(reqBodyBytes))
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.String()), bytes.NewBuffer(reqBodyBytes))
 		req.Header.Set("Content-Type", "application/json")
 		rr := httptest.NewRecorder()
 
@@ -295,8 +295,8 @@ func TestTaskController_UpdateTask(t *testing.T) {
 	})
 
 	t.Run("BadRequest_InvalidJSON", func(t *testing.T) {
-		taskID := primitive.NewObjectID()
-		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.Hex()), bytes.NewBufferString(`{`))
+		taskID := domain.NewID()
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.String()), bytes.NewBufferString(`{`))
 		req.Header.Set("Content-Type", "application/json")
 		rr := httptest.NewRecorder()
 		router.ServeHTTP(rr, req)
@@ -306,17 +306,17 @@ func TestTaskController_UpdateTask(t *testing.T) {
 
 	t.Run("InternalServerError_UsecaseError", func(t *testing.T) {
 		// Arrange
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		updateReq := domain.Task{Title: "Update Title"}
 		reqBodyBytes, _ := json.Marshal(updateReq)
 		usecaseError := errors.New("update failed in db")
 
 		mockUsecase.EXPECT().
-			UpdateTask(mock.Anything, taskID.Hex(), updateReq).
+			UpdateTask(mock.Anything, taskID.String(), updateReq, domain.Principal{Username: "taskupdater"}).
 			Return(usecaseError).
 			Once()
 
-		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.Hex()), bytes.NewBuffer(reqBodyBytes))
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.String()), bytes.NewBuffer(reqBodyBytes))
 		req.Header.Set("Content-Type", "application/json")
 		rr := httptest.NewRecorder()
 
@@ -330,6 +330,27 @@ func TestTaskController_UpdateTask(t *testing.T) {
 		assert.Equal(t, usecaseError.Error(), respBody["error"])
 		mockUsecase.AssertExpectations(t)
 	})
+
+	t.Run("Forbidden_SomeoneElsesTask", func(t *testing.T) {
+		// Arrange
+		taskID := domain.NewID()
+		updateReq := domain.Task{Status: "Done"}
+		reqBodyBytes, _ := json.Marshal(updateReq)
+		mockUsecase.EXPECT().
+			UpdateTask(mock.Anything, taskID.String(), updateReq, domain.Principal{Username: "taskupdater"}).
+			Return(domain.ErrForbidden).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/tasks/%s", taskID.String()), bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rr, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
 }
 
 func TestTaskController_DeleteTask(t *testing.T) {
@@ -342,13 +363,13 @@ func TestTaskController_DeleteTask(t *testing.T) {
 
 	t.Run("Success_DeletesTask", func(t *testing.T) {
 		// Arrange
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		mockUsecase.EXPECT().
-			DeleteTask(mock.Anything, taskID.Hex()).
+			DeleteTask(mock.Anything, taskID.String(), domain.Principal{Username: "taskdeleter"}).
 			Return(nil). // Successful delete returns nil error
 			Once()
 
-		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s", taskID.Hex()), nil)
+		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s", taskID.String()), nil)
 		rr := httptest.NewRecorder()
 
 		// Act
@@ -364,15 +385,15 @@ func TestTaskController_DeleteTask(t *testing.T) {
 
 	t.Run("InternalServerError_UsecaseError", func(t *testing.T) {
 		// Arrange
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		usecaseError := errors.New("delete failed in db")
 
 		mockUsecase.EXPECT().
-			DeleteTask(mock.Anything, taskID.Hex()).
+			DeleteTask(mock.Anything, taskID.String(), domain.Principal{Username: "taskdeleter"}).
 			Return(usecaseError).
 			Once()
 
-		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s", taskID.Hex()), nil)
+		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s", taskID.String()), nil)
 		rr := httptest.NewRecorder()
 
 		// Act
@@ -385,4 +406,122 @@ func TestTaskController_DeleteTask(t *testing.T) {
 		assert.Equal(t, usecaseError.Error(), respBody["error"])
 		mockUsecase.AssertExpectations(t)
 	})
+
+	t.Run("Forbidden_SomeoneElsesTask", func(t *testing.T) {
+		// Arrange
+		taskID := domain.NewID()
+		mockUsecase.EXPECT().
+			DeleteTask(mock.Anything, taskID.String(), domain.Principal{Username: "taskdeleter"}).
+			Return(domain.ErrForbidden).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s", taskID.String()), nil)
+		rr := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rr, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
+
+func TestTaskController_BulkTasks(t *testing.T) {
+	mockUsecase := mocks.NewMockTaskUsecase(t)
+	router, taskController := setupTaskRouter(mockUsecase)
+	router.POST("/tasks/bulk", func(c *gin.Context) {
+		addAuthToContext(c, "bulkuser", domain.RoleUser)
+		taskController.BulkTasks(c)
+	})
+	principal := domain.Principal{Username: "bulkuser"}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Success_AllApplied", func(t *testing.T) {
+		// Arrange
+		taskID := domain.NewID()
+		request := domain.BulkTaskRequest{Operations: []domain.BulkTaskOperation{{Op: domain.BulkOpDelete, ID: taskID.String()}}}
+		results := []domain.BulkTaskResult{{Index: 0, Op: domain.BulkOpDelete, ID: taskID.String(), Status: domain.BulkStatusApplied}}
+		mockUsecase.EXPECT().
+			BulkTasks(mock.Anything, request, principal).
+			Return(results, nil).
+			Once()
+
+		// Act
+		rr := post(fmt.Sprintf(`{"operations": [{"op": "delete", "id": %q}]}`, taskID.String()))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var respBody struct {
+			Results []domain.BulkTaskResult `json:"results"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+		assert.Equal(t, results, respBody.Results)
+	})
+
+	t.Run("MultiStatus_SomeFailed", func(t *testing.T) {
+		// Arrange
+		mockUsecase.EXPECT().
+			BulkTasks(mock.Anything, mock.Anything, principal).
+			Return([]domain.BulkTaskResult{
+				{Index: 0, Op: domain.BulkOpCreate, ID: domain.NewID().String(), Status: domain.BulkStatusApplied},
+				{Index: 1, Op: domain.BulkOpDelete, Status: domain.BulkStatusFailed, Error: domain.ErrForbidden.Error()},
+			}, nil).
+			Once()
+
+		// Act
+		rr := post(`{"operations": [{"op": "create", "task": {"title": "New"}}, {"op": "delete", "id": "6ad520a5337eb89a3b000001"}]}`)
+
+		// Assert
+		assert.Equal(t, http.StatusMultiStatus, rr.Code)
+	})
+
+	t.Run("BadRequest_InvalidRequest", func(t *testing.T) {
+		// Arrange
+		mockUsecase.EXPECT().
+			BulkTasks(mock.Anything, domain.BulkTaskRequest{}, principal).
+			Return(nil, domain.ErrInvalidBulkRequest).
+			Once()
+
+		// Act & Assert
+		assert.Equal(t, http.StatusBadRequest, post(`{}`).Code)
+		assert.Equal(t, http.StatusBadRequest, post(`{"operations": `).Code, "Malformed JSON never reaches the usecase")
+	})
+
+	t.Run("RequestEntityTooLarge_TooManyTasks", func(t *testing.T) {
+		// Arrange
+		mockUsecase.EXPECT().
+			BulkTasks(mock.Anything, mock.Anything, principal).
+			Return(nil, domain.ErrTooManyBulkTasks).
+			Once()
+
+		// Act
+		rr := post(`{"filter": {"status": "Todo"}, "update": {"status": "Done"}}`)
+
+		// Assert
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		var respBody map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &respBody))
+		assert.EqualValues(t, domain.MaxBulkTaskOperations, respBody["max_operations"])
+	})
+
+	t.Run("NotImplemented_NoTransactions", func(t *testing.T) {
+		// Arrange
+		mockUsecase.EXPECT().
+			BulkTasks(mock.Anything, mock.Anything, principal).
+			Return(nil, fmt.Errorf("%w: standalone server", domain.ErrNoTransactions)).
+			Once()
+
+		// Act
+		rr := post(`{"operations": [{"op": "create", "task": {"title": "New"}}], "atomic": true}`)
+
+		// Assert
+		assert.Equal(t, http.StatusNotImplemented, rr.Code)
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 
@@ -11,13 +12,12 @@ import (
 	domain "task_manager/Domain"
 	"task_manager/mocks"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Helper to set up a Gin router with the UserController for testing
@@ -43,18 +43,19 @@ func TestUserController_Register(t *testing.T) {
 		// Arrange
 		registerReq := domain.RegisterRequest{
 			Username: "newtestuser",
+			Email:    "newtestuser@example.com",
 			Password: "password123",
 		}
 		reqBodyBytes, _ := json.Marshal(registerReq)
 
-		mockObjectID := primitive.NewObjectID()
-		// Expects a *mongo.InsertOneResult from the usecase's Register method
-		mockInsertResult := &mongo.InsertOneResult{InsertedID: mockObjectID}
+		mockID := domain.NewID()
+		// The usecase returns the user it created
+		createdUser := &domain.User{ID: mockID, Username: registerReq.Username, Email: registerReq.Email, Role: domain.RoleUser}
 
 		// Expect the Register method on the mock usecase to be called
 		mockUsecase.EXPECT().
-			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Password).
-			Return(mockInsertResult, nil). // Return success
+			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Email, registerReq.Password).
+			Return(createdUser, nil). // Return success
 			Once()
 
 		// Create an HTTP request
@@ -75,7 +76,7 @@ func TestUserController_Register(t *testing.T) {
 		assert.Equal(t, "User registered successfully", responseBody["message"])
 		userData, ok := responseBody["user"].(map[string]interface{})
 		require.True(t, ok, "'user' field should be a map")
-		assert.Equal(t, mockObjectID.Hex(), userData["id"])
+		assert.Equal(t, mockID.String(), userData["id"])
 		assert.Equal(t, registerReq.Username, userData["username"])
 		assert.Equal(t, domain.RoleUser, userData["role"]) // As per your controller's response
 
@@ -97,8 +98,8 @@ func TestUserController_Register(t *testing.T) {
 	})
 
 	t.Run("BadRequest_ValidationFailure", func(t *testing.T) {
-		// Arrange: Send data that fails Gin's struct validation (e.g., password too short)
-		registerReq := domain.RegisterRequest{Username: "validuser", Password: "123"} // Password "123" < 6 chars
+		// Arrange: Send data that fails Gin's struct validation (e.g., password missing; its length is up to the password policy)
+		registerReq := domain.RegisterRequest{Username: "validuser", Email: "validuser@example.com"}
 		reqBodyBytes, _ := json.Marshal(registerReq)
 		req, _ := http.NewRequest(http.MethodPost, "/users/register", bytes.NewBuffer(reqBodyBytes))
 		req.Header.Set("Content-Type", "application/json")
@@ -117,12 +118,12 @@ func TestUserController_Register(t *testing.T) {
 
 	t.Run("InternalServerError_UsecaseError", func(t *testing.T) {
 		// Arrange
-		registerReq := domain.RegisterRequest{Username: "testuser", Password: "password123"}
+		registerReq := domain.RegisterRequest{Username: "testuser", Email: "testuser@example.com", Password: "password123"}
 		reqBodyBytes, _ := json.Marshal(registerReq)
 		usecaseError := errors.New("simulated usecase db error")
 
 		mockUsecase.EXPECT().
-			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Password).
+			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Email, registerReq.Password).
 			Return(nil, usecaseError). // Usecase returns an error
 			Once()
 
@@ -159,7 +160,7 @@ func TestUserController_Login(t *testing.T) {
 
 		mockUsecase.EXPECT().
 			Login(mock.AnythingOfType("*context.timerCtx"), loginReq.Username, loginReq.Password).
-			Return(expectedToken, nil).
+			Return(&domain.LoginResult{Token: expectedToken}, nil).
 			Once()
 
 		req, _ := http.NewRequest(http.MethodPost, "/users/login", bytes.NewBuffer(reqBodyBytes))
@@ -177,6 +178,28 @@ func TestUserController_Login(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("Success_MFARequired", func(t *testing.T) {
+		loginReq := domain.LoginRequest{Username: "mfauser", Password: "password123"}
+		reqBodyBytes, _ := json.Marshal(loginReq)
+
+		mockUsecase.EXPECT().
+			Login(mock.AnythingOfType("*context.timerCtx"), loginReq.Username, loginReq.Password).
+			Return(&domain.LoginResult{MFARequired: true, MFAToken: "mfa.pending.token"}, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/login", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var respBody map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &respBody)
+		assert.Equal(t, true, respBody["mfa_required"])
+		assert.Equal(t, "mfa.pending.token", respBody["mfa_token"])
+		assert.NotContains(t, respBody, "token", "No access token before the second factor")
+	})
+
 	t.Run("BadRequest_InvalidJSON", func(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodPost, "/users/login", bytes.NewBufferString(`{"user":`))
 		req.Header.Set("Content-Type", "application/json")
@@ -206,7 +229,7 @@ func TestUserController_Login(t *testing.T) {
 
 		mockUsecase.EXPECT().
 			Login(mock.AnythingOfType("*context.timerCtx"), loginReq.Username, loginReq.Password).
-			Return("", usecaseError).
+			Return(nil, usecaseError).
 			Once()
 
 		req, _ := http.NewRequest(http.MethodPost, "/users/login", bytes.NewBuffer(reqBodyBytes))
@@ -226,3 +249,301 @@ func TestUserController_Login(t *testing.T) {
 	})
 
 }
+
+func TestUserController_Account(t *testing.T) {
+	// --- Setup ---
+	mockUsecase := mocks.NewMockUserUsecase(t)
+	router, userController := setupUserRouter(mockUsecase)
+	signedInAt := time.Now().Add(-time.Minute)
+	withAuth := func(handler gin.HandlerFunc) gin.HandlerFunc { // Simulate Auth middleware
+		return func(c *gin.Context) {
+			c.Set("username", "testuser")
+			c.Set("role", domain.RoleUser)
+			c.Set("signed_in_at", signedInAt)
+			handler(c)
+		}
+	}
+	router.GET("/users/me", withAuth(userController.GetProfile))
+	router.PATCH("/users/me", withAuth(userController.UpdateProfile))
+	router.POST("/users/me/password", withAuth(userController.ChangePassword))
+	router.DELETE("/users/me", withAuth(userController.DeleteAccount))
+
+	t.Run("GetProfile_Success", func(t *testing.T) {
+		profile := &domain.User{ID: domain.NewID(), Username: "testuser", PasswordHash: "secret_hash", Role: domain.RoleUser}
+
+		mockUsecase.EXPECT().
+			GetProfile(mock.AnythingOfType("*context.timerCtx"), "testuser").
+			Return(profile, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/me", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"username":"testuser"`)
+		assert.NotContains(t, rr.Body.String(), "secret_hash", "Password hash must never be returned")
+	})
+
+	t.Run("UpdateProfile_BadRequest_InvalidTimezone", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(`{"timezone": "Not/AZone"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("ChangePassword_Success_ReturnsFreshToken", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ChangePassword(mock.AnythingOfType("*context.timerCtx"), "testuser", "oldpassword", "newpassword").
+			Return("fresh.jwt.token", nil).
+			Once()
+
+		body := `{"current_password": "oldpassword", "new_password": "newpassword"}`
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var respBody map[string]string
+		json.Unmarshal(rr.Body.Bytes(), &respBody)
+		assert.Equal(t, "fresh.jwt.token", respBody["token"])
+	})
+
+	t.Run("ChangePassword_Forbidden_IncorrectCurrentPassword", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			ChangePassword(mock.AnythingOfType("*context.timerCtx"), "testuser", "wrongpassword", "newpassword").
+			Return("", domain.ErrIncorrectPassword).
+			Once()
+
+		body := `{"current_password": "wrongpassword", "new_password": "newpassword"}`
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("DeleteAccount_BadRequest_ConfirmationMismatch", func(t *testing.T) {
+		body := `{"password": "password123", "confirm": "someoneelse"}`
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUsecase.AssertNotCalled(t, "DeleteAccount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("DeleteAccount_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			DeleteAccount(mock.AnythingOfType("*context.timerCtx"), "testuser", "password123", signedInAt).
+			Return(nil).
+			Once()
+
+		body := `{"password": "password123", "confirm": "testuser"}`
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("DeleteAccount_WithoutPassword_ReauthRequired", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			DeleteAccount(mock.AnythingOfType("*context.timerCtx"), "testuser", "", signedInAt).
+			Return(domain.ErrReauthRequired).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me", bytes.NewBufferString(`{"confirm": "testuser"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), domain.ErrReauthRequired.Error())
+	})
+}
+
+func TestUserController_Email(t *testing.T) {
+	// --- Setup ---
+	mockUsecase := mocks.NewMockUserUsecase(t)
+	router, userController := setupUserRouter(mockUsecase)
+	router.POST("/users/register", userController.Register)
+	router.POST("/users/email/verify", userController.VerifyEmail)
+
+	t.Run("Register_Conflict_EmailTaken", func(t *testing.T) {
+		registerReq := domain.RegisterRequest{Username: "newuser", Email: "taken@example.com", Password: "password123"}
+		reqBodyBytes, _ := json.Marshal(registerReq)
+
+		mockUsecase.EXPECT().
+			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Email, registerReq.Password).
+			Return(nil, domain.ErrEmailTaken).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/register", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Register_Conflict_UsernameTakenConcurrently", func(t *testing.T) {
+		registerReq := domain.RegisterRequest{Username: "racer", Email: "racer@example.com", Password: "password123"}
+		reqBodyBytes, _ := json.Marshal(registerReq)
+
+		// Another registration stored the username after the usecase checked for it
+		mockUsecase.EXPECT().
+			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Email, registerReq.Password).
+			Return(nil, fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrUserAlreadyExists)).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/register", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Register_BadRequest_WeakPassword", func(t *testing.T) {
+		registerReq := domain.RegisterRequest{Username: "newuser", Email: "newuser@example.com", Password: "newuser1"}
+		reqBodyBytes, _ := json.Marshal(registerReq)
+
+		mockUsecase.EXPECT().
+			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Email, registerReq.Password).
+			Return(nil, &domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+				{Rule: domain.PasswordRuleContainsUsername, Message: "must not contain the username"},
+			}}).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/register", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"rule":"contains_username"`)
+	})
+
+	t.Run("Register_BadRequest_ShortPassword_ReportedByPolicy", func(t *testing.T) {
+		registerReq := domain.RegisterRequest{Username: "newuser", Email: "newuser@example.com", Password: "abc"}
+		reqBodyBytes, _ := json.Marshal(registerReq)
+
+		mockUsecase.EXPECT().
+			Register(mock.AnythingOfType("*context.timerCtx"), registerReq.Username, registerReq.Email, registerReq.Password).
+			Return(nil, &domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+				{Rule: domain.PasswordRuleMinLength, Message: "must be at least 12 characters"},
+			}}).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/register", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"rule":"min_length"`)
+	})
+
+	t.Run("Register_BadRequest_InvalidEmailOrUsername", func(t *testing.T) {
+		for _, registerReq := range []domain.RegisterRequest{
+			{Username: "newuser", Email: "not-an-email", Password: "password123"},
+			{Username: "new@user", Email: "newuser@example.com", Password: "password123"}, // "@" is reserved for emails
+		} {
+			reqBodyBytes, _ := json.Marshal(registerReq)
+			req, _ := http.NewRequest(http.MethodPost, "/users/register", bytes.NewBuffer(reqBodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("VerifyEmail_BadRequest_InvalidToken", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			VerifyEmail(mock.AnythingOfType("*context.timerCtx"), "expired-token").
+			Return(domain.ErrInvalidToken).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/email/verify", bytes.NewBufferString(`{"token": "expired-token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("VerifyEmail_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			VerifyEmail(mock.AnythingOfType("*context.timerCtx"), "valid-token").
+			Return(nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/email/verify", bytes.NewBufferString(`{"token": "valid-token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_Lockout(t *testing.T) {
+	// --- Setup ---
+	mockUsecase := mocks.NewMockUserUsecase(t)
+	router, userController := setupUserRouter(mockUsecase)
+	router.POST("/users/login", userController.Login)
+	router.POST("/admin/users/:username/unlock", userController.UnlockUser)
+
+	t.Run("Login_TooManyRequests_SetsRetryAfter", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			Login(mock.AnythingOfType("*context.timerCtx"), "testuser", "password123").
+			Return(nil, &domain.TooManyAttemptsError{RetryAfter: 89500 * time.Millisecond}).
+			Once()
+
+		reqBody, _ := json.Marshal(domain.LoginRequest{Username: "testuser", Password: "password123"})
+		req, _ := http.NewRequest(http.MethodPost, "/users/login", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "90", rr.Header().Get("Retry-After"), "Retry-After should be rounded up to whole seconds")
+	})
+
+	t.Run("UnlockUser_NotFound", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			UnlockUser(mock.AnythingOfType("*context.timerCtx"), "ghost").
+			Return(domain.ErrUserNotFound).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/admin/users/ghost/unlock", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("UnlockUser_Success", func(t *testing.T) {
+		mockUsecase.EXPECT().
+			UnlockUser(mock.AnythingOfType("*context.timerCtx"), "testuser").
+			Return(nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/admin/users/testuser/unlock", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
@@ -2,31 +2,235 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
 	"task_manager/Delivery/router"
 	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	"task_manager/Repositories/sqlstore"
 	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func main() {
+	// "migrate up|down|status" changes the database schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Load settings from the config file, environment and flags
+	config, err := infrastructure.LoadConfig(os.Args[1:])
+	if err != nil {
+		fatal("failed to load configuration", err)
+	}
+
+	// Log as JSON (or text) from here on; the standard log package writes through it too
+	slog.SetDefault(infrastructure.NewLogger(config.Log, os.Stderr))
+
+	// Record traces when an exporter is configured; stdout keeps them apart from the logs on stderr
+	shutdownTracing, err := infrastructure.SetupTracing(context.Background(), config.Tracing, os.Stdout)
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+
+	// Failures once the database is open set the exit status and return instead of exiting, so it is
+	// disconnected and traces are flushed first
+	exitStatus := 0
+	defer func() {
+		if exitStatus != 0 {
+			os.Exit(exitStatus)
+		}
+	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("failed to flush traces", slog.Any("error", err))
+		}
+	}()
+
+	// Readiness checks; other subsystems may register their own
+	healthChecker := infrastructure.NewHealthChecker()
+
+	// Keep data in MongoDB, a SQL database, or in memory
+	var dbClient *mongo.Client
+	var sqlDB *sqlstore.DB
+	switch config.Database.Storage {
+	case "mongo":
+		dbClient = connectMongo(config.Database)
+
+		// Disconnect database when main exits.
+		defer infrastructure.DisconnectDB(dbClient)
+
+		healthChecker.Register("mongo", infrastructure.MongoHealthCheck(dbClient))
+	case "sqlite", "postgres":
+		sqlDB = openSQL(config.Database)
+		defer sqlDB.Close()
+
+		healthChecker.Register(config.Database.Storage, sqlDB.Ping)
+	default:
+		slog.Warn("keeping data in memory, it is lost when the server stops", slog.String("storage", config.Database.Storage))
+	}
+
+	routes, metricsRoutes, waitForBackgroundWork, err := router.SetupRouter(dbClient, sqlDB, config, healthChecker)
+	if err != nil {
+		slog.Error("failed to set up routes", slog.Any("error", err))
+		exitStatus = 1
+		return
+	}
+	// Emails still being sent need the database, so it is disconnected after them
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.Server.ShutdownTimeout))
+		defer cancel()
+		if err := waitForBackgroundWork(ctx); err != nil {
+			slog.Error("background work did not finish", slog.Any("error", err))
+		}
+	}()
+
+	// Stop on SIGINT or SIGTERM; a second signal stops the process without waiting.
+	// Readiness fails first, giving the orchestrator the drain delay to stop sending traffic.
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+	go func() {
+		<-signalCtx.Done()
+		stop()
+		healthChecker.SetShuttingDown()
+		time.Sleep(time.Duration(config.Server.DrainDelay))
+		stopServing()
+	}()
+
+	listener, err := net.Listen("tcp", config.Server.Address)
+	if err != nil {
+		slog.Error("failed to listen", slog.Any("error", err))
+		exitStatus = 1
+		return
+	}
+
+	metricsListener, err := net.Listen("tcp", config.Metrics.Address)
+	if err != nil {
+		slog.Error("failed to listen for metrics scrapes", slog.Any("error", err))
+		exitStatus = 1
+		return
+	}
+
+	// Serve metrics until the server stops
+	metricsServer := infrastructure.NewHTTPServer(config.Server, metricsRoutes)
+	metricsStopped := make(chan struct{})
+	go func() {
+		defer close(metricsStopped)
+		slog.Info("serving metrics", slog.String("address", metricsListener.Addr().String()))
+		if err := infrastructure.RunHTTPServer(ctx, metricsServer, metricsListener, time.Duration(config.Server.ShutdownTimeout)); err != nil {
+			slog.Error("metrics server stopped", slog.Any("error", err))
+		}
+	}()
+	defer func() { <-metricsStopped }()
+
+	// Start server
+	server := infrastructure.NewHTTPServer(config.Server, routes)
+	slog.Info("starting server", slog.String("address", listener.Addr().String()))
+	if err := infrastructure.RunHTTPServer(ctx, server, listener, time.Duration(config.Server.ShutdownTimeout)); err != nil {
+		slog.Error("server did not shut down cleanly", slog.Any("error", err))
+		return
+	}
+
+	slog.Info("server stopped, in-flight requests finished")
+}
+
+// Connects to MongoDB, brings its indexes and data up to date and creates the default roles
+func connectMongo(config infrastructure.DatabaseConfig) *mongo.Client {
 	// Set up context with a timeout for database connection
-	dbConnectContext, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	dbConnectContext, cancel := context.WithTimeout(context.Background(), time.Duration(config.ConnectTimeout))
 	defer cancel()
 
 	// Connect to Database.
-	dbClient, err := infrastructure.ConnectDB(dbConnectContext)
+	dbClient, err := infrastructure.ConnectDB(dbConnectContext, config.URI)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("failed to connect to database", err)
+	}
+
+	// Migrations may rewrite whole collections, so they are not bound by the connect timeout
+	migrator := newMongoMigrator(dbClient, config)
+	if config.AutoMigrate {
+		if err := migrator.Up(context.Background()); err != nil {
+			fatal("failed to migrate database", err)
+		}
+	} else {
+		requireMigrated(migrator.Pending(dbConnectContext))
 	}
 
-	// Disconnect database when main exits.
-	defer infrastructure.DisconnectDB(dbClient)
+	if err := repositories.EnsureDefaultRoles(dbConnectContext, dbClient, config.Name, config.Collections.Roles); err != nil {
+		fatal("failed to create default roles", err)
+	}
 
-	routes := router.SetupRouter(dbClient)
+	return dbClient
+}
 
-	// Start server
-	log.Println("Starting server on port 8080.")
-	if err := routes.Run("localhost:8080"); err != nil {
-		log.Fatalf("failed to run server: %v", err)
+func newMongoMigrator(client *mongo.Client, config infrastructure.DatabaseConfig) *repositories.Migrator {
+	collections := repositories.CollectionNames{
+		Users:                config.Collections.Users,
+		Tasks:                config.Collections.Tasks,
+		PersonalAccessTokens: config.Collections.PersonalAccessTokens,
+		AuditLog:             config.Collections.AuditLog,
+		RateLimits:           config.Collections.RateLimits,
+	}
+
+	return repositories.NewMigrator(client, config.Name, collections, repositories.Migrations())
+}
+
+// Opens the SQL database and brings its schema up to date
+func openSQL(config infrastructure.DatabaseConfig) *sqlstore.DB {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ConnectTimeout))
+	defer cancel()
+
+	db := dialSQL(ctx, config)
+
+	if config.AutoMigrate {
+		if err := db.Migrate(ctx); err != nil {
+			fatal("failed to migrate database", err)
+		}
+	} else {
+		requireMigrated(db.Pending(ctx))
 	}
+	if err := sqlstore.EnsureDefaultRoles(ctx, db); err != nil {
+		fatal("failed to create default roles", err)
+	}
+
+	return db
+}
+
+// Opens the SQL database and checks it can be reached
+func dialSQL(ctx context.Context, config infrastructure.DatabaseConfig) *sqlstore.DB {
+	db, err := sqlstore.Open(config.Storage, config.DSN)
+	if err != nil {
+		fatal("failed to open database", err)
+	}
+	if err := db.Ping(ctx); err != nil {
+		fatal("failed to connect to database", err)
+	}
+
+	return db
+}
+
+// Exits unless every migration has been applied, when they are not applied on start
+func requireMigrated(pending int, err error) {
+	if err != nil {
+		fatal("failed to check database migrations", err)
+	}
+	if pending > 0 {
+		fatal("database is not up to date, run \"migrate up\" first", fmt.Errorf("%d pending migrations", pending))
+	}
+}
+
+// Logs the error and exits, like log.Fatal
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
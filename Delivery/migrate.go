@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	"task_manager/Repositories/sqlstore"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: task_manager migrate up|down|status [flags]"
+
+// Runs "migrate up", "migrate down" or "migrate status" against the configured database. Takes the
+// same flags, environment and config file as the server.
+func runMigrate(args []string) {
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	if err := migrate(args[0], args[1:]); err != nil {
+		fatal("migration failed", err)
+	}
+}
+
+func migrate(action string, args []string) error {
+	config, err := infrastructure.LoadConfig(args)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(infrastructure.NewLogger(config.Log, os.Stderr))
+
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, time.Duration(config.Database.ConnectTimeout))
+	defer cancel()
+
+	switch config.Database.Storage {
+	case "mongo":
+		client, err := infrastructure.ConnectDB(connectCtx, config.Database.URI)
+		if err != nil {
+			return err
+		}
+		defer infrastructure.DisconnectDB(client)
+
+		return migrateMongo(ctx, newMongoMigrator(client, config.Database), action)
+	case "sqlite", "postgres":
+		db, err := sqlstore.Open(config.Database.Storage, config.Database.DSN)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		if err := db.Ping(connectCtx); err != nil {
+			return err
+		}
+
+		return migrateSQL(ctx, db, action)
+	default:
+		return errors.New("data kept in memory has no schema to migrate")
+	}
+}
+
+func migrateMongo(ctx context.Context, migrator *repositories.Migrator, action string) error {
+	switch action {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		return migrator.Down(ctx)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := newStatusTable()
+	for _, status := range statuses {
+		table.row(status.Version, status.AppliedAt, status.Description)
+	}
+
+	return table.Flush()
+}
+
+func migrateSQL(ctx context.Context, db *sqlstore.DB, action string) error {
+	switch action {
+	case "up":
+		return db.Migrate(ctx)
+	case "down":
+		return errors.New("SQL migrations cannot be rolled back, restore a backup instead")
+	}
+
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := newStatusTable()
+	for _, status := range statuses {
+		table.row(status.Version, status.AppliedAt, status.Name)
+	}
+
+	return table.Flush()
+}
+
+// Lists migrations on stdout, one per line
+type statusTable struct {
+	*tabwriter.Writer
+}
+
+func newStatusTable() statusTable {
+	table := statusTable{tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)}
+	fmt.Fprintln(table, "VERSION\tAPPLIED\tDESCRIPTION")
+
+	return table
+}
+
+func (table statusTable) row(version int, appliedAt *time.Time, description string) {
+	applied := "pending"
+	if appliedAt != nil {
+		applied = appliedAt.Format(time.RFC3339)
+	}
+	fmt.Fprintf(table, "%d\t%s\t%s\n", version, applied, description)
+}
@@ -1,58 +1,331 @@
 package router
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"os"
 	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
 	repositories "task_manager/Repositories"
+	"task_manager/Repositories/memory"
+	"task_manager/Repositories/sqlstore"
 	usecases "task_manager/Usecases"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRouter(dbClient *mongo.Client) *gin.Engine {
+// Request limits per route group. Public routes are limited per client IP, the others per user.
+var (
+	publicRateLimit  = domain.RateLimit{Rate: 20, Period: time.Minute, Burst: 20}
+	accountRateLimit = domain.RateLimit{Rate: 60, Period: time.Minute, Burst: 30}
+	adminRateLimit   = domain.RateLimit{Rate: 60, Period: time.Minute, Burst: 30}
+	taskRateLimit    = domain.RateLimit{Rate: 120, Period: time.Minute, Burst: 60}
+)
+
+// Returns the routes of the API and, to serve on the internal metrics address, those of the metrics.
+// Once the server stops, wait blocks until the work the routes left running in the background is done.
+// dbClient is nil unless data is kept in MongoDB, and sqlDB unless it is kept in a SQL database.
+func SetupRouter(dbClient *mongo.Client, sqlDB *sqlstore.DB, config *infrastructure.Config, healthChecker domain.HealthChecker) (routes, metricsRoutes *gin.Engine, wait func(ctx context.Context) error, err error) {
+	metrics := infrastructure.NewMetrics()
+
 	// Initialize repositories
-	taskRepo := repositories.NewTaskRepository(dbClient, "task_manager", "tasks")
-	userRepo := repositories.NewUserRepository(dbClient, "task_manager", "user")
+	repos := newRepositories(dbClient, sqlDB, config)
+	taskRepo := infrastructure.NewInstrumentedTaskRepository(repos.tasks, metrics)
+	userRepo := infrastructure.NewInstrumentedUserRepository(repos.users, metrics)
+	tokenRepo := repos.oneTimeTokens
+	mfaPolicyRepo := repos.mfaPolicies
+	accessTokenRepo := repos.accessTokens
+	roleRepo := repos.roles
+	auditRepo := repos.auditLog
 
 	// Initialize services
-	jwtService := infrastructure.NewJWTService()
-	passwordService := infrastructure.NewPasswordService()
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
+	jwtService := infrastructure.NewJWTService([]byte(config.JWT.Secret), time.Duration(config.JWT.TokenTTL))
+	passwordService := newPasswordService(config.Password)
+	breachedPasswords, err := newBreachedPasswordChecker(config.Password)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	passwordValidator := infrastructure.NewPasswordValidator(newPasswordPolicy(config.Password), breachedPasswords)
+	mailer := newMailer(config.Mail)
+	loginThrottle := infrastructure.NewLoginThrottle(infrastructure.DefaultLoginThrottlePolicy())
+	totpService := infrastructure.NewTOTPService("Task Manager")
+	auditLogger := infrastructure.NewAuditLogger(auditRepo)
+	rateLimiter := infrastructure.NewRateLimiter(newRateLimitStore(dbClient, config))
 
 	// Initialize usecases
-	taskUsecase := usecases.NewTaskUsecase(taskRepo)
-	userUsecase := usecases.NewUserUsecase(userRepo, passwordService, jwtService)
+	accessTokenUsecase := usecases.NewPersonalAccessTokenUsecase(accessTokenRepo, userRepo, roleRepo, auditLogger)
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, userRepo, roleRepo, accessTokenUsecase)
+	roleUsecase := usecases.NewRoleUsecase(roleRepo, userRepo, auditLogger)
+	taskUsecase := infrastructure.NewTracedTaskUsecase(usecases.NewTaskUsecase(taskRepo, repos.transactions, auditLogger))
+	userUsecase := infrastructure.NewTracedUserUsecase(usecases.NewUserUsecase(userRepo, passwordService, passwordValidator, jwtService, tokenRepo, accessTokenRepo, taskRepo, repos.transactions, mailer, loginThrottle, auditLogger, metrics))
+	passwordResetUsecase := usecases.NewPasswordResetUsecase(userRepo, tokenRepo, accessTokenRepo, passwordService, passwordValidator, mailer, auditLogger)
+	mfaUsecase := usecases.NewMFAUsecase(userRepo, mfaPolicyRepo, roleRepo, tokenRepo, passwordService, jwtService, totpService, loginThrottle, auditLogger)
 
 	taskController := controllers.NewTaskController(taskUsecase)
 	userController := controllers.NewUserController(userUsecase)
+	passwordResetController := controllers.NewPasswordResetController(passwordResetUsecase)
+	mfaController := controllers.NewMFAController(mfaUsecase)
+	accessTokenController := controllers.NewPersonalAccessTokenController(accessTokenUsecase)
+	roleController := controllers.NewRoleController(roleUsecase)
+	auditController := controllers.NewAuditController(usecases.NewAuditUsecase(auditRepo))
+	healthController := controllers.NewHealthController(healthChecker)
 
 	// Setup Gin router
-	router := gin.Default()
+	router, err := infrastructure.NewGinEngine(config.Server)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to set trusted proxies: %w", err)
+	}
+	// Tracing comes before the request metadata so log lines can carry the trace ID
+	router.Use(gin.Recovery(), otelgin.Middleware(config.Tracing.ServiceName, otelgin.WithFilter(notProbe)), infrastructure.RequestMetadata(), infrastructure.AccessLog(), metrics.Middleware())
 
 	// Public routes (no authentication required)
 	router.GET("", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{"message": "Welcome to Favour Olumese Task Manager"})
 	})
 
+	// Probes for the orchestrator
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
+
 	// User authentication routes (public)
 	userGroup := router.Group("/users") // Group related user routes
+	userGroup.Use(rateLimiter.Limit("users", publicRateLimit))
 	{
 		userGroup.POST("/register", userController.Register)
 		userGroup.POST("/login", userController.Login)
+		userGroup.POST("/login/mfa", mfaController.CompleteLogin)
+		userGroup.POST("/password/forgot", passwordResetController.ForgotPassword)
+		userGroup.POST("/password/reset", passwordResetController.ResetPassword)
+		userGroup.POST("/email/verify", userController.VerifyEmail)
+	}
+
+	// Single sign-on, when an OpenID Connect provider is configured
+	oidcProvider, err := newOIDCProvider(config.OIDC)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if oidcProvider != nil {
+		oidcController := controllers.NewOIDCController(usecases.NewOIDCUsecase(oidcProvider, userRepo, tokenRepo, jwtService, auditLogger))
+		userGroup.GET("/oidc/login", oidcController.Login)
+		userGroup.GET("/oidc/callback", oidcController.Callback)
+	}
+
+	// Self-service account routes (authentication required, personal access tokens are not accepted)
+	accountGroup := router.Group("/users/me")
+	accountGroup.Use(authMiddleware.AuthRequired(), rateLimiter.Limit("account", accountRateLimit), authMiddleware.RequireSession())
+	{
+		accountGroup.GET("", userController.GetProfile)
+		accountGroup.PATCH("", userController.UpdateProfile)
+		accountGroup.POST("/password", userController.ChangePassword)
+		accountGroup.DELETE("", userController.DeleteAccount)
+		accountGroup.POST("/email/verification", userController.RequestEmailVerification)
+		accountGroup.POST("/mfa/totp", mfaController.BeginEnrollment)
+		accountGroup.POST("/mfa/totp/confirm", mfaController.ConfirmEnrollment)
+		accountGroup.POST("/mfa/recovery-codes", mfaController.RegenerateRecoveryCodes)
+		accountGroup.DELETE("/mfa", mfaController.Disable)
+		accountGroup.POST("/tokens", accessTokenController.CreateToken)
+		accountGroup.GET("/tokens", accessTokenController.ListTokens)
+		accountGroup.DELETE("/tokens/:id", accessTokenController.RevokeToken)
+	}
+
+	// Admin routes (authentication and the users:admin permission required)
+	adminGroup := router.Group("/admin")
+	adminGroup.Use(authMiddleware.AuthRequired(), rateLimiter.Limit("admin", adminRateLimit), authMiddleware.RequirePermission(domain.PermissionUsersAdmin), authMiddleware.RequireMFA(mfaPolicyRepo))
+	{
+		adminGroup.POST("/users/:username/unlock", userController.UnlockUser)
+		adminGroup.PUT("/users/:username/role", roleController.AssignRole)
+		adminGroup.GET("/roles", roleController.ListRoles)
+		adminGroup.PUT("/roles/:name", roleController.SaveRole)
+		adminGroup.GET("/mfa/policy", mfaController.GetPolicy)
+		adminGroup.PUT("/mfa/policy", mfaController.SetPolicy)
+		adminGroup.GET("/audit", auditController.ListEvents)
 	}
 
 	// Protect tasks routes (authenication required)
 	// Apply the AuthRequired middleware to this group
 	protectedTaskGroup := router.Group("/tasks")
-	protectedTaskGroup.Use(authMiddleware.AuthRequired()) // Apply authentication middleware to all routes in this group
+	protectedTaskGroup.Use(authMiddleware.AuthRequired())             // Apply authentication middleware to all routes in this group
+	protectedTaskGroup.Use(rateLimiter.Limit("tasks", taskRateLimit)) // Per user, so it runs after authentication
+	protectedTaskGroup.Use(authMiddleware.RequireMFA(mfaPolicyRepo))  // Roles that must use 2FA are blocked until they enable it
 	{
-		protectedTaskGroup.GET("", taskController.GetAllTask)
-		protectedTaskGroup.GET("/:id", taskController.GetTaskByID)
-		protectedTaskGroup.PUT("/:id", taskController.UpdateTask)
-		protectedTaskGroup.DELETE("/:id", taskController.DeleteTask)
-		protectedTaskGroup.POST("", taskController.NewTask)
+		canRead := authMiddleware.RequirePermission(domain.PermissionTasksRead)
+		canWrite := authMiddleware.RequirePermission(domain.PermissionTasksWrite)
+
+		protectedTaskGroup.GET("", canRead, taskController.GetAllTask)
+		protectedTaskGroup.GET("/:id", canRead, taskController.GetTaskByID)
+		protectedTaskGroup.PUT("/:id", canWrite, taskController.UpdateTask)
+		protectedTaskGroup.DELETE("/:id", canWrite, taskController.DeleteTask)
+		createTask := append(append([]gin.HandlerFunc{canWrite}, newTaskPolicy(config.Tasks, authMiddleware)...), taskController.NewTask)
+		protectedTaskGroup.POST("", createTask...)
+		// Bulk requests may create tasks, so they follow the same policy
+		bulkTasks := append(append([]gin.HandlerFunc{canWrite}, newTaskPolicy(config.Tasks, authMiddleware)...), taskController.BulkTasks)
+		protectedTaskGroup.POST("/bulk", bulkTasks...)
+	}
+
+	// Prometheus metrics, kept off the public routes
+	metrics.RegisterTaskCounts(taskRepo)
+	metricsRouter := gin.New()
+	metricsRouter.Use(gin.Recovery())
+	metricsRouter.GET("/metrics", metrics.Handler(config.Metrics.BearerToken))
+
+	return router, metricsRouter, passwordResetUsecase.Close, nil
+}
+
+// Repositories the usecases are built on
+type repositorySet struct {
+	tasks         domain.TaskRepository
+	users         domain.UserRepository
+	oneTimeTokens domain.OneTimeTokenRepository
+	mfaPolicies   domain.MFAPolicyRepository
+	accessTokens  domain.PersonalAccessTokenRepository
+	roles         domain.RoleRepository
+	auditLog      domain.AuditLogRepository
+	transactions  domain.UnitOfWork // Runs calls to the repositories above in one transaction
+}
+
+// Keep data in MongoDB, a SQL database, or in memory when the storage is "memory", where it is lost
+// when the server stops
+func newRepositories(dbClient *mongo.Client, sqlDB *sqlstore.DB, config *infrastructure.Config) repositorySet {
+	switch config.Database.Storage {
+	case "sqlite", "postgres":
+		return repositorySet{
+			tasks:         sqlstore.NewTaskRepository(sqlDB),
+			users:         sqlstore.NewUserRepository(sqlDB),
+			oneTimeTokens: sqlstore.NewOneTimeTokenRepository(sqlDB),
+			mfaPolicies:   sqlstore.NewMFAPolicyRepository(sqlDB),
+			accessTokens:  sqlstore.NewPersonalAccessTokenRepository(sqlDB),
+			roles:         sqlstore.NewRoleRepository(sqlDB),
+			auditLog:      sqlstore.NewAuditLogRepository(sqlDB),
+			transactions:  sqlstore.NewUnitOfWork(sqlDB),
+		}
+	case "memory":
+		return repositorySet{
+			tasks:         memory.NewTaskRepository(),
+			users:         memory.NewUserRepository(),
+			oneTimeTokens: memory.NewOneTimeTokenRepository(),
+			mfaPolicies:   memory.NewMFAPolicyRepository(),
+			accessTokens:  memory.NewPersonalAccessTokenRepository(),
+			roles:         memory.NewRoleRepository(),
+			auditLog:      memory.NewAuditLogRepository(),
+			transactions:  memory.NewUnitOfWork(),
+		}
+	}
+
+	dbName := config.Database.Name
+	collections := config.Database.Collections
+
+	return repositorySet{
+		tasks:         repositories.NewTaskRepository(dbClient, dbName, collections.Tasks),
+		users:         repositories.NewUserRepository(dbClient, dbName, collections.Users),
+		oneTimeTokens: repositories.NewOneTimeTokenRepository(dbClient, dbName, collections.OneTimeTokens),
+		mfaPolicies:   repositories.NewMFAPolicyRepository(dbClient, dbName, collections.Settings),
+		accessTokens:  repositories.NewPersonalAccessTokenRepository(dbClient, dbName, collections.PersonalAccessTokens),
+		roles:         repositories.NewRoleRepository(dbClient, dbName, collections.Roles),
+		auditLog:      repositories.NewAuditLogRepository(dbClient, dbName, collections.AuditLog),
+		transactions:  repositories.NewUnitOfWork(dbClient),
+	}
+}
+
+// Keep rate limits in MongoDB when the rate limit store is "mongo", so they hold across several instances.
+// By default each instance keeps its own in memory.
+func newRateLimitStore(dbClient *mongo.Client, config *infrastructure.Config) domain.RateLimitStore {
+	if config.RateLimit.Store == "mongo" {
+		return repositories.NewRateLimitStore(dbClient, config.Database.Name, config.Database.Collections.RateLimits)
+	}
+
+	return infrastructure.NewMemoryRateLimitStore()
+}
+
+// Send mail over SMTP when a host is configured, otherwise print it to stdout for local development
+func newMailer(config infrastructure.MailConfig) domain.Mailer {
+	if config.Host == "" {
+		return infrastructure.NewLogMailer(os.Stdout, config.From)
 	}
-	return router
+
+	return infrastructure.NewSMTPMailer(config.Host, config.Port, config.Username, config.Password, config.From)
+}
+
+// Argon2id with OWASP's minimum parameters unless configured higher; existing hashes are upgraded
+// as users log in.
+func newPasswordService(config infrastructure.PasswordConfig) domain.PasswordService {
+	params := infrastructure.DefaultArgon2Params()
+	params.Memory = config.Argon2MemoryKiB
+	params.Iterations = config.Argon2Iterations
+	params.Parallelism = config.Argon2Parallelism
+
+	return infrastructure.NewArgon2PasswordService(params)
+}
+
+func newPasswordPolicy(config infrastructure.PasswordConfig) infrastructure.PasswordPolicy {
+	policy := infrastructure.DefaultPasswordPolicy()
+	policy.MinLength = config.MinLength
+	policy.MinCharacterClasses = config.MinCharacterClasses
+
+	return policy
+}
+
+// Check new passwords against breaches through a k-anonymity range API (e.g. https://api.pwnedpasswords.com),
+// or against a local file of SHA-1 hashes. Neither is used unless configured.
+func newBreachedPasswordChecker(config infrastructure.PasswordConfig) (domain.BreachedPasswordChecker, error) {
+	if config.BreachedAPIURL != "" {
+		return infrastructure.NewRangeBreachedPasswordChecker(config.BreachedAPIURL), nil
+	}
+
+	if config.BreachedFile == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(config.BreachedFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	checker, err := infrastructure.NewOfflineBreachedPasswordChecker(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breached password list: %w", err)
+	}
+
+	return checker, nil
+}
+
+// Single sign-on is enabled when an OpenID Connect issuer is configured
+func newOIDCProvider(config infrastructure.OIDCConfig) (domain.OIDCProvider, error) {
+	if config.IssuerURL == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	provider, err := infrastructure.NewOIDCProvider(ctx, config.IssuerURL, config.ClientID, config.ClientSecret, config.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up single sign-on: %w", err)
+	}
+
+	return provider, nil
+}
+
+// When verified email is required, users cannot create tasks until their email is verified
+func newTaskPolicy(config infrastructure.TasksConfig, authMiddleware *infrastructure.AuthMiddleware) []gin.HandlerFunc {
+	if config.RequireVerifiedEmail {
+		return []gin.HandlerFunc{authMiddleware.RequireVerifiedEmail()}
+	}
+
+	return nil
+}
+
+// Probes and scrapes arrive every few seconds and would drown out real requests, so they are not traced
+func notProbe(req *http.Request) bool {
+	switch req.URL.Path {
+	case "/healthz", "/readyz":
+		return false
+	}
+
+	return true
 }
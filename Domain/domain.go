@@ -2,31 +2,422 @@ package domain
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// Identifies a stored record. It is laid out like a MongoDB ObjectID, so records created before
+// keep their IDs, and is written as 24 hex characters.
+type ID [12]byte
+
+var (
+	idProcessUnique = newIDProcessUnique()
+	idCounter       atomic.Uint32
+)
+
+func newIDProcessUnique() [5]byte {
+	var b [5]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Generates a new ID: the current time in seconds, a value unique to this process and a counter
+func NewID() ID {
+	var id ID
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()))
+	copy(id[4:9], idProcessUnique[:])
+	counter := idCounter.Add(1)
+	id[9], id[10], id[11] = byte(counter>>16), byte(counter>>8), byte(counter)
+
+	return id
+}
+
+// Parses the 24 hex characters written by ID.String, failing with ErrInvalidID otherwise
+func ParseID(s string) (ID, error) {
+	var id ID
+	if len(s) != 2*len(id) {
+		return ID{}, ErrInvalidID
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return ID{}, ErrInvalidID
+	}
+
+	return id, nil
+}
+
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// When the ID was generated, to the second
+func (id ID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(id[0:4])), 0)
+}
+
+// Reports whether the ID was never set
+func (id ID) IsZero() bool {
+	return id == ID{}
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// An empty string leaves the zero ID
+func (id *ID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*id = ID{}
+		return nil
+	}
+
+	parsed, err := ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+
+	return nil
+}
+
 // Task structure in the database.
 type Task struct {
-	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Title       string             `json:"title" bson:"title"`
-	Description string             `json:"description" bson:"description"`
-	DueDate     time.Time          `json:"due_date,omitempty" bson:"due_date,omitempty"`
-	Status      string             `json:"status" bson:"status"`
-	CreatedBy   string             `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	ID          ID        `json:"id,omitempty" bson:"_id,omitempty"`
+	Title       string    `json:"title" bson:"title"`
+	Description string    `json:"description" bson:"description"`
+	DueDate     time.Time `json:"due_date,omitempty" bson:"due_date,omitempty"`
+	Status      string    `json:"status" bson:"status"`
+	CreatedBy   string    `json:"created_by,omitempty" bson:"created_by,omitempty"`
+}
+
+// Selects tasks. Fields left empty match every task.
+type TaskFilter struct {
+	IDs       []ID   `json:"ids,omitempty"`
+	Status    string `json:"status,omitempty"`
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+// Kinds of BulkTaskOperation
+const (
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+)
+
+// Most tasks a bulk request may change, counting every task its filter matches
+const MaxBulkTaskOperations = 100
+
+// One change in a bulk request. Creates store Task; updates set the fields of Task that are not
+// empty, like UpdateTask. Updates and deletes name their task by ID.
+type BulkTaskOperation struct {
+	Op   string `json:"op"`
+	ID   string `json:"id,omitempty"`
+	Task Task   `json:"task"`
+}
+
+// Either Operations, or a Filter with the Update to apply to every task it matches. With Atomic set,
+// nothing is changed unless every operation succeeds.
+type BulkTaskRequest struct {
+	Operations []BulkTaskOperation `json:"operations"`
+	Filter     *TaskFilter         `json:"filter"`
+	Update     Task                `json:"update"`
+	Atomic     bool                `json:"atomic"`
+}
+
+// Outcomes of a bulk operation
+const (
+	BulkStatusApplied = "applied"
+	BulkStatusFailed  = "failed"
+	BulkStatusSkipped = "skipped" // Not applied because another operation of an atomic request failed
+)
+
+// Outcome of one operation of a bulk request
+type BulkTaskResult struct {
+	Index  int    `json:"index"` // Position in the request's operations, or among the tasks its filter matched
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"` // Set for created tasks too
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 // User in the database
 type User struct {
-	ID           primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Username     string             `json:"username" bson:"username" binding:"required,min=3,max=50"`
-	PasswordHash string             `json:"-" bson:"password_hash"` // "-" is used to exclude from JSON marshalling for security.
-	Role         string             `json:"role" bson:"role"`       // e.g., "user, "admin
+	ID            ID     `json:"id,omitempty" bson:"_id,omitempty"`
+	Username      string `json:"username" bson:"username" binding:"required,min=3,max=50"`
+	PasswordHash  string `json:"-" bson:"password_hash"` // "-" is used to exclude from JSON marshalling for security.
+	Role          string `json:"role" bson:"role"`       // e.g., "user, "admin
+	DisplayName   string `json:"display_name,omitempty" bson:"display_name,omitempty"`
+	Timezone      string `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	TokenVersion  int    `json:"-" bson:"token_version"`                 // Bumped to revoke every token issued before it.
+	Email         string `json:"email,omitempty" bson:"email,omitempty"` // Stored lower-cased
+	EmailVerified bool   `json:"email_verified" bson:"email_verified"`
+	// Lockout state after repeated failed logins
+	FailedLoginAttempts int        `json:"-" bson:"failed_login_attempts,omitempty"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	// Two-factor authentication. The secret is set on enrolment and only used once TOTPEnabled is true.
+	TOTPSecret         string   `json:"-" bson:"totp_secret,omitempty"`
+	TOTPEnabled        bool     `json:"totp_enabled" bson:"totp_enabled"`
+	RecoveryCodeHashes []string `json:"-" bson:"recovery_code_hashes,omitempty"` // SHA-256 of each unused recovery code
+	TOTPLastStep       int64    `json:"-" bson:"totp_last_step,omitempty"`       // Time step of the last code accepted, so none is accepted twice
+	// Account at an OpenID Connect provider linked to this user, for single sign-on
+	OIDCIssuer  string `json:"-" bson:"oidc_issuer,omitempty"`
+	OIDCSubject string `json:"-" bson:"oidc_subject,omitempty"`
 	// Phone        string             `json:"phone,omitempty" bson:"phone,omitempty"`
-	// Email        string             `json:"email" bson:"email" binding:"required,email"`
+}
+
+// Single-use token sent to a user out of band, e.g. for a password reset.
+// Only the SHA-256 hash of the token is stored.
+type OneTimeToken struct {
+	ID        ID         `bson:"_id,omitempty"`
+	Username  string     `bson:"username"`
+	Purpose   string     `bson:"purpose"`
+	TokenHash string     `bson:"token_hash"`
+	CreatedAt time.Time  `bson:"created_at"`
+	ExpiresAt time.Time  `bson:"expires_at"`
+	UsedAt    *time.Time `bson:"used_at,omitempty"`
+	// Only set for TokenPurposeOIDCLogin, where the token is the OAuth state of a pending login
+	CodeVerifier string `bson:"code_verifier,omitempty"`
+	Nonce        string `bson:"nonce,omitempty"`
+}
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeMFALogin          = "mfa_login" // JWT purpose claim of the token between the two login steps, also tracked by its ID
+	TokenPurposeOIDCLogin         = "oidc_login"
+)
+
+// Long-lived token a user creates for scripts and CI instead of logging in with a password.
+// Only the SHA-256 hash is stored; Prefix is kept in clear so users can tell their tokens apart.
+type PersonalAccessToken struct {
+	ID         ID         `json:"id" bson:"_id,omitempty"`
+	Username   string     `json:"-" bson:"username"`
+	UserID     ID         `json:"-" bson:"user_id"` // Owner; unlike the username, never reused
+	Name       string     `json:"name" bson:"name"`
+	Prefix     string     `json:"prefix" bson:"prefix"`
+	TokenHash  string     `json:"-" bson:"token_hash"`
+	Scopes     []string   `json:"scopes" bson:"scopes"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at" bson:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" bson:"last_used_at,omitempty"`
+}
+
+// Every personal access token starts with this, which tells it apart from a JWT
+const PersonalAccessTokenPrefix = "tm_pat_"
+
+// Permissions are granted to users through their role. Personal access tokens carry
+// a subset of them as scopes.
+const (
+	PermissionTasksRead      = "tasks:read"
+	PermissionTasksWrite     = "tasks:write"
+	PermissionTasksDeleteAny = "tasks:delete:any" // Delete tasks created by other users
+	PermissionUsersAdmin     = "users:admin"
+)
+
+var AllPermissions = []string{PermissionTasksRead, PermissionTasksWrite, PermissionTasksDeleteAny, PermissionUsersAdmin}
+
+// A named set of permissions. Every user has exactly one role.
+type Role struct {
+	Name        string   `json:"name" bson:"_id"`
+	Permissions []string `json:"permissions" bson:"permissions"`
+}
+
+// Roles created on first start. Admins can change them, or add more, afterwards.
+func DefaultRoles() []Role {
+	return []Role{
+		{Name: RoleUser, Permissions: []string{PermissionTasksRead, PermissionTasksWrite}},
+		{Name: RoleAdmin, Permissions: append([]string{}, AllPermissions...)},
+	}
+}
+
+// The authenticated caller and the permissions in effect for this request
+type Principal struct {
+	Username    string
+	Permissions []string
+}
+
+func (principal Principal) Can(permission string) bool {
+	for _, granted := range principal.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Outcome of the password step of a login. When MFARequired is set, MFAToken must be
+// exchanged together with a TOTP or recovery code for the access token.
+type LoginResult struct {
+	Token       string
+	MFARequired bool
+	MFAToken    string
+}
+
+// User details asserted by a verified ID token from an OpenID Connect provider
+type OIDCIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// Returned when a user starts enrolling an authenticator app
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"` // Base64 encoded PNG of OTPAuthURI
+}
+
+// Roles whose members must have two-factor authentication enabled
+type MFAPolicy struct {
+	RequiredRoles []string `json:"required_roles" bson:"required_roles"`
+}
+
+func (policy *MFAPolicy) RequiresMFA(role string) bool {
+	for _, requiredRole := range policy.RequiredRoles {
+		if requiredRole == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Email to be delivered by a Mailer
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Security or data event in the audit log. Events are only ever appended, never changed.
+type AuditEvent struct {
+	ID         ID                `json:"id" bson:"_id,omitempty"`
+	Time       time.Time         `json:"time" bson:"time"`
+	Action     string            `json:"action" bson:"action"`
+	Outcome    string            `json:"outcome" bson:"outcome"`
+	Actor      string            `json:"actor,omitempty" bson:"actor,omitempty"` // Username of whoever caused the event
+	ClientIP   string            `json:"client_ip,omitempty" bson:"client_ip,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	RequestID  string            `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	TargetType string            `json:"target_type,omitempty" bson:"target_type,omitempty"`
+	TargetID   string            `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	Details    map[string]string `json:"details,omitempty" bson:"details,omitempty"`
+}
+
+const (
+	AuditActionLogin                = "user.login"
+	AuditActionRegister             = "user.register"
+	AuditActionPasswordChange       = "user.password_change"
+	AuditActionRoleChange           = "user.role_change"
+	AuditActionPasswordResetRequest = "user.password_reset_request"
+	AuditActionPasswordReset        = "user.password_reset"
+	AuditActionMFADisable           = "user.mfa_disable"
+	AuditActionAccountDelete        = "user.delete"
+	AuditActionRoleSave             = "role.save"
+	AuditActionTaskCreate           = "task.create"
+	AuditActionTaskUpdate           = "task.update"
+	AuditActionTaskDelete           = "task.delete"
+	AuditActionTokenRevoke          = "token.revoke"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+const (
+	AuditTargetUser                = "user"
+	AuditTargetTask                = "task"
+	AuditTargetPersonalAccessToken = "personal_access_token"
+	AuditTargetRole                = "role"
+)
+
+// Token bucket limiting requests: up to Burst at once, refilled at Rate requests per Period
+type RateLimit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+func (limit RateLimit) TokensPerSecond() float64 {
+	return float64(limit.Rate) / limit.Period.Seconds()
+}
+
+// Describes a bucket left holding tokens after a request was allowed or refused
+func (limit RateLimit) Decision(tokens float64, allowed bool) *RateLimitDecision {
+	perSecond := limit.TokensPerSecond()
+	decision := &RateLimitDecision{
+		Allowed:    allowed,
+		Remaining:  int(tokens),
+		ResetAfter: time.Duration((float64(limit.Burst) - tokens) / perSecond * float64(time.Second)),
+	}
+
+	if !allowed {
+		decision.RetryAfter = time.Duration((1 - tokens) / perSecond * float64(time.Second))
+	}
+
+	return decision
+}
+
+type RateLimitDecision struct {
+	Allowed    bool
+	Remaining  int           // Requests that can be made right away
+	ResetAfter time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the next request is allowed; only set when refused
+}
+
+// Outcomes of the password step of a login, as counted in metrics
+const (
+	LoginOutcomeSuccess            = "success"
+	LoginOutcomeMFARequired        = "mfa_required"
+	LoginOutcomeInvalidCredentials = "invalid_credentials"
+	LoginOutcomeThrottled          = "throttled"
+	LoginOutcomeError              = "error"
+)
+
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusError        = "error"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// Result of one readiness check
+type HealthCheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Overall readiness of the service, with the result of every registered check
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// A dependency the service needs in order to serve requests, e.g. pinging the database
+type HealthCheck func(ctx context.Context) error
+
+// Narrows down audit log queries. Empty fields match every event.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	Outcome    string
+	TargetType string
+	TargetID   string
+	From       time.Time // Inclusive
+	To         time.Time // Exclusive
+	Limit      int
 }
 
 const (
@@ -36,19 +427,281 @@ const (
 
 // Using only during registraton
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=50"`
-	Password string `json:"password" binding:"required,min=6"`
+	Username string `json:"username" binding:"required,min=3,max=50,excludes=@"` // "@" is reserved to tell emails apart at login
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required"`
 }
 
+// Username may also be the account's email address
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
+// Fields a user may change on their own profile. Nil fields are left untouched.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=100"`
+	Timezone    *string `json:"timezone" binding:"omitempty,timezone"`
+	Email       *string `json:"email" binding:"omitempty,email,max=254"` // Changing it requires verifying the new address
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Deleting an account needs the password and the username typed out again as confirmation.
+// Accounts without a password leave it out and sign in again shortly before instead.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	Confirm  string `json:"confirm" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Carries a TOTP code from an authenticator app, or a recovery code where accepted
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Second step of a login for users with two-factor authentication
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write tasks:delete:any users:admin"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"` // Defaults to 30 days
+}
+
+type MFAPolicyRequest struct {
+	RequiredRoles []string `json:"required_roles" binding:"dive,required"`
+}
+
+type SaveRoleRequest struct {
+	Permissions []string `json:"permissions" binding:"dive,oneof=tasks:read tasks:write tasks:delete:any users:admin"`
+}
+
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// Query string of GET /admin/audit. Times are RFC 3339.
+type AuditQuery struct {
+	Actor      string    `form:"actor"`
+	Action     string    `form:"action"`
+	Outcome    string    `form:"outcome" binding:"omitempty,oneof=success failure"`
+	TargetType string    `form:"target_type"`
+	TargetID   string    `form:"target_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int       `form:"limit" binding:"omitempty,min=1,max=10000"`
+	Format     string    `form:"format" binding:"omitempty,oneof=json csv ndjson"`
+}
+
+// Details about the client making a request, carried in the request context
+type RequestMeta struct {
+	ClientIP  string
+	UserAgent string
+	RequestID string
+	Actor     string // Username of the authenticated user, if any
+}
+
+type requestMetaKey struct{}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// Returns the zero RequestMeta when none was set, e.g. outside an HTTP request
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+type loggerKey struct{}
+
+// Carries a logger with the request's details attached, so everything handling the request logs through it
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Returns the default logger when none was set, e.g. outside an HTTP request
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// ------------------------- Errors -------------------------
+
+var (
+	ErrInvalidID            = errors.New("invalid ID")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidCredentials   = errors.New("invalid username or password")
+	ErrIncorrectPassword    = errors.New("current password is incorrect")
+	ErrNoFieldsToUpdate     = errors.New("no field provided")
+	ErrSessionRevoked       = errors.New("session has been revoked")
+	ErrReauthRequired       = errors.New("sign in again to confirm this action")
+	ErrInvalidToken         = errors.New("invalid or expired token")
+	ErrUserAlreadyExists    = errors.New("user already exists")
+	ErrEmailTaken           = errors.New("email is already registered")
+	ErrEmailNotVerified     = errors.New("email address has not been verified")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	ErrNoEmailAddress       = errors.New("account has no email address")
+	ErrTooManyAttempts      = errors.New("too many failed login attempts, try again later")
+	ErrInvalidMFACode       = errors.New("invalid authentication code")
+	ErrMFAAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled        = errors.New("two-factor authentication is not enabled")
+	ErrMFANotEnrolling      = errors.New("no two-factor enrolment in progress")
+	ErrMFARequired          = errors.New("two-factor authentication is required for your role")
+	ErrTokenNotFound        = errors.New("token not found")
+	ErrScopeNotAllowed      = errors.New("scope is not allowed for your role")
+	ErrNoScopes             = errors.New("token needs at least one scope")
+	ErrRoleNotFound         = errors.New("role not found")
+	ErrForbidden            = errors.New("insufficient permissions")
+	ErrSSOFailed            = errors.New("single sign-on failed")
+	ErrWeakPassword         = errors.New("password does not meet the password policy")
+	ErrRateLimited          = errors.New("too many requests, slow down")
+	ErrConflict             = errors.New("conflicts with an existing record") // A unique key is already in use
+	ErrInvalidBulkRequest   = errors.New("bulk request needs either operations, or a filter and an update")
+	ErrTooManyBulkTasks     = errors.New("bulk request changes too many tasks")
+	ErrNoTransactions       = errors.New("the database does not support transactions") // e.g. a standalone MongoDB server
+)
+
+// One rule of the password policy that a password breaks
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Rules reported in PasswordViolation
+const (
+	PasswordRuleMinLength        = "min_length"
+	PasswordRuleMaxLength        = "max_length"
+	PasswordRuleCharacterClasses = "character_classes"
+	PasswordRuleContainsUsername = "contains_username"
+	PasswordRuleCommonPassword   = "common_password"
+	PasswordRuleBreachedPassword = "breached_password"
+)
+
+// Returned when a new password is rejected, with every rule it breaks. Matches ErrWeakPassword with errors.Is.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error()
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// Returned when logins are temporarily blocked. Matches ErrTooManyAttempts with errors.Is.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *TooManyAttemptsError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
 // ------------------------- Repository -------------------------
+
+// Runs repository calls in one transaction, so that they succeed or fail together
+type UnitOfWork interface {
+	// Runs fn in a transaction, committed if fn returns nil and rolled back if it returns an error.
+	// Repository calls take part when made with the context fn is given. fn may run again when the
+	// database asks for the transaction to be retried. Called inside a transaction, fn joins it.
+	// Fails with ErrNoTransactions when the database cannot run transactions.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepository interface {
-	CreateUser(ctx context.Context, user *User) (*mongo.InsertOneResult, error)
+	// Sets user.ID. Usernames are unique regardless of case, checked atomically with the insert: a taken
+	// username fails with ErrConflict and ErrUserAlreadyExists, a taken email with ErrConflict and ErrEmailTaken.
+	CreateUser(ctx context.Context, user *User) error
+	FindUserByID(ctx context.Context, id ID) (*User, error)
 	FindUserByUsername(ctx context.Context, username string) (*User, error)
+	FindUserByEmail(ctx context.Context, email string) (*User, error)
+	FindUserByOIDCSubject(ctx context.Context, issuer, subject string) (*User, error)
+	// Saves every field but TOTPLastStep, which only UseTOTPStep changes
+	UpdateUser(ctx context.Context, user *User) error
+	DeleteUser(ctx context.Context, username string) error
+	// Atomically records step as the user's last TOTP time step if it is later than the stored one,
+	// or fails with ErrInvalidMFACode, so each code is accepted once.
+	UseTOTPStep(ctx context.Context, id ID, step int64) error
+	// Atomically removes the recovery code from the user, or fails with ErrInvalidMFACode if it is not there.
+	UseRecoveryCode(ctx context.Context, id ID, codeHash string) error
+}
+
+type OneTimeTokenRepository interface {
+	CreateToken(ctx context.Context, token *OneTimeToken) error
+	// Atomically marks an unused, unexpired token as used and returns it, or ErrInvalidToken.
+	ConsumeToken(ctx context.Context, purpose, tokenHash string) (*OneTimeToken, error)
+	DeleteTokensForUser(ctx context.Context, username, purpose string) error
+	// Returns an unused, unexpired token without using it up, or ErrInvalidToken.
+	FindToken(ctx context.Context, purpose, tokenHash string) (*OneTimeToken, error)
+}
+
+type PersonalAccessTokenRepository interface {
+	// Sets token.ID. Token hashes are unique; a duplicate fails with ErrConflict.
+	CreateToken(ctx context.Context, token *PersonalAccessToken) error
+	FindTokenByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)
+	ListTokensForUser(ctx context.Context, username string) ([]PersonalAccessToken, error)
+	// Only deletes the token if it belongs to username; ErrTokenNotFound otherwise
+	DeleteToken(ctx context.Context, username, id string) error
+	DeleteTokensForUser(ctx context.Context, username string) error
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+}
+
+type RoleRepository interface {
+	GetRole(ctx context.Context, name string) (*Role, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	// Creates the role or replaces its permissions
+	SaveRole(ctx context.Context, role *Role) error
+}
+
+type MFAPolicyRepository interface {
+	// Returns an empty policy when none has been saved yet
+	GetMFAPolicy(ctx context.Context) (*MFAPolicy, error)
+	SaveMFAPolicy(ctx context.Context, policy *MFAPolicy) error
+}
+
+// Only appends and reads, so recorded events cannot be altered through it
+type AuditLogRepository interface {
+	AppendEvent(ctx context.Context, event *AuditEvent) error
+	FindEvents(ctx context.Context, filter AuditFilter) ([]AuditEvent, error)
+}
+
+// Keeps rate limit buckets. A shared store applies the limits across every instance of the service.
+type RateLimitStore interface {
+	// Take a token from the bucket at key, creating it full if it does not exist
+	Take(ctx context.Context, key string, limit RateLimit) (*RateLimitDecision, error)
 }
 
 type TaskRepository interface {
@@ -56,40 +709,189 @@ type TaskRepository interface {
 	GetTaskByID(ctx context.Context, id string) (Task, error)
 	UpdateTask(ctx context.Context, id string, updatedTask Task) error
 	DeleteTask(ctx context.Context, id string) error
-	NewTask(ctx context.Context, task Task) (*mongo.InsertOneResult, error)
+	// Deletes every task the user created; none is not an error
+	DeleteTasksByCreator(ctx context.Context, username string) error
+	// Returns the task as stored, with its ID. Fails with ErrConflict if a task already has the given ID.
+	NewTask(ctx context.Context, task Task) (Task, error)
+	// Number of tasks with each status
+	CountTasksByStatus(ctx context.Context) (map[string]int64, error)
+	// At most limit tasks matching the filter
+	FindTasks(ctx context.Context, filter TaskFilter, limit int) ([]Task, error)
+	// Applies the operations and reports the outcome of each, in the same order, with the ID of
+	// created tasks. Unless atomic is set, operations may be applied in any order and one failing does
+	// not stop the others; with it, they are applied in order and the first failure undoes them all.
+	BulkWriteTasks(ctx context.Context, operations []BulkTaskOperation, atomic bool) ([]BulkTaskResult, error)
 }
 
 // ------------------------- Infrastructure -------------------------
 
 // JWTService Interface
 type JWTService interface {
-	GenerateToken(username, role string) (string, error)
+	GenerateToken(user *User) (string, error)
 	ValidateToken(token string) (*CustomClaims, error)
+	// Short-lived token proving the password step of a login; it is not an access token.
+	// tokenID becomes its ID claim, so the token can be tracked as a one-time token.
+	GenerateMFAToken(user *User, tokenID string, expiresAt time.Time) (string, error)
+	ValidateMFAToken(token string) (*CustomClaims, error)
 }
 
 type CustomClaims struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"ver"`
+	Purpose      string `json:"purpose,omitempty"` // Empty for access tokens
 	jwt.RegisteredClaims
 }
 
+// The ID of the user the token was issued to, kept in the subject claim
+func (claims *CustomClaims) UserID() (ID, error) {
+	return ParseID(claims.Subject)
+}
+
 // Password Service Interface
 type PasswordService interface {
 	HashPassword(password string) (string, error)
 	ComparePasswords(hashedPassword, plaintextPassword string) error
+	// Reports whether a hash uses an older algorithm or weaker parameters than new hashes do
+	NeedsRehash(hashedPassword string) bool
+}
+
+// Checks a new password against the password policy. Failures are a *PasswordPolicyError.
+type PasswordValidator interface {
+	Validate(ctx context.Context, password, username string) error
+}
+
+// Reports whether a password appears in a known data breach
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// Tracks failed logins per username and per client IP and decides when to lock them out
+type LoginThrottle interface {
+	// How long the caller must wait before trying again; zero when allowed
+	RetryAfter(ctx context.Context, username, clientIP string) time.Duration
+	// Records a failure and returns the username's failure count and, once locked out, until when
+	RecordFailure(ctx context.Context, username, clientIP string) (int, time.Time)
+	RecordSuccess(ctx context.Context, username, clientIP string)
+	// Clears the username's failures and lockout
+	Reset(ctx context.Context, username string)
+}
+
+// Generates and checks time-based one-time passwords (RFC 6238)
+type TOTPService interface {
+	GenerateSecret(accountName string) (*TOTPEnrollment, error)
+	// Reports whether the code is valid now, and the time step it belongs to
+	ValidateCode(secret, code string) (int64, bool)
+}
+
+// Authorization code flow with PKCE against an OpenID Connect provider
+type OIDCProvider interface {
+	// URL of the provider's login page. The PKCE challenge is derived from codeVerifier.
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	// Redeems an authorization code and verifies the ID token it returns. Fails with ErrSSOFailed.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCIdentity, error)
+}
+
+// Records audit events. Client details and the actor are taken from the request context
+// when the event does not set them. Failures are logged rather than returned, so a broken
+// audit log does not fail the action being audited.
+type AuditLogger interface {
+	Record(ctx context.Context, event AuditEvent)
+}
+
+// Mailer delivers emails (SMTP in production, a log/file for local development)
+type Mailer interface {
+	Send(ctx context.Context, message MailMessage) error
+}
+
+// Counts authentication attempts for monitoring
+type AuthMetrics interface {
+	ObserveLogin(method, outcome string)
+}
+
+// Runs the readiness checks registered by each subsystem. Once shutdown has started the
+// service reports itself not ready, so the orchestrator stops sending it traffic.
+type HealthChecker interface {
+	Register(name string, check HealthCheck)
+	Readiness(ctx context.Context) HealthReport
+	SetShuttingDown()
 }
 
 // ------------------------- Usecase -------------------------
 
 type UserUsecase interface {
-	Register(ctx context.Context, username, password string) (*mongo.InsertOneResult, error)
-	Login(ctx context.Context, username, password string) (string, error)
+	Register(ctx context.Context, username, email, password string) (*User, error)
+	// identifier is either the username or the email address
+	Login(ctx context.Context, identifier, password string) (*LoginResult, error)
+	GetProfile(ctx context.Context, username string) (*User, error)
+	UpdateProfile(ctx context.Context, username string, update UpdateProfileRequest) (*User, error)
+	ChangePassword(ctx context.Context, username, currentPassword, newPassword string) (string, error)
+	// Accounts without a password, e.g. created through single sign-on, must instead have signed in
+	// recently; signedInAt is when the session's token was issued.
+	DeleteAccount(ctx context.Context, username, password string, signedInAt time.Time) error
+	RequestEmailVerification(ctx context.Context, username string) error
+	VerifyEmail(ctx context.Context, token string) error
+	// Admin only: lift a login lockout
+	UnlockUser(ctx context.Context, username string) error
+}
+
+type PasswordResetUsecase interface {
+	// Returns straight away and sends the email in the background, never revealing whether the username exists
+	RequestReset(ctx context.Context, username string)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	// Waits until the emails being sent in the background are sent, or ctx is done. Call it once no
+	// more requests are taken.
+	Close(ctx context.Context) error
+}
+
+type PersonalAccessTokenUsecase interface {
+	// Returns the stored token and the token itself, which is never shown again
+	CreateToken(ctx context.Context, username string, request CreatePersonalAccessTokenRequest) (*PersonalAccessToken, string, error)
+	ListTokens(ctx context.Context, username string) ([]PersonalAccessToken, error)
+	RevokeToken(ctx context.Context, username, id string) error
+	// Resolves a token presented by a client to its owner, recording when it was last used
+	Authenticate(ctx context.Context, token string) (*User, *PersonalAccessToken, error)
+}
+
+type RoleUsecase interface {
+	ListRoles(ctx context.Context) ([]Role, error)
+	SaveRole(ctx context.Context, role *Role) error
+	AssignRole(ctx context.Context, username, role string) error
+}
+
+type OIDCUsecase interface {
+	// Returns the provider URL to send the browser to
+	BeginLogin(ctx context.Context) (string, error)
+	CompleteLogin(ctx context.Context, state, code string) (*LoginResult, error)
+}
+
+type MFAUsecase interface {
+	BeginEnrollment(ctx context.Context, username string) (*TOTPEnrollment, error)
+	// Enables two-factor authentication and returns the recovery codes, shown only this once
+	ConfirmEnrollment(ctx context.Context, username, code string) ([]string, error)
+	Disable(ctx context.Context, username, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, username, code string) ([]string, error)
+	// Exchanges the token from the password step and a TOTP or recovery code for an access token
+	CompleteLogin(ctx context.Context, mfaToken, code string) (string, error)
+	GetPolicy(ctx context.Context) (*MFAPolicy, error)
+	SetPolicy(ctx context.Context, policy *MFAPolicy) error
 }
 
 type TaskUsecase interface {
 	GetAllTask(ctx context.Context) ([]Task, error)
 	GetTaskByID(ctx context.Context, id string) (Task, error)
-	UpdateTask(ctx context.Context, id string, updatedTask Task) error
-	DeleteTask(ctx context.Context, id string) error
-	NewTask(ctx context.Context, task Task) (*mongo.InsertOneResult, error)
+	// Users may update their own tasks; other users' tasks need PermissionTasksDeleteAny
+	UpdateTask(ctx context.Context, id string, updatedTask Task, principal Principal) error
+	// Users may delete their own tasks; other users' tasks need PermissionTasksDeleteAny
+	DeleteTask(ctx context.Context, id string, principal Principal) error
+	NewTask(ctx context.Context, task Task) (Task, error)
+	// Applies a bulk request on behalf of principal, checking each operation like DeleteTask does.
+	// Updates of other users' tasks also need PermissionTasksDeleteAny. Fails only when the request
+	// itself is invalid; the outcome of each operation is in the results.
+	BulkTasks(ctx context.Context, request BulkTaskRequest, principal Principal) ([]BulkTaskResult, error)
+}
+
+type AuditUsecase interface {
+	// Newest events first
+	ListEvents(ctx context.Context, filter AuditFilter) ([]AuditEvent, error)
 }
@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"encoding/json"
+	domain "task_manager/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestID_ParseFormatsRoundTrip(t *testing.T) {
+	id := domain.NewID()
+
+	parsed, err := domain.ParseID(id.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+	assert.Len(t, id.String(), 24)
+	assert.False(t, id.IsZero())
+}
+
+func TestID_Time(t *testing.T) {
+	assert.WithinDuration(t, time.Now(), domain.NewID().Time(), time.Second)
+
+	id, err := domain.ParseID("6ad520a5337eb89a3b000001")
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(0x6ad520a5, 0), id.Time())
+}
+
+func TestID_NewIDsDiffer(t *testing.T) {
+	assert.NotEqual(t, domain.NewID(), domain.NewID())
+}
+
+func TestParseID_Invalid(t *testing.T) {
+	for _, input := range []string{"", "123", "zzzzzzzzzzzzzzzzzzzzzzzz", "6ad520a5337eb89a3b0000011"} {
+		_, err := domain.ParseID(input)
+		assert.ErrorIs(t, err, domain.ErrInvalidID, input)
+	}
+}
+
+func TestID_JSON(t *testing.T) {
+	task := domain.Task{ID: domain.NewID(), Title: "Task"}
+
+	encoded, err := json.Marshal(task)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"id":"`+task.ID.String()+`"`)
+
+	var decoded domain.Task
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, task.ID, decoded.ID)
+
+	// Clients may send an empty ID when creating a record
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"","title":"Task"}`), &decoded))
+	assert.True(t, decoded.ID.IsZero())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"not-an-id"}`), &decoded))
+}
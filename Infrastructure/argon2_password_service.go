@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	domain "task_manager/Domain"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	ErrPasswordMismatch = errors.New("password does not match")
+	ErrUnknownHash      = errors.New("unrecognised password hash format")
+)
+
+// Cost parameters of Argon2id. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// OWASP's recommended minimum for Argon2id: 19 MiB, two passes, one lane
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+type argon2PasswordService struct {
+	params Argon2Params
+	bcrypt *passwordService // Verifies hashes created before Argon2id was introduced
+}
+
+// New hashes use Argon2id with params. bcrypt hashes are still accepted, and reported by NeedsRehash.
+func NewArgon2PasswordService(params Argon2Params) domain.PasswordService {
+	return &argon2PasswordService{params: params, bcrypt: &passwordService{}}
+}
+
+// Hash the password into a PHC string: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func (service *argon2PasswordService) HashPassword(password string) (string, error) {
+	salt := make([]byte, service.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.New("failed to hash password")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, service.params.Iterations, service.params.Memory, service.params.Parallelism, service.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		service.params.Memory, service.params.Iterations, service.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare against an Argon2id hash using the parameters stored in it, or against a bcrypt hash
+func (service *argon2PasswordService) ComparePasswords(hashedPassword, plaintextPassword string) error {
+	if isBcryptHash(hashedPassword) {
+		return service.bcrypt.ComparePasswords(hashedPassword, plaintextPassword)
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(plaintextPassword), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+// A hash needs replacing when it is not Argon2id or was made with different parameters
+func (service *argon2PasswordService) NeedsRehash(hashedPassword string) bool {
+	params, _, _, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return params != service.params
+}
+
+func isBcryptHash(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") || strings.HasPrefix(hashedPassword, "$2b$") || strings.HasPrefix(hashedPassword, "$2y$")
+}
+
+func decodeArgon2Hash(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,90 @@
+package infrastructure_test
+
+import (
+	"strings"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters so the tests run quickly
+func testArgon2Params() infrastructure.Argon2Params {
+	return infrastructure.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestArgon2PasswordService(t *testing.T) {
+	passwordService := infrastructure.NewArgon2PasswordService(testArgon2Params())
+
+	t.Run("HashPassword_PHCFormat", func(t *testing.T) {
+		hashedPassword, err := passwordService.HashPassword("mySecurePassword123")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$m=1024,t=1,p=1$"), "got: %s", hashedPassword)
+		assert.Len(t, strings.Split(hashedPassword, "$"), 6)
+
+		other, err := passwordService.HashPassword("mySecurePassword123")
+		require.NoError(t, err)
+		assert.NotEqual(t, hashedPassword, other, "Every hash should have its own salt")
+	})
+
+	t.Run("ComparePasswords_MatchAndMismatch", func(t *testing.T) {
+		hashedPassword, err := passwordService.HashPassword("mySecurePassword123")
+		require.NoError(t, err)
+
+		assert.NoError(t, passwordService.ComparePasswords(hashedPassword, "mySecurePassword123"))
+		assert.ErrorIs(t, passwordService.ComparePasswords(hashedPassword, "ThisIsTheWrongPassword"), infrastructure.ErrPasswordMismatch)
+	})
+
+	t.Run("LongPasswords_AreNotTruncated", func(t *testing.T) {
+		// bcrypt only looks at the first 72 bytes
+		longPassword := strings.Repeat("a", 100)
+		hashedPassword, err := passwordService.HashPassword(longPassword)
+		require.NoError(t, err)
+
+		assert.NoError(t, passwordService.ComparePasswords(hashedPassword, longPassword))
+		assert.ErrorIs(t, passwordService.ComparePasswords(hashedPassword, strings.Repeat("a", 72)), infrastructure.ErrPasswordMismatch)
+	})
+
+	t.Run("ComparePasswords_UsesParametersInHash", func(t *testing.T) {
+		stronger := testArgon2Params()
+		stronger.Iterations = 2
+		hashedPassword, err := infrastructure.NewArgon2PasswordService(stronger).HashPassword("mySecurePassword123")
+		require.NoError(t, err)
+
+		assert.NoError(t, passwordService.ComparePasswords(hashedPassword, "mySecurePassword123"), "Hashes made with other parameters must still verify")
+		assert.True(t, passwordService.NeedsRehash(hashedPassword), "Hashes made with other parameters should be replaced")
+	})
+
+	t.Run("ComparePasswords_AcceptsBcrypt", func(t *testing.T) {
+		legacyHash, err := bcrypt.GenerateFromPassword([]byte("mySecurePassword123"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		assert.NoError(t, passwordService.ComparePasswords(string(legacyHash), "mySecurePassword123"))
+		assert.Error(t, passwordService.ComparePasswords(string(legacyHash), "ThisIsTheWrongPassword"))
+		assert.True(t, passwordService.NeedsRehash(string(legacyHash)), "bcrypt hashes should be upgraded")
+	})
+
+	t.Run("NeedsRehash_CurrentHash", func(t *testing.T) {
+		hashedPassword, err := passwordService.HashPassword("mySecurePassword123")
+		require.NoError(t, err)
+
+		assert.False(t, passwordService.NeedsRehash(hashedPassword))
+	})
+
+	t.Run("ComparePasswords_MalformedHash", func(t *testing.T) {
+		for _, malformed := range []string{
+			"",
+			"thisisnotavalidhash",
+			"$argon2i$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			"$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			"$argon2id$v=19$m=0,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			"$argon2id$v=19$m=1024,t=1,p=1$not-base64!$a2V5",
+		} {
+			assert.ErrorIs(t, passwordService.ComparePasswords(malformed, "mySecurePassword123"), infrastructure.ErrUnknownHash, "hash: %q", malformed)
+			assert.True(t, passwordService.NeedsRehash(malformed), "hash: %q", malformed)
+		}
+	})
+}
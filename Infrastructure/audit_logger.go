@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Time allowed for writing one audit event
+const auditWriteTimeout = 5 * time.Second
+
+type auditLogger struct {
+	repo domain.AuditLogRepository
+}
+
+func NewAuditLogger(repo domain.AuditLogRepository) domain.AuditLogger {
+	return &auditLogger{repo: repo}
+}
+
+// Fill in the time and request details, then append the event
+func (logger *auditLogger) Record(ctx context.Context, event domain.AuditEvent) {
+	meta := domain.RequestMetaFromContext(ctx)
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.Actor == "" {
+		event.Actor = meta.Actor
+	}
+	if event.ClientIP == "" {
+		event.ClientIP = meta.ClientIP
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = meta.UserAgent
+	}
+	if event.RequestID == "" {
+		event.RequestID = meta.RequestID
+	}
+
+	// The event is still written when the client has gone away or the request timed out
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+
+	if err := logger.repo.AppendEvent(writeCtx, &event); err != nil {
+		domain.LoggerFromContext(ctx).Error("failed to record audit event",
+			slog.String("action", event.Action), slog.String("outcome", event.Outcome), slog.String("actor", event.Actor), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,69 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditLogger(t *testing.T) {
+	meta := domain.RequestMeta{ClientIP: "203.0.113.7", UserAgent: "curl/8.5.0", RequestID: "req-1", Actor: "alice"}
+
+	t.Run("FillsRequestDetails", func(t *testing.T) {
+		repo := mocks.NewMockAuditLogRepository(t)
+		var stored *domain.AuditEvent
+		repo.EXPECT().
+			AppendEvent(mock.Anything, mock.AnythingOfType("*domain.AuditEvent")).
+			Run(func(_ context.Context, event *domain.AuditEvent) { stored = event }).
+			Return(nil).
+			Once()
+
+		ctx := domain.WithRequestMeta(context.Background(), meta)
+		infrastructure.NewAuditLogger(repo).Record(ctx, domain.AuditEvent{Action: domain.AuditActionTaskDelete, Outcome: domain.AuditOutcomeSuccess})
+
+		assert.Equal(t, "alice", stored.Actor)
+		assert.Equal(t, "203.0.113.7", stored.ClientIP)
+		assert.Equal(t, "curl/8.5.0", stored.UserAgent)
+		assert.Equal(t, "req-1", stored.RequestID)
+		assert.WithinDuration(t, time.Now(), stored.Time, time.Minute)
+	})
+
+	t.Run("KeepsExplicitActor", func(t *testing.T) {
+		repo := mocks.NewMockAuditLogRepository(t)
+		repo.EXPECT().
+			AppendEvent(mock.Anything, mock.MatchedBy(func(event *domain.AuditEvent) bool { return event.Actor == "bob" })).
+			Return(nil).
+			Once()
+
+		ctx := domain.WithRequestMeta(context.Background(), meta)
+		infrastructure.NewAuditLogger(repo).Record(ctx, domain.AuditEvent{Action: domain.AuditActionLogin, Actor: "bob"})
+	})
+
+	t.Run("WritesAfterRequestIsCancelled", func(t *testing.T) {
+		repo := mocks.NewMockAuditLogRepository(t)
+		repo.EXPECT().
+			AppendEvent(mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), mock.Anything).
+			Return(nil).
+			Once()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		infrastructure.NewAuditLogger(repo).Record(ctx, domain.AuditEvent{Action: domain.AuditActionLogin})
+	})
+
+	t.Run("StoreErrorIsNotFatal", func(t *testing.T) {
+		repo := mocks.NewMockAuditLogRepository(t)
+		repo.EXPECT().AppendEvent(mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+
+		assert.NotPanics(t, func() {
+			infrastructure.NewAuditLogger(repo).Record(context.Background(), domain.AuditEvent{Action: domain.AuditActionLogin})
+		})
+	})
+}
@@ -1,7 +1,9 @@
 package infrastructure
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 	domain "task_manager/Domain"
@@ -10,14 +12,17 @@ import (
 )
 
 type AuthMiddleware struct {
-	jwtService domain.JWTService
+	jwtService      domain.JWTService
+	userRepo        domain.UserRepository
+	roleRepo        domain.RoleRepository
+	accessTokenAuth domain.PersonalAccessTokenUsecase
 }
 
-func NewAuthMiddleware(jwtService domain.JWTService) *AuthMiddleware {
-	return &AuthMiddleware{jwtService: jwtService}
+func NewAuthMiddleware(jwtService domain.JWTService, userRepo domain.UserRepository, roleRepo domain.RoleRepository, accessTokenAuth domain.PersonalAccessTokenUsecase) *AuthMiddleware {
+	return &AuthMiddleware{jwtService: jwtService, userRepo: userRepo, roleRepo: roleRepo, accessTokenAuth: accessTokenAuth}
 }
 
-// Validates the JWT token
+// Validates the JWT token, or a personal access token
 func (middleware *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get to fron the authrorization header
@@ -36,6 +41,11 @@ func (middleware *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 
 		tokenString := parts[1] // Extract the token string
 
+		if strings.HasPrefix(tokenString, domain.PersonalAccessTokenPrefix) {
+			middleware.authenticateAccessToken(c, tokenString)
+			return
+		}
+
 		// Validate the token
 		claims, err := middleware.jwtService.ValidateToken(tokenString)
 		if err != nil {
@@ -44,50 +54,149 @@ func (middleware *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// The token is valid. Store the user's claims in the context for later use in the handlers
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
+		// Reject tokens for deleted accounts or issued before the user's sessions were revoked. The user
+		// is found by ID, so a token outlives its account even when the username is registered again.
+		userID, err := claims.UserID()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Invalid or expired token: %v", err.Error())})
+			return
+		}
+
+		user, err := middleware.userRepo.FindUserByID(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token: account no longer exists"})
+			return
+		}
+
+		if user.TokenVersion != claims.TokenVersion {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Invalid or expired token: %v", domain.ErrSessionRevoked)})
+			return
+		}
+
+		// The token is valid. Store the user in the context for later use in the handlers
+		if claims.IssuedAt != nil {
+			c.Set("signed_in_at", claims.IssuedAt.Time) // Accounts without a password re-authenticate by signing in again
+		}
+		middleware.setUser(c, user, false, nil)
+	}
+}
+
+// Authenticates a request made with a personal access token
+func (middleware *AuthMiddleware) authenticateAccessToken(c *gin.Context, tokenString string) {
+	user, accessToken, err := middleware.accessTokenAuth.Authenticate(c.Request.Context(), tokenString)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Invalid or expired token: %v", err.Error())})
+		return
+	}
+
+	c.Set("token_scopes", accessToken.Scopes)
+	middleware.setUser(c, user, true, accessToken.Scopes)
+}
+
+// Stores the authenticated user and their permissions in the context. The role is read from the
+// user rather than the token, so role changes apply immediately. A personal access token only
+// keeps the permissions that are also among its scopes; one without scopes has no permissions.
+func (middleware *AuthMiddleware) setUser(c *gin.Context, user *domain.User, accessToken bool, scopes []string) {
+	permissions := []string{}
+
+	role, err := middleware.roleRepo.GetRole(c.Request.Context(), user.Role)
+	if err != nil && !errors.Is(err, domain.ErrRoleNotFound) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load role"})
+		return
+	}
+
+	if role != nil {
+		permissions = role.Permissions
+	}
+
+	if accessToken {
+		tokenScopes := domain.Principal{Permissions: scopes}
+		scoped := []string{}
+		for _, permission := range permissions {
+			if tokenScopes.Can(permission) {
+				scoped = append(scoped, permission)
+			}
+		}
+		permissions = scoped
+	}
+
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	c.Set("permissions", permissions)
+	c.Set("email_verified", user.EmailVerified)
+	c.Set("mfa_enabled", user.TOTPEnabled)
+
+	// Usecases attribute audit events and log lines to the authenticated user
+	ctx := c.Request.Context()
+	meta := domain.RequestMetaFromContext(ctx)
+	meta.Actor = user.Username
+	ctx = domain.WithRequestMeta(ctx, meta)
+	ctx = domain.WithLogger(ctx, domain.LoggerFromContext(ctx).With(slog.String("user", user.Username)))
+	c.Request = c.Request.WithContext(ctx)
+
+	// Proceed to the next handler/middleware
+	c.Next()
+}
+
+// Requires the authenticated user to have the given permission.
+// It assumes AuthRequired middleware has already run.
+func (middleware *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !GetPrincipalFromContext(c).Can(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Insufficient permissions: %s is required", permission)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Rejects personal access tokens, for routes that manage the account itself (password, 2FA, tokens).
+// It assumes AuthRequired middleware has already run.
+func (middleware *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAccessToken := c.Get("token_scopes"); isAccessToken {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Personal access tokens cannot be used for this route"})
+			return
+		}
 
-		// Proceed to the next handler/middleware
 		c.Next()
 	}
 }
 
-// This checks if the authenicated user has one of the required roles
-// It assumes AuthRequired middleware has already run and set the "role" in the context
-func (middleware *AuthMiddleware) AuthorizeRole(requireRoles ...string) gin.HandlerFunc {
+// Blocks users whose email address has not been verified yet.
+// It assumes AuthRequired middleware has already run.
+func (middleware *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get the role from the context (set by AuthRequired middleware)
-		role, exists := c.Get("role")
-		if !exists {
-			// This indicates a middleware chain setup error (AuthorizeRole called before AuthRequired)
-			// Or AuthRequired failed but didn;t abort
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Role not found in context. Authentication middleware missing?"})
+		if !c.GetBool("email_verified") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": domain.ErrEmailNotVerified.Error()})
 			return
 		}
 
-		userRole, ok := role.(string)
-		if !ok {
-			// Context value is not a string? This indicaates an issue with setting the context
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Invalid role format in context"})
+		c.Next()
+	}
+}
+
+// Blocks users whose role must use two-factor authentication until they have enabled it.
+// It assumes AuthRequired middleware has already run.
+func (middleware *AuthMiddleware) RequireMFA(policyRepo domain.MFAPolicyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("mfa_enabled") {
+			c.Next()
 			return
 		}
 
-		// Check if user's role is in the list of required roles
-		isAuthorized := false
-		for _, requiredRole := range requireRoles {
-			if userRole == requiredRole {
-				isAuthorized = true
-				break
-			}
+		policy, err := policyRepo.GetMFAPolicy(c.Request.Context())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load two-factor policy"})
+			return
 		}
 
-		if !isAuthorized {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		if policy.RequiresMFA(c.GetString("role")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": domain.ErrMFARequired.Error()})
 			return
 		}
 
-		// User has the required role. Proceed.
 		c.Next()
 	}
 }
@@ -116,3 +225,11 @@ func GetUserFromContext(c *gin.Context) (string, string, error) {
 
 	return userStr, roleStr, nil
 }
+
+// Returns the authenticated user and their permissions, as set by AuthRequired
+func GetPrincipalFromContext(c *gin.Context) domain.Principal {
+	permissions, _ := c.Get("permissions")
+	granted, _ := permissions.([]string)
+
+	return domain.Principal{Username: c.GetString("username"), Permissions: granted}
+}
@@ -0,0 +1,191 @@
+package infrastructure_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware_Permissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtService := infrastructure.NewJWTService([]byte("ahnljdbjiohwebljnsknpihdbuo"), 24*time.Hour)
+	mockUserRepo := mocks.NewMockUserRepository(t)
+	mockRoleRepo := mocks.NewMockRoleRepository(t)
+	mockTokenUsecase := mocks.NewMockPersonalAccessTokenUsecase(t)
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, mockUserRepo, mockRoleRepo, mockTokenUsecase)
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"username": c.GetString("username")}) }
+	router.GET("/tasks", authMiddleware.AuthRequired(), authMiddleware.RequirePermission(domain.PermissionTasksRead), ok)
+	router.POST("/tasks", authMiddleware.AuthRequired(), authMiddleware.RequirePermission(domain.PermissionTasksWrite), ok)
+	router.GET("/admin", authMiddleware.AuthRequired(), authMiddleware.RequirePermission(domain.PermissionUsersAdmin), ok)
+	router.GET("/users/me", authMiddleware.AuthRequired(), authMiddleware.RequireSession(), ok)
+	router.GET("/actor", authMiddleware.AuthRequired(), func(c *gin.Context) {
+		c.String(http.StatusOK, domain.RequestMetaFromContext(c.Request.Context()).Actor)
+	})
+
+	user := &domain.User{ID: domain.NewID(), Username: "testuser", Role: domain.RoleUser}
+	mockRoleRepo.EXPECT().
+		GetRole(mock.Anything, domain.RoleUser).
+		Return(&domain.Role{Name: domain.RoleUser, Permissions: []string{domain.PermissionTasksRead, domain.PermissionTasksWrite}}, nil).
+		Maybe()
+	mockUserRepo.EXPECT().FindUserByID(mock.Anything, user.ID).Return(user, nil).Maybe()
+
+	readOnlyToken := domain.PersonalAccessTokenPrefix + "readonly"
+	mockTokenUsecase.EXPECT().
+		Authenticate(mock.Anything, readOnlyToken).
+		Return(user, &domain.PersonalAccessToken{Scopes: []string{domain.PermissionTasksRead}}, nil).
+		Maybe()
+
+	// Scopes cannot grant more than the user's role has
+	adminScopedToken := domain.PersonalAccessTokenPrefix + "adminscoped"
+	mockTokenUsecase.EXPECT().
+		Authenticate(mock.Anything, adminScopedToken).
+		Return(user, &domain.PersonalAccessToken{Scopes: []string{domain.PermissionUsersAdmin}}, nil).
+		Maybe()
+
+	accessToken, err := jwtService.GenerateToken(user)
+	require.NoError(t, err)
+
+	send := func(method, path, token string) int {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	t.Run("Session_RolePermissions", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/tasks", accessToken))
+		assert.Equal(t, http.StatusOK, send(http.MethodPost, "/tasks", accessToken))
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/admin", accessToken), "The user role lacks users:admin")
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/users/me", accessToken))
+	})
+
+	t.Run("AccessToken_LimitedToScopes", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/tasks", readOnlyToken))
+		assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/tasks", readOnlyToken), "A read-only token must not write")
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/users/me", readOnlyToken), "Tokens cannot manage the account")
+	})
+
+	t.Run("SetsAuditActor", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/actor", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, "testuser", rr.Body.String())
+	})
+
+	t.Run("AccessToken_ScopeBeyondRole", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/admin", adminScopedToken))
+	})
+
+	t.Run("AccessToken_WithoutScopes", func(t *testing.T) {
+		unscoped := domain.PersonalAccessTokenPrefix + "unscoped"
+		mockTokenUsecase.EXPECT().Authenticate(mock.Anything, unscoped).Return(user, &domain.PersonalAccessToken{}, nil).Once()
+
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/tasks", unscoped), "A token without scopes has no permissions")
+	})
+
+	t.Run("AccessToken_Invalid", func(t *testing.T) {
+		revoked := domain.PersonalAccessTokenPrefix + "revoked"
+		mockTokenUsecase.EXPECT().Authenticate(mock.Anything, revoked).Return(nil, nil, domain.ErrInvalidToken).Once()
+
+		assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/tasks", revoked))
+	})
+
+	t.Run("UnknownRole_NoPermissions", func(t *testing.T) {
+		orphan := &domain.User{ID: domain.NewID(), Username: "orphan", Role: "removed"}
+		orphanToken, err := jwtService.GenerateToken(orphan)
+		require.NoError(t, err)
+		mockUserRepo.EXPECT().FindUserByID(mock.Anything, orphan.ID).Return(orphan, nil).Once()
+		mockRoleRepo.EXPECT().GetRole(mock.Anything, "removed").Return(nil, domain.ErrRoleNotFound).Once()
+
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/tasks", orphanToken))
+	})
+}
+
+func TestAuthMiddleware_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtService := infrastructure.NewJWTService([]byte("ahnljdbjiohwebljnsknpihdbuo"), 24*time.Hour)
+	mockUserRepo := mocks.NewMockUserRepository(t)
+	mockRoleRepo := mocks.NewMockRoleRepository(t)
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, mockUserRepo, mockRoleRepo, mocks.NewMockPersonalAccessTokenUsecase(t))
+	mockRoleRepo.EXPECT().GetRole(mock.Anything, domain.RoleUser).Return(&domain.Role{Name: domain.RoleUser}, nil).Maybe()
+
+	router := gin.New()
+	router.GET("/users/me", authMiddleware.AuthRequired(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"username": c.GetString("username"), "signed_in_at": c.GetTime("signed_in_at").Unix()})
+	})
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/users/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("SetsSignedInAt", func(t *testing.T) {
+		user := &domain.User{ID: domain.NewID(), Username: "testuser", Role: domain.RoleUser}
+		mockUserRepo.EXPECT().FindUserByID(mock.Anything, user.ID).Return(user, nil).Once()
+		token, err := jwtService.GenerateToken(user)
+		require.NoError(t, err)
+
+		rr := send(token)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var body struct {
+			Username   string `json:"username"`
+			SignedInAt int64  `json:"signed_in_at"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "testuser", body.Username)
+		assert.WithinDuration(t, time.Now(), time.Unix(body.SignedInAt, 0), 5*time.Second, "The token's issue time should be kept for re-authentication")
+	})
+
+	t.Run("TokenVersionChanged", func(t *testing.T) {
+		user := &domain.User{ID: domain.NewID(), Username: "testuser", Role: domain.RoleUser}
+		token, err := jwtService.GenerateToken(user)
+		require.NoError(t, err)
+		mockUserRepo.EXPECT().FindUserByID(mock.Anything, user.ID).Return(&domain.User{ID: user.ID, Username: "testuser", TokenVersion: 1}, nil).Once()
+
+		assert.Equal(t, http.StatusUnauthorized, send(token).Code)
+	})
+
+	t.Run("UsernameRegisteredAgain", func(t *testing.T) {
+		// The account was deleted and someone else registered the same username
+		deleted := &domain.User{ID: domain.NewID(), Username: "testuser", Role: domain.RoleUser}
+		token, err := jwtService.GenerateToken(deleted)
+		require.NoError(t, err)
+		mockUserRepo.EXPECT().FindUserByID(mock.Anything, deleted.ID).Return(nil, domain.ErrUserNotFound).Once()
+
+		assert.Equal(t, http.StatusUnauthorized, send(token).Code)
+		mockUserRepo.AssertNotCalled(t, "FindUserByUsername", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SubjectNotAnID", func(t *testing.T) {
+		claims := domain.CustomClaims{
+			Username:         "testuser",
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "testuser", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("ahnljdbjiohwebljnsknpihdbuo"))
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, send(token).Code, "Tokens naming the user rather than their ID are no longer accepted")
+	})
+}
@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Characters of the SHA-1 hash sent to a range API. The service only learns this prefix,
+// which hundreds of known passwords share.
+const hashPrefixLength = 5
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// Checks passwords with a k-anonymity range API such as https://api.pwnedpasswords.com
+type rangeBreachedPasswordChecker struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewRangeBreachedPasswordChecker(baseURL string) domain.BreachedPasswordChecker {
+	return &rangeBreachedPasswordChecker{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Fetch every hash suffix sharing the password's prefix and look for the rest of its hash
+func (checker *rangeBreachedPasswordChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	hash := sha1Hex(password)
+	prefix, suffix := hash[:hashPrefixLength], hash[hashPrefixLength:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checker.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Pads the response with fake entries so its size does not give the prefix away
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := checker.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breached password range API returned %s", resp.Status)
+	}
+
+	// Each line is "<hash suffix>:<times seen>"; padding entries are seen 0 times
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return count != "0", nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// Checks passwords against a local list of SHA-1 hashes, for deployments without internet access
+type offlineBreachedPasswordChecker struct {
+	hashes map[string]struct{}
+}
+
+// Reads one upper or lower case SHA-1 hex hash per line, optionally followed by ":<count>",
+// the format of the downloadable Pwned Passwords list
+func NewOfflineBreachedPasswordChecker(hashList io.Reader) (domain.BreachedPasswordChecker, error) {
+	hashes := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(hashList)
+	for scanner.Scan() {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if hash == "" {
+			continue
+		}
+		hashes[strings.ToUpper(hash)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return &offlineBreachedPasswordChecker{hashes: hashes}, nil
+}
+
+func (checker *offlineBreachedPasswordChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	_, breached := checker.hashes[sha1Hex(password)]
+
+	return breached, nil
+}
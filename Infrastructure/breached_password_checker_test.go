@@ -0,0 +1,104 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Upper(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestRangeBreachedPasswordChecker(t *testing.T) {
+	ctx := context.Background()
+	breachedHash := sha1Upper("password")
+	paddedHash := sha1Upper("padded-entry")
+	var requestedPaths []string
+
+	// Only the breached hash is known; the padded entry shares no real breach
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		if prefix == breachedHash[:5] {
+			fmt.Fprintf(w, "0000000000000000000000000000000000A:3\r\n%s:9545824\r\n", breachedHash[5:])
+			return
+		}
+		if prefix == paddedHash[:5] {
+			fmt.Fprintf(w, "%s:0\r\n", paddedHash[5:])
+			return
+		}
+		fmt.Fprint(w, "0000000000000000000000000000000000A:0\r\n")
+	}))
+	defer server.Close()
+
+	checker := infrastructure.NewRangeBreachedPasswordChecker(server.URL + "/")
+
+	t.Run("Breached", func(t *testing.T) {
+		breached, err := checker.IsBreached(ctx, "password")
+
+		require.NoError(t, err)
+		assert.True(t, breached)
+		assert.Equal(t, "/range/"+breachedHash[:5], requestedPaths[len(requestedPaths)-1], "Only the hash prefix may leave the server")
+	})
+
+	t.Run("NotBreached", func(t *testing.T) {
+		breached, err := checker.IsBreached(ctx, "correct horse battery staple")
+
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("PaddingEntryIsNotABreach", func(t *testing.T) {
+		breached, err := checker.IsBreached(ctx, "padded-entry")
+
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		_, err := infrastructure.NewRangeBreachedPasswordChecker(failing.URL).IsBreached(ctx, "password")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOfflineBreachedPasswordChecker(t *testing.T) {
+	ctx := context.Background()
+	hashList := strings.Join([]string{
+		sha1Upper("password") + ":9545824",
+		strings.ToLower(sha1Upper("letmein")),
+		"",
+	}, "\n")
+
+	checker, err := infrastructure.NewOfflineBreachedPasswordChecker(strings.NewReader(hashList))
+	require.NoError(t, err)
+
+	for password, want := range map[string]bool{
+		"password":                     true,
+		"letmein":                      true,
+		"correct horse battery staple": false,
+	} {
+		breached, err := checker.IsBreached(ctx, password)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, breached, password)
+	}
+}
@@ -0,0 +1,499 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Settings for the whole service. Values come from the defaults below, then a YAML or TOML file,
+// then environment variables (the env tags), then command-line flags, each overriding the last.
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Log       LogConfig       `yaml:"log" toml:"log"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	JWT       JWTConfig       `yaml:"jwt" toml:"jwt"`
+	Mail      MailConfig      `yaml:"mail" toml:"mail"`
+	Password  PasswordConfig  `yaml:"password" toml:"password"`
+	OIDC      OIDCConfig      `yaml:"oidc" toml:"oidc"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Tasks     TasksConfig     `yaml:"tasks" toml:"tasks"`
+	Metrics   MetricsConfig   `yaml:"metrics" toml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+}
+
+type ServerConfig struct {
+	Address           string   `yaml:"address" toml:"address" env:"LISTEN_ADDRESS"`
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	ReadTimeout       Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout      Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	MaxHeaderBytes    int      `yaml:"max_header_bytes" toml:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES"`
+	ShutdownTimeout   Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"` // For in-flight requests to finish
+	DrainDelay        Duration `yaml:"drain_delay" toml:"drain_delay" env:"SERVER_DRAIN_DELAY"`                // Reported not ready for this long before shutting down
+	TrustedProxies    []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`           // IPs or CIDR ranges whose X-Forwarded-For is believed; none by default
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL"`    // debug, info, warn or error
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"` // json or text
+}
+
+// Only Storage applies when data is kept in memory. SQL databases use DSN, ConnectTimeout and
+// AutoMigrate; the other settings are MongoDB's.
+type DatabaseConfig struct {
+	Storage        string            `yaml:"storage" toml:"storage" env:"STORAGE"` // "mongo", "sqlite", "postgres" or "memory"
+	DSN            string            `yaml:"dsn" toml:"dsn" env:"DATABASE_DSN"`    // SQLite file or PostgreSQL connection string
+	URI            string            `yaml:"uri" toml:"uri" env:"MONGODB_URI"`
+	Name           string            `yaml:"name" toml:"name" env:"MONGODB_DATABASE"`
+	ConnectTimeout Duration          `yaml:"connect_timeout" toml:"connect_timeout" env:"MONGODB_CONNECT_TIMEOUT"`
+	AutoMigrate    bool              `yaml:"auto_migrate" toml:"auto_migrate" env:"DATABASE_AUTO_MIGRATE"` // Apply pending migrations on start, instead of requiring "migrate up"
+	Collections    CollectionsConfig `yaml:"collections" toml:"collections"`
+}
+
+type CollectionsConfig struct {
+	Users                string `yaml:"users" toml:"users"`
+	Tasks                string `yaml:"tasks" toml:"tasks"`
+	OneTimeTokens        string `yaml:"one_time_tokens" toml:"one_time_tokens"`
+	Settings             string `yaml:"settings" toml:"settings"`
+	PersonalAccessTokens string `yaml:"personal_access_tokens" toml:"personal_access_tokens"`
+	Roles                string `yaml:"roles" toml:"roles"`
+	AuditLog             string `yaml:"audit_log" toml:"audit_log"`
+	RateLimits           string `yaml:"rate_limits" toml:"rate_limits"`
+}
+
+type JWTConfig struct {
+	Secret   string   `yaml:"secret" toml:"secret" env:"JWT_SECRET"`
+	TokenTTL Duration `yaml:"token_ttl" toml:"token_ttl" env:"JWT_TOKEN_TTL"`
+}
+
+// Mail is printed to stdout unless Host is set
+type MailConfig struct {
+	From     string `yaml:"from" toml:"from" env:"SMTP_FROM"`
+	Host     string `yaml:"host" toml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" toml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD"`
+}
+
+type PasswordConfig struct {
+	Argon2MemoryKiB     uint32 `yaml:"argon2_memory_kib" toml:"argon2_memory_kib" env:"ARGON2_MEMORY_KIB"`
+	Argon2Iterations    uint32 `yaml:"argon2_iterations" toml:"argon2_iterations" env:"ARGON2_ITERATIONS"`
+	Argon2Parallelism   uint8  `yaml:"argon2_parallelism" toml:"argon2_parallelism" env:"ARGON2_PARALLELISM"`
+	MinLength           int    `yaml:"min_length" toml:"min_length" env:"PASSWORD_MIN_LENGTH"`
+	MinCharacterClasses int    `yaml:"min_character_classes" toml:"min_character_classes" env:"PASSWORD_MIN_CHARACTER_CLASSES"`
+	BreachedAPIURL      string `yaml:"breached_api_url" toml:"breached_api_url" env:"BREACHED_PASSWORDS_API_URL"`
+	BreachedFile        string `yaml:"breached_file" toml:"breached_file" env:"BREACHED_PASSWORDS_FILE"`
+}
+
+// Single sign-on is enabled when IssuerURL is set
+type OIDCConfig struct {
+	IssuerURL    string `yaml:"issuer_url" toml:"issuer_url" env:"OIDC_ISSUER_URL"`
+	ClientID     string `yaml:"client_id" toml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret string `yaml:"client_secret" toml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string `yaml:"redirect_url" toml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+}
+
+type RateLimitConfig struct {
+	Store string `yaml:"store" toml:"store" env:"RATE_LIMIT_STORE"` // "memory" or "mongo"
+}
+
+type TasksConfig struct {
+	RequireVerifiedEmail bool `yaml:"require_verified_email" toml:"require_verified_email" env:"REQUIRE_VERIFIED_EMAIL"`
+}
+
+// Metrics are served on their own listener, kept off the public address
+type MetricsConfig struct {
+	Address     string `yaml:"address" toml:"address" env:"METRICS_ADDRESS"`
+	BearerToken string `yaml:"bearer_token" toml:"bearer_token" env:"METRICS_BEARER_TOKEN"` // Required from scrapers when set
+}
+
+// Traces are only recorded when Exporter is stdout or otlp
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`                // none, stdout or otlp
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"` // host:port of an OTLP/HTTP collector
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure" env:"TRACING_OTLP_INSECURE"` // Send without TLS
+	ServiceName  string  `yaml:"service_name" toml:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"` // Share of new traces recorded, from 0 to 1
+}
+
+// A time.Duration written as a string such as "24h" or "90m" in config files and the environment
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Settings used when nothing else is given. There is no default JWT secret; it must be configured.
+func DefaultConfig() Config {
+	argon2 := DefaultArgon2Params()
+	policy := DefaultPasswordPolicy()
+
+	return Config{
+		Server: ServerConfig{
+			Address:           "localhost:8080",
+			ReadHeaderTimeout: Duration(10 * time.Second),
+			ReadTimeout:       Duration(30 * time.Second),
+			WriteTimeout:      Duration(30 * time.Second),
+			IdleTimeout:       Duration(2 * time.Minute),
+			MaxHeaderBytes:    64 << 10,
+			ShutdownTimeout:   Duration(20 * time.Second),
+		},
+		Log: LogConfig{Level: "info", Format: "json"},
+		Database: DatabaseConfig{
+			Storage:        "mongo",
+			URI:            "mongodb://localhost:27017",
+			Name:           "task_manager",
+			ConnectTimeout: Duration(10 * time.Second),
+			AutoMigrate:    true,
+			Collections: CollectionsConfig{
+				Users:                "user",
+				Tasks:                "tasks",
+				OneTimeTokens:        "one_time_tokens",
+				Settings:             "settings",
+				PersonalAccessTokens: "personal_access_tokens",
+				Roles:                "roles",
+				AuditLog:             "audit_log",
+				RateLimits:           "rate_limits",
+			},
+		},
+		JWT:  JWTConfig{TokenTTL: Duration(24 * time.Hour)},
+		Mail: MailConfig{From: "no-reply@taskmanager.local", Port: 587},
+		Password: PasswordConfig{
+			Argon2MemoryKiB:     argon2.Memory,
+			Argon2Iterations:    argon2.Iterations,
+			Argon2Parallelism:   argon2.Parallelism,
+			MinLength:           policy.MinLength,
+			MinCharacterClasses: policy.MinCharacterClasses,
+		},
+		RateLimit: RateLimitConfig{Store: "memory"},
+		Metrics:   MetricsConfig{Address: "localhost:9091"},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4318",
+			ServiceName:  "task_manager",
+			SampleRatio:  1,
+		},
+	}
+}
+
+// Builds the configuration from the command-line arguments (without the program name) and the
+// environment, and validates it. The file is named by -config or CONFIG_FILE; .yaml, .yml and
+// .toml files are accepted.
+func LoadConfig(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("task_manager", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	address := flags.String("addr", "", "address to listen on, e.g. localhost:8080")
+	mongoURI := flags.String("mongo-uri", "", "MongoDB connection string")
+	dbName := flags.String("db", "", "MongoDB database name")
+	storage := flags.String("storage", "", `where data is kept: "mongo", "sqlite", "postgres", or "memory" to lose it on exit`)
+	dsn := flags.String("dsn", "", "SQLite file or PostgreSQL connection string")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := DefaultConfig()
+
+	if *configFile != "" {
+		if err := config.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(reflect.ValueOf(&config).Elem(), os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	// Only flags given on the command line override the values loaded so far
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			config.Server.Address = *address
+		case "mongo-uri":
+			config.Database.URI = *mongoURI
+		case "db":
+			config.Database.Name = *dbName
+		case "storage":
+			config.Database.Storage = *storage
+		case "dsn":
+			config.Database.DSN = *dsn
+		}
+	})
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// Unknown keys are rejected, so a misspelt setting is not silently ignored
+func (config *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+		if errors.Is(err, io.EOF) {
+			err = nil // An empty file keeps the defaults
+		}
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields().Decode(config)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Sets every field with an env tag whose variable is present, recursing into nested structs
+func loadEnv(value reflect.Value, lookup func(string) (string, bool)) error {
+	var errs []error
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := value.Type().Field(i)
+
+		if structField.Type.Kind() == reflect.Struct {
+			if err := loadEnv(field, lookup); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		name := structField.Tag.Get("env")
+		raw, ok := lookup(name)
+		if name == "" || !ok {
+			continue
+		}
+
+		if err := setField(field, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", name, raw, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func setField(field reflect.Value, raw string) error {
+	if unmarshaler, ok := field.Addr().Interface().(interface{ UnmarshalText([]byte) error }); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		field.SetBool(parsed)
+	case reflect.Int:
+		parsed, err := strconv.ParseInt(raw, 10, 0)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		field.SetInt(parsed)
+	case reflect.Uint8, reflect.Uint32:
+		parsed, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a whole number between 0 and %d", uint64(1)<<field.Type().Bits()-1)
+		}
+		field.SetUint(parsed)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported setting type %s", field.Type())
+		}
+		var values []string
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+
+	return nil
+}
+
+// Checks the configuration, reporting every problem at once
+func (config *Config) Validate() error {
+	var errs []error
+	invalid := func(setting, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", setting, fmt.Sprintf(format, args...)))
+	}
+
+	if config.Server.Address == "" {
+		invalid("server.address", "is required")
+	}
+	server := reflect.ValueOf(config.Server)
+	for i := 0; i < server.NumField(); i++ {
+		name := server.Type().Field(i).Tag.Get("yaml")
+		if timeout, ok := server.Field(i).Interface().(Duration); ok && timeout <= 0 && name != "drain_delay" {
+			invalid("server."+name, "must be positive")
+		}
+	}
+	if config.Server.DrainDelay < 0 {
+		invalid("server.drain_delay", "must not be negative")
+	}
+	if config.Server.MaxHeaderBytes < 4<<10 {
+		invalid("server.max_header_bytes", "must be at least 4096")
+	}
+	for _, proxy := range config.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			invalid("server.trusted_proxies", "must be IP addresses or CIDR ranges, not %q", proxy)
+		}
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.Log.Level)); err != nil {
+		invalid("log.level", "must be debug, info, warn or error, not %q", config.Log.Level)
+	}
+	if config.Log.Format != "json" && config.Log.Format != "text" {
+		invalid("log.format", "must be json or text, not %q", config.Log.Format)
+	}
+
+	switch config.Database.Storage {
+	case "mongo":
+		config.validateMongo(invalid)
+	case "sqlite", "postgres":
+		if config.Database.DSN == "" {
+			invalid("database.dsn", "is required for %s storage", config.Database.Storage)
+		}
+		if config.Database.ConnectTimeout <= 0 {
+			invalid("database.connect_timeout", "must be positive")
+		}
+		if config.RateLimit.Store == "mongo" {
+			invalid("rate_limit.store", "cannot be mongo when data is kept in %s", config.Database.Storage)
+		}
+	case "memory":
+		if config.RateLimit.Store == "mongo" {
+			invalid("rate_limit.store", "cannot be mongo when data is kept in memory")
+		}
+	default:
+		invalid("database.storage", "must be mongo, sqlite, postgres or memory, not %q", config.Database.Storage)
+	}
+
+	if len(config.JWT.Secret) < 32 {
+		invalid("jwt.secret", "must be at least 32 bytes (set JWT_SECRET)")
+	}
+	if config.JWT.TokenTTL < Duration(time.Minute) {
+		invalid("jwt.token_ttl", "must be at least 1m")
+	}
+
+	if config.Mail.Host != "" && (config.Mail.Port < 1 || config.Mail.Port > 65535) {
+		invalid("mail.port", "must be between 1 and 65535")
+	}
+	if config.Mail.From == "" {
+		invalid("mail.from", "is required")
+	}
+
+	if config.Password.Argon2MemoryKiB == 0 || config.Password.Argon2Iterations == 0 || config.Password.Argon2Parallelism == 0 {
+		invalid("password", "argon2 memory, iterations and parallelism must be positive")
+	}
+	if config.Password.MinLength < 1 {
+		invalid("password.min_length", "must be positive")
+	}
+	if config.Password.MinCharacterClasses < 0 || config.Password.MinCharacterClasses > 4 {
+		invalid("password.min_character_classes", "must be between 0 and 4")
+	}
+
+	if config.OIDC.IssuerURL != "" {
+		if config.OIDC.ClientID == "" || config.OIDC.RedirectURL == "" {
+			invalid("oidc", "client_id and redirect_url are required when issuer_url is set")
+		}
+	}
+
+	if config.RateLimit.Store != "memory" && config.RateLimit.Store != "mongo" {
+		invalid("rate_limit.store", "must be memory or mongo, not %q", config.RateLimit.Store)
+	}
+
+	if config.Metrics.Address == "" {
+		invalid("metrics.address", "is required")
+	} else if config.Metrics.Address == config.Server.Address {
+		invalid("metrics.address", "must differ from server.address")
+	}
+
+	switch config.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if config.Tracing.OTLPEndpoint == "" {
+			invalid("tracing.otlp_endpoint", "is required when the exporter is otlp")
+		}
+	default:
+		invalid("tracing.exporter", "must be none, stdout or otlp, not %q", config.Tracing.Exporter)
+	}
+	if config.Tracing.ServiceName == "" {
+		invalid("tracing.service_name", "is required")
+	}
+	if config.Tracing.SampleRatio < 0 || config.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "must be between 0 and 1")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Checks the settings used to connect to MongoDB
+func (config *Config) validateMongo(invalid func(setting, format string, args ...any)) {
+	if uri, err := url.Parse(config.Database.URI); err != nil || (uri.Scheme != "mongodb" && uri.Scheme != "mongodb+srv") {
+		invalid("database.uri", "must be a mongodb:// or mongodb+srv:// connection string")
+	}
+	if config.Database.Name == "" {
+		invalid("database.name", "is required")
+	}
+	if config.Database.ConnectTimeout <= 0 {
+		invalid("database.connect_timeout", "must be positive")
+	}
+	collections := reflect.ValueOf(config.Database.Collections)
+	for i := 0; i < collections.NumField(); i++ {
+		if collections.Field(i).String() == "" {
+			invalid("database.collections."+collections.Type().Field(i).Tag.Get("yaml"), "is required")
+		}
+	}
+}
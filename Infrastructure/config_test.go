@@ -0,0 +1,222 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	config, err := infrastructure.LoadConfig(nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8080", config.Server.Address)
+	assert.Equal(t, "mongodb://localhost:27017", config.Database.URI)
+	assert.Equal(t, "task_manager", config.Database.Name)
+	assert.Equal(t, "user", config.Database.Collections.Users)
+	assert.Equal(t, infrastructure.Duration(24*time.Hour), config.JWT.TokenTTL)
+	assert.Equal(t, "memory", config.RateLimit.Store)
+	assert.Equal(t, "mongo", config.Database.Storage)
+	assert.True(t, config.Database.AutoMigrate)
+	assert.Empty(t, config.Server.TrustedProxies, "No proxy is trusted unless configured")
+	assert.Equal(t, "localhost:9091", config.Metrics.Address)
+}
+
+func TestLoadConfig_StorageFlag(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("MONGODB_URI", "")
+
+	config, err := infrastructure.LoadConfig([]string{"-storage", "memory"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "memory", config.Database.Storage)
+
+	config, err = infrastructure.LoadConfig([]string{"-storage", "postgres", "-dsn", "postgres://localhost/task_manager"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", config.Database.Storage)
+	assert.Equal(t, "postgres://localhost/task_manager", config.Database.DSN)
+}
+
+func TestLoadConfig_YAMLFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  address: ":9090"
+database:
+  uri: mongodb://db.internal:27017
+  collections:
+    tasks: team_tasks
+jwt:
+  secret: `+testJWTSecret+`
+  token_ttl: 2h
+tasks:
+  require_verified_email: true
+`)
+
+	config, err := infrastructure.LoadConfig([]string{"-config", path})
+
+	require.NoError(t, err)
+	assert.Equal(t, ":9090", config.Server.Address)
+	assert.Equal(t, "mongodb://db.internal:27017", config.Database.URI)
+	assert.Equal(t, "team_tasks", config.Database.Collections.Tasks)
+	assert.Equal(t, "user", config.Database.Collections.Users, "Settings missing from the file keep their defaults")
+	assert.Equal(t, infrastructure.Duration(2*time.Hour), config.JWT.TokenTTL)
+	assert.True(t, config.Tasks.RequireVerifiedEmail)
+}
+
+func TestLoadConfig_TOMLFileFromEnvironment(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+[database]
+name = "tasks_test"
+
+[jwt]
+secret = "`+testJWTSecret+`"
+token_ttl = "30m"
+`)
+	t.Setenv("CONFIG_FILE", path)
+
+	config, err := infrastructure.LoadConfig(nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "tasks_test", config.Database.Name)
+	assert.Equal(t, infrastructure.Duration(30*time.Minute), config.JWT.TokenTTL)
+}
+
+func TestLoadConfig_UnknownKeyInFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "database:\n  uri_typo: mongodb://db\n")
+
+	_, err := infrastructure.LoadConfig([]string{"-config", path})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "uri_typo")
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "server:\n  address: file:1\ndatabase:\n  name: from_file\n")
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("LISTEN_ADDRESS", "env:2")
+	t.Setenv("MONGODB_DATABASE", "from_env")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("ARGON2_PARALLELISM", "4")
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+
+	config, err := infrastructure.LoadConfig([]string{"-config", path, "-addr", "flag:3"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "flag:3", config.Server.Address, "Flags override the environment")
+	assert.Equal(t, "from_env", config.Database.Name, "The environment overrides the file")
+	assert.Equal(t, 2525, config.Mail.Port)
+	assert.Equal(t, uint8(4), config.Password.Argon2Parallelism)
+	assert.Equal(t, 0.25, config.Tracing.SampleRatio)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, config.Server.TrustedProxies)
+}
+
+func TestLoadConfig_InvalidEnvironmentValue(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("JWT_TOKEN_TTL", "a day")
+	t.Setenv("ARGON2_PARALLELISM", "300")
+
+	_, err := infrastructure.LoadConfig(nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_TOKEN_TTL")
+	assert.Contains(t, err.Error(), "ARGON2_PARALLELISM")
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := func() infrastructure.Config {
+		config := infrastructure.DefaultConfig()
+		config.JWT.Secret = testJWTSecret
+		return config
+	}
+
+	config := valid()
+	assert.NoError(t, config.Validate())
+
+	testCases := []struct {
+		name    string
+		modify  func(*infrastructure.Config)
+		message string
+	}{
+		{"MissingSecret", func(c *infrastructure.Config) { c.JWT.Secret = "" }, "jwt.secret"},
+		{"ShortSecret", func(c *infrastructure.Config) { c.JWT.Secret = "short" }, "jwt.secret"},
+		{"ZeroWriteTimeout", func(c *infrastructure.Config) { c.Server.WriteTimeout = 0 }, "server.write_timeout"},
+		{"UnknownLogLevel", func(c *infrastructure.Config) { c.Log.Level = "verbose" }, "log.level"},
+		{"UnknownLogFormat", func(c *infrastructure.Config) { c.Log.Format = "xml" }, "log.format"},
+		{"UnknownTracingExporter", func(c *infrastructure.Config) { c.Tracing.Exporter = "jaeger" }, "tracing.exporter"},
+		{"OTLPWithoutEndpoint", func(c *infrastructure.Config) { c.Tracing.Exporter = "otlp"; c.Tracing.OTLPEndpoint = "" }, "tracing.otlp_endpoint"},
+		{"SampleRatioAboveOne", func(c *infrastructure.Config) { c.Tracing.SampleRatio = 1.5 }, "tracing.sample_ratio"},
+		{"BadURI", func(c *infrastructure.Config) { c.Database.URI = "postgres://localhost" }, "database.uri"},
+		{"EmptyCollection", func(c *infrastructure.Config) { c.Database.Collections.Roles = "" }, "database.collections.roles"},
+		{"UnknownStorage", func(c *infrastructure.Config) { c.Database.Storage = "cassandra" }, "database.storage"},
+		{"SQLWithoutDSN", func(c *infrastructure.Config) { c.Database.Storage = "postgres" }, "database.dsn"},
+		{"MongoRateLimitsInSQL", func(c *infrastructure.Config) {
+			c.Database.Storage, c.Database.DSN, c.RateLimit.Store = "sqlite", "task_manager.db", "mongo"
+		}, "rate_limit.store"},
+		{"MongoRateLimitsInMemory", func(c *infrastructure.Config) { c.Database.Storage = "memory"; c.RateLimit.Store = "mongo" }, "rate_limit.store"},
+		{"UnknownRateLimitStore", func(c *infrastructure.Config) { c.RateLimit.Store = "redis" }, "rate_limit.store"},
+		{"IncompleteOIDC", func(c *infrastructure.Config) { c.OIDC.IssuerURL = "https://accounts.example.com" }, "oidc"},
+		{"MissingMetricsAddress", func(c *infrastructure.Config) { c.Metrics.Address = "" }, "metrics.address"},
+		{"MetricsOnPublicAddress", func(c *infrastructure.Config) { c.Metrics.Address = c.Server.Address }, "metrics.address"},
+		{"InvalidTrustedProxy", func(c *infrastructure.Config) { c.Server.TrustedProxies = []string{"proxy.internal"} }, "server.trusted_proxies"},
+		{"TooManyCharacterClasses", func(c *infrastructure.Config) { c.Password.MinCharacterClasses = 5 }, "password.min_character_classes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := valid()
+			tc.modify(&config)
+
+			err := config.Validate()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+
+	t.Run("MemoryStorageIgnoresMongoSettings", func(t *testing.T) {
+		config := valid()
+		config.Database.Storage = "memory"
+		config.Database.URI = ""
+		config.Database.Collections.Tasks = ""
+
+		assert.NoError(t, config.Validate())
+	})
+
+	t.Run("SQLStorageIgnoresMongoSettings", func(t *testing.T) {
+		config := valid()
+		config.Database.Storage = "sqlite"
+		config.Database.DSN = "task_manager.db"
+		config.Database.URI = ""
+
+		assert.NoError(t, config.Validate())
+	})
+
+	t.Run("ReportsEveryProblem", func(t *testing.T) {
+		config := valid()
+		config.JWT.Secret = ""
+		config.Database.Name = ""
+
+		err := config.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "jwt.secret")
+		assert.Contains(t, err.Error(), "database.name")
+	})
+}
@@ -3,7 +3,8 @@ package infrastructure
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	domain "task_manager/Domain"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -12,10 +13,10 @@ import (
 
 // Establish a connection to MongoDB and returns the client.
 // Returns an error if connection fails.
-func ConnectDB(ctx context.Context) (*mongo.Client, error) {
+func ConnectDB(ctx context.Context, uri string) (*mongo.Client, error) {
 
-	// Set client options
-	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	// Set client options; commands are traced as part of the request that sent them
+	clientOptions := options.Client().ApplyURI(uri).SetMonitor(NewMongoCommandMonitor())
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
@@ -35,7 +36,7 @@ func ConnectDB(ctx context.Context) (*mongo.Client, error) {
 	}
 
 	// collection := client.Database("task_manager").Collection("tasks")
-	log.Println("Successfully connected and pinged MongoDB.")
+	slog.Info("connected to MongoDB")
 	return client, nil // Return client on success
 }
 
@@ -52,8 +53,15 @@ func DisconnectDB(client *mongo.Client) {
 	err := client.Disconnect(disconnectContext)
 
 	if err != nil {
-		log.Printf("failed to disconnect MongoDB: %v", err)
+		slog.Error("failed to disconnect MongoDB", slog.Any("error", err))
 	} else {
-		log.Println("Connection to MongoDB closed.")
+		slog.Info("connection to MongoDB closed")
+	}
+}
+
+// Readiness check that pings the MongoDB primary
+func MongoHealthCheck(client *mongo.Client) domain.HealthCheck {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, nil)
 	}
 }
@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Time allowed for each readiness check
+const healthCheckTimeout = 2 * time.Second
+
+type healthChecker struct {
+	mu           sync.RWMutex
+	checks       map[string]domain.HealthCheck
+	shuttingDown atomic.Bool
+}
+
+func NewHealthChecker() domain.HealthChecker {
+	return &healthChecker{checks: make(map[string]domain.HealthCheck)}
+}
+
+// Registering a name again replaces its check
+func (checker *healthChecker) Register(name string, check domain.HealthCheck) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	checker.checks[name] = check
+}
+
+func (checker *healthChecker) SetShuttingDown() {
+	checker.shuttingDown.Store(true)
+}
+
+// Runs every check at once, each with its own timeout. The service is ready only if all pass.
+// Error details are logged rather than reported, since the endpoint is public.
+func (checker *healthChecker) Readiness(ctx context.Context) domain.HealthReport {
+	if checker.shuttingDown.Load() {
+		return domain.HealthReport{Status: domain.HealthStatusShuttingDown}
+	}
+
+	checker.mu.RLock()
+	checks := make(map[string]domain.HealthCheck, len(checker.checks))
+	for name, check := range checker.checks {
+		checks[name] = check
+	}
+	checker.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]domain.HealthCheckResult, len(checks))
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := runHealthCheck(ctx, name, check)
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report := domain.HealthReport{Status: domain.HealthStatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != domain.HealthStatusOK {
+			report.Status = domain.HealthStatusError
+		}
+	}
+
+	return report
+}
+
+func runHealthCheck(ctx context.Context, name string, check domain.HealthCheck) domain.HealthCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(checkCtx)
+	result := domain.HealthCheckResult{
+		Status:    domain.HealthStatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		domain.LoggerFromContext(ctx).Warn("readiness check failed", slog.String("check", name), slog.Any("error", err))
+		result.Status = domain.HealthStatusError
+		result.Error = "check failed"
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = "timed out"
+		}
+	}
+
+	return result
+}
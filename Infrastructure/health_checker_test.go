@@ -0,0 +1,74 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecker_Readiness(t *testing.T) {
+	passing := func(ctx context.Context) error { return nil }
+
+	t.Run("AllChecksPass", func(t *testing.T) {
+		checker := infrastructure.NewHealthChecker()
+		checker.Register("mongo", passing)
+		checker.Register("cache", passing)
+
+		report := checker.Readiness(context.Background())
+
+		assert.Equal(t, domain.HealthStatusOK, report.Status)
+		require.Len(t, report.Checks, 2)
+		assert.Equal(t, domain.HealthStatusOK, report.Checks["mongo"].Status)
+	})
+
+	t.Run("NoChecks", func(t *testing.T) {
+		report := infrastructure.NewHealthChecker().Readiness(context.Background())
+
+		assert.Equal(t, domain.HealthStatusOK, report.Status)
+	})
+
+	t.Run("FailingCheckHidesDetails", func(t *testing.T) {
+		checker := infrastructure.NewHealthChecker()
+		checker.Register("mongo", func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.5:27017: connection refused") })
+		checker.Register("cache", passing)
+
+		report := checker.Readiness(context.Background())
+
+		assert.Equal(t, domain.HealthStatusError, report.Status)
+		assert.Equal(t, domain.HealthCheckResult{Status: domain.HealthStatusError, LatencyMS: report.Checks["mongo"].LatencyMS, Error: "check failed"}, report.Checks["mongo"])
+		assert.Equal(t, domain.HealthStatusOK, report.Checks["cache"].Status)
+	})
+
+	t.Run("SlowCheckTimesOut", func(t *testing.T) {
+		checker := infrastructure.NewHealthChecker()
+		checker.Register("mongo", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		start := time.Now()
+		report := checker.Readiness(context.Background())
+
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Equal(t, "timed out", report.Checks["mongo"].Error)
+		assert.GreaterOrEqual(t, report.Checks["mongo"].LatencyMS, 1000.0)
+	})
+
+	t.Run("NotReadyOnceShuttingDown", func(t *testing.T) {
+		checker := infrastructure.NewHealthChecker()
+		called := false
+		checker.Register("mongo", func(ctx context.Context) error { called = true; return nil })
+
+		checker.SetShuttingDown()
+		report := checker.Readiness(context.Background())
+
+		assert.Equal(t, domain.HealthStatusShuttingDown, report.Status)
+		assert.False(t, called, "Checks are skipped once shutdown has started")
+	})
+}
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Builds the gin engine. The client IP of a request is its peer address, unless the peer is one of
+// the trusted proxies, which are then believed about the client in X-Forwarded-For.
+func NewGinEngine(config ServerConfig) (*gin.Engine, error) {
+	engine := gin.New()
+	if err := engine.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, err
+	}
+
+	return engine, nil
+}
+
+// Builds the HTTP server with timeouts and a header size limit, so slow or idle clients cannot
+// hold connections open indefinitely
+func NewHTTPServer(config ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              config.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(config.ReadHeaderTimeout),
+		ReadTimeout:       time.Duration(config.ReadTimeout),
+		WriteTimeout:      time.Duration(config.WriteTimeout),
+		IdleTimeout:       time.Duration(config.IdleTimeout),
+		MaxHeaderBytes:    config.MaxHeaderBytes,
+	}
+}
+
+// Serves on the listener until ctx is cancelled, then stops accepting connections and waits up to
+// shutdownTimeout for in-flight requests to finish. Connections still open after that are closed
+// and the deadline error is returned.
+func RunHTTPServer(ctx context.Context, server *http.Server, listener net.Listener, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// The server stopped by itself, e.g. the listener failed
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return err
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package infrastructure_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Starts a server whose handler blocks until release is closed, and returns its URL
+func startBlockingServer(t *testing.T, ctx context.Context, release chan struct{}, shutdownTimeout time.Duration) (string, chan struct{}, chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		io.WriteString(w, "done")
+	})
+
+	server := infrastructure.NewHTTPServer(infrastructure.DefaultConfig().Server, handler)
+	done := make(chan error, 1)
+	go func() {
+		done <- infrastructure.RunHTTPServer(ctx, server, listener, shutdownTimeout)
+	}()
+
+	return "http://" + listener.Addr().String(), started, done
+}
+
+func TestNewHTTPServer_AppliesLimits(t *testing.T) {
+	config := infrastructure.DefaultConfig().Server
+
+	server := infrastructure.NewHTTPServer(config, http.NotFoundHandler())
+
+	assert.Equal(t, time.Duration(config.ReadHeaderTimeout), server.ReadHeaderTimeout)
+	assert.Equal(t, time.Duration(config.ReadTimeout), server.ReadTimeout)
+	assert.Equal(t, time.Duration(config.WriteTimeout), server.WriteTimeout)
+	assert.Equal(t, time.Duration(config.IdleTimeout), server.IdleTimeout)
+	assert.Equal(t, config.MaxHeaderBytes, server.MaxHeaderBytes)
+}
+
+func TestRunHTTPServer_DrainsInFlightRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	url, started, done := startBlockingServer(t, ctx, release, 5*time.Second)
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+	<-started
+
+	// Shut down while the request is still being handled
+	cancel()
+	require.Eventually(t, func() bool {
+		_, err := net.DialTimeout("tcp", url[len("http://"):], 100*time.Millisecond)
+		return err != nil
+	}, 2*time.Second, 10*time.Millisecond, "The server should stop accepting connections")
+
+	select {
+	case <-done:
+		t.Fatal("RunHTTPServer returned before the in-flight request finished")
+	default:
+	}
+
+	close(release)
+	assert.Equal(t, "done", <-responses)
+	assert.NoError(t, <-done)
+}
+
+func TestRunHTTPServer_ShutdownDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	url, started, done := startBlockingServer(t, ctx, release, 50*time.Millisecond)
+
+	go http.Get(url)
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunHTTPServer did not give up after the shutdown timeout")
+	}
+}
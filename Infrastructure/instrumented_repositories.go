@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// TaskRepository that times and logs every call to the repository it wraps
+type instrumentedTaskRepository struct {
+	next    domain.TaskRepository
+	metrics *Metrics
+}
+
+var _ domain.TaskRepository = (*instrumentedTaskRepository)(nil)
+
+func NewInstrumentedTaskRepository(repo domain.TaskRepository, metrics *Metrics) domain.TaskRepository {
+	return &instrumentedTaskRepository{next: repo, metrics: metrics}
+}
+
+func (repo *instrumentedTaskRepository) GetAllTask(ctx context.Context) (tasks []domain.Task, err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "GetAllTask", time.Now(), &err)
+	return repo.next.GetAllTask(ctx)
+}
+
+func (repo *instrumentedTaskRepository) GetTaskByID(ctx context.Context, id string) (task domain.Task, err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "GetTaskByID", time.Now(), &err)
+	return repo.next.GetTaskByID(ctx, id)
+}
+
+func (repo *instrumentedTaskRepository) UpdateTask(ctx context.Context, id string, updatedTask domain.Task) (err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "UpdateTask", time.Now(), &err)
+	return repo.next.UpdateTask(ctx, id, updatedTask)
+}
+
+func (repo *instrumentedTaskRepository) DeleteTask(ctx context.Context, id string) (err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "DeleteTask", time.Now(), &err)
+	return repo.next.DeleteTask(ctx, id)
+}
+
+func (repo *instrumentedTaskRepository) DeleteTasksByCreator(ctx context.Context, username string) (err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "DeleteTasksByCreator", time.Now(), &err)
+	return repo.next.DeleteTasksByCreator(ctx, username)
+}
+
+func (repo *instrumentedTaskRepository) NewTask(ctx context.Context, task domain.Task) (created domain.Task, err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "NewTask", time.Now(), &err)
+	return repo.next.NewTask(ctx, task)
+}
+
+func (repo *instrumentedTaskRepository) CountTasksByStatus(ctx context.Context) (counts map[string]int64, err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "CountTasksByStatus", time.Now(), &err)
+	return repo.next.CountTasksByStatus(ctx)
+}
+
+func (repo *instrumentedTaskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter, limit int) (tasks []domain.Task, err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "FindTasks", time.Now(), &err)
+	return repo.next.FindTasks(ctx, filter, limit)
+}
+
+func (repo *instrumentedTaskRepository) BulkWriteTasks(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool) (results []domain.BulkTaskResult, err error) {
+	defer repo.metrics.observeRepository(ctx, "task", "BulkWriteTasks", time.Now(), &err)
+	return repo.next.BulkWriteTasks(ctx, operations, atomic)
+}
+
+// UserRepository that times and logs every call to the repository it wraps
+type instrumentedUserRepository struct {
+	next    domain.UserRepository
+	metrics *Metrics
+}
+
+var _ domain.UserRepository = (*instrumentedUserRepository)(nil)
+
+func NewInstrumentedUserRepository(repo domain.UserRepository, metrics *Metrics) domain.UserRepository {
+	return &instrumentedUserRepository{next: repo, metrics: metrics}
+}
+
+func (repo *instrumentedUserRepository) CreateUser(ctx context.Context, user *domain.User) (err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "CreateUser", time.Now(), &err)
+	return repo.next.CreateUser(ctx, user)
+}
+
+func (repo *instrumentedUserRepository) FindUserByID(ctx context.Context, id domain.ID) (user *domain.User, err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "FindUserByID", time.Now(), &err)
+	return repo.next.FindUserByID(ctx, id)
+}
+
+func (repo *instrumentedUserRepository) FindUserByUsername(ctx context.Context, username string) (user *domain.User, err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "FindUserByUsername", time.Now(), &err)
+	return repo.next.FindUserByUsername(ctx, username)
+}
+
+func (repo *instrumentedUserRepository) FindUserByEmail(ctx context.Context, email string) (user *domain.User, err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "FindUserByEmail", time.Now(), &err)
+	return repo.next.FindUserByEmail(ctx, email)
+}
+
+func (repo *instrumentedUserRepository) FindUserByOIDCSubject(ctx context.Context, issuer, subject string) (user *domain.User, err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "FindUserByOIDCSubject", time.Now(), &err)
+	return repo.next.FindUserByOIDCSubject(ctx, issuer, subject)
+}
+
+func (repo *instrumentedUserRepository) UpdateUser(ctx context.Context, user *domain.User) (err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "UpdateUser", time.Now(), &err)
+	return repo.next.UpdateUser(ctx, user)
+}
+
+func (repo *instrumentedUserRepository) DeleteUser(ctx context.Context, username string) (err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "DeleteUser", time.Now(), &err)
+	return repo.next.DeleteUser(ctx, username)
+}
+
+func (repo *instrumentedUserRepository) UseTOTPStep(ctx context.Context, id domain.ID, step int64) (err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "UseTOTPStep", time.Now(), &err)
+	return repo.next.UseTOTPStep(ctx, id, step)
+}
+
+func (repo *instrumentedUserRepository) UseRecoveryCode(ctx context.Context, id domain.ID, codeHash string) (err error) {
+	defer repo.metrics.observeRepository(ctx, "user", "UseRecoveryCode", time.Now(), &err)
+	return repo.next.UseRecoveryCode(ctx, id, codeHash)
+}
@@ -9,32 +9,34 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("ahnljdbjiohwebljnsknpihdbuo")
-
-type jwtService struct{}
+type jwtService struct {
+	secret   []byte        // HMAC key for signing and verifying tokens
+	tokenTTL time.Duration // Lifetime of access tokens
+}
 
-func NewJWTService() domain.JWTService {
-	return &jwtService{}
+func NewJWTService(secret []byte, tokenTTL time.Duration) domain.JWTService {
+	return &jwtService{secret: secret, tokenTTL: tokenTTL}
 }
 
-// Creates a new JWT for a given username and role
-func (service *jwtService) GenerateToken(username, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // Token valid for 24 hours
+// Creates a new JWT for a given user, carrying their role and current token version
+func (service *jwtService) GenerateToken(user *domain.User) (string, error) {
+	expirationTime := time.Now().Add(service.tokenTTL)
 
 	claims := domain.CustomClaims{
-		Username: username,
-		Role:     role,
+		Username:     user.Username,
+		Role:         user.Role,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   username, // Unique identifier for the subject
+			Subject:   user.ID.String(), // Usernames are freed when an account is deleted, IDs are never reused
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims) // Using HS256 signing method.
 
 	// Sign the token with the secret key.
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString(service.secret)
 	if err != nil {
 		return "", errors.New("failed to sign token")
 	}
@@ -42,8 +44,60 @@ func (service *jwtService) GenerateToken(username, role string) (string, error)
 	return tokenString, nil
 }
 
-// Parses and validates a JWT
+// Parses and validates an access token
 func (service *jwtService) ValidateToken(tokenString string) (*domain.CustomClaims, error) {
+	claims, err := service.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens issued for another purpose, e.g. halfway through a login, grant no access
+	if claims.Purpose != "" {
+		return nil, fmt.Errorf("invalid token: not an access token")
+	}
+
+	return claims, nil
+}
+
+// Creates the short-lived token handed out after the password step of a two-factor login
+func (service *jwtService) GenerateMFAToken(user *domain.User, tokenID string, expiresAt time.Time) (string, error) {
+	claims := domain.CustomClaims{
+		Username:     user.Username,
+		Role:         user.Role,
+		TokenVersion: user.TokenVersion,
+		Purpose:      domain.TokenPurposeMFALogin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   user.ID.String(),
+			ID:        tokenID,
+		},
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(service.secret)
+	if err != nil {
+		return "", errors.New("failed to sign token")
+	}
+
+	return tokenString, nil
+}
+
+// Parses and validates a token created by GenerateMFAToken
+func (service *jwtService) ValidateMFAToken(tokenString string) (*domain.CustomClaims, error) {
+	claims, err := service.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != domain.TokenPurposeMFALogin {
+		return nil, fmt.Errorf("invalid token: not a two-factor login token")
+	}
+
+	return claims, nil
+}
+
+// Parses a JWT and checks its signature and lifetime
+func (service *jwtService) parse(tokenString string) (*domain.CustomClaims, error) {
 	claims := &domain.CustomClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -51,7 +105,7 @@ func (service *jwtService) ValidateToken(tokenString string) (*domain.CustomClai
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return jwtSecret, nil // Provide the secret key for validation
+		return service.secret, nil // Provide the secret key for validation
 	})
 
 	if err != nil {
@@ -12,16 +12,17 @@ import (
 )
 
 func TestJWTServie(t *testing.T) {
-	jwtService := infrastructure.NewJWTService()
+	jwtService := infrastructure.NewJWTService([]byte("ahnljdbjiohwebljnsknpihdbuo"), 24*time.Hour)
 
 	require.NotNil(t, jwtService, "NewJWTService should not retuen nil")
 
 	username := "testuser"
 	role := domain.RoleUser
+	user := &domain.User{ID: domain.NewID(), Username: username, Role: role, TokenVersion: 3}
 
 	// ---- Test GenerateToken ----
 	t.Run("GenerateToken_Success", func(t *testing.T) {
-		tokenString, err := jwtService.GenerateToken(username, role)
+		tokenString, err := jwtService.GenerateToken(user)
 
 		require.NoError(t, err, "GenerateToken should not return an error on success")
 		require.NotEmpty(t, tokenString, "Generated token string should not be empty")
@@ -40,7 +41,8 @@ func TestJWTServie(t *testing.T) {
 
 		assert.Equal(t, username, claims.Username, "Username in claims should match")
 		assert.Equal(t, role, claims.Role, "Role in claims should match")
-		assert.Equal(t, username, claims.Subject, "Subject in claims should match username")
+		assert.Equal(t, user.ID.String(), claims.Subject, "Subject in claims should be the user's ID, which is never reused")
+		assert.Equal(t, user.TokenVersion, claims.TokenVersion, "Token version in claims should match the user's")
 
 		// Check timestamps (allowinng for a small delta due to processing time)
 		// Service generates tokens valid for 24 hours
@@ -54,7 +56,7 @@ func TestJWTServie(t *testing.T) {
 
 	t.Run("ValidateToken_Success_ValidToken", func(t *testing.T) {
 		// Generate a token
-		validTokenString, genErr := jwtService.GenerateToken(username, role)
+		validTokenString, genErr := jwtService.GenerateToken(user)
 		require.NoError(t, genErr, "Pre-condition: Failed to generate token for validation test")
 
 		// Validate generated token
@@ -65,7 +67,9 @@ func TestJWTServie(t *testing.T) {
 
 		assert.Equal(t, username, claims.Username)
 		assert.Equal(t, role, claims.Role)
-		assert.Equal(t, username, claims.Subject)
+		userID, err := claims.UserID()
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, userID)
 
 	})
 
@@ -164,6 +168,27 @@ func TestJWTServie(t *testing.T) {
 		assert.Contains(t, err.Error(), jwt.ErrTokenNotValidYet.Error(), "Error message should indicate token is not yet valid")
 	})
 
+	t.Run("MFAToken_NotAnAccessToken", func(t *testing.T) {
+		mfaToken, err := jwtService.GenerateMFAToken(user, "token-id", time.Now().Add(5*time.Minute))
+		require.NoError(t, err, "GenerateMFAToken should not return an error on success")
+
+		claims, err := jwtService.ValidateMFAToken(mfaToken)
+		require.NoError(t, err, "ValidateMFAToken should accept its own tokens")
+		assert.Equal(t, username, claims.Username)
+		assert.Equal(t, user.TokenVersion, claims.TokenVersion)
+		assert.Equal(t, "token-id", claims.ID)
+		assert.Equal(t, user.ID.String(), claims.Subject)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), claims.ExpiresAt.Time, 5*time.Second, "MFA tokens should expire when asked")
+
+		_, err = jwtService.ValidateToken(mfaToken)
+		assert.Error(t, err, "An MFA token must not be usable as an access token")
+
+		accessToken, err := jwtService.GenerateToken(user)
+		require.NoError(t, err)
+		_, err = jwtService.ValidateMFAToken(accessToken)
+		assert.Error(t, err, "An access token must not be usable as an MFA token")
+	})
+
 	t.Run("ValidateToken_Failure_IncorrectSigningMethod", func(t *testing.T) {
 		tokenWithAlgNone := "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJzdWIiOiJ0ZXN0dXNlciJ9."
 
@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Writes emails to an io.Writer (stdout, a log file, a buffer in tests) instead of sending them.
+type logMailer struct {
+	mu   sync.Mutex
+	out  io.Writer
+	from string
+}
+
+func NewLogMailer(out io.Writer, from string) domain.Mailer {
+	return &logMailer{out: out, from: from}
+}
+
+// Write the message in a readable, email-like format
+func (mailer *logMailer) Send(ctx context.Context, message domain.MailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	_, err := fmt.Fprintf(mailer.out, "----- mail %s -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n----- end mail -----\n",
+		time.Now().UTC().Format(time.RFC3339), mailer.from, message.To, message.Subject, message.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer(t *testing.T) {
+	var out bytes.Buffer
+	mailer := infrastructure.NewLogMailer(&out, "no-reply@example.com")
+	require.NotNil(t, mailer, "NewLogMailer should not return nil")
+
+	t.Run("Send_WritesMessage", func(t *testing.T) {
+		out.Reset()
+		message := domain.MailMessage{To: "user@example.com", Subject: "Hello", Body: "Reset token: abc123"}
+
+		err := mailer.Send(context.Background(), message)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "From: no-reply@example.com")
+		assert.Contains(t, out.String(), "To: user@example.com")
+		assert.Contains(t, out.String(), "Subject: Hello")
+		assert.Contains(t, out.String(), "Reset token: abc123")
+	})
+
+	t.Run("Send_CancelledContext", func(t *testing.T) {
+		out.Reset()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := mailer.Send(ctx, domain.MailMessage{To: "user@example.com"})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, out.String(), "Nothing should be written for a cancelled send")
+	})
+}
@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"io"
+	"log/slog"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Builds the service's logger from the log settings, which Validate has already checked
+func NewLogger(config LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(config.Level))
+
+	options := &slog.HandlerOptions{Level: level}
+	if config.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// Logs one line per request once it has been handled, through the request's logger, so the line
+// carries the request ID and, after authentication, the username
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		domain.LoggerFromContext(c.Request.Context()).LogAttrs(c.Request.Context(), level, "request handled",
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		)
+	}
+}
@@ -0,0 +1,111 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Decodes JSON log output, one entry per line
+func logEntries(t *testing.T, output *bytes.Buffer) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestNewLogger(t *testing.T) {
+	t.Run("JSONAboveLevel", func(t *testing.T) {
+		var output bytes.Buffer
+		logger := infrastructure.NewLogger(infrastructure.LogConfig{Level: "warn", Format: "json"}, &output)
+
+		logger.Info("not written")
+		logger.Warn("written", slog.String("key", "value"))
+
+		entries := logEntries(t, &output)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "written", entries[0]["msg"])
+		assert.Equal(t, "WARN", entries[0]["level"])
+		assert.Equal(t, "value", entries[0]["key"])
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var output bytes.Buffer
+		logger := infrastructure.NewLogger(infrastructure.LogConfig{Level: "debug", Format: "text"}, &output)
+
+		logger.Debug("hello", slog.String("key", "value"))
+
+		assert.Contains(t, output.String(), "level=DEBUG msg=hello key=value")
+	})
+}
+
+func TestAccessLog_CarriesRequestIDAndUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var output bytes.Buffer
+	logger := infrastructure.NewLogger(infrastructure.LogConfig{Level: "info", Format: "json"}, &output)
+
+	router := gin.New()
+	router.Use(infrastructure.RequestMetadata(), infrastructure.AccessLog())
+	router.GET("/tasks/:id", func(c *gin.Context) {
+		// Stands in for the auth middleware, which adds the username the same way
+		ctx := domain.WithLogger(c.Request.Context(), domain.LoggerFromContext(c.Request.Context()).With(slog.String("user", "testuser")))
+		c.Request = c.Request.WithContext(ctx)
+
+		domain.LoggerFromContext(ctx).Info("loading task")
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/42", nil)
+	req.Header.Set(infrastructure.RequestIDHeader, "trace-me")
+	req = req.WithContext(domain.WithLogger(req.Context(), logger))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logEntries(t, &output)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "trace-me", entry["request_id"], "Every line of the request carries its ID")
+	}
+	assert.Equal(t, "loading task", entries[0]["msg"])
+
+	accessLine := entries[1]
+	assert.Equal(t, "request handled", accessLine["msg"])
+	assert.Equal(t, "testuser", accessLine["user"])
+	assert.Equal(t, "GET", accessLine["method"])
+	assert.Equal(t, "/tasks/:id", accessLine["route"])
+	assert.Equal(t, "/tasks/42", accessLine["path"])
+	assert.Equal(t, float64(http.StatusOK), accessLine["status"])
+}
+
+func TestAccessLog_ServerErrorsLoggedAsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var output bytes.Buffer
+	logger := infrastructure.NewLogger(infrastructure.LogConfig{Level: "info", Format: "json"}, &output)
+
+	router := gin.New()
+	router.Use(infrastructure.RequestMetadata(), infrastructure.AccessLog())
+	router.GET("/broken", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	req, _ := http.NewRequest(http.MethodGet, "/broken", nil)
+	req = req.WithContext(domain.WithLogger(req.Context(), logger))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logEntries(t, &output)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "ERROR", entries[0]["level"])
+}
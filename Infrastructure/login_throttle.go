@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"context"
+	"strings"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Thresholds for locking out usernames and client IPs after failed logins.
+// Each lockout past the threshold doubles, from BaseLockout up to MaxLockout.
+type LoginThrottlePolicy struct {
+	MaxAttemptsPerUser int
+	MaxAttemptsPerIP   int           // Higher than per user, since many users can share an IP
+	BaseLockout        time.Duration // First lockout
+	MaxLockout         time.Duration // Cap for the doubling lockouts
+	Window             time.Duration // Failures older than this are forgotten
+}
+
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		MaxAttemptsPerUser: 5,
+		MaxAttemptsPerIP:   20,
+		BaseLockout:        time.Minute,
+		MaxLockout:         time.Hour,
+		Window:             15 * time.Minute,
+	}
+}
+
+type attemptRecord struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// In-memory LoginThrottle. Counts are per process; lockouts are also recorded on the user
+// by the user usecase, so they survive restarts.
+type loginThrottle struct {
+	mu        sync.Mutex
+	policy    LoginThrottlePolicy
+	records   map[string]*attemptRecord
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewLoginThrottle(policy LoginThrottlePolicy) domain.LoginThrottle {
+	return &loginThrottle{
+		policy:  policy,
+		records: make(map[string]*attemptRecord),
+		now:     time.Now,
+	}
+}
+
+func userKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
+
+// Longest remaining lockout of the username and the client IP
+func (throttle *loginThrottle) RetryAfter(ctx context.Context, username, clientIP string) time.Duration {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	now := throttle.now()
+	wait := throttle.remainingLockout(userKey(username), now)
+
+	if clientIP != "" {
+		if ipWait := throttle.remainingLockout(ipKey(clientIP), now); ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	return wait
+}
+
+func (throttle *loginThrottle) RecordFailure(ctx context.Context, username, clientIP string) (int, time.Time) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	now := throttle.now()
+	throttle.pruneExpired(now)
+
+	userRecord := throttle.recordFailure(userKey(username), throttle.policy.MaxAttemptsPerUser, now)
+
+	if clientIP != "" {
+		throttle.recordFailure(ipKey(clientIP), throttle.policy.MaxAttemptsPerIP, now)
+	}
+
+	return userRecord.failures, userRecord.lockedUntil
+}
+
+// A successful login clears the username's history. The IP keeps its count, so one
+// valid account cannot be used to reset the counter while guessing others.
+func (throttle *loginThrottle) RecordSuccess(ctx context.Context, username, clientIP string) {
+	throttle.Reset(ctx, username)
+}
+
+func (throttle *loginThrottle) Reset(ctx context.Context, username string) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	delete(throttle.records, userKey(username))
+}
+
+func (throttle *loginThrottle) remainingLockout(key string, now time.Time) time.Duration {
+	record, ok := throttle.records[key]
+	if !ok || !record.lockedUntil.After(now) {
+		return 0
+	}
+
+	return record.lockedUntil.Sub(now)
+}
+
+func (throttle *loginThrottle) recordFailure(key string, maxAttempts int, now time.Time) *attemptRecord {
+	record, ok := throttle.records[key]
+	if !ok || now.Sub(record.lastFailure) > throttle.policy.Window && !record.lockedUntil.After(now) {
+		record = &attemptRecord{}
+		throttle.records[key] = record
+	}
+
+	record.failures++
+	record.lastFailure = now
+
+	if record.failures >= maxAttempts {
+		record.lockedUntil = now.Add(throttle.lockoutFor(record.failures - maxAttempts))
+	}
+
+	return record
+}
+
+// BaseLockout doubled once per failure past the threshold, capped at MaxLockout
+func (throttle *loginThrottle) lockoutFor(failuresPastThreshold int) time.Duration {
+	lockout := throttle.policy.BaseLockout
+	for i := 0; i < failuresPastThreshold && lockout < throttle.policy.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	if lockout > throttle.policy.MaxLockout {
+		lockout = throttle.policy.MaxLockout
+	}
+
+	return lockout
+}
+
+// Drop records that are neither locked nor inside the window, at most once per window
+func (throttle *loginThrottle) pruneExpired(now time.Time) {
+	if now.Sub(throttle.lastPrune) < throttle.policy.Window {
+		return
+	}
+	throttle.lastPrune = now
+
+	for key, record := range throttle.records {
+		if now.Sub(record.lastFailure) > throttle.policy.Window && !record.lockedUntil.After(now) {
+			delete(throttle.records, key)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package infrastructure_test
+
+import (
+	"context"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	ctx := context.Background()
+	policy := infrastructure.LoginThrottlePolicy{
+		MaxAttemptsPerUser: 3,
+		MaxAttemptsPerIP:   5,
+		BaseLockout:        time.Minute,
+		MaxLockout:         4 * time.Minute,
+		Window:             15 * time.Minute,
+	}
+
+	t.Run("LocksUserAfterThreshold", func(t *testing.T) {
+		throttle := infrastructure.NewLoginThrottle(policy)
+
+		for attempt := 1; attempt < policy.MaxAttemptsPerUser; attempt++ {
+			failures, lockedUntil := throttle.RecordFailure(ctx, "victim", "198.51.100.1")
+			assert.Equal(t, attempt, failures)
+			assert.True(t, lockedUntil.IsZero(), "Should not lock before the threshold")
+			assert.Zero(t, throttle.RetryAfter(ctx, "victim", "198.51.100.1"))
+		}
+
+		failures, lockedUntil := throttle.RecordFailure(ctx, "victim", "198.51.100.1")
+		assert.Equal(t, policy.MaxAttemptsPerUser, failures)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), lockedUntil, time.Second)
+
+		// The lock applies to the username from any IP, and is case-insensitive
+		wait := throttle.RetryAfter(ctx, "VICTIM", "192.0.2.50")
+		assert.InDelta(t, time.Minute.Seconds(), wait.Seconds(), 1)
+	})
+
+	t.Run("LockoutDoublesUpToMax", func(t *testing.T) {
+		throttle := infrastructure.NewLoginThrottle(policy)
+
+		var lockouts []time.Duration
+		for attempt := 1; attempt <= policy.MaxAttemptsPerUser+3; attempt++ {
+			_, lockedUntil := throttle.RecordFailure(ctx, "victim", "")
+			if !lockedUntil.IsZero() {
+				lockouts = append(lockouts, time.Until(lockedUntil).Round(time.Minute))
+			}
+		}
+
+		assert.Equal(t, []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute}, lockouts)
+	})
+
+	t.Run("LocksIPAcrossUsernames", func(t *testing.T) {
+		throttle := infrastructure.NewLoginThrottle(policy)
+
+		for i := 0; i < policy.MaxAttemptsPerIP; i++ {
+			throttle.RecordFailure(ctx, "user"+string(rune('a'+i)), "203.0.113.9")
+		}
+
+		assert.Greater(t, throttle.RetryAfter(ctx, "freshuser", "203.0.113.9"), time.Duration(0), "IP should be locked")
+		assert.Zero(t, throttle.RetryAfter(ctx, "freshuser", "203.0.113.10"), "Other IPs are unaffected")
+	})
+
+	t.Run("SuccessAndResetClearUser", func(t *testing.T) {
+		throttle := infrastructure.NewLoginThrottle(policy)
+
+		for i := 0; i < policy.MaxAttemptsPerUser; i++ {
+			throttle.RecordFailure(ctx, "victim", "")
+		}
+		require.Greater(t, throttle.RetryAfter(ctx, "victim", ""), time.Duration(0))
+
+		throttle.Reset(ctx, "victim")
+		assert.Zero(t, throttle.RetryAfter(ctx, "victim", ""))
+
+		throttle.RecordFailure(ctx, "victim", "")
+		throttle.RecordSuccess(ctx, "victim", "")
+		failures, _ := throttle.RecordFailure(ctx, "victim", "")
+		assert.Equal(t, 1, failures, "A successful login should start the count again")
+	})
+}
@@ -0,0 +1,199 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "task_manager"
+
+// Time allowed for counting tasks when metrics are scraped
+const taskCountTimeout = 5 * time.Second
+
+// How long task counts are reused, so frequent scrapes do not each count every task
+const taskCountCacheTTL = 30 * time.Second
+
+// Prometheus metrics for the service, kept in their own registry
+type Metrics struct {
+	registry           *prometheus.Registry
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	logins             *prometheus.CounterVec
+	repositoryDuration *prometheus.HistogramVec
+}
+
+var _ domain.AuthMetrics = (*Metrics)(nil)
+
+func NewMetrics() *Metrics {
+	metrics := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests, by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "logins_total",
+			Help:      "Login attempts by method and outcome.",
+		}, []string{"method", "outcome"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Time taken by repository operations, i.e. database queries, by repository, method and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"repository", "operation", "outcome"}),
+	}
+
+	metrics.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.httpRequests,
+		metrics.httpDuration,
+		metrics.logins,
+		metrics.repositoryDuration,
+	)
+
+	return metrics
+}
+
+// Serves the metrics in the Prometheus text format. When bearerToken is set, scrapers must send it
+// in the Authorization header.
+func (metrics *Metrics) Handler(bearerToken string) gin.HandlerFunc {
+	handler := promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{
+		ErrorLog:      slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+		ErrorHandling: promhttp.ContinueOnError, // Serve what could be collected
+	})
+
+	return func(c *gin.Context) {
+		if bearerToken != "" {
+			given := []byte(c.GetHeader("Authorization"))
+			if subtle.ConstantTimeCompare(given, []byte("Bearer "+bearerToken)) != 1 {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid metrics token"})
+				return
+			}
+		}
+
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// Counts and times every request. Routes are labelled by their template (/tasks/:id), so IDs do not
+// create new series; requests matching no route share one label.
+func (metrics *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		labels := prometheus.Labels{"method": c.Request.Method, "route": route, "status": strconv.Itoa(c.Writer.Status())}
+
+		metrics.httpRequests.With(labels).Inc()
+		metrics.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	}
+}
+
+func (metrics *Metrics) ObserveLogin(method, outcome string) {
+	metrics.logins.WithLabelValues(method, outcome).Inc()
+}
+
+// Records how long a repository method took, and logs the call at debug level through the request's
+// logger. Meant to be deferred with a pointer to the named error result.
+func (metrics *Metrics) observeRepository(ctx context.Context, repository, operation string, start time.Time, err *error) {
+	duration := time.Since(start)
+	outcome := "success"
+	if *err != nil {
+		outcome = "error"
+	}
+
+	metrics.repositoryDuration.WithLabelValues(repository, operation, outcome).Observe(duration.Seconds())
+
+	logger := domain.LoggerFromContext(ctx)
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		attrs := []slog.Attr{
+			slog.String("repository", repository),
+			slog.String("operation", operation),
+			slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+		}
+		if *err != nil {
+			attrs = append(attrs, slog.Any("error", *err))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "repository operation", attrs...)
+	}
+}
+
+// Reports the number of tasks with each status, counted from the repository when scraped at most
+// once every taskCountCacheTTL
+func (metrics *Metrics) RegisterTaskCounts(repo domain.TaskRepository) {
+	metrics.registry.MustRegister(&taskCountCollector{
+		repo: repo,
+		desc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "tasks"), "Number of tasks by status.", []string{"status"}, nil),
+	})
+}
+
+type taskCountCollector struct {
+	repo domain.TaskRepository
+	desc *prometheus.Desc
+
+	mu        sync.Mutex // Held while counting, so concurrent scrapes share one count
+	counts    map[string]int64
+	countedAt time.Time
+}
+
+func (collector *taskCountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.desc
+}
+
+func (collector *taskCountCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := collector.taskCounts()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(collector.desc, err)
+		return
+	}
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(collector.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
+
+// The cached counts, counted again once they are older than taskCountCacheTTL. Failures are not cached.
+func (collector *taskCountCollector) taskCounts() (map[string]int64, error) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	if collector.counts != nil && time.Since(collector.countedAt) < taskCountCacheTTL {
+		return collector.counts, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskCountTimeout)
+	defer cancel()
+
+	counts, err := collector.repo.CountTasksByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collector.counts, collector.countedAt = counts, time.Now()
+
+	return counts, nil
+}
@@ -0,0 +1,133 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns the metrics in the Prometheus text format
+func scrapeMetrics(t *testing.T, router *gin.Engine, authorization string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("CountsRequestsByRouteTemplate", func(t *testing.T) {
+		metrics := infrastructure.NewMetrics()
+		router := gin.New()
+		router.Use(metrics.Middleware())
+		router.GET("/tasks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/metrics", metrics.Handler(""))
+
+		for _, path := range []string{"/tasks/1", "/tasks/2", "/nowhere"} {
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		body := scrapeMetrics(t, router, "").Body.String()
+		assert.Contains(t, body, `task_manager_http_requests_total{method="GET",route="/tasks/:id",status="200"} 2`)
+		assert.Contains(t, body, `task_manager_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		assert.Contains(t, body, `task_manager_http_request_duration_seconds_count{method="GET",route="/tasks/:id",status="200"} 2`)
+	})
+
+	t.Run("CountsLogins", func(t *testing.T) {
+		metrics := infrastructure.NewMetrics()
+		router := gin.New()
+		router.GET("/metrics", metrics.Handler(""))
+
+		metrics.ObserveLogin("password", domain.LoginOutcomeSuccess)
+		metrics.ObserveLogin("password", domain.LoginOutcomeInvalidCredentials)
+		metrics.ObserveLogin("password", domain.LoginOutcomeInvalidCredentials)
+
+		body := scrapeMetrics(t, router, "").Body.String()
+		assert.Contains(t, body, `task_manager_logins_total{method="password",outcome="success"} 1`)
+		assert.Contains(t, body, `task_manager_logins_total{method="password",outcome="invalid_credentials"} 2`)
+	})
+
+	t.Run("BearerTokenRequiredWhenSet", func(t *testing.T) {
+		metrics := infrastructure.NewMetrics()
+		router := gin.New()
+		router.GET("/metrics", metrics.Handler("scrape-secret"))
+
+		assert.Equal(t, http.StatusUnauthorized, scrapeMetrics(t, router, "").Code)
+		assert.Equal(t, http.StatusUnauthorized, scrapeMetrics(t, router, "Bearer wrong").Code)
+		assert.Equal(t, http.StatusOK, scrapeMetrics(t, router, "Bearer scrape-secret").Code)
+	})
+
+	t.Run("TimesRepositoryOperations", func(t *testing.T) {
+		metrics := infrastructure.NewMetrics()
+		router := gin.New()
+		router.GET("/metrics", metrics.Handler(""))
+		mockTaskRepo := mocks.NewMockTaskRepository(t)
+		mockUserRepo := mocks.NewMockUserRepository(t)
+		taskRepo := infrastructure.NewInstrumentedTaskRepository(mockTaskRepo, metrics)
+		userRepo := infrastructure.NewInstrumentedUserRepository(mockUserRepo, metrics)
+
+		mockTaskRepo.EXPECT().GetTaskByID(mock.Anything, "1").Return(domain.Task{Title: "Task"}, nil).Once()
+		mockUserRepo.EXPECT().FindUserByUsername(mock.Anything, "ghost").Return(nil, domain.ErrUserNotFound).Once()
+
+		task, err := taskRepo.GetTaskByID(context.Background(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, "Task", task.Title, "Results are passed through")
+		_, err = userRepo.FindUserByUsername(context.Background(), "ghost")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Errors are passed through")
+
+		body := scrapeMetrics(t, router, "").Body.String()
+		assert.Contains(t, body, `task_manager_repository_operation_duration_seconds_count{operation="GetTaskByID",outcome="success",repository="task"} 1`)
+		assert.Contains(t, body, `task_manager_repository_operation_duration_seconds_count{operation="FindUserByUsername",outcome="error",repository="user"} 1`)
+	})
+
+	t.Run("ReportsTaskCountsOnScrape", func(t *testing.T) {
+		metrics := infrastructure.NewMetrics()
+		router := gin.New()
+		router.GET("/metrics", metrics.Handler(""))
+		mockTaskRepo := mocks.NewMockTaskRepository(t)
+		metrics.RegisterTaskCounts(mockTaskRepo)
+
+		mockTaskRepo.EXPECT().CountTasksByStatus(mock.Anything).Return(map[string]int64{"Todo": 3, "Done": 1}, nil).Once()
+
+		body := scrapeMetrics(t, router, "").Body.String()
+		assert.Contains(t, body, `task_manager_tasks{status="Todo"} 3`)
+		assert.Contains(t, body, `task_manager_tasks{status="Done"} 1`)
+
+		body = scrapeMetrics(t, router, "").Body.String()
+		assert.Contains(t, body, `task_manager_tasks{status="Todo"} 3`, "A scrape soon after reuses the counts instead of counting again")
+	})
+
+	t.Run("TaskCountFailureKeepsOtherMetrics", func(t *testing.T) {
+		metrics := infrastructure.NewMetrics()
+		router := gin.New()
+		router.GET("/metrics", metrics.Handler(""))
+		mockTaskRepo := mocks.NewMockTaskRepository(t)
+		metrics.RegisterTaskCounts(mockTaskRepo)
+		metrics.ObserveLogin("password", domain.LoginOutcomeSuccess)
+
+		mockTaskRepo.EXPECT().CountTasksByStatus(mock.Anything).Return(nil, errors.New("database unavailable")).Once()
+
+		rr := scrapeMetrics(t, router, "")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `task_manager_logins_total{method="password",outcome="success"} 1`)
+		assert.NotContains(t, rr.Body.String(), `task_manager_tasks{`)
+
+		mockTaskRepo.EXPECT().CountTasksByStatus(mock.Anything).Return(map[string]int64{"Todo": 2}, nil).Once()
+		assert.Contains(t, scrapeMetrics(t, router, "").Body.String(), `task_manager_tasks{status="Todo"} 2`, "Failures are not cached")
+	})
+}
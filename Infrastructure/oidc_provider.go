@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	domain "task_manager/Domain"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type oidcProvider struct {
+	oauthConfig *oauth2.Config
+	verifier    *oidc.IDTokenVerifier
+}
+
+// Discovers the provider's endpoints and signing keys from issuerURL/.well-known/openid-configuration.
+// redirectURL is this service's callback route, registered with the provider.
+func NewOIDCProvider(ctx context.Context, issuerURL, clientID, clientSecret, redirectURL string) (domain.OIDCProvider, error) {
+	provider, err := oidc.NewProvider(ctx, issuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OpenID Connect provider: %w", err)
+	}
+
+	return &oidcProvider{
+		oauthConfig: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: clientID}),
+	}, nil
+}
+
+func (provider *oidcProvider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return provider.oauthConfig.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+// Redeem the code, then check the ID token's signature against the provider's keys,
+// its issuer, audience and expiry, and that it belongs to this login attempt
+func (provider *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCIdentity, error) {
+	token, err := provider.oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrSSOFailed, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: no ID token in the token response", domain.ErrSSOFailed)
+	}
+
+	idToken, err := provider.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrSSOFailed, err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%w: ID token nonce does not match", domain.ErrSSOFailed)
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrSSOFailed, err)
+	}
+
+	return &domain.OIDCIdentity{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
@@ -0,0 +1,236 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	standInClientID     = "task-manager"
+	standInClientSecret = "stand-in-secret"
+	standInRedirectURL  = "http://localhost:8080/users/oidc/callback"
+)
+
+// Minimal OpenID Connect provider: discovery, JWKS, an authorize endpoint that logs the
+// user in straight away, and a token endpoint that checks the PKCE verifier.
+type standInProvider struct {
+	server     *httptest.Server
+	key        *rsa.PrivateKey // Published in the JWKS
+	signingKey *rsa.PrivateKey // Signs ID tokens, normally the same as key
+	subject    string
+
+	mu    sync.Mutex
+	codes map[string]url.Values // Authorization code -> the authorize request it was issued for
+}
+
+func newStandInProvider(t *testing.T) *standInProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider := &standInProvider{key: key, signingKey: key, subject: "stand-in-subject", codes: map[string]url.Values{}}
+
+	router := gin.New()
+	router.GET("/.well-known/openid-configuration", provider.discovery)
+	router.GET("/jwks", provider.jwks)
+	router.GET("/authorize", provider.authorize)
+	router.POST("/token", provider.token)
+
+	provider.server = httptest.NewServer(router)
+	t.Cleanup(provider.server.Close)
+
+	return provider
+}
+
+func (provider *standInProvider) discovery(c *gin.Context) {
+	issuer := provider.server.URL
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (provider *standInProvider) jwks(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"keys": []gin.H{{
+		"kty": "RSA",
+		"alg": "RS256",
+		"use": "sig",
+		"kid": "stand-in",
+		"n":   base64.RawURLEncoding.EncodeToString(provider.key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(provider.key.E)).Bytes()),
+	}}})
+}
+
+// Skips the login page and redirects back with a code
+func (provider *standInProvider) authorize(c *gin.Context) {
+	query := c.Request.URL.Query()
+	code := rand.Text()
+
+	provider.mu.Lock()
+	provider.codes[code] = query
+	provider.mu.Unlock()
+
+	c.Redirect(http.StatusFound, query.Get("redirect_uri")+"?"+url.Values{"code": {code}, "state": {query.Get("state")}}.Encode())
+}
+
+func (provider *standInProvider) token(c *gin.Context) {
+	clientID, clientSecret, _ := c.Request.BasicAuth()
+	if clientID != standInClientID || clientSecret != standInClientSecret {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+
+	provider.mu.Lock()
+	request, ok := provider.codes[c.PostForm("code")]
+	delete(provider.codes, c.PostForm("code"))
+	provider.mu.Unlock()
+
+	if !ok || c.PostForm("redirect_uri") != request.Get("redirect_uri") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(c.PostForm("code_verifier")))
+	if request.Get("code_challenge_method") != "S256" || base64.RawURLEncoding.EncodeToString(challenge[:]) != request.Get("code_challenge") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                provider.server.URL,
+		"sub":                provider.subject,
+		"aud":                standInClientID,
+		"iat":                time.Now().Unix(),
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              request.Get("nonce"),
+		"email":              "sso.user@example.com",
+		"email_verified":     true,
+		"preferred_username": "sso.user",
+	})
+	idToken.Header["kid"] = "stand-in"
+
+	signed, err := idToken.SignedString(provider.signingKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"access_token": "stand-in-access-token", "token_type": "Bearer", "expires_in": 3600, "id_token": signed})
+}
+
+// Follows AuthCodeURL like a browser would and returns the code the provider redirected back with
+func loginAtProvider(t *testing.T, authURL, expectedState string) string {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, expectedState, location.Query().Get("state"))
+
+	return location.Query().Get("code")
+}
+
+func TestOIDCProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	standIn := newStandInProvider(t)
+	ctx := context.Background()
+
+	provider, err := infrastructure.NewOIDCProvider(ctx, standIn.server.URL, standInClientID, standInClientSecret, standInRedirectURL)
+	require.NoError(t, err)
+
+	t.Run("AuthCodeURL_UsesPKCE", func(t *testing.T) {
+		authURL, err := url.Parse(provider.AuthCodeURL("state-1", "nonce-1", "verifier-1"))
+		require.NoError(t, err)
+
+		query := authURL.Query()
+		assert.Equal(t, standIn.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+		assert.Equal(t, "code", query.Get("response_type"))
+		assert.Equal(t, standInRedirectURL, query.Get("redirect_uri"))
+		assert.Contains(t, query.Get("scope"), "openid")
+		assert.Equal(t, "nonce-1", query.Get("nonce"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.NotEmpty(t, query.Get("code_challenge"))
+		assert.NotContains(t, authURL.String(), "verifier-1", "The verifier must never leave the server")
+	})
+
+	t.Run("Exchange_Success", func(t *testing.T) {
+		code := loginAtProvider(t, provider.AuthCodeURL("state-2", "nonce-2", "verifier-for-a-successful-login"), "state-2")
+
+		identity, err := provider.Exchange(ctx, code, "verifier-for-a-successful-login", "nonce-2")
+		require.NoError(t, err)
+
+		assert.Equal(t, &domain.OIDCIdentity{
+			Issuer:            standIn.server.URL,
+			Subject:           "stand-in-subject",
+			Email:             "sso.user@example.com",
+			EmailVerified:     true,
+			PreferredUsername: "sso.user",
+		}, identity)
+	})
+
+	t.Run("Exchange_WrongCodeVerifier", func(t *testing.T) {
+		code := loginAtProvider(t, provider.AuthCodeURL("state-3", "nonce-3", "the-real-verifier"), "state-3")
+
+		_, err := provider.Exchange(ctx, code, "an-intercepted-code-without-the-verifier", "nonce-3")
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("Exchange_WrongNonce", func(t *testing.T) {
+		code := loginAtProvider(t, provider.AuthCodeURL("state-4", "nonce-4", "verifier-4"), "state-4")
+
+		_, err := provider.Exchange(ctx, code, "verifier-4", "nonce-of-another-login")
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("Exchange_CodeUsedTwice", func(t *testing.T) {
+		code := loginAtProvider(t, provider.AuthCodeURL("state-5", "nonce-5", "verifier-5"), "state-5")
+
+		_, err := provider.Exchange(ctx, code, "verifier-5", "nonce-5")
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, code, "verifier-5", "nonce-5")
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("Exchange_IDTokenNotSignedByProvider", func(t *testing.T) {
+		forgedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		standIn.signingKey = forgedKey
+		defer func() { standIn.signingKey = standIn.key }()
+
+		code := loginAtProvider(t, provider.AuthCodeURL("state-6", "nonce-6", "verifier-6"), "state-6")
+
+		_, err = provider.Exchange(ctx, code, "verifier-6", "nonce-6")
+		assert.ErrorIs(t, err, domain.ErrSSOFailed)
+	})
+
+	t.Run("Discovery_IssuerMismatch", func(t *testing.T) {
+		// The discovery document names a different issuer than the one configured
+		_, err := infrastructure.NewOIDCProvider(ctx, standIn.server.URL+"/", standInClientID, standInClientSecret, standInRedirectURL)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,143 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"strings"
+	domain "task_manager/Domain"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rules for new passwords. Zero values switch a rule off.
+type PasswordPolicy struct {
+	MinLength           int  // In characters
+	MaxLength           int  // In characters; bounds the cost of hashing
+	MinCharacterClasses int  // Of lower case, upper case, digits and symbols
+	ForbidUsername      bool // Reject passwords containing the username
+	ForbidCommon        bool // Reject the passwords in common_passwords.txt
+}
+
+// Follows NIST SP 800-63B: length and blocklists rather than composition rules
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:      8,
+		MaxLength:      128,
+		ForbidUsername: true,
+		ForbidCommon:   true,
+	}
+}
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = loadCommonPasswords(commonPasswordsFile)
+
+func loadCommonPasswords(list string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+
+	return passwords
+}
+
+type passwordValidator struct {
+	policy   PasswordPolicy
+	breached domain.BreachedPasswordChecker
+}
+
+// breached is optional; without it passwords are not checked against breaches
+func NewPasswordValidator(policy PasswordPolicy, breached domain.BreachedPasswordChecker) domain.PasswordValidator {
+	return &passwordValidator{policy: policy, breached: breached}
+}
+
+// Check every rule and report all the ones the password breaks
+func (validator *passwordValidator) Validate(ctx context.Context, password, username string) error {
+	var violations []domain.PasswordViolation
+	policy := validator.policy
+	length := utf8.RuneCountInString(password)
+
+	if policy.MinLength > 0 && length < policy.MinLength {
+		violations = append(violations, domain.PasswordViolation{
+			Rule:    domain.PasswordRuleMinLength,
+			Message: fmt.Sprintf("must be at least %d characters long", policy.MinLength),
+		})
+	}
+
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		violations = append(violations, domain.PasswordViolation{
+			Rule:    domain.PasswordRuleMaxLength,
+			Message: fmt.Sprintf("must be at most %d characters long", policy.MaxLength),
+		})
+	}
+
+	if policy.MinCharacterClasses > 0 && characterClasses(password) < policy.MinCharacterClasses {
+		violations = append(violations, domain.PasswordViolation{
+			Rule:    domain.PasswordRuleCharacterClasses,
+			Message: fmt.Sprintf("must use at least %d of lower case letters, upper case letters, digits and symbols", policy.MinCharacterClasses),
+		})
+	}
+
+	if policy.ForbidUsername && len(username) >= 3 && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		violations = append(violations, domain.PasswordViolation{
+			Rule:    domain.PasswordRuleContainsUsername,
+			Message: "must not contain the username",
+		})
+	}
+
+	if policy.ForbidCommon {
+		if _, common := commonPasswords[strings.ToLower(password)]; common {
+			violations = append(violations, domain.PasswordViolation{
+				Rule:    domain.PasswordRuleCommonPassword,
+				Message: "is too common",
+			})
+		}
+	}
+
+	// An unavailable breach service must not stop users from setting passwords
+	if validator.breached != nil {
+		breached, err := validator.breached.IsBreached(ctx, password)
+		if err != nil {
+			domain.LoggerFromContext(ctx).Warn("breached password check failed", slog.Any("error", err))
+		} else if breached {
+			violations = append(violations, domain.PasswordViolation{
+				Rule:    domain.PasswordRuleBreachedPassword,
+				Message: "has appeared in a data breach",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
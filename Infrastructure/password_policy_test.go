@@ -0,0 +1,99 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Rules reported by a failed validation, in order
+func violatedRules(t *testing.T, err error) []string {
+	t.Helper()
+
+	var policyErr *domain.PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	require.ErrorIs(t, err, domain.ErrWeakPassword)
+
+	rules := make([]string, 0, len(policyErr.Violations))
+	for _, violation := range policyErr.Violations {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func TestPasswordValidator(t *testing.T) {
+	ctx := context.Background()
+	validator := infrastructure.NewPasswordValidator(infrastructure.DefaultPasswordPolicy(), nil)
+
+	t.Run("AcceptsLongUncommonPassword", func(t *testing.T) {
+		assert.NoError(t, validator.Validate(ctx, "correct horse battery staple", "testuser"))
+	})
+
+	t.Run("ReportsEveryViolation", func(t *testing.T) {
+		err := validator.Validate(ctx, "qwerty", "testuser")
+
+		assert.Equal(t, []string{domain.PasswordRuleMinLength, domain.PasswordRuleCommonPassword}, violatedRules(t, err))
+	})
+
+	t.Run("CommonPassword_IgnoresCase", func(t *testing.T) {
+		err := validator.Validate(ctx, "PassWord", "testuser")
+
+		assert.Equal(t, []string{domain.PasswordRuleCommonPassword}, violatedRules(t, err))
+	})
+
+	t.Run("ContainsUsername", func(t *testing.T) {
+		err := validator.Validate(ctx, "my-TestUser-password!", "testuser")
+
+		assert.Equal(t, []string{domain.PasswordRuleContainsUsername}, violatedRules(t, err))
+	})
+
+	t.Run("MaxLength", func(t *testing.T) {
+		long := make([]rune, 129)
+		for i := range long {
+			long[i] = 'é' // Counted as characters, not bytes
+		}
+
+		err := validator.Validate(ctx, string(long), "testuser")
+
+		assert.Equal(t, []string{domain.PasswordRuleMaxLength}, violatedRules(t, err))
+	})
+
+	t.Run("CharacterClasses", func(t *testing.T) {
+		policy := infrastructure.DefaultPasswordPolicy()
+		policy.MinCharacterClasses = 3
+		strict := infrastructure.NewPasswordValidator(policy, nil)
+
+		err := strict.Validate(ctx, "onlylowercaseletters", "testuser")
+		assert.Equal(t, []string{domain.PasswordRuleCharacterClasses}, violatedRules(t, err))
+
+		assert.NoError(t, strict.Validate(ctx, "Lower-and-UPPER-and-symbols", "testuser"))
+	})
+}
+
+func TestPasswordValidator_BreachedPasswords(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RejectsBreachedPassword", func(t *testing.T) {
+		checker := mocks.NewMockBreachedPasswordChecker(t)
+		checker.EXPECT().IsBreached(ctx, "correct horse battery staple").Return(true, nil).Once()
+		validator := infrastructure.NewPasswordValidator(infrastructure.DefaultPasswordPolicy(), checker)
+
+		err := validator.Validate(ctx, "correct horse battery staple", "testuser")
+
+		assert.Equal(t, []string{domain.PasswordRuleBreachedPassword}, violatedRules(t, err))
+	})
+
+	t.Run("CheckerError_FailsOpen", func(t *testing.T) {
+		checker := mocks.NewMockBreachedPasswordChecker(t)
+		checker.EXPECT().IsBreached(ctx, "correct horse battery staple").Return(false, errors.New("service unavailable")).Once()
+		validator := infrastructure.NewPasswordValidator(infrastructure.DefaultPasswordPolicy(), checker)
+
+		assert.NoError(t, validator.Validate(ctx, "correct horse battery staple", "testuser"))
+	})
+}
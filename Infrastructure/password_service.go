@@ -28,3 +28,10 @@ func (service *passwordService) ComparePasswords(hashedPassword, plaintextPasswo
 
 	return err
 }
+
+// Hashes made with a lower cost than the current default should be replaced
+func (service *passwordService) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+
+	return err != nil || cost < bcrypt.DefaultCost
+}
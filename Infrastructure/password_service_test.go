@@ -119,4 +119,14 @@ func TestPasswordService(t *testing.T) {
 
 	})
 
+	t.Run("NeedsRehash_LowerCost", func(t *testing.T) {
+		cheapHash, err := bcrypt.GenerateFromPassword([]byte("mySecurePassword123"), bcrypt.MinCost)
+		require.NoError(t, err)
+		assert.True(t, passwordService.NeedsRehash(string(cheapHash)), "Hashes below the default cost should be replaced")
+
+		hashedPassword, err := passwordService.HashPassword("mySecurePassword123")
+		require.NoError(t, err)
+		assert.False(t, passwordService.NeedsRehash(hashedPassword))
+	})
+
 }
@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// How often idle buckets are dropped from the in-memory store
+const rateLimitPruneInterval = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time // Once past, the bucket is the same as a new one
+}
+
+// In-memory RateLimitStore. Limits are per process, so use a shared store when running several instances.
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimitStore() domain.RateLimitStore {
+	return &memoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (store *memoryRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.now()
+	store.pruneFull(now)
+
+	bucket, ok := store.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updatedAt: now}
+		store.buckets[key] = bucket
+	}
+
+	// Refill for the time since the last request, up to the burst size
+	elapsed := now.Sub(bucket.updatedAt).Seconds()
+	bucket.tokens = math.Min(float64(limit.Burst), bucket.tokens+elapsed*limit.TokensPerSecond())
+	bucket.updatedAt = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+
+	decision := limit.Decision(bucket.tokens, allowed)
+	bucket.fullAt = now.Add(decision.ResetAfter)
+
+	return decision, nil
+}
+
+// Drop buckets that have refilled completely, at most once per interval
+func (store *memoryRateLimitStore) pruneFull(now time.Time) {
+	if now.Sub(store.lastPrune) < rateLimitPruneInterval {
+		return
+	}
+	store.lastPrune = now
+
+	for key, bucket := range store.buckets {
+		if !bucket.fullAt.After(now) {
+			delete(store.buckets, key)
+		}
+	}
+}
+
+// Builds rate limiting middleware on top of a RateLimitStore
+type RateLimiter struct {
+	store domain.RateLimitStore
+}
+
+func NewRateLimiter(store domain.RateLimitStore) *RateLimiter {
+	return &RateLimiter{store: store}
+}
+
+// Limits requests per authenticated user, or per client IP before authentication. Buckets are
+// separate for each name, so route groups with different limits do not share them.
+// Responses carry the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers; refused
+// requests get 429 with Retry-After. If the store fails, requests are let through.
+func (limiter *RateLimiter) Limit(name string, limit domain.RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := name + ":ip:" + c.ClientIP()
+		if username, _, err := GetUserFromContext(c); err == nil {
+			key = name + ":user:" + username
+		}
+
+		decision, err := limiter.store.Take(c.Request.Context(), key, limit)
+		if err != nil {
+			domain.LoggerFromContext(c.Request.Context()).Warn("rate limit store failed, allowing request", slog.Any("error", err))
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.ResetAfter)))
+
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": domain.ErrRateLimited.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
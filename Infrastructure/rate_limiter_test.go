@@ -0,0 +1,152 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AllowsBurstThenRefuses", func(t *testing.T) {
+		store := infrastructure.NewMemoryRateLimitStore()
+		limit := domain.RateLimit{Rate: 1, Period: time.Minute, Burst: 3}
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			decision, err := store.Take(ctx, "client", limit)
+			require.NoError(t, err)
+			assert.True(t, decision.Allowed)
+			assert.Equal(t, remaining, decision.Remaining)
+		}
+
+		decision, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.InDelta(t, time.Minute.Seconds(), decision.RetryAfter.Seconds(), 1, "One token refills per minute")
+		assert.InDelta(t, 3*time.Minute.Seconds(), decision.ResetAfter.Seconds(), 1)
+
+		other, err := store.Take(ctx, "other-client", limit)
+		require.NoError(t, err)
+		assert.True(t, other.Allowed, "Keys have separate buckets")
+	})
+
+	t.Run("Refills", func(t *testing.T) {
+		store := infrastructure.NewMemoryRateLimitStore()
+		limit := domain.RateLimit{Rate: 1, Period: 20 * time.Millisecond, Burst: 1}
+
+		first, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		require.True(t, first.Allowed)
+
+		refused, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		require.False(t, refused.Allowed)
+
+		time.Sleep(30 * time.Millisecond)
+
+		refilled, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, refilled.Allowed)
+	})
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limit := domain.RateLimit{Rate: 2, Period: time.Minute, Burst: 2}
+
+	newRouter := func(store domain.RateLimitStore, trustedProxies ...string) *gin.Engine {
+		router, err := infrastructure.NewGinEngine(infrastructure.ServerConfig{TrustedProxies: trustedProxies})
+		require.NoError(t, err)
+		limiter := infrastructure.NewRateLimiter(store)
+		setUser := func(c *gin.Context) {
+			if username := c.GetHeader("X-Test-User"); username != "" {
+				c.Set("username", username)
+				c.Set("role", domain.RoleUser)
+			}
+		}
+		router.GET("/limited", setUser, limiter.Limit("test", limit), func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+
+	send := func(router *gin.Engine, clientIP, username string, forwardedFor ...string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = clientIP + ":1234"
+		if username != "" {
+			req.Header.Set("X-Test-User", username)
+		}
+		for _, ip := range forwardedFor {
+			req.Header.Add("X-Forwarded-For", ip)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("PerIP_WithHeaders", func(t *testing.T) {
+		router := newRouter(infrastructure.NewMemoryRateLimitStore())
+
+		first := send(router, "198.51.100.1", "")
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", first.Header().Get("RateLimit-Reset"))
+
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.1", "").Code)
+
+		refused := send(router, "198.51.100.1", "")
+		assert.Equal(t, http.StatusTooManyRequests, refused.Code)
+		assert.Equal(t, "0", refused.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", refused.Header().Get("Retry-After"))
+
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.2", "").Code, "Another IP has its own bucket")
+	})
+
+	t.Run("PerUser_AcrossIPs", func(t *testing.T) {
+		router := newRouter(infrastructure.NewMemoryRateLimitStore())
+
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.1", "alice").Code)
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.2", "alice").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send(router, "198.51.100.3", "alice").Code, "Changing IP does not reset a user's limit")
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.1", "bob").Code)
+	})
+
+	t.Run("SpoofedForwardedFor_KeepsIPKey", func(t *testing.T) {
+		store := mocks.NewMockRateLimitStore(t)
+		store.EXPECT().Take(mock.Anything, "test:ip:198.51.100.1", limit).Return(&domain.RateLimitDecision{Allowed: true}, nil).Twice()
+		router := newRouter(store)
+
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.1", "", "203.0.113.7").Code)
+		assert.Equal(t, http.StatusOK, send(router, "198.51.100.1", "", "203.0.113.8").Code)
+	})
+
+	t.Run("TrustedProxy_KeysByForwardedFor", func(t *testing.T) {
+		store := mocks.NewMockRateLimitStore(t)
+		store.EXPECT().Take(mock.Anything, "test:ip:203.0.113.7", limit).Return(&domain.RateLimitDecision{Allowed: true}, nil).Once()
+
+		rr := send(newRouter(store, "10.0.0.0/8"), "10.1.2.3", "", "203.0.113.7")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("StoreError_AllowsRequest", func(t *testing.T) {
+		store := mocks.NewMockRateLimitStore(t)
+		store.EXPECT().Take(mock.Anything, "test:ip:198.51.100.1", limit).Return(nil, errors.New("db down")).Once()
+
+		rr := send(newRouter(store), "198.51.100.1", "")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	})
+}
@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header carrying the ID that ties a request to its log lines and audit events
+const RequestIDHeader = "X-Request-ID"
+
+// Request IDs accepted from clients or proxies; anything else is replaced
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Stores the client IP, user agent and request ID in the request context so usecases can read them,
+// along with a logger that tags every line with the request ID and, when the request is traced, the trace ID.
+// The client IP only comes from X-Forwarded-For when the engine trusts the proxy that sent it; see NewGinEngine.
+// The request ID is taken from the X-Request-ID header when it has one, and echoed in the response.
+func RequestMetadata() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		meta := domain.RequestMeta{
+			ClientIP:  c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: requestID,
+		}
+
+		ctx := domain.WithRequestMeta(c.Request.Context(), meta)
+		logger := domain.LoggerFromContext(ctx).With(slog.String("request_id", requestID))
+		if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+			span.SetAttributes(attribute.String("request.id", requestID))
+			logger = logger.With(slog.String("trace_id", span.SpanContext().TraceID().String()))
+		}
+		ctx = domain.WithLogger(ctx, logger)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id) // Never fails on supported platforms
+
+	return hex.EncodeToString(id)
+}
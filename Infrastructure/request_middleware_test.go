@@ -0,0 +1,86 @@
+package infrastructure_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMetadata_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(infrastructure.RequestMetadata())
+
+	var meta domain.RequestMeta
+	router.GET("/", func(c *gin.Context) {
+		meta = domain.RequestMetaFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("GeneratedWhenMissing", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("User-Agent", "curl/8.5.0")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Len(t, meta.RequestID, 32)
+		assert.Equal(t, meta.RequestID, rr.Header().Get(infrastructure.RequestIDHeader))
+		assert.Equal(t, "curl/8.5.0", meta.UserAgent)
+	})
+
+	t.Run("TakenFromHeader", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(infrastructure.RequestIDHeader, "upstream-proxy-42")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, "upstream-proxy-42", meta.RequestID)
+		assert.Equal(t, "upstream-proxy-42", rr.Header().Get(infrastructure.RequestIDHeader))
+	})
+
+	t.Run("InvalidHeaderReplaced", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(infrastructure.RequestIDHeader, "bad id\nwith newline")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.NotEqual(t, "bad id\nwith newline", meta.RequestID)
+		assert.Len(t, meta.RequestID, 32)
+	})
+}
+
+func TestRequestMetadata_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(t *testing.T, trustedProxies []string, remoteAddr, forwardedFor string) string {
+		router, err := infrastructure.NewGinEngine(infrastructure.ServerConfig{TrustedProxies: trustedProxies})
+		require.NoError(t, err)
+		router.Use(infrastructure.RequestMetadata())
+
+		var meta domain.RequestMeta
+		router.GET("/", func(c *gin.Context) {
+			meta = domain.RequestMetaFromContext(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		return meta.ClientIP
+	}
+
+	t.Run("SpoofedForwardedForIgnored", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.1", clientIP(t, nil, "198.51.100.1:1234", "203.0.113.7"))
+	})
+
+	t.Run("TrustedProxyForwardsClientIP", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP(t, []string{"10.0.0.0/8"}, "10.1.2.3:1234", "203.0.113.7"))
+	})
+}
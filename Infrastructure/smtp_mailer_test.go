@@ -0,0 +1,112 @@
+package infrastructure_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal SMTP server that accepts a single message and reports what it received
+func startFakeSMTPServer(t *testing.T) (string, int, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "Failed to start fake SMTP server")
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP fake")
+
+		var transcript strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			transcript.WriteString(line)
+
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250-localhost")
+				reply("250 8BITMIME")
+			case strings.HasPrefix(command, "DATA"):
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					transcript.WriteString(dataLine)
+				}
+				reply("250 OK: queued")
+			case strings.HasPrefix(command, "QUIT"):
+				reply("221 Bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, portString, _ := net.SplitHostPort(listener.Addr().String())
+	port, _ := strconv.Atoi(portString)
+	return host, port, received
+}
+
+func TestSMTPMailer(t *testing.T) {
+	t.Run("Send_Success", func(t *testing.T) {
+		host, port, received := startFakeSMTPServer(t)
+		mailer := infrastructure.NewSMTPMailer(host, port, "", "", "no-reply@example.com")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := mailer.Send(ctx, domain.MailMessage{To: "user@example.com", Subject: "Reset\r\nBcc: evil@example.com", Body: "Your token"})
+		require.NoError(t, err, "Send should succeed against a working SMTP server")
+
+		select {
+		case transcript := <-received:
+			assert.Contains(t, transcript, "MAIL FROM:<no-reply@example.com>")
+			assert.Contains(t, transcript, "RCPT TO:<user@example.com>")
+			assert.Contains(t, transcript, "Subject: ResetBcc: evil@example.com\r\n", "Line breaks in headers should be stripped")
+			assert.Contains(t, transcript, "Your token")
+		case <-time.After(5 * time.Second):
+			t.Fatal("Fake SMTP server did not receive the message")
+		}
+	})
+
+	t.Run("Send_ConnectionRefused", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close() // Nothing listens on this port any more
+
+		mailer := infrastructure.NewSMTPMailer("127.0.0.1", port, "", "", "no-reply@example.com")
+		err = mailer.Send(context.Background(), domain.MailMessage{To: "user@example.com"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to connect to SMTP server")
+	})
+}
@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"strings"
+	domain "task_manager/Domain"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// Codes change every 30 seconds, the default of authenticator apps
+const totpPeriod = 30 * time.Second
+
+type totpService struct {
+	issuer string
+}
+
+// issuer is the name authenticator apps show next to the account
+func NewTOTPService(issuer string) domain.TOTPService {
+	return &totpService{issuer: issuer}
+}
+
+// Generate a new secret along with the otpauth:// URI and a QR code of it
+func (service *totpService) GenerateSecret(accountName string) (*domain.TOTPEnrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      service.issuer,
+		AccountName: accountName,
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate TOTP secret")
+	}
+
+	image, err := key.Image(256, 256)
+	if err != nil {
+		return nil, errors.New("failed to render QR code")
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image); err != nil {
+		return nil, errors.New("failed to render QR code")
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret:     key.Secret(),
+		OTPAuthURI: key.URL(),
+		QRCode:     base64.StdEncoding.EncodeToString(buffer.Bytes()),
+	}, nil
+}
+
+// Check a 6 digit code, allowing one step of clock drift either way, and return the step (the
+// number of periods since the Unix epoch) it was generated for
+func (service *totpService) ValidateCode(secret, code string) (int64, bool) {
+	code = strings.TrimSpace(code)
+	now := time.Now()
+
+	for _, drift := range []time.Duration{0, -totpPeriod, totpPeriod} {
+		at := now.Add(drift)
+		expected, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+			Period:    uint(totpPeriod / time.Second),
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / int64(totpPeriod/time.Second), true
+		}
+	}
+
+	return 0, false
+}
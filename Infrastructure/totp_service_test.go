@@ -0,0 +1,65 @@
+package infrastructure_test
+
+import (
+	"encoding/base64"
+	"net/url"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPService(t *testing.T) {
+	totpService := infrastructure.NewTOTPService("Task Manager")
+
+	enrollment, err := totpService.GenerateSecret("testuser")
+	require.NoError(t, err, "GenerateSecret should not return an error")
+	require.NotEmpty(t, enrollment.Secret)
+
+	t.Run("GenerateSecret_URIAndQRCode", func(t *testing.T) {
+		uri, err := url.Parse(enrollment.OTPAuthURI)
+		require.NoError(t, err, "otpauth URI should be parsable")
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, "totp", uri.Host)
+		assert.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+		assert.Equal(t, "Task Manager", uri.Query().Get("issuer"))
+
+		image, err := base64.StdEncoding.DecodeString(enrollment.QRCode)
+		require.NoError(t, err, "QR code should be base64 encoded")
+		assert.Equal(t, "\x89PNG", string(image[:4]), "QR code should be a PNG")
+	})
+
+	t.Run("ValidateCode", func(t *testing.T) {
+		now := time.Now()
+		code, err := totp.GenerateCode(enrollment.Secret, now)
+		require.NoError(t, err)
+
+		step, ok := totpService.ValidateCode(enrollment.Secret, code)
+		assert.True(t, ok, "Current code should be accepted")
+		assert.InDelta(t, now.Unix()/30, step, 1, "The step of the current code should be returned")
+
+		_, ok = totpService.ValidateCode(enrollment.Secret, " "+code+" ")
+		assert.True(t, ok, "Surrounding spaces should be ignored")
+
+		staleCode, err := totp.GenerateCode(enrollment.Secret, now.Add(-5*time.Minute))
+		require.NoError(t, err)
+		_, ok = totpService.ValidateCode(enrollment.Secret, staleCode)
+		assert.False(t, ok, "Old codes should be rejected")
+
+		_, ok = totpService.ValidateCode(enrollment.Secret, "not a code")
+		assert.False(t, ok)
+	})
+
+	t.Run("ValidateCode_PreviousStep", func(t *testing.T) {
+		now := time.Now()
+		code, err := totp.GenerateCode(enrollment.Secret, now.Add(-30*time.Second))
+		require.NoError(t, err)
+
+		step, ok := totpService.ValidateCode(enrollment.Secret, code)
+		assert.True(t, ok, "A code from one step ago should be accepted for clock drift")
+		assert.Less(t, step, time.Now().Unix()/30, "The step the code was generated for should be returned")
+	})
+}
@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TaskUsecase that records a span around every call to the usecase it wraps
+type tracedTaskUsecase struct {
+	next   domain.TaskUsecase
+	tracer trace.Tracer
+}
+
+var _ domain.TaskUsecase = (*tracedTaskUsecase)(nil)
+
+func NewTracedTaskUsecase(usecase domain.TaskUsecase) domain.TaskUsecase {
+	return &tracedTaskUsecase{next: usecase, tracer: otel.Tracer(tracerName)}
+}
+
+func (usecase *tracedTaskUsecase) GetAllTask(ctx context.Context) (tasks []domain.Task, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "TaskUsecase.GetAllTask")
+	defer endSpan(span, &err)
+	return usecase.next.GetAllTask(ctx)
+}
+
+func (usecase *tracedTaskUsecase) GetTaskByID(ctx context.Context, id string) (task domain.Task, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "TaskUsecase.GetTaskByID", trace.WithAttributes(attribute.String("task.id", id)))
+	defer endSpan(span, &err)
+	return usecase.next.GetTaskByID(ctx, id)
+}
+
+func (usecase *tracedTaskUsecase) UpdateTask(ctx context.Context, id string, updatedTask domain.Task, principal domain.Principal) (err error) {
+	ctx, span := usecase.tracer.Start(ctx, "TaskUsecase.UpdateTask", trace.WithAttributes(attribute.String("task.id", id)))
+	defer endSpan(span, &err)
+	return usecase.next.UpdateTask(ctx, id, updatedTask, principal)
+}
+
+func (usecase *tracedTaskUsecase) DeleteTask(ctx context.Context, id string, principal domain.Principal) (err error) {
+	ctx, span := usecase.tracer.Start(ctx, "TaskUsecase.DeleteTask", trace.WithAttributes(attribute.String("task.id", id)))
+	defer endSpan(span, &err)
+	return usecase.next.DeleteTask(ctx, id, principal)
+}
+
+func (usecase *tracedTaskUsecase) NewTask(ctx context.Context, task domain.Task) (created domain.Task, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "TaskUsecase.NewTask")
+	defer endSpan(span, &err)
+	return usecase.next.NewTask(ctx, task)
+}
+
+func (usecase *tracedTaskUsecase) BulkTasks(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal) (results []domain.BulkTaskResult, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "TaskUsecase.BulkTasks", trace.WithAttributes(
+		attribute.Int("tasks.bulk.operations", len(request.Operations)),
+		attribute.Bool("tasks.bulk.atomic", request.Atomic)))
+	defer endSpan(span, &err)
+	return usecase.next.BulkTasks(ctx, request, principal)
+}
+
+// UserUsecase that records a span around every call to the usecase it wraps. Credentials and
+// tokens passed in are never recorded.
+type tracedUserUsecase struct {
+	next   domain.UserUsecase
+	tracer trace.Tracer
+}
+
+var _ domain.UserUsecase = (*tracedUserUsecase)(nil)
+
+func NewTracedUserUsecase(usecase domain.UserUsecase) domain.UserUsecase {
+	return &tracedUserUsecase{next: usecase, tracer: otel.Tracer(tracerName)}
+}
+
+func (usecase *tracedUserUsecase) Register(ctx context.Context, username, email, password string) (user *domain.User, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.Register")
+	defer endSpan(span, &err)
+	return usecase.next.Register(ctx, username, email, password)
+}
+
+func (usecase *tracedUserUsecase) Login(ctx context.Context, identifier, password string) (result *domain.LoginResult, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.Login")
+	defer endSpan(span, &err)
+	return usecase.next.Login(ctx, identifier, password)
+}
+
+func (usecase *tracedUserUsecase) GetProfile(ctx context.Context, username string) (user *domain.User, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.GetProfile")
+	defer endSpan(span, &err)
+	return usecase.next.GetProfile(ctx, username)
+}
+
+func (usecase *tracedUserUsecase) UpdateProfile(ctx context.Context, username string, update domain.UpdateProfileRequest) (user *domain.User, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.UpdateProfile")
+	defer endSpan(span, &err)
+	return usecase.next.UpdateProfile(ctx, username, update)
+}
+
+func (usecase *tracedUserUsecase) ChangePassword(ctx context.Context, username, currentPassword, newPassword string) (token string, err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.ChangePassword")
+	defer endSpan(span, &err)
+	return usecase.next.ChangePassword(ctx, username, currentPassword, newPassword)
+}
+
+func (usecase *tracedUserUsecase) DeleteAccount(ctx context.Context, username, password string, signedInAt time.Time) (err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.DeleteAccount")
+	defer endSpan(span, &err)
+	return usecase.next.DeleteAccount(ctx, username, password, signedInAt)
+}
+
+func (usecase *tracedUserUsecase) RequestEmailVerification(ctx context.Context, username string) (err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.RequestEmailVerification")
+	defer endSpan(span, &err)
+	return usecase.next.RequestEmailVerification(ctx, username)
+}
+
+func (usecase *tracedUserUsecase) VerifyEmail(ctx context.Context, token string) (err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.VerifyEmail")
+	defer endSpan(span, &err)
+	return usecase.next.VerifyEmail(ctx, token)
+}
+
+func (usecase *tracedUserUsecase) UnlockUser(ctx context.Context, username string) (err error) {
+	ctx, span := usecase.tracer.Start(ctx, "UserUsecase.UnlockUser")
+	defer endSpan(span, &err)
+	return usecase.next.UnlockUser(ctx, username)
+}
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation name for the spans this service creates itself
+const tracerName = "task_manager"
+
+// Sets the global tracer provider from the tracing settings, and the W3C trace context and baggage
+// propagators whatever the exporter, so trace headers are passed on even when nothing is recorded.
+// The stdout exporter writes one JSON span per line to w. The returned function flushes buffered
+// spans and must be called before the process exits.
+func SetupTracing(ctx context.Context, config TracingConfig, w io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch config.Exporter {
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case "otlp":
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.OTLPEndpoint)}
+		if config.OTLPInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", config.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Follow the caller's sampling decision; sample new traces at the configured ratio
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Ends a span, marking it failed when the call returned an error. Meant to be deferred with a
+// pointer to the named error result.
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// Identifies a command on the connection it was sent over
+type mongoCommandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// Records a client span for each command the driver sends, ended when the reply arrives. Only the
+// command name and collection are recorded, never the command itself, which may hold password hashes
+// or tokens. Commands sent outside a traced request, such as readiness pings, are skipped.
+func NewMongoCommandMonitor() *event.CommandMonitor {
+	tracer := otel.Tracer(tracerName)
+	var spans sync.Map // mongoCommandKey to trace.Span
+
+	finish := func(evt event.CommandFinishedEvent, failure error) {
+		value, ok := spans.LoadAndDelete(mongoCommandKey{evt.ConnectionID, evt.RequestID})
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		endSpan(span, &failure)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				return
+			}
+
+			name := evt.CommandName
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemNameMongoDB,
+				semconv.DBNamespace(evt.DatabaseName),
+				semconv.DBOperationName(evt.CommandName),
+			}
+			// Most commands name their collection as the command's value, e.g. {find: "tasks"}
+			if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+				name += " " + collection
+				attrs = append(attrs, semconv.DBCollectionName(collection))
+			}
+
+			_, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			spans.Store(mongoCommandKey{evt.ConnectionID, evt.RequestID}, span)
+		},
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			finish(evt.CommandFinishedEvent, nil)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			finish(evt.CommandFinishedEvent, errors.New(evt.Failure))
+		},
+	}
+}
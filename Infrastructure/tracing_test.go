@@ -0,0 +1,181 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Installs a tracer provider that keeps finished spans in memory, restoring the previous one afterwards
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracedTaskUsecase(t *testing.T) {
+	recorder := recordSpans(t)
+	mockUsecase := mocks.NewMockTaskUsecase(t)
+	usecase := infrastructure.NewTracedTaskUsecase(mockUsecase)
+
+	inSpan := mock.MatchedBy(func(ctx context.Context) bool { return trace.SpanFromContext(ctx).SpanContext().IsValid() })
+	mockUsecase.EXPECT().GetAllTask(inSpan).Return([]domain.Task{}, nil).Once()
+	errNotFound := errors.New("task not found")
+	mockUsecase.EXPECT().GetTaskByID(inSpan, "42").Return(domain.Task{}, errNotFound).Once()
+
+	_, err := usecase.GetAllTask(context.Background())
+	require.NoError(t, err)
+	_, err = usecase.GetTaskByID(context.Background(), "42")
+	assert.ErrorIs(t, err, errNotFound)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "TaskUsecase.GetAllTask", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "TaskUsecase.GetTaskByID", spans[1].Name())
+	assert.Equal(t, "42", spanAttribute(spans[1], "task.id"))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestMongoCommandMonitor(t *testing.T) {
+	recorder := recordSpans(t)
+	monitor := infrastructure.NewMongoCommandMonitor()
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	started := func(ctx context.Context, requestID int64, command bson.D) {
+		raw, err := bson.Marshal(command)
+		require.NoError(t, err)
+		monitor.Started(ctx, &event.CommandStartedEvent{
+			Command:      raw,
+			DatabaseName: "task_manager",
+			CommandName:  command[0].Key,
+			RequestID:    requestID,
+			ConnectionID: "localhost:27017[-1]",
+		})
+	}
+	finished := func(requestID int64, commandName string) event.CommandFinishedEvent {
+		return event.CommandFinishedEvent{CommandName: commandName, DatabaseName: "task_manager", RequestID: requestID, ConnectionID: "localhost:27017[-1]"}
+	}
+
+	started(ctx, 1, bson.D{{Key: "find", Value: "tasks"}, {Key: "filter", Value: bson.D{{Key: "password", Value: "secret"}}}})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1, "find")})
+
+	started(ctx, 2, bson.D{{Key: "insert", Value: "user"}})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished(2, "insert"), Failure: "duplicate key"})
+
+	// Outside a traced request nothing is recorded
+	started(context.Background(), 3, bson.D{{Key: "ping", Value: 1}})
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{CommandFinishedEvent: finished(3, "ping")})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	find := spans[0]
+	assert.Equal(t, "find tasks", find.Name())
+	assert.Equal(t, trace.SpanKindClient, find.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), find.Parent().SpanID())
+	assert.Equal(t, "mongodb", spanAttribute(find, "db.system.name"))
+	assert.Equal(t, "task_manager", spanAttribute(find, "db.namespace"))
+	assert.Equal(t, "tasks", spanAttribute(find, "db.collection.name"))
+	assert.Equal(t, codes.Unset, find.Status().Code)
+	for _, attr := range find.Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "secret", "The command itself is not recorded")
+	}
+
+	insert := spans[1]
+	assert.Equal(t, "insert user", insert.Name())
+	assert.Equal(t, codes.Error, insert.Status().Code)
+	assert.Equal(t, "duplicate key", insert.Status().Description)
+
+	assert.Equal(t, "request", spans[2].Name())
+}
+
+func TestSetupTracing(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		config := infrastructure.DefaultConfig().Tracing
+		shutdown, err := infrastructure.SetupTracing(context.Background(), config, nil)
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		previous := otel.GetTracerProvider()
+		t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+		var output bytes.Buffer
+		config := infrastructure.DefaultConfig().Tracing
+		config.Exporter = "stdout"
+		shutdown, err := infrastructure.SetupTracing(context.Background(), config, &output)
+		require.NoError(t, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "GET /tasks")
+		span.End()
+		require.NoError(t, shutdown(context.Background()))
+
+		assert.Contains(t, output.String(), `"Name":"GET /tasks"`)
+		assert.Contains(t, output.String(), `"Value":"task_manager"`, "Spans name the service")
+	})
+}
+
+func TestTracing_PropagatesW3CTraceContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recordSpans(t)
+	_, err := infrastructure.SetupTracing(context.Background(), infrastructure.DefaultConfig().Tracing, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+
+	var output bytes.Buffer
+	logger := infrastructure.NewLogger(infrastructure.LogConfig{Level: "info", Format: "json"}, &output)
+
+	var traceID string
+	router := gin.New()
+	router.Use(otelgin.Middleware("task_manager"), infrastructure.RequestMetadata())
+	router.GET("/tasks", func(c *gin.Context) {
+		traceID = trace.SpanFromContext(c.Request.Context()).SpanContext().TraceID().String()
+		domain.LoggerFromContext(c.Request.Context()).Info("listing tasks")
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req = req.WithContext(domain.WithLogger(req.Context(), logger))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID, "The request joins the caller's trace")
+	entries := logEntries(t, &output)
+	require.Len(t, entries, 1)
+	assert.Equal(t, traceID, entries[0]["trace_id"], "Log lines carry the trace ID")
+}
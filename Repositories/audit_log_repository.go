@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditLogRepository struct {
+	collection *mongo.Collection
+}
+
+var _ domain.AuditLogRepository = (*auditLogRepository)(nil)
+
+func NewAuditLogRepository(db *mongo.Client, dbName, collectionName string) domain.AuditLogRepository {
+	return &auditLogRepository{
+		collection: openCollection(db, dbName, collectionName),
+	}
+}
+
+func (repo *auditLogRepository) AppendEvent(ctx context.Context, event *domain.AuditEvent) error {
+	result, err := repo.collection.InsertOne(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	event.ID = insertedID(result)
+
+	return nil
+}
+
+// Events matching the filter, newest first
+func (repo *auditLogRepository) FindEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	query := bson.M{}
+	for field, value := range map[string]string{
+		"actor":       filter.Actor,
+		"action":      filter.Action,
+		"outcome":     filter.Outcome,
+		"target_type": filter.TargetType,
+		"target_id":   filter.TargetID,
+	} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+
+	timeRange := bson.M{}
+	if !filter.From.IsZero() {
+		timeRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timeRange["$lt"] = filter.To
+	}
+	if len(timeRange) > 0 {
+		query["time"] = timeRange
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := repo.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []domain.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Creates the indexes audit log queries rely on. Safe to call on every start.
+func EnsureAuditLogIndexes(ctx context.Context, db *mongo.Client, dbName, collectionName string) error {
+	collection := openCollection(db, dbName, collectionName)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "time", Value: -1}}, Options: options.Index().SetName("time")},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetName("actor_time")},
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetName("action_time")},
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetName("target_time")},
+	})
+	return err
+}
@@ -0,0 +1,82 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testAuditCollectionName = "audit_log_integration_test_coll"
+
+func TestAuditLogRepository_Integration(t *testing.T) {
+	if testDBClient == nil {
+		t.Fatal("testDBClient is nil. TestMain setup for DB connection likely failed or was skipped.")
+	}
+
+	auditRepo := repositories.NewAuditLogRepository(testDBClient, TestDatabaseName, testAuditCollectionName)
+	collection := testDBClient.Database(TestDatabaseName).Collection(testAuditCollectionName)
+	ctx := context.Background()
+
+	cleanCollection := func(t *testing.T) {
+		_, err := collection.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err, "Failed to clean audit log test collection")
+	}
+
+	newEvent := func(actor, action, outcome string, at time.Time) *domain.AuditEvent {
+		return &domain.AuditEvent{
+			Time:       at,
+			Action:     action,
+			Outcome:    outcome,
+			Actor:      actor,
+			ClientIP:   "203.0.113.7",
+			TargetType: domain.AuditTargetUser,
+			TargetID:   actor,
+		}
+	}
+
+	t.Run("AppendEvent_SetsID", func(t *testing.T) {
+		cleanCollection(t)
+
+		event := newEvent("integ_audit_user", domain.AuditActionLogin, domain.AuditOutcomeSuccess, time.Now())
+		require.NoError(t, auditRepo.AppendEvent(ctx, event))
+		assert.False(t, event.ID.IsZero(), "AppendEvent should set the ID")
+	})
+
+	t.Run("FindEvents_FiltersNewestFirst", func(t *testing.T) {
+		cleanCollection(t)
+
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		require.NoError(t, auditRepo.AppendEvent(ctx, newEvent("alice", domain.AuditActionLogin, domain.AuditOutcomeFailure, now.Add(-2*time.Hour))))
+		require.NoError(t, auditRepo.AppendEvent(ctx, newEvent("alice", domain.AuditActionLogin, domain.AuditOutcomeSuccess, now.Add(-time.Hour))))
+		require.NoError(t, auditRepo.AppendEvent(ctx, newEvent("alice", domain.AuditActionTaskDelete, domain.AuditOutcomeSuccess, now)))
+		require.NoError(t, auditRepo.AppendEvent(ctx, newEvent("bob", domain.AuditActionLogin, domain.AuditOutcomeSuccess, now)))
+
+		events, err := auditRepo.FindEvents(ctx, domain.AuditFilter{Actor: "alice", Action: domain.AuditActionLogin})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, domain.AuditOutcomeSuccess, events[0].Outcome, "Newest event first")
+
+		events, err = auditRepo.FindEvents(ctx, domain.AuditFilter{Outcome: domain.AuditOutcomeFailure})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "alice", events[0].Actor)
+
+		events, err = auditRepo.FindEvents(ctx, domain.AuditFilter{From: now.Add(-90 * time.Minute), To: now})
+		require.NoError(t, err)
+		require.Len(t, events, 1, "From is inclusive and To exclusive")
+
+		events, err = auditRepo.FindEvents(ctx, domain.AuditFilter{Limit: 3})
+		require.NoError(t, err)
+		assert.Len(t, events, 3)
+
+		events, err = auditRepo.FindEvents(ctx, domain.AuditFilter{Actor: "nobody"})
+		require.NoError(t, err)
+		assert.NotNil(t, events, "An empty list should not be nil")
+	})
+}
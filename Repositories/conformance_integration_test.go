@@ -0,0 +1,84 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"task_manager/Repositories/repositorytest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The shared repository suites, run against MongoDB. The in-memory repositories run the same suites.
+
+// Returns the name of a collection emptied for the test
+func cleanConformanceCollection(t *testing.T, name string) string {
+	require.NotNil(t, testDBClient, "Database client not initialized. TestMain setup might have failed.")
+	_, err := testDBClient.Database(TestDatabaseName).Collection(name).DeleteMany(context.Background(), bson.M{})
+	require.NoError(t, err, "Failed to clean %s", name)
+	return name
+}
+
+// Atomic bulk writes run in a transaction, so MONGO_TEST_URI must point to a replica set; a single
+// node started with --replSet will do
+func TestTaskRepository_Conformance(t *testing.T) {
+	repositorytest.TestTaskRepository(t, func(t *testing.T) domain.TaskRepository {
+		return repositories.NewTaskRepository(testDBClient, TestDatabaseName, cleanConformanceCollection(t, "tasks_conformance"))
+	})
+}
+
+// Needs a replica set, like atomic bulk writes
+func TestUnitOfWork_Conformance(t *testing.T) {
+	repositorytest.TestUnitOfWork(t, func(t *testing.T) (domain.UnitOfWork, domain.TaskRepository, domain.UserRepository) {
+		tasks := cleanConformanceCollection(t, "tasks_conformance")
+		users := cleanConformanceCollection(t, "users_conformance")
+		return repositories.NewUnitOfWork(testDBClient),
+			repositories.NewTaskRepository(testDBClient, TestDatabaseName, tasks),
+			repositories.NewUserRepository(testDBClient, TestDatabaseName, users)
+	})
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	repositorytest.TestUserRepository(t, func(t *testing.T) domain.UserRepository {
+		collection := cleanConformanceCollection(t, "users_conformance")
+		require.NoError(t, repositories.EnsureUserIndexes(context.Background(), testDBClient, TestDatabaseName, collection))
+		require.NoError(t, repositories.EnsureUsernameIndex(context.Background(), testDBClient, TestDatabaseName, collection))
+		return repositories.NewUserRepository(testDBClient, TestDatabaseName, collection)
+	})
+}
+
+func TestOneTimeTokenRepository_Conformance(t *testing.T) {
+	repositorytest.TestOneTimeTokenRepository(t, func(t *testing.T) domain.OneTimeTokenRepository {
+		return repositories.NewOneTimeTokenRepository(testDBClient, TestDatabaseName, cleanConformanceCollection(t, "one_time_tokens_conformance"))
+	})
+}
+
+func TestPersonalAccessTokenRepository_Conformance(t *testing.T) {
+	repositorytest.TestPersonalAccessTokenRepository(t, func(t *testing.T) domain.PersonalAccessTokenRepository {
+		collection := cleanConformanceCollection(t, "personal_access_tokens_conformance")
+		require.NoError(t, repositories.EnsurePersonalAccessTokenIndexes(context.Background(), testDBClient, TestDatabaseName, collection))
+		return repositories.NewPersonalAccessTokenRepository(testDBClient, TestDatabaseName, collection)
+	})
+}
+
+func TestRoleRepository_Conformance(t *testing.T) {
+	repositorytest.TestRoleRepository(t, func(t *testing.T) domain.RoleRepository {
+		collection := cleanConformanceCollection(t, "roles_conformance")
+		require.NoError(t, repositories.EnsureDefaultRoles(context.Background(), testDBClient, TestDatabaseName, collection))
+		return repositories.NewRoleRepository(testDBClient, TestDatabaseName, collection)
+	})
+}
+
+func TestMFAPolicyRepository_Conformance(t *testing.T) {
+	repositorytest.TestMFAPolicyRepository(t, func(t *testing.T) domain.MFAPolicyRepository {
+		return repositories.NewMFAPolicyRepository(testDBClient, TestDatabaseName, cleanConformanceCollection(t, "settings_conformance"))
+	})
+}
+
+func TestAuditLogRepository_Conformance(t *testing.T) {
+	repositorytest.TestAuditLogRepository(t, func(t *testing.T) domain.AuditLogRepository {
+		return repositories.NewAuditLogRepository(testDBClient, TestDatabaseName, cleanConformanceCollection(t, "audit_log_conformance"))
+	})
+}
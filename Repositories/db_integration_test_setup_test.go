@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	repositories "task_manager/Repositories"
 	"testing"
 	"time"
 
@@ -29,7 +30,8 @@ func TestMain(m *testing.M) {
 		log.Printf("Using MONGO_TEST_URI: %s\n", mongoURI)
 	}
 
-	clientOptions := options.Client().ApplyURI(mongoURI)
+	// Repositories store domain.ID as an ObjectID; tests reading collections directly decode it the same way
+	clientOptions := options.Client().ApplyURI(mongoURI).SetRegistry(repositories.Registry())
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second) // Increased timeout for CI
 	defer cancel()
 
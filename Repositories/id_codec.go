@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var idType = reflect.TypeOf(domain.ID{})
+
+// Default registry, plus domain.ID stored as an ObjectID
+var registry = newRegistry()
+
+func newRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	registry.RegisterTypeEncoder(idType, bsoncodec.ValueEncoderFunc(encodeID))
+	registry.RegisterTypeDecoder(idType, bsoncodec.ValueDecoderFunc(decodeID))
+
+	return registry
+}
+
+// Registry the repositories encode and decode documents with. Code reading or writing the
+// same collections without a repository, e.g. tests, should use it too.
+func Registry() *bsoncodec.Registry {
+	return registry
+}
+
+// Opens a collection that stores domain.ID fields as ObjectIDs
+func openCollection(db *mongo.Client, dbName, collectionName string) *mongo.Collection {
+	return db.Database(dbName).Collection(collectionName, options.Collection().SetRegistry(registry))
+}
+
+func encodeID(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != idType {
+		return bsoncodec.ValueEncoderError{Name: "encodeID", Types: []reflect.Type{idType}, Received: val}
+	}
+
+	return vw.WriteObjectID(primitive.ObjectID(val.Interface().(domain.ID)))
+}
+
+func decodeID(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != idType {
+		return bsoncodec.ValueDecoderError{Name: "decodeID", Types: []reflect.Type{idType}, Received: val}
+	}
+
+	if vr.Type() != bsontype.ObjectID {
+		return fmt.Errorf("cannot decode %v into a domain.ID", vr.Type())
+	}
+	objectID, err := vr.ReadObjectID()
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(domain.ID(objectID)))
+
+	return nil
+}
+
+// ID of the document InsertOne created, which MongoDB generates when the document has none
+func insertedID(result *mongo.InsertOneResult) domain.ID {
+	objectID, _ := result.InsertedID.(primitive.ObjectID)
+	return domain.ID(objectID)
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	domain "task_manager/Domain"
+)
+
+type auditLogRepository struct {
+	mu     sync.RWMutex
+	events []domain.AuditEvent
+}
+
+var _ domain.AuditLogRepository = (*auditLogRepository)(nil)
+
+func NewAuditLogRepository() domain.AuditLogRepository {
+	return &auditLogRepository{}
+}
+
+func (repo *auditLogRepository) AppendEvent(ctx context.Context, event *domain.AuditEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if event.ID.IsZero() {
+		event.ID = domain.NewID()
+	}
+
+	stored := *event
+	stored.Time = storedTime(event.Time)
+	stored.Details = maps.Clone(event.Details)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.events = append(repo.events, stored)
+	recordUndo(ctx, &repo.mu, func() {
+		repo.events = slices.DeleteFunc(repo.events, func(event domain.AuditEvent) bool { return event.ID == stored.ID })
+	})
+
+	return nil
+}
+
+// Events matching the filter, newest first
+func (repo *auditLogRepository) FindEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	events := []domain.AuditEvent{}
+	for _, event := range repo.events {
+		if matchesAuditFilter(event, filter) {
+			event.Details = maps.Clone(event.Details)
+			events = append(events, event)
+		}
+	}
+
+	slices.SortFunc(events, func(a, b domain.AuditEvent) int {
+		if byTime := b.Time.Compare(a.Time); byTime != 0 {
+			return byTime
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+
+	return events, nil
+}
+
+// Empty fields of the filter match every event. From is inclusive and To exclusive.
+func matchesAuditFilter(event domain.AuditEvent, filter domain.AuditFilter) bool {
+	for _, field := range [][2]string{
+		{filter.Actor, event.Actor},
+		{filter.Action, event.Action},
+		{filter.Outcome, event.Outcome},
+		{filter.TargetType, event.TargetType},
+		{filter.TargetID, event.TargetID},
+	} {
+		if field[0] != "" && field[0] != field[1] {
+			return false
+		}
+	}
+
+	if !filter.From.IsZero() && event.Time.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !event.Time.Before(filter.To) {
+		return false
+	}
+
+	return true
+}
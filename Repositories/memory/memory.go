@@ -0,0 +1,33 @@
+// Package memory keeps the repositories in process memory. Nothing is persisted, so it suits tests and
+// trying the service out without a database. Each repository behaves like its MongoDB counterpart in
+// package repositories; the suites in repositorytest are run against both to keep it that way.
+package memory
+
+import (
+	"fmt"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Returned where MongoDB would report a unique index violation
+func duplicateKeyError(index string) error {
+	return fmt.Errorf("%w: duplicate key in index %s", domain.ErrConflict, index)
+}
+
+// Times as MongoDB returns them: in UTC, to the millisecond
+func storedTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+
+	return t.Truncate(time.Millisecond).UTC()
+}
+
+func storedTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	stored := storedTime(*t)
+	return &stored
+}
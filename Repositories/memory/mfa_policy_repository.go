@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	domain "task_manager/Domain"
+)
+
+type mfaPolicyRepository struct {
+	mu     sync.RWMutex
+	policy *domain.MFAPolicy // Nil until an admin saves one
+}
+
+var _ domain.MFAPolicyRepository = (*mfaPolicyRepository)(nil)
+
+func NewMFAPolicyRepository() domain.MFAPolicyRepository {
+	return &mfaPolicyRepository{}
+}
+
+// Returns the saved policy, or an empty one if an admin never set it
+func (repo *mfaPolicyRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if repo.policy == nil {
+		return &domain.MFAPolicy{RequiredRoles: []string{}}, nil
+	}
+
+	return &domain.MFAPolicy{RequiredRoles: slices.Clone(repo.policy.RequiredRoles)}, nil
+}
+
+// Replaces the policy
+func (repo *mfaPolicyRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// A saved policy is replaced, never changed in place
+	previous := repo.policy
+	recordUndo(ctx, &repo.mu, func() { repo.policy = previous })
+	repo.policy = &domain.MFAPolicy{RequiredRoles: slices.Clone(policy.RequiredRoles)}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+)
+
+type oneTimeTokenRepository struct {
+	mu     sync.Mutex
+	tokens []*domain.OneTimeToken
+}
+
+var _ domain.OneTimeTokenRepository = (*oneTimeTokenRepository)(nil)
+
+func NewOneTimeTokenRepository() domain.OneTimeTokenRepository {
+	return &oneTimeTokenRepository{}
+}
+
+// Stores a new (hashed) token
+func (repo *oneTimeTokenRepository) CreateToken(ctx context.Context, token *domain.OneTimeToken) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if token.ID.IsZero() {
+		token.ID = domain.NewID()
+	}
+
+	stored := cloneOneTimeToken(token)
+	stored.CreatedAt = storedTime(stored.CreatedAt)
+	stored.ExpiresAt = storedTime(stored.ExpiresAt)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.tokens = append(repo.tokens, stored)
+	recordUndo(ctx, &repo.mu, func() {
+		repo.tokens = slices.DeleteFunc(repo.tokens, func(token *domain.OneTimeToken) bool { return token == stored })
+	})
+
+	return nil
+}
+
+// Marks the token as used under the lock, so two concurrent requests with the same token cannot
+// both succeed.
+func (repo *oneTimeTokenRepository) ConsumeToken(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token := repo.findUsableLocked(purpose, tokenHash)
+	if token == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	usedAt := storedTime(time.Now())
+	token.UsedAt = &usedAt
+	recordUndo(ctx, &repo.mu, func() { token.UsedAt = nil })
+
+	return cloneOneTimeToken(token), nil
+}
+
+// Looks a token up without marking it as used
+func (repo *oneTimeTokenRepository) FindToken(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token := repo.findUsableLocked(purpose, tokenHash)
+	if token == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return cloneOneTimeToken(token), nil
+}
+
+// Removes every token of the given purpose issued to a user
+func (repo *oneTimeTokenRepository) DeleteTokensForUser(ctx context.Context, username, purpose string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var deleted []*domain.OneTimeToken
+	repo.tokens = slices.DeleteFunc(repo.tokens, func(token *domain.OneTimeToken) bool {
+		if token.Username != username || token.Purpose != purpose {
+			return false
+		}
+		deleted = append(deleted, token)
+		return true
+	})
+	recordUndo(ctx, &repo.mu, func() { repo.tokens = append(repo.tokens, deleted...) })
+
+	return nil
+}
+
+// Returns the unused, unexpired token with the given purpose and hash, or nil
+func (repo *oneTimeTokenRepository) findUsableLocked(purpose, tokenHash string) *domain.OneTimeToken {
+	now := time.Now()
+	for _, token := range repo.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash && token.UsedAt == nil && token.ExpiresAt.After(now) {
+			return token
+		}
+	}
+
+	return nil
+}
+
+func cloneOneTimeToken(token *domain.OneTimeToken) *domain.OneTimeToken {
+	clone := *token
+	clone.UsedAt = storedTimePtr(token.UsedAt)
+
+	return &clone
+}
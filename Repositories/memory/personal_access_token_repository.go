@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+)
+
+type personalAccessTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[domain.ID]*domain.PersonalAccessToken
+}
+
+var _ domain.PersonalAccessTokenRepository = (*personalAccessTokenRepository)(nil)
+
+func NewPersonalAccessTokenRepository() domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{tokens: make(map[domain.ID]*domain.PersonalAccessToken)}
+}
+
+// Stores a new (hashed) token. Token hashes are unique.
+func (repo *personalAccessTokenRepository) CreateToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existing := range repo.tokens {
+		if existing.TokenHash == token.TokenHash {
+			return duplicateKeyError("token_hash_unique")
+		}
+	}
+
+	if token.ID.IsZero() {
+		token.ID = domain.NewID()
+	}
+
+	stored := clonePersonalAccessToken(token)
+	stored.CreatedAt = storedTime(stored.CreatedAt)
+	stored.ExpiresAt = storedTime(stored.ExpiresAt)
+	recordPut(ctx, &repo.mu, repo.tokens, stored.ID, clonePersonalAccessToken)
+	repo.tokens[stored.ID] = stored
+
+	return nil
+}
+
+func (repo *personalAccessTokenRepository) FindTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, token := range repo.tokens {
+		if token.TokenHash == tokenHash {
+			return clonePersonalAccessToken(token), nil
+		}
+	}
+
+	return nil, domain.ErrTokenNotFound
+}
+
+// Lists a user's tokens, newest first
+func (repo *personalAccessTokenRepository) ListTokensForUser(ctx context.Context, username string) ([]domain.PersonalAccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	tokens := []domain.PersonalAccessToken{}
+	for _, token := range repo.tokens {
+		if token.Username == username {
+			tokens = append(tokens, *clonePersonalAccessToken(token))
+		}
+	}
+
+	slices.SortFunc(tokens, func(a, b domain.PersonalAccessToken) int {
+		return cmp.Compare(b.CreatedAt.UnixNano(), a.CreatedAt.UnixNano())
+	})
+
+	return tokens, nil
+}
+
+func (repo *personalAccessTokenRepository) DeleteToken(ctx context.Context, username, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	parsedID, err := domain.ParseID(id)
+	if err != nil {
+		return domain.ErrTokenNotFound
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token, ok := repo.tokens[parsedID]
+	if !ok || token.Username != username {
+		return domain.ErrTokenNotFound
+	}
+
+	recordPut(ctx, &repo.mu, repo.tokens, parsedID, clonePersonalAccessToken)
+	delete(repo.tokens, parsedID)
+
+	return nil
+}
+
+// Revokes every token of a user
+func (repo *personalAccessTokenRepository) DeleteTokensForUser(ctx context.Context, username string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	maps.DeleteFunc(repo.tokens, func(id domain.ID, token *domain.PersonalAccessToken) bool {
+		if token.Username != username {
+			return false
+		}
+		recordPut(ctx, &repo.mu, repo.tokens, id, clonePersonalAccessToken)
+		return true
+	})
+
+	return nil
+}
+
+func (repo *personalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	parsedID, err := domain.ParseID(id)
+	if err != nil {
+		return domain.ErrTokenNotFound
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if token, ok := repo.tokens[parsedID]; ok {
+		recordPut(ctx, &repo.mu, repo.tokens, parsedID, clonePersonalAccessToken)
+		token.LastUsedAt = storedTimePtr(&lastUsedAt)
+	}
+
+	return nil
+}
+
+func clonePersonalAccessToken(token *domain.PersonalAccessToken) *domain.PersonalAccessToken {
+	clone := *token
+	clone.Scopes = slices.Clone(token.Scopes)
+	clone.LastUsedAt = storedTimePtr(token.LastUsedAt)
+
+	return &clone
+}
@@ -0,0 +1,142 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	"task_manager/Repositories/memory"
+	"task_manager/Repositories/repositorytest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository(t *testing.T) {
+	repositorytest.TestTaskRepository(t, func(t *testing.T) domain.TaskRepository { return memory.NewTaskRepository() })
+}
+
+func TestUserRepository(t *testing.T) {
+	repositorytest.TestUserRepository(t, func(t *testing.T) domain.UserRepository { return memory.NewUserRepository() })
+}
+
+func TestOneTimeTokenRepository(t *testing.T) {
+	repositorytest.TestOneTimeTokenRepository(t, func(t *testing.T) domain.OneTimeTokenRepository { return memory.NewOneTimeTokenRepository() })
+}
+
+func TestPersonalAccessTokenRepository(t *testing.T) {
+	repositorytest.TestPersonalAccessTokenRepository(t, func(t *testing.T) domain.PersonalAccessTokenRepository {
+		return memory.NewPersonalAccessTokenRepository()
+	})
+}
+
+func TestRoleRepository(t *testing.T) {
+	repositorytest.TestRoleRepository(t, func(t *testing.T) domain.RoleRepository { return memory.NewRoleRepository() })
+}
+
+func TestMFAPolicyRepository(t *testing.T) {
+	repositorytest.TestMFAPolicyRepository(t, func(t *testing.T) domain.MFAPolicyRepository { return memory.NewMFAPolicyRepository() })
+}
+
+func TestAuditLogRepository(t *testing.T) {
+	repositorytest.TestAuditLogRepository(t, func(t *testing.T) domain.AuditLogRepository { return memory.NewAuditLogRepository() })
+}
+
+func TestUnitOfWork(t *testing.T) {
+	repositorytest.TestUnitOfWork(t, func(t *testing.T) (domain.UnitOfWork, domain.TaskRepository, domain.UserRepository) {
+		tasks, users := memory.NewTaskRepository(), memory.NewUserRepository()
+		return memory.NewUnitOfWork(), tasks, users
+	})
+}
+
+// Every repository of the package can be rolled back
+func TestUnitOfWork_RollsBackEveryRepository(t *testing.T) {
+	ctx := context.Background()
+	tokens := memory.NewOneTimeTokenRepository()
+	accessTokens := memory.NewPersonalAccessTokenRepository()
+	roles := memory.NewRoleRepository()
+	policies := memory.NewMFAPolicyRepository()
+	auditLog := memory.NewAuditLogRepository()
+	transactions := memory.NewUnitOfWork()
+	errFailed := errors.New("something went wrong")
+
+	err := transactions.Do(ctx, func(ctx context.Context) error {
+		require.NoError(t, tokens.CreateToken(ctx, &domain.OneTimeToken{Username: "alice", Purpose: domain.TokenPurposePasswordReset, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}))
+		require.NoError(t, accessTokens.CreateToken(ctx, &domain.PersonalAccessToken{Username: "alice", Name: "ci", TokenHash: "pat-hash"}))
+		require.NoError(t, roles.SaveRole(ctx, &domain.Role{Name: "viewer", Permissions: []string{domain.PermissionTasksRead}}))
+		require.NoError(t, policies.SaveMFAPolicy(ctx, &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin}}))
+		require.NoError(t, auditLog.AppendEvent(ctx, &domain.AuditEvent{Action: domain.AuditActionRoleSave}))
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+
+	_, err = tokens.FindToken(ctx, domain.TokenPurposePasswordReset, "hash")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	_, err = accessTokens.FindTokenByHash(ctx, "pat-hash")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+	_, err = roles.GetRole(ctx, "viewer")
+	assert.ErrorIs(t, err, domain.ErrRoleNotFound)
+	policy, err := policies.GetMFAPolicy(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, policy.RequiredRoles)
+	events, err := auditLog.FindEvents(ctx, domain.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+// A rollback undoes only what was written through the unit of work
+func TestUnitOfWork_KeepsConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	tasks, users, auditLog := memory.NewTaskRepository(), memory.NewUserRepository(), memory.NewAuditLogRepository()
+	transactions := memory.NewUnitOfWork()
+	errFailed := errors.New("something went wrong")
+
+	kept, err := tasks.NewTask(ctx, domain.Task{Title: "Kept", Status: "Pending"})
+	require.NoError(t, err)
+	deleted, err := tasks.NewTask(ctx, domain.Task{Title: "Deleted"})
+	require.NoError(t, err)
+	alice := &domain.User{Username: "alice", Email: "alice@example.com"}
+	require.NoError(t, users.CreateUser(ctx, alice))
+
+	var concurrent domain.Task
+	err = transactions.Do(ctx, func(txCtx context.Context) error {
+		require.NoError(t, tasks.UpdateTask(txCtx, kept.ID.String(), domain.Task{Title: "Renamed"}))
+		require.NoError(t, tasks.DeleteTask(txCtx, deleted.ID.String()))
+		_, err := tasks.NewTask(txCtx, domain.Task{Title: "Created inside"})
+		require.NoError(t, err)
+		require.NoError(t, users.UpdateUser(txCtx, &domain.User{ID: alice.ID, Email: "renamed@example.com"}))
+
+		// Another request writes while fn runs
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			var err error
+			concurrent, err = tasks.NewTask(ctx, domain.Task{Title: "Created outside"})
+			assert.NoError(t, err)
+			assert.NoError(t, users.CreateUser(ctx, &domain.User{Username: "bob", Email: "bob@example.com"}))
+			assert.NoError(t, auditLog.AppendEvent(ctx, &domain.AuditEvent{Action: domain.AuditActionRoleSave}))
+		}()
+		<-done
+
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+
+	all, err := tasks.GetAllTask(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, kept.ID, all[0].ID)
+	assert.Equal(t, "Kept", all[0].Title)
+	assert.Equal(t, deleted.ID, all[1].ID)
+	assert.Equal(t, concurrent.ID, all[2].ID)
+
+	user, err := users.FindUserByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", user.Email)
+	_, err = users.FindUserByUsername(ctx, "bob")
+	assert.NoError(t, err)
+	events, err := auditLog.FindEvents(ctx, domain.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
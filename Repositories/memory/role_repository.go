@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	domain "task_manager/Domain"
+)
+
+type roleRepository struct {
+	mu    sync.RWMutex
+	roles map[string][]string // Permissions by role name
+}
+
+var _ domain.RoleRepository = (*roleRepository)(nil)
+
+// Starts with the default roles, like a database after EnsureDefaultRoles
+func NewRoleRepository() domain.RoleRepository {
+	repo := &roleRepository{roles: make(map[string][]string)}
+	for _, role := range domain.DefaultRoles() {
+		repo.roles[role.Name] = role.Permissions
+	}
+
+	return repo
+}
+
+func (repo *roleRepository) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	permissions, ok := repo.roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	return &domain.Role{Name: name, Permissions: slices.Clone(permissions)}, nil
+}
+
+// Lists every role by name
+func (repo *roleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	roles := []domain.Role{}
+	for name, permissions := range repo.roles {
+		roles = append(roles, domain.Role{Name: name, Permissions: slices.Clone(permissions)})
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	return roles, nil
+}
+
+// Creates the role or replaces its permissions
+func (repo *roleRepository) SaveRole(ctx context.Context, role *domain.Role) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	recordPut(ctx, &repo.mu, repo.roles, role.Name, slices.Clone)
+	repo.roles[role.Name] = slices.Clone(role.Permissions)
+
+	return nil
+}
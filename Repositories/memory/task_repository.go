@@ -0,0 +1,359 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	domain "task_manager/Domain"
+)
+
+type taskRepository struct {
+	mu    sync.RWMutex
+	tasks map[domain.ID]domain.Task
+	order []domain.ID // Insertion order, in which GetAllTask returns tasks like a collection scan
+}
+
+var _ domain.TaskRepository = (*taskRepository)(nil)
+
+func NewTaskRepository() domain.TaskRepository {
+	return &taskRepository{tasks: make(map[domain.ID]domain.Task)}
+}
+
+// Gets all Tasks
+func (repo *taskRepository) GetAllTask(ctx context.Context) ([]domain.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var tasks []domain.Task
+	for _, id := range repo.order {
+		tasks = append(tasks, repo.tasks[id])
+	}
+
+	return tasks, nil
+}
+
+// Gets task by ID
+func (repo *taskRepository) GetTaskByID(ctx context.Context, id string) (domain.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Task{}, err
+	}
+
+	parsedID, err := domain.ParseID(id)
+	if err != nil {
+		return domain.Task{}, errors.New("invalid task ID format")
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	task, ok := repo.tasks[parsedID]
+	if !ok {
+		return domain.Task{}, errors.New("task not found")
+	}
+
+	return task, nil
+}
+
+// Sets the fields of updatedTask that are not empty
+func (repo *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask domain.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	undo, err := repo.update(id, updatedTask)
+	if err != nil {
+		return err
+	}
+	recordUndo(ctx, &repo.mu, undo)
+
+	return nil
+}
+
+// Returns how to undo the update. Callers must hold mu, also when calling undo.
+func (repo *taskRepository) update(id string, updatedTask domain.Task) (undo func(), err error) {
+	parsedID, err := domain.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedTask.Title == "" && updatedTask.Description == "" && updatedTask.Status == "" && updatedTask.DueDate.IsZero() {
+		return nil, errors.New("no field provided")
+	}
+
+	task, ok := repo.tasks[parsedID]
+	if !ok {
+		return nil, errors.New("task not found")
+	}
+	previous := task
+
+	if updatedTask.Title != "" {
+		task.Title = updatedTask.Title
+	}
+	if updatedTask.Description != "" {
+		task.Description = updatedTask.Description
+	}
+	if updatedTask.Status != "" {
+		task.Status = updatedTask.Status
+	}
+	if !updatedTask.DueDate.IsZero() {
+		task.DueDate = storedTime(updatedTask.DueDate)
+	}
+	repo.tasks[parsedID] = task
+
+	return func() {
+		if _, ok := repo.tasks[parsedID]; ok {
+			repo.tasks[parsedID] = previous
+		}
+	}, nil
+}
+
+func (repo *taskRepository) DeleteTask(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	undo, err := repo.delete(id)
+	if err != nil {
+		return err
+	}
+	recordUndo(ctx, &repo.mu, undo)
+
+	return nil
+}
+
+// Deletes every task the user created
+func (repo *taskRepository) DeleteTasksByCreator(ctx context.Context, username string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var deleted []domain.Task
+	var positions []int
+	for i, id := range repo.order {
+		if task := repo.tasks[id]; task.CreatedBy == username {
+			deleted, positions = append(deleted, task), append(positions, i)
+		}
+	}
+
+	for _, task := range deleted {
+		repo.remove(task.ID)
+	}
+
+	recordUndo(ctx, &repo.mu, func() {
+		for i, task := range deleted {
+			repo.restore(task, positions[i])
+		}
+	})
+
+	return nil
+}
+
+// Returns how to undo the deletion. Callers must hold mu, also when calling undo.
+func (repo *taskRepository) delete(id string) (undo func(), err error) {
+	parsedID, err := domain.ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, ok := repo.tasks[parsedID]
+	if !ok {
+		return nil, errors.New("task not found")
+	}
+
+	position := slices.Index(repo.order, parsedID)
+	repo.remove(parsedID)
+
+	return func() { repo.restore(task, position) }, nil
+}
+
+// Callers must hold mu
+func (repo *taskRepository) remove(id domain.ID) {
+	delete(repo.tasks, id)
+	repo.order = slices.DeleteFunc(repo.order, func(existing domain.ID) bool { return existing == id })
+}
+
+// Puts a deleted task back at its position in the insertion order, unless a task with its ID was
+// created since. Callers must hold mu.
+func (repo *taskRepository) restore(task domain.Task, position int) {
+	if _, exists := repo.tasks[task.ID]; exists {
+		return
+	}
+
+	repo.tasks[task.ID] = task
+	repo.order = slices.Insert(repo.order, min(position, len(repo.order)), task.ID)
+}
+
+// Creates a new task, generating its ID unless it has one
+func (repo *taskRepository) NewTask(ctx context.Context, task domain.Task) (domain.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Task{}, err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	created, undo, err := repo.insert(task)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	recordUndo(ctx, &repo.mu, undo)
+
+	return created, nil
+}
+
+// Returns how to undo the insertion. Callers must hold mu, also when calling undo.
+func (repo *taskRepository) insert(task domain.Task) (created domain.Task, undo func(), err error) {
+	if task.ID.IsZero() {
+		task.ID = domain.NewID()
+	}
+	task.DueDate = storedTime(task.DueDate)
+
+	if _, exists := repo.tasks[task.ID]; exists {
+		return domain.Task{}, nil, duplicateKeyError("_id_")
+	}
+
+	repo.tasks[task.ID] = task
+	repo.order = append(repo.order, task.ID)
+
+	return task, func() { repo.remove(task.ID) }, nil
+}
+
+// Counts tasks grouped by status
+func (repo *taskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, task := range repo.tasks {
+		counts[task.Status]++
+	}
+
+	return counts, nil
+}
+
+// Tasks matching the filter, in insertion order
+func (repo *taskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter, limit int) ([]domain.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var tasks []domain.Task
+	for _, id := range repo.order {
+		task := repo.tasks[id]
+		if (filter.IDs != nil && !slices.Contains(filter.IDs, id)) ||
+			(filter.Status != "" && task.Status != filter.Status) ||
+			(filter.CreatedBy != "" && task.CreatedBy != filter.CreatedBy) {
+			continue
+		}
+		if len(tasks) == limit {
+			break
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// Applies the operations in order. Atomic requests undo the operations applied before one fails.
+func (repo *taskRepository) BulkWriteTasks(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool) ([]domain.BulkTaskResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var undos []func()
+	results := make([]domain.BulkTaskResult, len(operations))
+	failed := false
+	for i, operation := range operations {
+		results[i] = domain.BulkTaskResult{Index: i, Op: operation.Op, ID: operation.ID}
+		if atomic && failed {
+			results[i].Status = domain.BulkStatusSkipped
+			continue
+		}
+
+		var undo func()
+		var err error
+		switch operation.Op {
+		case domain.BulkOpCreate:
+			var created domain.Task
+			if created, undo, err = repo.insert(operation.Task); err == nil {
+				results[i].ID = created.ID.String()
+			}
+		case domain.BulkOpUpdate:
+			undo, err = repo.update(operation.ID, operation.Task)
+		case domain.BulkOpDelete:
+			undo, err = repo.delete(operation.ID)
+		default:
+			err = fmt.Errorf("unknown operation %q", operation.Op)
+		}
+
+		results[i].Status = domain.BulkStatusApplied
+		if err != nil {
+			results[i].Status = domain.BulkStatusFailed
+			results[i].Error = bulkError(err).Error()
+			failed = true
+			continue
+		}
+		undos = append(undos, undo)
+	}
+
+	if !atomic || !failed {
+		for _, undo := range undos {
+			recordUndo(ctx, &repo.mu, undo)
+		}
+	}
+
+	if atomic && failed {
+		for _, undo := range slices.Backward(undos) {
+			undo()
+		}
+		for i := range results {
+			if results[i].Status != domain.BulkStatusApplied {
+				continue
+			}
+			results[i].Status = domain.BulkStatusSkipped
+			if results[i].Op == domain.BulkOpCreate {
+				results[i].ID = ""
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// The error reported for a failed operation. IDs that cannot be parsed are reported like
+// GetTaskByID does.
+func bulkError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidID):
+		return errors.New("invalid task ID format")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.ErrConflict
+	}
+
+	return err
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	domain "task_manager/Domain"
+)
+
+type unitOfWork struct {
+	mu sync.Mutex // Held for the whole of fn, so units of work cannot undo each other's writes
+}
+
+var _ domain.UnitOfWork = (*unitOfWork)(nil)
+
+// Key of the context value marking calls made inside fn
+type unitOfWorkKey struct{}
+
+// How to undo the writes made through the context of one unit of work, in the order they were made
+type journal struct {
+	uow  *unitOfWork
+	mu   sync.Mutex
+	undo []func()
+}
+
+// Runs fn as a transaction over the repositories of this package. Each write made through the
+// context passed to fn records how to undo it, and if fn fails the writes are undone, newest first.
+// Only the records fn wrote are put back: writes made outside the unit of work while fn runs are
+// kept, unless they went to the same record. Units of work run one at a time, but calls made
+// outside any are not held up. Suits tests and demos.
+func NewUnitOfWork() domain.UnitOfWork {
+	return &unitOfWork{}
+}
+
+func (uow *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Called inside fn: join, so only the outermost call commits or rolls back
+	if journal := journalFrom(ctx); journal != nil && journal.uow == uow {
+		return fn(ctx)
+	}
+
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	journal := &journal{uow: uow}
+	if err := fn(context.WithValue(ctx, unitOfWorkKey{}, journal)); err != nil {
+		journal.mu.Lock()
+		defer journal.mu.Unlock()
+		for _, undo := range slices.Backward(journal.undo) {
+			undo()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// The journal of the unit of work ctx belongs to, or nil
+func journalFrom(ctx context.Context) *journal {
+	journal, _ := ctx.Value(unitOfWorkKey{}).(*journal)
+	return journal
+}
+
+// Records how to undo a write made through ctx, if it belongs to a unit of work. undo runs with mu
+// held; callers hold it too, so the record cannot change before undo is known.
+func recordUndo(ctx context.Context, mu sync.Locker, undo func()) {
+	journal := journalFrom(ctx)
+	if journal == nil {
+		return
+	}
+
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	journal.undo = append(journal.undo, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		undo()
+	})
+}
+
+// Records how to put back what m holds under key, or remove key if it holds nothing. Values that
+// can be changed in place are copied with clone.
+func recordPut[K comparable, V any](ctx context.Context, mu sync.Locker, m map[K]V, key K, clone func(V) V) {
+	if journalFrom(ctx) == nil {
+		return
+	}
+
+	previous, existed := m[key]
+	if existed {
+		previous = clone(previous)
+	}
+
+	recordUndo(ctx, mu, func() {
+		if existed {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+	})
+}
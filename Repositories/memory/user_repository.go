@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	domain "task_manager/Domain"
+)
+
+type userRepository struct {
+	mu    sync.RWMutex
+	users map[domain.ID]*domain.User
+}
+
+var _ domain.UserRepository = (*userRepository)(nil)
+
+func NewUserRepository() domain.UserRepository {
+	return &userRepository{users: make(map[domain.ID]*domain.User)}
+}
+
+// Creates a new user. Usernames are unique, and so are emails regardless of case.
+func (repo *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// Usernames are unique regardless of case, like the case-insensitive index of the other stores
+	if repo.findLocked(func(existing *domain.User) bool { return strings.EqualFold(existing.Username, user.Username) }) != nil {
+		return fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrUserAlreadyExists)
+	}
+
+	stored := cloneUser(user)
+	if stored.ID.IsZero() {
+		stored.ID = domain.NewID()
+	}
+
+	// The MongoDB repository reports every other unique index violation as a taken email
+	if _, exists := repo.users[stored.ID]; exists || repo.conflictLocked(stored) {
+		return fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrEmailTaken)
+	}
+
+	recordPut(ctx, &repo.mu, repo.users, stored.ID, cloneUser)
+	repo.users[stored.ID] = stored
+	user.ID = stored.ID
+
+	return nil
+}
+
+// Get a user by their ID, which unlike the username is never reused.
+func (repo *userRepository) FindUserByID(ctx context.Context, id domain.ID) (*domain.User, error) {
+	return repo.find(ctx, func(user *domain.User) bool { return user.ID == id })
+}
+
+// Get a user by their username.
+func (repo *userRepository) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return repo.find(ctx, func(user *domain.User) bool { return user.Username == username })
+}
+
+// Get a user by their email address, ignoring case.
+func (repo *userRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return repo.find(ctx, func(user *domain.User) bool { return user.Email != "" && strings.EqualFold(user.Email, email) })
+}
+
+// Get the user linked to an account at an OpenID Connect provider.
+func (repo *userRepository) FindUserByOIDCSubject(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	return repo.find(ctx, func(user *domain.User) bool {
+		return user.OIDCSubject != "" && user.OIDCIssuer == issuer && user.OIDCSubject == subject
+	})
+}
+
+// Saves changes to an existing user, matched by ID. The username never changes, and a linked
+// provider account is never unlinked.
+func (repo *userRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	existing, ok := repo.users[user.ID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	updated := cloneUser(user)
+	updated.Username = existing.Username
+	updated.TOTPLastStep = existing.TOTPLastStep
+	if updated.OIDCSubject == "" {
+		updated.OIDCIssuer, updated.OIDCSubject = existing.OIDCIssuer, existing.OIDCSubject
+	}
+
+	if repo.conflictLocked(updated) {
+		return domain.ErrEmailTaken
+	}
+
+	recordPut(ctx, &repo.mu, repo.users, user.ID, cloneUser)
+	repo.users[user.ID] = updated
+
+	return nil
+}
+
+// Removes a user by their username.
+func (repo *userRepository) DeleteUser(ctx context.Context, username string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user := repo.findLocked(func(user *domain.User) bool { return user.Username == username })
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+
+	recordPut(ctx, &repo.mu, repo.users, user.ID, cloneUser)
+	delete(repo.users, user.ID)
+
+	return nil
+}
+
+// Records the TOTP step unless the user already used it or a later one.
+func (repo *userRepository) UseTOTPStep(ctx context.Context, id domain.ID, step int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user, ok := repo.users[id]
+	if !ok || user.TOTPLastStep >= step {
+		return domain.ErrInvalidMFACode
+	}
+
+	recordPut(ctx, &repo.mu, repo.users, id, cloneUser)
+	user.TOTPLastStep = step
+
+	return nil
+}
+
+// Removes the recovery code if the user still has it.
+func (repo *userRepository) UseRecoveryCode(ctx context.Context, id domain.ID, codeHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user, ok := repo.users[id]
+	if !ok || !slices.Contains(user.RecoveryCodeHashes, codeHash) {
+		return domain.ErrInvalidMFACode
+	}
+
+	recordPut(ctx, &repo.mu, repo.users, id, cloneUser)
+	user.RecoveryCodeHashes = slices.DeleteFunc(user.RecoveryCodeHashes, func(hash string) bool { return hash == codeHash })
+
+	return nil
+}
+
+// Returns a copy of the first user matching, or ErrUserNotFound
+func (repo *userRepository) find(ctx context.Context, match func(*domain.User) bool) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	user := repo.findLocked(match)
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return cloneUser(user), nil
+}
+
+func (repo *userRepository) findLocked(match func(*domain.User) bool) *domain.User {
+	for _, user := range repo.users {
+		if match(user) {
+			return user
+		}
+	}
+
+	return nil
+}
+
+// Reports whether another user has the same email, ignoring case, or is linked to the same
+// provider account, which the MongoDB indexes forbid
+func (repo *userRepository) conflictLocked(user *domain.User) bool {
+	return repo.findLocked(func(other *domain.User) bool {
+		if other.ID == user.ID {
+			return false
+		}
+
+		sameEmail := user.Email != "" && strings.EqualFold(other.Email, user.Email)
+		sameSubject := user.OIDCSubject != "" && other.OIDCIssuer == user.OIDCIssuer && other.OIDCSubject == user.OIDCSubject
+
+		return sameEmail || sameSubject
+	}) != nil
+}
+
+// Copies a user so callers cannot change what is stored through it
+func cloneUser(user *domain.User) *domain.User {
+	clone := *user
+	clone.LockedUntil = storedTimePtr(user.LockedUntil)
+	clone.RecoveryCodeHashes = slices.Clone(user.RecoveryCodeHashes)
+
+	return &clone
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The policy is a single document in a settings collection
+const mfaPolicyID = "mfa_policy"
+
+type mfaPolicyRepository struct {
+	collection *mongo.Collection
+}
+
+var _ domain.MFAPolicyRepository = (*mfaPolicyRepository)(nil)
+
+func NewMFAPolicyRepository(db *mongo.Client, dbName, collectionName string) domain.MFAPolicyRepository {
+	return &mfaPolicyRepository{
+		collection: openCollection(db, dbName, collectionName),
+	}
+}
+
+// Returns the saved policy, or an empty one if an admin never set it
+func (repo *mfaPolicyRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	var policy domain.MFAPolicy
+
+	err := repo.collection.FindOne(ctx, bson.M{"_id": mfaPolicyID}).Decode(&policy)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &domain.MFAPolicy{RequiredRoles: []string{}}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// Replaces the policy
+func (repo *mfaPolicyRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	update := bson.M{"$set": bson.M{"required_roles": policy.RequiredRoles}}
+	_, err := repo.collection.UpdateOne(ctx, bson.M{"_id": mfaPolicyID}, update, options.Update().SetUpsert(true))
+
+	return err
+}
@@ -0,0 +1,46 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testSettingsCollectionName = "settings_integration_test_coll"
+
+func TestMFAPolicyRepository_Integration(t *testing.T) {
+	if testDBClient == nil {
+		t.Fatal("testDBClient is nil. TestMain setup for DB connection likely failed or was skipped.")
+	}
+
+	policyRepo := repositories.NewMFAPolicyRepository(testDBClient, TestDatabaseName, testSettingsCollectionName)
+	collection := testDBClient.Database(TestDatabaseName).Collection(testSettingsCollectionName)
+	ctx := context.Background()
+
+	_, err := collection.DeleteMany(ctx, bson.M{})
+	require.NoError(t, err, "Failed to clean settings test collection")
+
+	t.Run("GetMFAPolicy_DefaultsToEmpty", func(t *testing.T) {
+		policy, err := policyRepo.GetMFAPolicy(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, policy.RequiredRoles)
+	})
+
+	t.Run("SaveMFAPolicy_ReplacesPolicy", func(t *testing.T) {
+		require.NoError(t, policyRepo.SaveMFAPolicy(ctx, &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin, domain.RoleUser}}))
+		require.NoError(t, policyRepo.SaveMFAPolicy(ctx, &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin}}))
+
+		policy, err := policyRepo.GetMFAPolicy(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.RoleAdmin}, policy.RequiredRoles)
+
+		count, err := collection.CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count, "The policy should be stored as a single document")
+	})
+}
@@ -0,0 +1,391 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	domain "task_manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection that records which migrations have been applied
+const migrationsCollection = "schema_migrations"
+
+// How long a migrator may hold the lock before another instance may take it over
+const migrationLockLease = 10 * time.Minute
+
+const migrationLockID = "lock"
+
+// Names of the collections migrations change, as configured under database.collections
+type CollectionNames struct {
+	Users                string
+	Tasks                string
+	PersonalAccessTokens string
+	AuditLog             string
+	RateLimits           string
+}
+
+// A versioned change to the database: indexes, or data rewritten for a new version of the code.
+// Down undoes Up; nil means there is nothing to undo, e.g. for a backfill.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error
+	Down        func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error
+}
+
+// Whether a migration has been applied, and when
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time
+}
+
+// Every migration, oldest first. Add new ones at the end with the next version; never change one
+// that has been released, as databases that applied it will not run it again.
+func Migrations() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "create user, personal access token, audit log and rate limit indexes",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				// The indexes earlier versions created on every start
+				if err := EnsureUserIndexes(ctx, client, dbName, collections.Users); err != nil {
+					return err
+				}
+				if err := EnsurePersonalAccessTokenIndexes(ctx, client, dbName, collections.PersonalAccessTokens); err != nil {
+					return err
+				}
+				if err := EnsureAuditLogIndexes(ctx, client, dbName, collections.AuditLog); err != nil {
+					return err
+				}
+				return EnsureRateLimitIndexes(ctx, client, dbName, collections.RateLimits)
+			},
+			Down: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				if err := dropIndexes(ctx, client, dbName, collections.Users, "email_unique_ci", "oidc_subject_unique"); err != nil {
+					return err
+				}
+				if err := dropIndexes(ctx, client, dbName, collections.PersonalAccessTokens, "token_hash_unique", "username_created_at"); err != nil {
+					return err
+				}
+				if err := dropIndexes(ctx, client, dbName, collections.AuditLog, "time", "actor_time", "action_time", "target_time"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, client, dbName, collections.RateLimits, "expires_at_ttl")
+			},
+		},
+		{
+			Version:     2,
+			Description: "create unique username index",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				_, err := openCollection(client, dbName, collections.Users).Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "username", Value: 1}},
+					Options: options.Index().SetName(usernameIndex).SetUnique(true),
+				})
+				if mongo.IsDuplicateKeyError(err) {
+					return fmt.Errorf("several users share a username, rename all but one of them first: %w", err)
+				}
+				return err
+			},
+			Down: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				return dropIndexes(ctx, client, dbName, collections.Users, usernameIndex)
+			},
+		},
+		{
+			Version:     3,
+			Description: "create task status and creator indexes",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				_, err := openCollection(client, dbName, collections.Tasks).Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "status", Value: 1}}, Options: options.Index().SetName("status")},
+					{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "due_date", Value: 1}}, Options: options.Index().SetName("created_by_due_date")},
+				})
+				return err
+			},
+			Down: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				return dropIndexes(ctx, client, dbName, collections.Tasks, "status", "created_by_due_date")
+			},
+		},
+		{
+			Version:     4,
+			Description: "backfill role, token version and email verification of older users",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				users := openCollection(client, dbName, collections.Users)
+				backfills := []struct {
+					filter bson.M
+					set    bson.M
+				}{
+					{bson.M{"$or": bson.A{bson.M{"role": bson.M{"$exists": false}}, bson.M{"role": ""}}}, bson.M{"role": "user"}},
+					{bson.M{"token_version": bson.M{"$exists": false}}, bson.M{"token_version": 0}},
+					{bson.M{"email_verified": bson.M{"$exists": false}}, bson.M{"email_verified": false}},
+				}
+				for _, backfill := range backfills {
+					if _, err := users.UpdateMany(ctx, backfill.filter, bson.M{"$set": backfill.set}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     5,
+			Description: "create unique username index that ignores case",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				return EnsureUsernameIndex(ctx, client, dbName, collections.Users)
+			},
+			Down: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				return dropIndexes(ctx, client, dbName, collections.Users, usernameCaseInsensitiveIndex)
+			},
+		},
+		{
+			Version:     6,
+			Description: "rename the admin scope of personal access tokens to users:admin",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				_, err := openCollection(client, dbName, collections.PersonalAccessTokens).UpdateMany(ctx,
+					bson.M{"scopes": "admin"},
+					mongo.Pipeline{{{Key: "$set", Value: bson.M{"scopes": bson.M{"$setUnion": bson.A{
+						bson.M{"$setDifference": bson.A{"$scopes", bson.A{"admin"}}},
+						bson.A{domain.PermissionUsersAdmin},
+					}}}}}},
+				)
+				return err
+			},
+		},
+		{
+			Version:     7,
+			Description: "store the owner ID of personal access tokens",
+			Up: func(ctx context.Context, client *mongo.Client, dbName string, collections CollectionNames) error {
+				return fillTokenOwners(ctx, openCollection(client, dbName, collections.PersonalAccessTokens), openCollection(client, dbName, collections.Users))
+			},
+		},
+	}
+}
+
+// Sets the owner ID of tokens created before it was stored. A token is only given to a user created
+// before it; others belonged to a deleted account whose username was taken again, and are removed.
+func fillTokenOwners(ctx context.Context, tokens, users *mongo.Collection) error {
+	cursor, err := tokens.Find(ctx, bson.M{"user_id": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+
+	var pending []domain.PersonalAccessToken
+	if err := cursor.All(ctx, &pending); err != nil {
+		return err
+	}
+
+	for _, token := range pending {
+		var owner domain.User
+		err := users.FindOne(ctx, bson.M{"username": token.Username}).Decode(&owner)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+
+		if err == nil && !owner.ID.Time().After(token.CreatedAt) {
+			_, err = tokens.UpdateOne(ctx, bson.M{"_id": token.ID}, bson.M{"$set": bson.M{"user_id": owner.ID}})
+		} else {
+			_, err = tokens.DeleteOne(ctx, bson.M{"_id": token.ID})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Drops the named indexes, ignoring those that do not exist
+func dropIndexes(ctx context.Context, client *mongo.Client, dbName, collectionName string, names ...string) error {
+	indexes := openCollection(client, dbName, collectionName).Indexes()
+	for _, name := range names {
+		_, err := indexes.DropOne(ctx, name)
+		var commandErr mongo.CommandError
+		if errors.As(err, &commandErr) && (commandErr.Name == "IndexNotFound" || commandErr.Name == "NamespaceNotFound") {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Applies and rolls back migrations, one instance at a time
+type Migrator struct {
+	client      *mongo.Client
+	dbName      string
+	collections CollectionNames
+	migrations  []Migration
+	state       *mongo.Collection
+}
+
+// Creates a migrator for the given migrations, usually Migrations()
+func NewMigrator(client *mongo.Client, dbName string, collections CollectionNames, migrations []Migration) *Migrator {
+	return &Migrator{
+		client:      client,
+		dbName:      dbName,
+		collections: collections,
+		migrations:  migrations,
+		state:       openCollection(client, dbName, migrationsCollection),
+	}
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Every known migration, oldest first, with when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Number of migrations not applied yet
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// Applies every pending migration in version order
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := migration.Up(ctx, m.client, m.dbName, m.collections); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+			}
+			record := appliedMigration{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now().UTC()}
+			if _, err := m.state.InsertOne(ctx, record); err != nil {
+				return err
+			}
+
+			slog.InfoContext(ctx, "applied migration", slog.Int("version", migration.Version), slog.String("description", migration.Description))
+		}
+
+		return nil
+	})
+}
+
+// Rolls back the most recently applied migration. Does nothing when none has been applied.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if migration.Down != nil {
+				if err := migration.Down(ctx, m.client, m.dbName, m.collections); err != nil {
+					return fmt.Errorf("rolling back migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+				}
+			}
+			if _, err := m.state.DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+				return err
+			}
+
+			slog.InfoContext(ctx, "rolled back migration", slog.Int("version", migration.Version), slog.String("description", migration.Description))
+			return nil
+		}
+
+		return nil
+	})
+}
+
+// Applied migrations by version
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	cursor, err := m.state.Find(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// Runs fn while holding the migration lock, so instances starting together migrate one at a time.
+// A lock left behind by a crashed instance expires after migrationLockLease.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	for {
+		now := time.Now()
+		_, err := m.state.UpdateOne(ctx,
+			bson.M{"_id": migrationLockID, "locked_until": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"locked_until": now.Add(migrationLockLease)}},
+			options.Update().SetUpsert(true))
+		if err == nil {
+			break
+		}
+		// The upsert collides with the lock document while another instance holds it
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for another instance to finish migrating: %w", ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+
+	defer func() {
+		if _, err := m.state.DeleteOne(context.WithoutCancel(ctx), bson.M{"_id": migrationLockID}); err != nil {
+			slog.ErrorContext(ctx, "failed to release migration lock", slog.Any("error", err))
+		}
+	}()
+
+	return fn()
+}
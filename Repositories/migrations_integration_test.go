@@ -0,0 +1,219 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var testMigrationCollections = repositories.CollectionNames{
+	Users:                "users_migration_test_coll",
+	Tasks:                "tasks_migration_test_coll",
+	PersonalAccessTokens: "pats_migration_test_coll",
+	AuditLog:             "audit_migration_test_coll",
+	RateLimits:           "rate_limits_migration_test_coll",
+}
+
+// Drops the collections migrations touch, so every test starts from an empty database
+func resetMigrationDatabase(t *testing.T) {
+	require.NotNil(t, testDBClient, "Database client not initialized. TestMain setup might have failed.")
+	db := testDBClient.Database(TestDatabaseName)
+	for _, name := range []string{"schema_migrations", testMigrationCollections.Users, testMigrationCollections.Tasks,
+		testMigrationCollections.PersonalAccessTokens, testMigrationCollections.AuditLog, testMigrationCollections.RateLimits} {
+		require.NoError(t, db.Collection(name).Drop(context.Background()))
+	}
+}
+
+func indexNames(t *testing.T, collectionName string) []string {
+	cursor, err := testDBClient.Database(TestDatabaseName).Collection(collectionName).Indexes().List(context.Background())
+	require.NoError(t, err)
+	var indexes []struct {
+		Name string `bson:"name"`
+	}
+	require.NoError(t, cursor.All(context.Background(), &indexes))
+
+	names := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		names = append(names, index.Name)
+	}
+	return names
+}
+
+func TestMigrator_Integration(t *testing.T) {
+	ctx := context.Background()
+	newMigrator := func() *repositories.Migrator {
+		return repositories.NewMigrator(testDBClient, TestDatabaseName, testMigrationCollections, repositories.Migrations())
+	}
+
+	t.Run("Up_AppliesEveryMigrationOnce", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		migrator := newMigrator()
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, len(repositories.Migrations()), pending)
+
+		require.NoError(t, migrator.Up(ctx))
+		require.NoError(t, migrator.Up(ctx), "Migrating an up to date database should change nothing")
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.NotNil(t, status.AppliedAt, "Migration %d should be applied", status.Version)
+		}
+		assert.Contains(t, indexNames(t, testMigrationCollections.Users), "username_unique")
+		assert.Contains(t, indexNames(t, testMigrationCollections.Tasks), "created_by_due_date")
+	})
+
+	t.Run("UniqueUsernameIndex_RejectsDuplicates", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		require.NoError(t, newMigrator().Up(ctx))
+		users := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.Users)
+
+		_, err := users.InsertOne(ctx, bson.M{"username": "taken"})
+		require.NoError(t, err)
+		_, err = users.InsertOne(ctx, bson.M{"username": "taken"})
+		assert.True(t, mongo.IsDuplicateKeyError(err), "A second user with the same username should be rejected")
+		_, err = users.InsertOne(ctx, bson.M{"username": "TAKEN"})
+		assert.True(t, mongo.IsDuplicateKeyError(err), "Usernames should be unique regardless of case")
+	})
+
+	t.Run("Up_FailsOnDuplicateUsernames", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		users := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.Users)
+		_, err := users.InsertMany(ctx, []any{bson.M{"username": "twin"}, bson.M{"username": "twin"}})
+		require.NoError(t, err)
+
+		err = newMigrator().Up(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "migration 2")
+
+		// Migrations before the failed one stay applied
+		statuses, err := newMigrator().Status(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, statuses[0].AppliedAt)
+		assert.Nil(t, statuses[1].AppliedAt)
+	})
+
+	t.Run("Up_FailsOnUsernamesDifferingInCase", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		users := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.Users)
+		_, err := users.InsertMany(ctx, []any{bson.M{"username": "twin"}, bson.M{"username": "Twin"}})
+		require.NoError(t, err)
+
+		err = newMigrator().Up(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "differ only in case")
+	})
+
+	t.Run("Up_BackfillsOlderUsers", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		users := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.Users)
+		_, err := users.InsertOne(ctx, bson.M{"username": "old_user", "password_hash": "hash"})
+		require.NoError(t, err)
+
+		require.NoError(t, newMigrator().Up(ctx))
+
+		var user domain.User
+		require.NoError(t, users.FindOne(ctx, bson.M{"username": "old_user"}).Decode(&user))
+		assert.Equal(t, domain.RoleUser, user.Role)
+		assert.False(t, user.EmailVerified)
+	})
+
+	t.Run("Up_RenamesTheAdminScope", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		users := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.Users)
+		_, err := users.InsertOne(ctx, bson.M{"username": "pat_user"})
+		require.NoError(t, err)
+		tokens := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.PersonalAccessTokens)
+		createdAt := time.Now().Add(time.Minute)
+		_, err = tokens.InsertMany(ctx, []any{
+			bson.M{"username": "pat_user", "token_hash": "old", "scopes": bson.A{"tasks:read", "admin"}, "created_at": createdAt},
+			bson.M{"username": "pat_user", "token_hash": "new", "scopes": bson.A{domain.PermissionTasksRead}, "created_at": createdAt},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, newMigrator().Up(ctx))
+
+		var token domain.PersonalAccessToken
+		require.NoError(t, tokens.FindOne(ctx, bson.M{"token_hash": "old"}).Decode(&token))
+		assert.ElementsMatch(t, []string{domain.PermissionTasksRead, domain.PermissionUsersAdmin}, token.Scopes)
+		require.NoError(t, tokens.FindOne(ctx, bson.M{"token_hash": "new"}).Decode(&token))
+		assert.Equal(t, []string{domain.PermissionTasksRead}, token.Scopes)
+	})
+
+	t.Run("Up_FillsTokenOwners", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		users := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.Users)
+		owner := domain.NewID()
+		_, err := users.InsertOne(ctx, bson.M{"_id": owner, "username": "alice"})
+		require.NoError(t, err)
+		tokens := testDBClient.Database(TestDatabaseName).Collection(testMigrationCollections.PersonalAccessTokens)
+		now := time.Now()
+		_, err = tokens.InsertMany(ctx, []any{
+			bson.M{"username": "alice", "token_hash": "owned", "created_at": now.Add(time.Minute)},
+			// Created by an earlier account named alice, deleted before this one registered
+			bson.M{"username": "alice", "token_hash": "earlier", "created_at": now.Add(-time.Hour)},
+			bson.M{"username": "ghost", "token_hash": "orphaned", "created_at": now},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, newMigrator().Up(ctx))
+
+		var token domain.PersonalAccessToken
+		require.NoError(t, tokens.FindOne(ctx, bson.M{"token_hash": "owned"}).Decode(&token))
+		assert.Equal(t, owner, token.UserID)
+		count, err := tokens.CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count, "Tokens without an owner should be removed")
+	})
+
+	t.Run("Down_RollsBackTheLatestMigration", func(t *testing.T) {
+		resetMigrationDatabase(t)
+		migrator := newMigrator()
+		require.NoError(t, migrator.Up(ctx))
+
+		// Rolls back the token owner backfill and the scope rename, which have nothing to undo, then
+		// drops the case-insensitive username index
+		require.NoError(t, migrator.Down(ctx))
+		require.NoError(t, migrator.Down(ctx))
+		require.NoError(t, migrator.Down(ctx))
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, statuses[6].AppliedAt)
+		assert.Nil(t, statuses[5].AppliedAt)
+		assert.Nil(t, statuses[4].AppliedAt)
+		assert.NotNil(t, statuses[3].AppliedAt)
+		assert.NotContains(t, indexNames(t, testMigrationCollections.Users), "username_unique_ci")
+		assert.Contains(t, indexNames(t, testMigrationCollections.Tasks), "created_by_due_date")
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, len(statuses)-4, pending)
+	})
+
+	t.Run("Up_WaitsForTheLock", func(t *testing.T) {
+		resetMigrationDatabase(t)
+
+		// Migrators started together apply each migration once between them
+		errs := make(chan error, 3)
+		for range 3 {
+			go func() { errs <- newMigrator().Up(ctx) }()
+		}
+		for range 3 {
+			require.NoError(t, <-errs)
+		}
+
+		count, err := testDBClient.Database(TestDatabaseName).Collection("schema_migrations").CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(repositories.Migrations())), count, "Only the applied migrations should be recorded, and the lock released")
+	})
+}
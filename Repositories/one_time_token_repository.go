@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type oneTimeTokenRepository struct {
+	collection *mongo.Collection
+}
+
+var _ domain.OneTimeTokenRepository = (*oneTimeTokenRepository)(nil)
+
+func NewOneTimeTokenRepository(db *mongo.Client, dbName, collectionName string) domain.OneTimeTokenRepository {
+	return &oneTimeTokenRepository{
+		collection: openCollection(db, dbName, collectionName),
+	}
+}
+
+// Stores a new (hashed) token
+func (repo *oneTimeTokenRepository) CreateToken(ctx context.Context, token *domain.OneTimeToken) error {
+	result, err := repo.collection.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	token.ID = insertedID(result)
+
+	return nil
+}
+
+// Marks the token as used in a single update, so two concurrent requests
+// with the same token cannot both succeed.
+func (repo *oneTimeTokenRepository) ConsumeToken(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	now := time.Now()
+
+	filter := bson.M{
+		"purpose":    purpose,
+		"token_hash": tokenHash,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"used_at": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var token domain.OneTimeToken
+	err := repo.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Looks a token up without marking it as used
+func (repo *oneTimeTokenRepository) FindToken(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	filter := bson.M{
+		"purpose":    purpose,
+		"token_hash": tokenHash,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	var token domain.OneTimeToken
+	err := repo.collection.FindOne(ctx, filter).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Removes every token of the given purpose issued to a user
+func (repo *oneTimeTokenRepository) DeleteTokensForUser(ctx context.Context, username, purpose string) error {
+	_, err := repo.collection.DeleteMany(ctx, bson.M{"username": username, "purpose": purpose})
+
+	return err
+}
@@ -0,0 +1,95 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testTokenCollectionName = "one_time_tokens_integration_test_coll"
+
+func TestOneTimeTokenRepository_Integration(t *testing.T) {
+	if testDBClient == nil {
+		t.Fatal("testDBClient is nil. TestMain setup for DB connection likely failed or was skipped.")
+	}
+
+	tokenRepo := repositories.NewOneTimeTokenRepository(testDBClient, TestDatabaseName, testTokenCollectionName)
+	collection := testDBClient.Database(TestDatabaseName).Collection(testTokenCollectionName)
+	ctx := context.Background()
+
+	cleanCollection := func(t *testing.T) {
+		_, err := collection.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err, "Failed to clean token test collection")
+	}
+
+	newToken := func(hash string, expiresAt time.Time) *domain.OneTimeToken {
+		return &domain.OneTimeToken{
+			Username:  "integ_token_user",
+			Purpose:   domain.TokenPurposePasswordReset,
+			TokenHash: hash,
+			CreatedAt: time.Now(),
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	t.Run("ConsumeToken_SingleUse", func(t *testing.T) {
+		cleanCollection(t)
+		token := newToken("hash-1", time.Now().Add(time.Hour))
+		require.NoError(t, tokenRepo.CreateToken(ctx, token))
+		assert.False(t, token.ID.IsZero(), "CreateToken should set the generated ID")
+
+		consumed, err := tokenRepo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, "integ_token_user", consumed.Username)
+		assert.NotNil(t, consumed.UsedAt)
+
+		_, err = tokenRepo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-1")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, "A token must not be usable twice")
+	})
+
+	t.Run("ConsumeToken_Expired", func(t *testing.T) {
+		cleanCollection(t)
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("hash-2", time.Now().Add(-time.Minute))))
+
+		_, err := tokenRepo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-2")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("ConsumeToken_WrongPurpose", func(t *testing.T) {
+		cleanCollection(t)
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("hash-3", time.Now().Add(time.Hour))))
+
+		_, err := tokenRepo.ConsumeToken(ctx, "some_other_purpose", "hash-3")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("DeleteTokensForUser", func(t *testing.T) {
+		cleanCollection(t)
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("hash-4", time.Now().Add(time.Hour))))
+
+		require.NoError(t, tokenRepo.DeleteTokensForUser(ctx, "integ_token_user", domain.TokenPurposePasswordReset))
+
+		_, err := tokenRepo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-4")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, "Deleted tokens should no longer be usable")
+	})
+	t.Run("FindToken_DoesNotUseToken", func(t *testing.T) {
+		cleanCollection(t)
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("hash-5", time.Now().Add(time.Hour))))
+
+		found, err := tokenRepo.FindToken(ctx, domain.TokenPurposePasswordReset, "hash-5")
+		require.NoError(t, err)
+		assert.Equal(t, "integ_token_user", found.Username)
+
+		_, err = tokenRepo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-5")
+		assert.NoError(t, err, "Finding a token must not use it up")
+
+		_, err = tokenRepo.FindToken(ctx, domain.TokenPurposePasswordReset, "hash-5")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, "Used tokens are not found")
+	})
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	domain "task_manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type personalAccessTokenRepository struct {
+	collection *mongo.Collection
+}
+
+var _ domain.PersonalAccessTokenRepository = (*personalAccessTokenRepository)(nil)
+
+func NewPersonalAccessTokenRepository(db *mongo.Client, dbName, collectionName string) domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		collection: openCollection(db, dbName, collectionName),
+	}
+}
+
+// Stores a new (hashed) token
+func (repo *personalAccessTokenRepository) CreateToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+	result, err := repo.collection.InsertOne(ctx, token)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	token.ID = insertedID(result)
+
+	return nil
+}
+
+func (repo *personalAccessTokenRepository) FindTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+
+	err := repo.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrTokenNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Lists a user's tokens, newest first
+func (repo *personalAccessTokenRepository) ListTokensForUser(ctx context.Context, username string) ([]domain.PersonalAccessToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := repo.collection.Find(ctx, bson.M{"username": username}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []domain.PersonalAccessToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (repo *personalAccessTokenRepository) DeleteToken(ctx context.Context, username, id string) error {
+	objectID, err := domain.ParseID(id)
+	if err != nil {
+		return domain.ErrTokenNotFound
+	}
+
+	result, err := repo.collection.DeleteOne(ctx, bson.M{"_id": objectID, "username": username})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// Revokes every token of a user
+func (repo *personalAccessTokenRepository) DeleteTokensForUser(ctx context.Context, username string) error {
+	_, err := repo.collection.DeleteMany(ctx, bson.M{"username": username})
+
+	return err
+}
+
+func (repo *personalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	objectID, err := domain.ParseID(id)
+	if err != nil {
+		return domain.ErrTokenNotFound
+	}
+
+	_, err = repo.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_used_at": lastUsedAt}})
+
+	return err
+}
+
+// Creates the indexes personal access token lookups rely on. Safe to call on every start.
+func EnsurePersonalAccessTokenIndexes(ctx context.Context, db *mongo.Client, dbName, collectionName string) error {
+	collection := openCollection(db, dbName, collectionName)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetName("token_hash_unique").SetUnique(true)},
+		{Keys: bson.D{{Key: "username", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("username_created_at")},
+	})
+	return err
+}
@@ -0,0 +1,108 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testPATCollectionName = "personal_access_tokens_integration_test_coll"
+
+func TestPersonalAccessTokenRepository_Integration(t *testing.T) {
+	if testDBClient == nil {
+		t.Fatal("testDBClient is nil. TestMain setup for DB connection likely failed or was skipped.")
+	}
+
+	tokenRepo := repositories.NewPersonalAccessTokenRepository(testDBClient, TestDatabaseName, testPATCollectionName)
+	collection := testDBClient.Database(TestDatabaseName).Collection(testPATCollectionName)
+	ctx := context.Background()
+
+	cleanCollection := func(t *testing.T) {
+		_, err := collection.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err, "Failed to clean personal access token test collection")
+	}
+
+	newToken := func(username, hash string, createdAt time.Time) *domain.PersonalAccessToken {
+		return &domain.PersonalAccessToken{
+			Username:  username,
+			Name:      "ci",
+			Prefix:    "tm_pat_abcd1234",
+			TokenHash: hash,
+			Scopes:    []string{domain.PermissionTasksRead},
+			CreatedAt: createdAt,
+			ExpiresAt: createdAt.Add(30 * 24 * time.Hour),
+		}
+	}
+
+	t.Run("CreateAndFindByHash", func(t *testing.T) {
+		cleanCollection(t)
+
+		token := newToken("integ_pat_user", "hash-1", time.Now())
+		require.NoError(t, tokenRepo.CreateToken(ctx, token))
+		assert.False(t, token.ID.IsZero(), "CreateToken should set the ID")
+
+		found, err := tokenRepo.FindTokenByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, found.ID)
+		assert.Equal(t, []string{domain.PermissionTasksRead}, found.Scopes)
+
+		_, err = tokenRepo.FindTokenByHash(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+	})
+
+	t.Run("ListTokensForUser_NewestFirst", func(t *testing.T) {
+		cleanCollection(t)
+
+		now := time.Now()
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("integ_pat_user", "older", now.Add(-time.Hour))))
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("integ_pat_user", "newer", now)))
+		require.NoError(t, tokenRepo.CreateToken(ctx, newToken("someone_else", "other", now)))
+
+		tokens, err := tokenRepo.ListTokensForUser(ctx, "integ_pat_user")
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		assert.Equal(t, "newer", tokens[0].TokenHash)
+
+		tokens, err = tokenRepo.ListTokensForUser(ctx, "nobody")
+		require.NoError(t, err)
+		assert.NotNil(t, tokens, "An empty list should not be nil")
+	})
+
+	t.Run("DeleteToken_OnlyOwnTokens", func(t *testing.T) {
+		cleanCollection(t)
+
+		token := newToken("integ_pat_user", "hash-2", time.Now())
+		require.NoError(t, tokenRepo.CreateToken(ctx, token))
+
+		err := tokenRepo.DeleteToken(ctx, "someone_else", token.ID.String())
+		assert.ErrorIs(t, err, domain.ErrTokenNotFound, "Users must not revoke other users' tokens")
+
+		require.NoError(t, tokenRepo.DeleteToken(ctx, "integ_pat_user", token.ID.String()))
+		_, err = tokenRepo.FindTokenByHash(ctx, "hash-2")
+		assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+		assert.ErrorIs(t, tokenRepo.DeleteToken(ctx, "integ_pat_user", "not-an-id"), domain.ErrTokenNotFound)
+		assert.ErrorIs(t, tokenRepo.DeleteToken(ctx, "integ_pat_user", domain.NewID().String()), domain.ErrTokenNotFound)
+	})
+
+	t.Run("UpdateLastUsed", func(t *testing.T) {
+		cleanCollection(t)
+
+		token := newToken("integ_pat_user", "hash-3", time.Now())
+		require.NoError(t, tokenRepo.CreateToken(ctx, token))
+
+		usedAt := time.Now().Truncate(time.Millisecond)
+		require.NoError(t, tokenRepo.UpdateLastUsed(ctx, token.ID.String(), usedAt))
+
+		found, err := tokenRepo.FindTokenByHash(ctx, "hash-3")
+		require.NoError(t, err)
+		require.NotNil(t, found.LastUsedAt)
+		assert.True(t, usedAt.Equal(*found.LastUsedAt))
+	})
+}
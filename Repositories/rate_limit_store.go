@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Token bucket stored in MongoDB, so every instance of the service draws from the same one
+type rateLimitBucket struct {
+	Key     string  `bson:"_id"`
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+type rateLimitStore struct {
+	collection *mongo.Collection
+}
+
+var _ domain.RateLimitStore = (*rateLimitStore)(nil)
+
+func NewRateLimitStore(db *mongo.Client, dbName, collectionName string) domain.RateLimitStore {
+	return &rateLimitStore{
+		collection: openCollection(db, dbName, collectionName),
+	}
+}
+
+// Refill and take from the bucket in a single update, so concurrent requests cannot both take the
+// last token. Times come from the database server ($$NOW), which keeps instances with drifting
+// clocks consistent.
+func (store *rateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error) {
+	burst := float64(limit.Burst)
+	tokensPerMilli := limit.TokensPerSecond() / 1000
+	fullRefill := time.Duration(burst / limit.TokensPerSecond() * float64(time.Second))
+
+	elapsedMillis := bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$ifNull": bson.A{"$updated_at", "$$NOW"}}}}
+	refilled := bson.M{"$min": bson.A{
+		burst,
+		bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$tokens", burst}}, bson.M{"$multiply": bson.A{elapsedMillis, tokensPerMilli}}}},
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tokens": refilled, "updated_at": "$$NOW"}}},
+		{{Key: "$set", Value: bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}}},
+		{{Key: "$set", Value: bson.M{
+			"tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": bson.M{"$add": bson.A{"$$NOW", fullRefill.Milliseconds()}},
+		}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var bucket rateLimitBucket
+	err := store.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&bucket)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the bucket at the same time; it exists now, so update it
+		err = store.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&bucket)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return limit.Decision(bucket.Tokens, bucket.Allowed), nil
+}
+
+// Creates the TTL index that removes buckets once they have refilled. Safe to call on every start.
+func EnsureRateLimitIndexes(ctx context.Context, db *mongo.Client, dbName, collectionName string) error {
+	collection := openCollection(db, dbName, collectionName)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	})
+	return err
+}
@@ -0,0 +1,75 @@
+package repositories_test
+
+import (
+	"context"
+	"sync"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testRateLimitCollectionName = "rate_limits_integration_test_coll"
+
+func TestRateLimitStore_Integration(t *testing.T) {
+	if testDBClient == nil {
+		t.Fatal("testDBClient is nil. TestMain setup for DB connection likely failed or was skipped.")
+	}
+
+	store := repositories.NewRateLimitStore(testDBClient, TestDatabaseName, testRateLimitCollectionName)
+	collection := testDBClient.Database(TestDatabaseName).Collection(testRateLimitCollectionName)
+	ctx := context.Background()
+
+	cleanCollection := func(t *testing.T) {
+		_, err := collection.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err, "Failed to clean rate limit test collection")
+	}
+
+	t.Run("AllowsBurstThenRefuses", func(t *testing.T) {
+		cleanCollection(t)
+		limit := domain.RateLimit{Rate: 1, Period: time.Hour, Burst: 2}
+
+		for remaining := 1; remaining >= 0; remaining-- {
+			decision, err := store.Take(ctx, "ip:198.51.100.1", limit)
+			require.NoError(t, err)
+			assert.True(t, decision.Allowed)
+			assert.Equal(t, remaining, decision.Remaining)
+		}
+
+		decision, err := store.Take(ctx, "ip:198.51.100.1", limit)
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Greater(t, decision.RetryAfter, 59*time.Minute)
+	})
+
+	t.Run("ConcurrentRequestsShareTheBucket", func(t *testing.T) {
+		cleanCollection(t)
+		limit := domain.RateLimit{Rate: 1, Period: time.Hour, Burst: 5}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		allowed := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				decision, err := store.Take(ctx, "user:alice", limit)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if decision.Allowed {
+					allowed++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 5, allowed, "Exactly the burst size may get through")
+	})
+}
@@ -0,0 +1,74 @@
+package repositorytest
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks an AuditLogRepository. newRepo must return an empty repository each time it is called.
+func TestAuditLogRepository(t *testing.T, newRepo func(t *testing.T) domain.AuditLogRepository) {
+	ctx := context.Background()
+
+	newEvent := func(actor, action, outcome string, at time.Time) *domain.AuditEvent {
+		return &domain.AuditEvent{
+			Time:       at,
+			Action:     action,
+			Outcome:    outcome,
+			Actor:      actor,
+			ClientIP:   "203.0.113.7",
+			TargetType: domain.AuditTargetUser,
+			TargetID:   actor,
+			Details:    map[string]string{"method": "password"},
+		}
+	}
+
+	t.Run("AppendEvent_SetsID", func(t *testing.T) {
+		repo := newRepo(t)
+		event := newEvent("alice", domain.AuditActionLogin, domain.AuditOutcomeSuccess, time.Now())
+
+		require.NoError(t, repo.AppendEvent(ctx, event))
+		assert.False(t, event.ID.IsZero(), "AppendEvent should set the ID")
+
+		events, err := repo.FindEvents(ctx, domain.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, event.ID, events[0].ID)
+		assert.Equal(t, map[string]string{"method": "password"}, events[0].Details)
+	})
+
+	t.Run("FindEvents_FiltersNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now().Truncate(time.Millisecond)
+		require.NoError(t, repo.AppendEvent(ctx, newEvent("alice", domain.AuditActionLogin, domain.AuditOutcomeFailure, now.Add(-2*time.Hour))))
+		require.NoError(t, repo.AppendEvent(ctx, newEvent("alice", domain.AuditActionLogin, domain.AuditOutcomeSuccess, now.Add(-time.Hour))))
+		require.NoError(t, repo.AppendEvent(ctx, newEvent("alice", domain.AuditActionTaskDelete, domain.AuditOutcomeSuccess, now)))
+		require.NoError(t, repo.AppendEvent(ctx, newEvent("bob", domain.AuditActionLogin, domain.AuditOutcomeSuccess, now)))
+
+		events, err := repo.FindEvents(ctx, domain.AuditFilter{Actor: "alice", Action: domain.AuditActionLogin})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, domain.AuditOutcomeSuccess, events[0].Outcome, "Newest event first")
+
+		events, err = repo.FindEvents(ctx, domain.AuditFilter{Outcome: domain.AuditOutcomeFailure})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "alice", events[0].Actor)
+
+		events, err = repo.FindEvents(ctx, domain.AuditFilter{From: now.Add(-90 * time.Minute), To: now})
+		require.NoError(t, err)
+		require.Len(t, events, 1, "From is inclusive and To exclusive")
+
+		events, err = repo.FindEvents(ctx, domain.AuditFilter{Limit: 3})
+		require.NoError(t, err)
+		assert.Len(t, events, 3)
+
+		events, err = repo.FindEvents(ctx, domain.AuditFilter{Actor: "nobody"})
+		require.NoError(t, err)
+		assert.NotNil(t, events, "An empty list should not be nil")
+	})
+}
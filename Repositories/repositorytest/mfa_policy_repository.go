@@ -0,0 +1,35 @@
+package repositorytest
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks an MFAPolicyRepository. newRepo must return a repository with no policy saved each time it
+// is called.
+func TestMFAPolicyRepository(t *testing.T, newRepo func(t *testing.T) domain.MFAPolicyRepository) {
+	ctx := context.Background()
+
+	t.Run("GetMFAPolicy_DefaultsToEmpty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		policy, err := repo.GetMFAPolicy(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, policy.RequiredRoles)
+	})
+
+	t.Run("SaveMFAPolicy_ReplacesPolicy", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.NoError(t, repo.SaveMFAPolicy(ctx, &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin, domain.RoleUser}}))
+		require.NoError(t, repo.SaveMFAPolicy(ctx, &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin}}))
+
+		policy, err := repo.GetMFAPolicy(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.RoleAdmin}, policy.RequiredRoles)
+	})
+}
@@ -0,0 +1,83 @@
+package repositorytest
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks a OneTimeTokenRepository. newRepo must return an empty repository each time it is called.
+func TestOneTimeTokenRepository(t *testing.T, newRepo func(t *testing.T) domain.OneTimeTokenRepository) {
+	ctx := context.Background()
+
+	newToken := func(hash string, expiresAt time.Time) *domain.OneTimeToken {
+		return &domain.OneTimeToken{
+			Username:  "token_user",
+			Purpose:   domain.TokenPurposePasswordReset,
+			TokenHash: hash,
+			CreatedAt: time.Now(),
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	t.Run("ConsumeToken_SingleUse", func(t *testing.T) {
+		repo := newRepo(t)
+		token := newToken("hash-1", time.Now().Add(time.Hour))
+		require.NoError(t, repo.CreateToken(ctx, token))
+		assert.False(t, token.ID.IsZero(), "CreateToken should set the generated ID")
+
+		consumed, err := repo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, consumed.ID)
+		assert.Equal(t, "token_user", consumed.Username)
+		assert.NotNil(t, consumed.UsedAt)
+
+		_, err = repo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-1")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, "A token must not be usable twice")
+	})
+
+	t.Run("ConsumeToken_Expired", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.CreateToken(ctx, newToken("hash-2", time.Now().Add(-time.Minute))))
+
+		_, err := repo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-2")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("ConsumeToken_WrongPurpose", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.CreateToken(ctx, newToken("hash-3", time.Now().Add(time.Hour))))
+
+		_, err := repo.ConsumeToken(ctx, domain.TokenPurposeEmailVerification, "hash-3")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("DeleteTokensForUser", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.CreateToken(ctx, newToken("hash-4", time.Now().Add(time.Hour))))
+
+		require.NoError(t, repo.DeleteTokensForUser(ctx, "token_user", domain.TokenPurposePasswordReset))
+
+		_, err := repo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-4")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, "Deleted tokens should no longer be usable")
+	})
+
+	t.Run("FindToken_DoesNotUseToken", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.CreateToken(ctx, newToken("hash-5", time.Now().Add(time.Hour))))
+
+		found, err := repo.FindToken(ctx, domain.TokenPurposePasswordReset, "hash-5")
+		require.NoError(t, err)
+		assert.Equal(t, "token_user", found.Username)
+
+		_, err = repo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, "hash-5")
+		assert.NoError(t, err, "Finding a token must not use it up")
+
+		_, err = repo.FindToken(ctx, domain.TokenPurposePasswordReset, "hash-5")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, "Used tokens are not found")
+	})
+}
@@ -0,0 +1,111 @@
+package repositorytest
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks a PersonalAccessTokenRepository. newRepo must return an empty repository each time it is
+// called, with token hashes unique.
+func TestPersonalAccessTokenRepository(t *testing.T, newRepo func(t *testing.T) domain.PersonalAccessTokenRepository) {
+	ctx := context.Background()
+
+	newToken := func(username, hash string, createdAt time.Time) *domain.PersonalAccessToken {
+		return &domain.PersonalAccessToken{
+			Username:  username,
+			Name:      "ci",
+			Prefix:    "tm_pat_abcd1234",
+			TokenHash: hash,
+			Scopes:    []string{domain.PermissionTasksRead},
+			CreatedAt: createdAt,
+			ExpiresAt: createdAt.Add(30 * 24 * time.Hour),
+		}
+	}
+
+	t.Run("CreateAndFindByHash", func(t *testing.T) {
+		repo := newRepo(t)
+		token := newToken("pat_user", "hash-1", time.Now())
+		token.UserID = domain.NewID()
+		require.NoError(t, repo.CreateToken(ctx, token))
+		assert.False(t, token.ID.IsZero(), "CreateToken should set the ID")
+
+		found, err := repo.FindTokenByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, found.ID)
+		assert.Equal(t, token.UserID, found.UserID)
+		assert.Equal(t, []string{domain.PermissionTasksRead}, found.Scopes)
+
+		_, err = repo.FindTokenByHash(ctx, "unknown")
+		assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+		err = repo.CreateToken(ctx, newToken("pat_user", "hash-1", time.Now()))
+		assert.ErrorIs(t, err, domain.ErrConflict, "Token hashes are unique")
+	})
+
+	t.Run("ListTokensForUser_NewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now()
+		require.NoError(t, repo.CreateToken(ctx, newToken("pat_user", "older", now.Add(-time.Hour))))
+		require.NoError(t, repo.CreateToken(ctx, newToken("pat_user", "newer", now)))
+		require.NoError(t, repo.CreateToken(ctx, newToken("someone_else", "other", now)))
+
+		tokens, err := repo.ListTokensForUser(ctx, "pat_user")
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		assert.Equal(t, "newer", tokens[0].TokenHash)
+
+		tokens, err = repo.ListTokensForUser(ctx, "nobody")
+		require.NoError(t, err)
+		assert.NotNil(t, tokens, "An empty list should not be nil")
+	})
+
+	t.Run("DeleteToken_OnlyOwnTokens", func(t *testing.T) {
+		repo := newRepo(t)
+		token := newToken("pat_user", "hash-2", time.Now())
+		require.NoError(t, repo.CreateToken(ctx, token))
+
+		err := repo.DeleteToken(ctx, "someone_else", token.ID.String())
+		assert.ErrorIs(t, err, domain.ErrTokenNotFound, "Users must not revoke other users' tokens")
+
+		require.NoError(t, repo.DeleteToken(ctx, "pat_user", token.ID.String()))
+		_, err = repo.FindTokenByHash(ctx, "hash-2")
+		assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+		assert.ErrorIs(t, repo.DeleteToken(ctx, "pat_user", "not-an-id"), domain.ErrTokenNotFound)
+		assert.ErrorIs(t, repo.DeleteToken(ctx, "pat_user", domain.NewID().String()), domain.ErrTokenNotFound)
+	})
+
+	t.Run("DeleteTokensForUser", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.CreateToken(ctx, newToken("pat_user", "hash-3", time.Now())))
+		require.NoError(t, repo.CreateToken(ctx, newToken("pat_user", "hash-4", time.Now())))
+		require.NoError(t, repo.CreateToken(ctx, newToken("someone_else", "hash-5", time.Now())))
+
+		require.NoError(t, repo.DeleteTokensForUser(ctx, "pat_user"))
+
+		tokens, err := repo.ListTokensForUser(ctx, "pat_user")
+		require.NoError(t, err)
+		assert.Empty(t, tokens)
+		_, err = repo.FindTokenByHash(ctx, "hash-5")
+		assert.NoError(t, err, "Other users' tokens are kept")
+	})
+
+	t.Run("UpdateLastUsed", func(t *testing.T) {
+		repo := newRepo(t)
+		token := newToken("pat_user", "hash-3", time.Now())
+		require.NoError(t, repo.CreateToken(ctx, token))
+
+		usedAt := time.Now().Truncate(time.Millisecond)
+		require.NoError(t, repo.UpdateLastUsed(ctx, token.ID.String(), usedAt))
+
+		found, err := repo.FindTokenByHash(ctx, "hash-3")
+		require.NoError(t, err)
+		require.NotNil(t, found.LastUsedAt)
+		assert.True(t, usedAt.Equal(*found.LastUsedAt))
+	})
+}
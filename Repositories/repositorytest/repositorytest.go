@@ -0,0 +1,19 @@
+// Package repositorytest holds the behaviour every implementation of a domain repository must
+// share. Each implementation's tests run these suites with a factory returning an empty repository,
+// so the MongoDB, SQL and in-memory repositories cannot drift apart.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Runs fn with a context that is already cancelled and checks that it fails
+func requireFailsWhenCancelled(t *testing.T, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.Error(t, fn(ctx), "Calls with a cancelled context should fail")
+}
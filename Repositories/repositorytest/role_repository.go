@@ -0,0 +1,59 @@
+package repositorytest
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks a RoleRepository. newRepo must return a repository holding only the default roles each
+// time it is called.
+func TestRoleRepository(t *testing.T, newRepo func(t *testing.T) domain.RoleRepository) {
+	ctx := context.Background()
+
+	t.Run("DefaultRoles", func(t *testing.T) {
+		repo := newRepo(t)
+
+		role, err := repo.GetRole(ctx, domain.RoleUser)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{domain.PermissionTasksRead, domain.PermissionTasksWrite}, role.Permissions)
+
+		role, err = repo.GetRole(ctx, domain.RoleAdmin)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, domain.AllPermissions, role.Permissions)
+	})
+
+	t.Run("SaveRole_ReplacesPermissions", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.NoError(t, repo.SaveRole(ctx, &domain.Role{Name: domain.RoleUser, Permissions: []string{domain.PermissionTasksRead}}))
+
+		role, err := repo.GetRole(ctx, domain.RoleUser)
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.PermissionTasksRead}, role.Permissions)
+	})
+
+	t.Run("SaveRole_CreatesAndLists", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.NoError(t, repo.SaveRole(ctx, &domain.Role{Name: "auditor", Permissions: []string{domain.PermissionTasksRead}}))
+
+		roles, err := repo.ListRoles(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, role := range roles {
+			names = append(names, role.Name)
+		}
+		assert.Equal(t, []string{domain.RoleAdmin, "auditor", domain.RoleUser}, names, "Roles are listed by name")
+	})
+
+	t.Run("GetRole_NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetRole(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrRoleNotFound)
+	})
+}
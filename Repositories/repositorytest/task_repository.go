@@ -0,0 +1,318 @@
+package repositorytest
+
+import (
+	"context"
+	"sync"
+	domain "task_manager/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks a TaskRepository. newRepo must return an empty repository each time it is called.
+func TestTaskRepository(t *testing.T, newRepo func(t *testing.T) domain.TaskRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo domain.TaskRepository, task domain.Task) domain.ID {
+		created, err := repo.NewTask(ctx, task)
+		require.NoError(t, err)
+		assert.Equal(t, task.Title, created.Title)
+		return created.ID
+	}
+
+	t.Run("NewTask_GeneratesID", func(t *testing.T) {
+		repo := newRepo(t)
+		task := domain.Task{
+			Title:       "Write report",
+			Description: "Quarterly numbers",
+			Status:      "Pending",
+			CreatedBy:   "alice",
+			DueDate:     time.Now().Add(48 * time.Hour).Truncate(time.Millisecond),
+		}
+
+		id := create(t, repo, task)
+		assert.False(t, id.IsZero())
+
+		found, err := repo.GetTaskByID(ctx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID)
+		assert.Equal(t, task.Title, found.Title)
+		assert.Equal(t, task.Description, found.Description)
+		assert.Equal(t, task.Status, found.Status)
+		assert.Equal(t, task.CreatedBy, found.CreatedBy)
+		assert.True(t, task.DueDate.Equal(found.DueDate), "Due dates should match")
+	})
+
+	t.Run("NewTask_KeepsGivenID", func(t *testing.T) {
+		repo := newRepo(t)
+		given := domain.NewID()
+
+		assert.Equal(t, given, create(t, repo, domain.Task{ID: given, Title: "Given ID"}))
+
+		_, err := repo.NewTask(ctx, domain.Task{ID: given, Title: "Same ID"})
+		assert.ErrorIs(t, err, domain.ErrConflict, "IDs are unique")
+	})
+
+	t.Run("GetTaskByID_NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetTaskByID(ctx, domain.NewID().String())
+		assert.EqualError(t, err, "task not found")
+	})
+
+	t.Run("GetTaskByID_InvalidIDFormat", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetTaskByID(ctx, "this-is-not-an-object-id")
+		assert.EqualError(t, err, "invalid task ID format")
+	})
+
+	t.Run("GetAllTask", func(t *testing.T) {
+		repo := newRepo(t)
+
+		tasks, err := repo.GetAllTask(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+
+		first := create(t, repo, domain.Task{Title: "Task A", Status: "Todo"})
+		second := create(t, repo, domain.Task{Title: "Task B", Status: "Done"})
+
+		tasks, err = repo.GetAllTask(ctx)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.ElementsMatch(t, []domain.ID{first, second}, []domain.ID{tasks[0].ID, tasks[1].ID})
+	})
+
+	t.Run("UpdateTask_SetsFieldsGiven", func(t *testing.T) {
+		repo := newRepo(t)
+		id := create(t, repo, domain.Task{Title: "Original", Description: "Kept", Status: "Pending", CreatedBy: "alice"})
+		dueDate := time.Now().Add(72 * time.Hour).Truncate(time.Millisecond)
+
+		require.NoError(t, repo.UpdateTask(ctx, id.String(), domain.Task{Title: "Updated", Status: "Completed", DueDate: dueDate, CreatedBy: "mallory"}))
+
+		updated, err := repo.GetTaskByID(ctx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, "Updated", updated.Title)
+		assert.Equal(t, "Kept", updated.Description, "Empty fields are left alone")
+		assert.Equal(t, "Completed", updated.Status)
+		assert.True(t, dueDate.Equal(updated.DueDate))
+		assert.Equal(t, "alice", updated.CreatedBy, "The creator never changes")
+	})
+
+	t.Run("UpdateTask_Errors", func(t *testing.T) {
+		repo := newRepo(t)
+		id := create(t, repo, domain.Task{Title: "A Task"})
+
+		assert.EqualError(t, repo.UpdateTask(ctx, id.String(), domain.Task{}), "no field provided")
+		assert.EqualError(t, repo.UpdateTask(ctx, domain.NewID().String(), domain.Task{Title: "Won't Update"}), "task not found")
+		assert.Error(t, repo.UpdateTask(ctx, "not-an-id", domain.Task{Title: "Won't Update"}))
+	})
+
+	t.Run("DeleteTask", func(t *testing.T) {
+		repo := newRepo(t)
+		id := create(t, repo, domain.Task{Title: "To Be Deleted"})
+		kept := create(t, repo, domain.Task{Title: "Kept"})
+
+		require.NoError(t, repo.DeleteTask(ctx, id.String()))
+
+		_, err := repo.GetTaskByID(ctx, id.String())
+		assert.EqualError(t, err, "task not found")
+		tasks, err := repo.GetAllTask(ctx)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, kept, tasks[0].ID)
+
+		assert.EqualError(t, repo.DeleteTask(ctx, id.String()), "task not found", "Deleting twice should report the task as missing")
+		assert.Error(t, repo.DeleteTask(ctx, "not-an-id"))
+	})
+
+	t.Run("DeleteTasksByCreator", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, domain.Task{Title: "First", CreatedBy: "alice"})
+		create(t, repo, domain.Task{Title: "Second", CreatedBy: "alice"})
+		kept := create(t, repo, domain.Task{Title: "Kept", CreatedBy: "bob"})
+
+		require.NoError(t, repo.DeleteTasksByCreator(ctx, "alice"))
+
+		tasks, err := repo.GetAllTask(ctx)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, kept, tasks[0].ID)
+
+		assert.NoError(t, repo.DeleteTasksByCreator(ctx, "alice"), "A user without tasks is not an error")
+	})
+
+	t.Run("CountTasksByStatus", func(t *testing.T) {
+		repo := newRepo(t)
+
+		counts, err := repo.CountTasksByStatus(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+
+		create(t, repo, domain.Task{Title: "Task A", Status: "Todo"})
+		create(t, repo, domain.Task{Title: "Task B", Status: "Todo"})
+		create(t, repo, domain.Task{Title: "Task C", Status: "Done"})
+
+		counts, err = repo.CountTasksByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"Todo": 2, "Done": 1}, counts)
+	})
+
+	t.Run("ConcurrentNewTask", func(t *testing.T) {
+		repo := newRepo(t)
+		const workers = 20
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.NewTask(ctx, domain.Task{Title: "Concurrent", Status: "Todo"})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		tasks, err := repo.GetAllTask(ctx)
+		require.NoError(t, err)
+		ids := make(map[domain.ID]bool)
+		for _, task := range tasks {
+			ids[task.ID] = true
+		}
+		assert.Len(t, ids, workers, "Every task should be stored under its own ID")
+	})
+
+	t.Run("FindTasks", func(t *testing.T) {
+		repo := newRepo(t)
+		first := create(t, repo, domain.Task{Title: "Task A", Status: "Todo", CreatedBy: "alice"})
+		second := create(t, repo, domain.Task{Title: "Task B", Status: "Todo", CreatedBy: "bob"})
+		create(t, repo, domain.Task{Title: "Task C", Status: "Done", CreatedBy: "alice"})
+
+		ids := func(tasks []domain.Task) []domain.ID {
+			var ids []domain.ID
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			return ids
+		}
+
+		tasks, err := repo.FindTasks(ctx, domain.TaskFilter{Status: "Todo"}, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []domain.ID{first, second}, ids(tasks))
+
+		tasks, err = repo.FindTasks(ctx, domain.TaskFilter{Status: "Todo", CreatedBy: "alice"}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ID{first}, ids(tasks))
+
+		tasks, err = repo.FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{second, domain.NewID()}}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ID{second}, ids(tasks))
+
+		tasks, err = repo.FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{}}, 10)
+		require.NoError(t, err)
+		assert.Empty(t, tasks, "An empty list of IDs matches nothing")
+
+		tasks, err = repo.FindTasks(ctx, domain.TaskFilter{}, 2)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 2, "At most limit tasks are returned")
+	})
+
+	t.Run("BulkWriteTasks_ReportsEachOperation", func(t *testing.T) {
+		repo := newRepo(t)
+		updated := create(t, repo, domain.Task{Title: "To Update", Status: "Todo"})
+		deleted := create(t, repo, domain.Task{Title: "To Delete"})
+		taken := create(t, repo, domain.Task{Title: "Taken ID"})
+		missing := domain.NewID()
+
+		results, err := repo.BulkWriteTasks(ctx, []domain.BulkTaskOperation{
+			{Op: domain.BulkOpCreate, Task: domain.Task{Title: "Created", CreatedBy: "alice"}},
+			{Op: domain.BulkOpUpdate, ID: updated.String(), Task: domain.Task{Status: "Done"}},
+			{Op: domain.BulkOpDelete, ID: deleted.String()},
+			{Op: domain.BulkOpDelete, ID: missing.String()},
+			{Op: domain.BulkOpUpdate, ID: "not-an-id", Task: domain.Task{Status: "Done"}},
+			{Op: domain.BulkOpUpdate, ID: updated.String()},
+			{Op: domain.BulkOpCreate, Task: domain.Task{ID: taken, Title: "Same ID"}},
+		}, false)
+		require.NoError(t, err)
+		require.Len(t, results, 7)
+
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+		}
+		assert.Equal(t, domain.BulkStatusApplied, results[0].Status)
+		assert.Equal(t, domain.BulkStatusApplied, results[1].Status)
+		assert.Equal(t, domain.BulkStatusApplied, results[2].Status)
+		assert.Equal(t, domain.BulkTaskResult{Index: 3, Op: domain.BulkOpDelete, ID: missing.String(), Status: domain.BulkStatusFailed, Error: "task not found"}, results[3])
+		assert.Equal(t, "invalid task ID format", results[4].Error)
+		assert.Equal(t, "no field provided", results[5].Error)
+		assert.Equal(t, domain.BulkStatusFailed, results[6].Status)
+		assert.Equal(t, domain.ErrConflict.Error(), results[6].Error)
+
+		created, err := repo.GetTaskByID(ctx, results[0].ID)
+		require.NoError(t, err, "Creates report the ID of the new task")
+		assert.Equal(t, "Created", created.Title)
+		assert.Equal(t, "alice", created.CreatedBy)
+
+		task, err := repo.GetTaskByID(ctx, updated.String())
+		require.NoError(t, err)
+		assert.Equal(t, "Done", task.Status)
+		assert.Equal(t, "To Update", task.Title)
+
+		_, err = repo.GetTaskByID(ctx, deleted.String())
+		assert.EqualError(t, err, "task not found")
+	})
+
+	t.Run("BulkWriteTasks_AtomicUndoesEverythingOnFailure", func(t *testing.T) {
+		repo := newRepo(t)
+		kept := create(t, repo, domain.Task{Title: "Kept", Status: "Todo"})
+
+		results, err := repo.BulkWriteTasks(ctx, []domain.BulkTaskOperation{
+			{Op: domain.BulkOpCreate, Task: domain.Task{Title: "Undone"}},
+			{Op: domain.BulkOpUpdate, ID: kept.String(), Task: domain.Task{Status: "Done"}},
+			{Op: domain.BulkOpDelete, ID: domain.NewID().String()},
+			{Op: domain.BulkOpDelete, ID: kept.String()},
+		}, true)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		assert.Equal(t, domain.BulkTaskResult{Index: 0, Op: domain.BulkOpCreate, Status: domain.BulkStatusSkipped}, results[0], "An undone create has no ID")
+		assert.Equal(t, domain.BulkStatusSkipped, results[1].Status)
+		assert.Equal(t, domain.BulkStatusFailed, results[2].Status)
+		assert.Equal(t, "task not found", results[2].Error)
+		assert.Equal(t, domain.BulkStatusSkipped, results[3].Status)
+
+		tasks, err := repo.GetAllTask(ctx)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1, "Nothing should have been created or deleted")
+		assert.Equal(t, "Todo", tasks[0].Status, "The update should have been undone")
+	})
+
+	t.Run("BulkWriteTasks_AtomicAppliesAll", func(t *testing.T) {
+		repo := newRepo(t)
+		id := create(t, repo, domain.Task{Title: "Task", Status: "Todo"})
+
+		results, err := repo.BulkWriteTasks(ctx, []domain.BulkTaskOperation{
+			{Op: domain.BulkOpUpdate, ID: id.String(), Task: domain.Task{Status: "Done"}},
+			{Op: domain.BulkOpCreate, Task: domain.Task{Title: "Created"}},
+		}, true)
+		require.NoError(t, err)
+		for _, result := range results {
+			assert.Equal(t, domain.BulkStatusApplied, result.Status)
+		}
+
+		counts, err := repo.CountTasksByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"Done": 1, "": 1}, counts)
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		repo := newRepo(t)
+
+		requireFailsWhenCancelled(t, func(ctx context.Context) error {
+			_, err := repo.GetAllTask(ctx)
+			return err
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks a UnitOfWork. newStore must return the unit of work together with empty repositories it
+// covers, each time it is called.
+func TestUnitOfWork(t *testing.T, newStore func(t *testing.T) (domain.UnitOfWork, domain.TaskRepository, domain.UserRepository)) {
+	ctx := context.Background()
+	errFailed := errors.New("something went wrong")
+
+	// Writes a task and a user, returning the task's ID
+	write := func(t *testing.T, ctx context.Context, tasks domain.TaskRepository, users domain.UserRepository, username string) string {
+		task, err := tasks.NewTask(ctx, domain.Task{Title: "Written in a transaction", CreatedBy: username})
+		require.NoError(t, err)
+		require.NoError(t, users.CreateUser(ctx, &domain.User{Username: username, Role: domain.RoleUser}))
+		return task.ID.String()
+	}
+
+	t.Run("Do_CommitsAcrossRepositories", func(t *testing.T) {
+		transactions, tasks, users := newStore(t)
+
+		var taskID string
+		err := transactions.Do(ctx, func(ctx context.Context) error {
+			taskID = write(t, ctx, tasks, users, "committed")
+
+			// Reads inside the transaction see its writes
+			_, err := tasks.GetTaskByID(ctx, taskID)
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = tasks.GetTaskByID(ctx, taskID)
+		assert.NoError(t, err)
+		_, err = users.FindUserByUsername(ctx, "committed")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Do_RollsBackOnError", func(t *testing.T) {
+		transactions, tasks, users := newStore(t)
+
+		var taskID string
+		err := transactions.Do(ctx, func(ctx context.Context) error {
+			taskID = write(t, ctx, tasks, users, "rolled_back")
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+
+		_, err = tasks.GetTaskByID(ctx, taskID)
+		assert.EqualError(t, err, "task not found")
+		_, err = users.FindUserByUsername(ctx, "rolled_back")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Do_NestedJoinsTheOuterTransaction", func(t *testing.T) {
+		transactions, tasks, users := newStore(t)
+
+		var taskID string
+		err := transactions.Do(ctx, func(ctx context.Context) error {
+			if err := transactions.Do(ctx, func(ctx context.Context) error {
+				taskID = write(t, ctx, tasks, users, "nested")
+				return nil
+			}); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+
+		_, err = tasks.GetTaskByID(ctx, taskID)
+		assert.EqualError(t, err, "task not found", "The inner call commits nothing on its own")
+		_, err = users.FindUserByUsername(ctx, "nested")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
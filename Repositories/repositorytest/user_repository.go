@@ -0,0 +1,361 @@
+package repositorytest
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks a UserRepository. newRepo must return an empty repository each time it is called, with
+// usernames and emails unique regardless of case.
+func TestUserRepository(t *testing.T, newRepo func(t *testing.T) domain.UserRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo domain.UserRepository, user *domain.User) *domain.User {
+		require.NoError(t, repo.CreateUser(ctx, user))
+		require.False(t, user.ID.IsZero(), "CreateUser should set the ID")
+
+		created, err := repo.FindUserByUsername(ctx, user.Username)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, created.ID)
+		return created
+	}
+
+	t.Run("CreateUser_Success", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created := create(t, repo, &domain.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash", Role: domain.RoleUser})
+
+		assert.False(t, created.ID.IsZero())
+		assert.Equal(t, "alice", created.Username)
+		assert.Equal(t, "alice@example.com", created.Email)
+		assert.Equal(t, "hash", created.PasswordHash)
+		assert.Equal(t, domain.RoleUser, created.Role)
+	})
+
+	t.Run("CreateUser_UsernameAlreadyExists", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, &domain.User{Username: "alice", PasswordHash: "hash1"})
+
+		err := repo.CreateUser(ctx, &domain.User{Username: "alice", PasswordHash: "hash2"})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+
+		user, err := repo.FindUserByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "hash1", user.PasswordHash, "The first user is kept")
+	})
+
+	t.Run("CreateUser_UsernameTakenRegardlessOfCase", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, &domain.User{Username: "alice"})
+
+		err := repo.CreateUser(ctx, &domain.User{Username: "Alice"})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+
+		_, err = repo.FindUserByUsername(ctx, "Alice")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "The second user should not have been created")
+	})
+
+	t.Run("CreateUser_NonASCIIUsernameTakenRegardlessOfCase", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, &domain.User{Username: "Émile"})
+
+		err := repo.CreateUser(ctx, &domain.User{Username: "émile"})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+
+		create(t, repo, &domain.User{Username: "emile"}) // Accents still tell usernames apart
+	})
+
+	t.Run("CreateUser_ConcurrentRegistrationsOneWins", func(t *testing.T) {
+		repo := newRepo(t)
+		const registrations = 10
+
+		// Every registration passed any check for an existing user before the first was stored
+		start := make(chan struct{})
+		errs := make(chan error, registrations)
+		for i := range registrations {
+			go func() {
+				<-start
+				username := "racer"
+				if i%2 == 1 {
+					username = "Racer"
+				}
+				errs <- repo.CreateUser(ctx, &domain.User{Username: username, PasswordHash: "hash"})
+			}()
+		}
+		close(start)
+
+		succeeded := 0
+		for range registrations {
+			err := <-errs
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrConflict)
+			assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+		}
+		assert.Equal(t, 1, succeeded, "Exactly one registration should win")
+	})
+
+	t.Run("CreateUser_EmailTakenRegardlessOfCase", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, &domain.User{Username: "alice", Email: "shared@example.com"})
+
+		err := repo.CreateUser(ctx, &domain.User{Username: "bob", Email: "SHARED@example.com"})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorIs(t, err, domain.ErrEmailTaken)
+
+		// Users without an email must not collide with each other
+		create(t, repo, &domain.User{Username: "no_email_1"})
+		create(t, repo, &domain.User{Username: "no_email_2"})
+	})
+
+	t.Run("FindUserByUsername_NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		user, err := repo.FindUserByUsername(ctx, "nobody")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.Nil(t, user)
+	})
+
+	t.Run("FindUserByID", func(t *testing.T) {
+		repo := newRepo(t)
+		created := create(t, repo, &domain.User{Username: "alice", Role: domain.RoleUser})
+
+		found, err := repo.FindUserByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", found.Username)
+
+		// A username registered again after the account was deleted belongs to a new ID
+		require.NoError(t, repo.DeleteUser(ctx, "alice"))
+		create(t, repo, &domain.User{Username: "alice"})
+		_, err = repo.FindUserByID(ctx, created.ID)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("FindUserByEmail_CaseInsensitive", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, &domain.User{Username: "alice", Email: "alice@example.com"})
+		create(t, repo, &domain.User{Username: "no_email"})
+
+		user, err := repo.FindUserByEmail(ctx, "Alice@Example.COM")
+		require.NoError(t, err, "Email lookup should ignore case")
+		assert.Equal(t, "alice", user.Username)
+
+		_, err = repo.FindUserByEmail(ctx, "nobody@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		_, err = repo.FindUserByEmail(ctx, "")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Users without an email are not found by an empty one")
+	})
+
+	t.Run("UpdateUser_SavesChanges", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash", Role: domain.RoleUser})
+		lockedUntil := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+		user.Username = "renamed"
+		user.PasswordHash = "new-hash"
+		user.Role = domain.RoleAdmin
+		user.DisplayName = "Alice"
+		user.TokenVersion = 4
+		user.EmailVerified = true
+		user.FailedLoginAttempts = 3
+		user.LockedUntil = &lockedUntil
+		user.RecoveryCodeHashes = []string{"code-1", "code-2"}
+		require.NoError(t, repo.UpdateUser(ctx, user))
+
+		updated, err := repo.FindUserByUsername(ctx, "alice")
+		require.NoError(t, err, "The username never changes")
+		assert.Equal(t, "new-hash", updated.PasswordHash)
+		assert.Equal(t, domain.RoleAdmin, updated.Role)
+		assert.Equal(t, "Alice", updated.DisplayName)
+		assert.Equal(t, 4, updated.TokenVersion)
+		assert.True(t, updated.EmailVerified)
+		assert.Equal(t, 3, updated.FailedLoginAttempts)
+		require.NotNil(t, updated.LockedUntil)
+		assert.True(t, lockedUntil.Equal(*updated.LockedUntil))
+		assert.Equal(t, []string{"code-1", "code-2"}, updated.RecoveryCodeHashes)
+
+		_, err = repo.FindUserByUsername(ctx, "renamed")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("UpdateUser_Email", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := create(t, repo, &domain.User{Username: "alice", Email: "alice@example.com"})
+		create(t, repo, &domain.User{Username: "bob", Email: "bob@example.com"})
+
+		alice.Email = "BOB@example.com"
+		assert.ErrorIs(t, repo.UpdateUser(ctx, alice), domain.ErrEmailTaken)
+
+		alice.Email = ""
+		require.NoError(t, repo.UpdateUser(ctx, alice))
+		_, err := repo.FindUserByEmail(ctx, "alice@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "An empty email removes it")
+	})
+
+	t.Run("UpdateUser_NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		err := repo.UpdateUser(ctx, &domain.User{ID: domain.NewID(), Username: "ghost"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("FindUserByOIDCSubject_LinkedByUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice"})
+
+		_, err := repo.FindUserByOIDCSubject(ctx, "https://idp.example.com", "subject-1")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "The user is not linked yet")
+
+		user.OIDCIssuer = "https://idp.example.com"
+		user.OIDCSubject = "subject-1"
+		require.NoError(t, repo.UpdateUser(ctx, user))
+
+		linked, err := repo.FindUserByOIDCSubject(ctx, "https://idp.example.com", "subject-1")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", linked.Username)
+
+		_, err = repo.FindUserByOIDCSubject(ctx, "https://other-idp.example.com", "subject-1")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Subjects are only unique per issuer")
+
+		linked.OIDCIssuer, linked.OIDCSubject = "", ""
+		linked.DisplayName = "Alice"
+		require.NoError(t, repo.UpdateUser(ctx, linked))
+		_, err = repo.FindUserByOIDCSubject(ctx, "https://idp.example.com", "subject-1")
+		assert.NoError(t, err, "A linked account is never unlinked by an update")
+	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, &domain.User{Username: "alice"})
+
+		require.NoError(t, repo.DeleteUser(ctx, "alice"))
+
+		_, err := repo.FindUserByUsername(ctx, "alice")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Deleted user should no longer be found")
+		assert.ErrorIs(t, repo.DeleteUser(ctx, "alice"), domain.ErrUserNotFound, "Deleting twice should report the user as missing")
+	})
+
+	t.Run("UseTOTPStep_OnlyLaterSteps", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice", TOTPEnabled: true})
+
+		require.NoError(t, repo.UseTOTPStep(ctx, user.ID, 1000))
+		assert.ErrorIs(t, repo.UseTOTPStep(ctx, user.ID, 1000), domain.ErrInvalidMFACode, "A step is used once")
+		assert.ErrorIs(t, repo.UseTOTPStep(ctx, user.ID, 999), domain.ErrInvalidMFACode, "An earlier step cannot be used after a later one")
+		require.NoError(t, repo.UseTOTPStep(ctx, user.ID, 1001))
+
+		// A stale copy of the user saved afterwards does not bring back an earlier step
+		user.DisplayName = "Alice"
+		require.NoError(t, repo.UpdateUser(ctx, user))
+		found, err := repo.FindUserByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1001), found.TOTPLastStep)
+		assert.ErrorIs(t, repo.UseTOTPStep(ctx, user.ID, 1001), domain.ErrInvalidMFACode)
+
+		assert.ErrorIs(t, repo.UseTOTPStep(ctx, domain.NewID(), 1000), domain.ErrInvalidMFACode, "Unknown users have no codes")
+	})
+
+	t.Run("UseTOTPStep_ConcurrentUsesOneWins", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice", TOTPEnabled: true})
+		const attempts = 10
+
+		start := make(chan struct{})
+		errs := make(chan error, attempts)
+		for range attempts {
+			go func() {
+				<-start
+				errs <- repo.UseTOTPStep(ctx, user.ID, 1000)
+			}()
+		}
+		close(start)
+
+		succeeded := 0
+		for range attempts {
+			err := <-errs
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrInvalidMFACode)
+		}
+		assert.Equal(t, 1, succeeded, "Exactly one request may use the code")
+	})
+
+	t.Run("UseRecoveryCode_SpentOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice", RecoveryCodeHashes: []string{"code-1", "code-2", "code-3"}})
+
+		require.NoError(t, repo.UseRecoveryCode(ctx, user.ID, "code-2"))
+		assert.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "code-2"), domain.ErrInvalidMFACode, "A code is spent once")
+		assert.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "unknown"), domain.ErrInvalidMFACode)
+		assert.ErrorIs(t, repo.UseRecoveryCode(ctx, domain.NewID(), "code-1"), domain.ErrInvalidMFACode)
+
+		found, err := repo.FindUserByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"code-1", "code-3"}, found.RecoveryCodeHashes)
+	})
+
+	t.Run("UseRecoveryCode_ConcurrentUsesOneWins", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice", RecoveryCodeHashes: []string{"code-1", "code-2"}})
+		const attempts = 10
+
+		start := make(chan struct{})
+		errs := make(chan error, attempts)
+		for range attempts {
+			go func() {
+				<-start
+				errs <- repo.UseRecoveryCode(ctx, user.ID, "code-1")
+			}()
+		}
+		close(start)
+
+		succeeded := 0
+		for range attempts {
+			err := <-errs
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrInvalidMFACode)
+		}
+		assert.Equal(t, 1, succeeded, "Exactly one request may spend the code")
+
+		found, err := repo.FindUserByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"code-2"}, found.RecoveryCodeHashes)
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		repo := newRepo(t)
+		user := create(t, repo, &domain.User{Username: "alice", RecoveryCodeHashes: []string{"code-1"}})
+
+		user.DisplayName = "Changed without saving"
+		user.RecoveryCodeHashes[0] = "tampered"
+
+		found, err := repo.FindUserByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Empty(t, found.DisplayName)
+		assert.Equal(t, []string{"code-1"}, found.RecoveryCodeHashes)
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		repo := newRepo(t)
+
+		requireFailsWhenCancelled(t, func(ctx context.Context) error {
+			_, err := repo.FindUserByUsername(ctx, "alice")
+			return err
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type roleRepository struct {
+	collection *mongo.Collection
+}
+
+var _ domain.RoleRepository = (*roleRepository)(nil)
+
+func NewRoleRepository(db *mongo.Client, dbName, collectionName string) domain.RoleRepository {
+	return &roleRepository{
+		collection: openCollection(db, dbName, collectionName),
+	}
+}
+
+func (repo *roleRepository) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+
+	err := repo.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&role)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// Lists every role by name
+func (repo *roleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	cursor, err := repo.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []domain.Role{}
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (repo *roleRepository) SaveRole(ctx context.Context, role *domain.Role) error {
+	update := bson.M{"$set": bson.M{"permissions": role.Permissions}}
+	_, err := repo.collection.UpdateOne(ctx, bson.M{"_id": role.Name}, update, options.Update().SetUpsert(true))
+
+	return err
+}
+
+// Creates the default roles that do not exist yet. Roles an admin has changed are left alone.
+func EnsureDefaultRoles(ctx context.Context, db *mongo.Client, dbName, collectionName string) error {
+	collection := openCollection(db, dbName, collectionName)
+
+	for _, role := range domain.DefaultRoles() {
+		update := bson.M{"$setOnInsert": bson.M{"permissions": role.Permissions}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": role.Name}, update, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
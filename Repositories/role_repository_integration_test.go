@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testRoleCollectionName = "roles_integration_test_coll"
+
+func TestRoleRepository_Integration(t *testing.T) {
+	if testDBClient == nil {
+		t.Fatal("testDBClient is nil. TestMain setup for DB connection likely failed or was skipped.")
+	}
+
+	roleRepo := repositories.NewRoleRepository(testDBClient, TestDatabaseName, testRoleCollectionName)
+	collection := testDBClient.Database(TestDatabaseName).Collection(testRoleCollectionName)
+	ctx := context.Background()
+
+	_, err := collection.DeleteMany(ctx, bson.M{})
+	require.NoError(t, err, "Failed to clean role test collection")
+
+	t.Run("EnsureDefaultRoles_KeepsChanges", func(t *testing.T) {
+		require.NoError(t, repositories.EnsureDefaultRoles(ctx, testDBClient, TestDatabaseName, testRoleCollectionName))
+
+		role, err := roleRepo.GetRole(ctx, domain.RoleUser)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{domain.PermissionTasksRead, domain.PermissionTasksWrite}, role.Permissions)
+
+		// An admin narrows the user role, then the server restarts
+		require.NoError(t, roleRepo.SaveRole(ctx, &domain.Role{Name: domain.RoleUser, Permissions: []string{domain.PermissionTasksRead}}))
+		require.NoError(t, repositories.EnsureDefaultRoles(ctx, testDBClient, TestDatabaseName, testRoleCollectionName))
+
+		role, err = roleRepo.GetRole(ctx, domain.RoleUser)
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.PermissionTasksRead}, role.Permissions)
+	})
+
+	t.Run("SaveRole_CreatesAndLists", func(t *testing.T) {
+		require.NoError(t, roleRepo.SaveRole(ctx, &domain.Role{Name: "auditor", Permissions: []string{domain.PermissionTasksRead}}))
+
+		roles, err := roleRepo.ListRoles(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, role := range roles {
+			names = append(names, role.Name)
+		}
+		assert.Equal(t, []string{domain.RoleAdmin, "auditor", domain.RoleUser}, names)
+	})
+
+	t.Run("GetRole_NotFound", func(t *testing.T) {
+		_, err := roleRepo.GetRole(ctx, "ghost")
+		assert.ErrorIs(t, err, domain.ErrRoleNotFound)
+	})
+}
@@ -0,0 +1,122 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	domain "task_manager/Domain"
+)
+
+const auditEventColumns = `id, time, action, outcome, actor, client_ip, user_agent, request_id, target_type, target_id, details`
+
+type auditLogRepository struct {
+	db *DB
+}
+
+var _ domain.AuditLogRepository = (*auditLogRepository)(nil)
+
+func NewAuditLogRepository(db *DB) domain.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (repo *auditLogRepository) AppendEvent(ctx context.Context, event *domain.AuditEvent) error {
+	id := event.ID
+	if id.IsZero() {
+		id = domain.NewID()
+	}
+
+	var details sql.NullString
+	if event.Details != nil {
+		encoded, err := json.Marshal(event.Details)
+		if err != nil {
+			return err
+		}
+		details = sql.NullString{String: string(encoded), Valid: true}
+	}
+
+	_, err := repo.db.exec(ctx, `INSERT INTO audit_log (`+auditEventColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), toMillis(event.Time), event.Action, event.Outcome, event.Actor, event.ClientIP, event.UserAgent,
+		event.RequestID, event.TargetType, event.TargetID, details)
+	if err != nil {
+		return err
+	}
+
+	event.ID = id
+
+	return nil
+}
+
+// Events matching the filter, newest first
+func (repo *auditLogRepository) FindEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	conditions := []string{"1 = 1"}
+	var args []any
+	where := func(condition string, value any) {
+		conditions = append(conditions, condition)
+		args = append(args, value)
+	}
+	for column, value := range map[string]string{
+		"actor":       filter.Actor,
+		"action":      filter.Action,
+		"outcome":     filter.Outcome,
+		"target_type": filter.TargetType,
+		"target_id":   filter.TargetID,
+	} {
+		if value != "" {
+			where(column+" = ?", value)
+		}
+	}
+	if !filter.From.IsZero() {
+		where("time >= ?", filter.From.UnixMilli())
+	}
+	if !filter.To.IsZero() {
+		where("time < ?", filter.To.UnixMilli())
+	}
+
+	query := `SELECT ` + auditEventColumns + ` FROM audit_log WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY time DESC, id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := repo.db.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.AuditEvent{}
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func scanAuditEvent(row scanner) (domain.AuditEvent, error) {
+	var event domain.AuditEvent
+	var id string
+	var eventTime sql.NullInt64
+	var details sql.NullString
+	err := row.Scan(&id, &eventTime, &event.Action, &event.Outcome, &event.Actor, &event.ClientIP, &event.UserAgent,
+		&event.RequestID, &event.TargetType, &event.TargetID, &details)
+	if err != nil {
+		return domain.AuditEvent{}, err
+	}
+
+	if event.ID, err = domain.ParseID(id); err != nil {
+		return domain.AuditEvent{}, err
+	}
+	if details.Valid {
+		if err := json.Unmarshal([]byte(details.String), &event.Details); err != nil {
+			return domain.AuditEvent{}, err
+		}
+	}
+	event.Time = fromMillis(eventTime)
+
+	return event, nil
+}
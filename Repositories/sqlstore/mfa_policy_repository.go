@@ -0,0 +1,49 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "task_manager/Domain"
+)
+
+type mfaPolicyRepository struct {
+	db *DB
+}
+
+var _ domain.MFAPolicyRepository = (*mfaPolicyRepository)(nil)
+
+func NewMFAPolicyRepository(db *DB) domain.MFAPolicyRepository {
+	return &mfaPolicyRepository{db: db}
+}
+
+// Returns the saved policy, or an empty one if an admin never set it
+func (repo *mfaPolicyRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	var requiredRoles sql.NullString
+	err := repo.db.queryRow(ctx, `SELECT required_roles FROM mfa_policy WHERE id = 1`).Scan(&requiredRoles)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &domain.MFAPolicy{RequiredRoles: []string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := listFromJSON(requiredRoles)
+	if err != nil {
+		return nil, err
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return &domain.MFAPolicy{RequiredRoles: roles}, nil
+}
+
+// Replaces the policy
+func (repo *mfaPolicyRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	_, err := repo.db.exec(ctx, `INSERT INTO mfa_policy (id, required_roles) VALUES (1, ?)
+		ON CONFLICT (id) DO UPDATE SET required_roles = excluded.required_roles`,
+		listToJSON(policy.RequiredRoles))
+
+	return err
+}
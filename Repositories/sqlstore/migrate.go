@@ -0,0 +1,259 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Schema changes for each dialect, applied in order of the number their file name starts with.
+// Applied migrations must never be edited; add a new one instead.
+//
+//go:embed migrations
+var migrations embed.FS
+
+// One schema change
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Whether a migration has been applied, and when
+type MigrationStatus struct {
+	Version   int
+	Name      string // File name
+	AppliedAt *time.Time
+}
+
+// Brings the schema up to date, applying every migration not yet recorded in schema_migrations,
+// each in its own transaction. Safe to call on every start.
+func (db *DB) Migrate(ctx context.Context) error {
+	pending, err := loadMigrations(db.dialect)
+	if err != nil {
+		return err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range pending {
+		if _, ok := applied[migration.version]; ok {
+			continue
+		}
+
+		if err := db.applyMigration(ctx, migration); err != nil {
+			return fmt.Errorf("migration %s: %w", migration.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Every migration of the dialect, oldest first, with when it was applied
+func (db *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	known, err := loadMigrations(db.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(known))
+	for _, migration := range known {
+		status := MigrationStatus{Version: migration.version, Name: migration.name}
+		if appliedAt, ok := applied[migration.version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Number of migrations not applied yet
+func (db *DB) Pending(ctx context.Context) (int, error) {
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// When each applied migration was applied, by version
+func (db *DB) appliedMigrations(ctx context.Context) (map[int]time.Time, error) {
+	if _, err := db.exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied_at BIGINT NOT NULL)`); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt int64
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = time.UnixMilli(appliedAt).UTC()
+	}
+
+	return applied, rows.Err()
+}
+
+// Data changes that cannot be written in SQL, run after the migration of the same version and in
+// its transaction
+var migrationSteps = map[int]func(ctx context.Context, db *DB) error{
+	4: fillUsernameKeys,
+	6: fillTokenOwners,
+}
+
+func (db *DB) applyMigration(ctx context.Context, migration migration) error {
+	return db.inTx(ctx, func(ctx context.Context) error {
+		if _, err := db.conn(ctx).ExecContext(ctx, migration.sql); err != nil {
+			return err
+		}
+
+		if step, ok := migrationSteps[migration.version]; ok {
+			if err := step(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		_, err := db.exec(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, migration.version, time.Now().UnixMilli())
+		return err
+	})
+}
+
+// Sets the username key of users created before it was stored
+func fillUsernameKeys(ctx context.Context, db *DB) error {
+	rows, err := db.query(ctx, `SELECT id, username FROM users WHERE username_key IS NULL`)
+	if err != nil {
+		return err
+	}
+
+	usernames := make(map[string]string)
+	for rows.Next() {
+		var id, username string
+		if err := rows.Scan(&id, &username); err != nil {
+			rows.Close()
+			return err
+		}
+		usernames[id] = username
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, username := range usernames {
+		_, err := db.exec(ctx, `UPDATE users SET username_key = ? WHERE id = ?`, usernameKey(username), id)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("username %q differs only in case from another, rename one of them first: %w", username, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sets the owner ID of tokens created before it was stored. A token is only given to a user created
+// before it; others belonged to a deleted account whose username was taken again, and are removed.
+func fillTokenOwners(ctx context.Context, db *DB) error {
+	rows, err := db.query(ctx, `SELECT t.id, t.created_at, u.id FROM personal_access_tokens t
+		LEFT JOIN users u ON u.username = t.username WHERE t.user_id = ''`)
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var createdAt sql.NullInt64
+		var userID sql.NullString
+		if err := rows.Scan(&id, &createdAt, &userID); err != nil {
+			rows.Close()
+			return err
+		}
+		owner, err := domain.ParseID(userID.String)
+		if err == nil && !owner.Time().After(fromMillis(createdAt)) {
+			owners[id] = userID.String
+		} else {
+			owners[id] = ""
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, owner := range owners {
+		var err error
+		if owner == "" {
+			_, err = db.exec(ctx, `DELETE FROM personal_access_tokens WHERE id = ?`, id)
+		} else {
+			_, err = db.exec(ctx, `UPDATE personal_access_tokens SET user_id = ? WHERE id = ?`, owner, id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reads the dialect's migrations, ordered by version
+func loadMigrations(dialect string) ([]migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []migration
+	for _, entry := range entries {
+		number, _, ok := strings.Cut(entry.Name(), "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || !strings.HasSuffix(entry.Name(), ".sql") {
+			return nil, fmt.Errorf("migration %s: file name must look like 0001_description.sql", entry.Name())
+		}
+
+		content, err := fs.ReadFile(migrations, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = append(loaded, migration{version: version, name: entry.Name(), sql: string(content)})
+	}
+
+	slices.SortFunc(loaded, func(a, b migration) int { return a.version - b.version })
+
+	return loaded, nil
+}
@@ -0,0 +1,91 @@
+-- Times are milliseconds since the Unix epoch. Lists and maps are JSON text.
+
+CREATE TABLE tasks (
+    id          TEXT PRIMARY KEY,
+    title       TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    due_date    BIGINT,
+    status      TEXT NOT NULL DEFAULT '',
+    created_by  TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX tasks_status ON tasks (status);
+
+CREATE TABLE users (
+    id                    TEXT PRIMARY KEY,
+    username              TEXT NOT NULL UNIQUE,
+    password_hash         TEXT NOT NULL DEFAULT '',
+    role                  TEXT NOT NULL DEFAULT '',
+    display_name          TEXT NOT NULL DEFAULT '',
+    timezone              TEXT NOT NULL DEFAULT '',
+    token_version         INTEGER NOT NULL DEFAULT 0,
+    email                 TEXT,
+    email_verified        BOOLEAN NOT NULL DEFAULT FALSE,
+    failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until          BIGINT,
+    totp_secret           TEXT NOT NULL DEFAULT '',
+    totp_enabled          BOOLEAN NOT NULL DEFAULT FALSE,
+    recovery_code_hashes  TEXT,
+    oidc_issuer           TEXT,
+    oidc_subject          TEXT
+);
+
+CREATE UNIQUE INDEX users_email_unique ON users (lower(email));
+CREATE UNIQUE INDEX users_oidc_subject_unique ON users (oidc_issuer, oidc_subject);
+
+CREATE TABLE one_time_tokens (
+    id            TEXT PRIMARY KEY,
+    username      TEXT NOT NULL,
+    purpose       TEXT NOT NULL,
+    token_hash    TEXT NOT NULL,
+    created_at    BIGINT,
+    expires_at    BIGINT,
+    used_at       BIGINT,
+    code_verifier TEXT NOT NULL DEFAULT '',
+    nonce         TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX one_time_tokens_purpose_hash ON one_time_tokens (purpose, token_hash);
+CREATE INDEX one_time_tokens_username ON one_time_tokens (username, purpose);
+
+CREATE TABLE personal_access_tokens (
+    id           TEXT PRIMARY KEY,
+    username     TEXT NOT NULL,
+    name         TEXT NOT NULL DEFAULT '',
+    prefix       TEXT NOT NULL DEFAULT '',
+    token_hash   TEXT NOT NULL UNIQUE,
+    scopes       TEXT,
+    created_at   BIGINT,
+    expires_at   BIGINT,
+    last_used_at BIGINT
+);
+
+CREATE INDEX personal_access_tokens_username_created_at ON personal_access_tokens (username, created_at DESC);
+
+CREATE TABLE roles (
+    name        TEXT PRIMARY KEY,
+    permissions TEXT
+);
+
+-- Holds at most one row
+CREATE TABLE mfa_policy (
+    id             INTEGER PRIMARY KEY CHECK (id = 1),
+    required_roles TEXT
+);
+
+CREATE TABLE audit_log (
+    id          TEXT PRIMARY KEY,
+    time        BIGINT,
+    action      TEXT NOT NULL DEFAULT '',
+    outcome     TEXT NOT NULL DEFAULT '',
+    actor       TEXT NOT NULL DEFAULT '',
+    client_ip   TEXT NOT NULL DEFAULT '',
+    user_agent  TEXT NOT NULL DEFAULT '',
+    request_id  TEXT NOT NULL DEFAULT '',
+    target_type TEXT NOT NULL DEFAULT '',
+    target_id   TEXT NOT NULL DEFAULT '',
+    details     TEXT
+);
+
+CREATE INDEX audit_log_time ON audit_log (time DESC);
+CREATE INDEX audit_log_actor_time ON audit_log (actor, time DESC);
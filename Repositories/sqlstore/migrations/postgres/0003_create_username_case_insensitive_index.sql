@@ -0,0 +1,3 @@
+-- Usernames are unique regardless of case, as they are in MongoDB
+
+CREATE UNIQUE INDEX users_username_unique_ci ON users (lower(username));
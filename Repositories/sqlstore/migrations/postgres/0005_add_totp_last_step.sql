@@ -0,0 +1,3 @@
+-- Time step of the last TOTP code accepted from each user, so that no code is accepted twice
+
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
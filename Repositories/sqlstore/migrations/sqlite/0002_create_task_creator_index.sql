@@ -0,0 +1,3 @@
+-- Tasks are looked up by creator, e.g. to check who may change them
+
+CREATE INDEX tasks_created_by_due_date ON tasks (created_by, due_date);
@@ -0,0 +1,6 @@
+-- Usernames folded to one case by the server, so they compare as in MongoDB and in memory whatever
+-- the script; lower() only folds ASCII in SQLite. Filled in for existing users by fillUsernameKeys.
+
+ALTER TABLE users ADD COLUMN username_key TEXT;
+
+CREATE UNIQUE INDEX users_username_key_unique ON users (username_key);
@@ -0,0 +1,4 @@
+-- Owner of each token by ID, as usernames are freed when an account is deleted. Filled in for
+-- existing tokens by fillTokenOwners.
+
+ALTER TABLE personal_access_tokens ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,100 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "task_manager/Domain"
+	"time"
+)
+
+const oneTimeTokenColumns = `id, username, purpose, token_hash, created_at, expires_at, used_at, code_verifier, nonce`
+
+type oneTimeTokenRepository struct {
+	db *DB
+}
+
+var _ domain.OneTimeTokenRepository = (*oneTimeTokenRepository)(nil)
+
+func NewOneTimeTokenRepository(db *DB) domain.OneTimeTokenRepository {
+	return &oneTimeTokenRepository{db: db}
+}
+
+// Stores a new (hashed) token
+func (repo *oneTimeTokenRepository) CreateToken(ctx context.Context, token *domain.OneTimeToken) error {
+	id := token.ID
+	if id.IsZero() {
+		id = domain.NewID()
+	}
+
+	_, err := repo.db.exec(ctx, `INSERT INTO one_time_tokens (`+oneTimeTokenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), token.Username, token.Purpose, token.TokenHash, toMillis(token.CreatedAt), toMillis(token.ExpiresAt),
+		toMillisPtr(token.UsedAt), token.CodeVerifier, token.Nonce)
+	if err != nil {
+		return err
+	}
+
+	token.ID = id
+
+	return nil
+}
+
+// Marks the token as used in the same statement that finds it, so two concurrent requests with
+// the same token cannot both succeed.
+func (repo *oneTimeTokenRepository) ConsumeToken(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	now := time.Now().UnixMilli()
+	token, err := scanOneTimeToken(repo.db.queryRow(ctx, `UPDATE one_time_tokens SET used_at = ?
+		WHERE id = (SELECT id FROM one_time_tokens WHERE purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ? LIMIT 1)
+		AND used_at IS NULL
+		RETURNING `+oneTimeTokenColumns,
+		now, purpose, tokenHash, now))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Looks a token up without marking it as used
+func (repo *oneTimeTokenRepository) FindToken(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	token, err := scanOneTimeToken(repo.db.queryRow(ctx, `SELECT `+oneTimeTokenColumns+` FROM one_time_tokens
+		WHERE purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ? LIMIT 1`,
+		purpose, tokenHash, time.Now().UnixMilli()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Removes every token of the given purpose issued to a user
+func (repo *oneTimeTokenRepository) DeleteTokensForUser(ctx context.Context, username, purpose string) error {
+	_, err := repo.db.exec(ctx, `DELETE FROM one_time_tokens WHERE username = ? AND purpose = ?`, username, purpose)
+
+	return err
+}
+
+func scanOneTimeToken(row scanner) (*domain.OneTimeToken, error) {
+	var token domain.OneTimeToken
+	var id string
+	var createdAt, expiresAt, usedAt sql.NullInt64
+	err := row.Scan(&id, &token.Username, &token.Purpose, &token.TokenHash, &createdAt, &expiresAt, &usedAt, &token.CodeVerifier, &token.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ID, err = domain.ParseID(id); err != nil {
+		return nil, err
+	}
+	token.CreatedAt = fromMillis(createdAt)
+	token.ExpiresAt = fromMillis(expiresAt)
+	token.UsedAt = fromMillisPtr(usedAt)
+
+	return &token, nil
+}
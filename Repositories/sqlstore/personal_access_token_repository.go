@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	domain "task_manager/Domain"
+	"time"
+)
+
+const personalAccessTokenColumns = `id, username, user_id, name, prefix, token_hash, scopes, created_at, expires_at, last_used_at`
+
+type personalAccessTokenRepository struct {
+	db *DB
+}
+
+var _ domain.PersonalAccessTokenRepository = (*personalAccessTokenRepository)(nil)
+
+func NewPersonalAccessTokenRepository(db *DB) domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// Stores a new (hashed) token. Token hashes are unique.
+func (repo *personalAccessTokenRepository) CreateToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+	id := token.ID
+	if id.IsZero() {
+		id = domain.NewID()
+	}
+
+	_, err := repo.db.exec(ctx, `INSERT INTO personal_access_tokens (`+personalAccessTokenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), token.Username, token.UserID.String(), token.Name, token.Prefix, token.TokenHash, listToJSON(token.Scopes),
+		toMillis(token.CreatedAt), toMillis(token.ExpiresAt), toMillisPtr(token.LastUsedAt))
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	token.ID = id
+
+	return nil
+}
+
+func (repo *personalAccessTokenRepository) FindTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	token, err := scanPersonalAccessToken(repo.db.queryRow(ctx,
+		`SELECT `+personalAccessTokenColumns+` FROM personal_access_tokens WHERE token_hash = ?`, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Lists a user's tokens, newest first
+func (repo *personalAccessTokenRepository) ListTokensForUser(ctx context.Context, username string) ([]domain.PersonalAccessToken, error) {
+	rows, err := repo.db.query(ctx,
+		`SELECT `+personalAccessTokenColumns+` FROM personal_access_tokens WHERE username = ? ORDER BY created_at DESC`, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (repo *personalAccessTokenRepository) DeleteToken(ctx context.Context, username, id string) error {
+	result, err := repo.db.exec(ctx, `DELETE FROM personal_access_tokens WHERE id = ? AND username = ?`, id, username)
+	if err != nil {
+		return err
+	}
+
+	return requireRowAffected(result, domain.ErrTokenNotFound)
+}
+
+// Revokes every token of a user
+func (repo *personalAccessTokenRepository) DeleteTokensForUser(ctx context.Context, username string) error {
+	_, err := repo.db.exec(ctx, `DELETE FROM personal_access_tokens WHERE username = ?`, username)
+
+	return err
+}
+
+func (repo *personalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	_, err := repo.db.exec(ctx, `UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?`, toMillis(lastUsedAt), id)
+
+	return err
+}
+
+func scanPersonalAccessToken(row scanner) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	var id, userID string
+	var scopes sql.NullString
+	var createdAt, expiresAt, lastUsedAt sql.NullInt64
+	err := row.Scan(&id, &token.Username, &userID, &token.Name, &token.Prefix, &token.TokenHash, &scopes, &createdAt, &expiresAt, &lastUsedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ID, err = domain.ParseID(id); err != nil {
+		return nil, err
+	}
+	if token.UserID, err = domain.ParseID(userID); err != nil {
+		return nil, err
+	}
+	if token.Scopes, err = listFromJSON(scopes); err != nil {
+		return nil, err
+	}
+	token.CreatedAt = fromMillis(createdAt)
+	token.ExpiresAt = fromMillis(expiresAt)
+	token.LastUsedAt = fromMillisPtr(lastUsedAt)
+
+	return &token, nil
+}
@@ -0,0 +1,86 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "task_manager/Domain"
+)
+
+type roleRepository struct {
+	db *DB
+}
+
+var _ domain.RoleRepository = (*roleRepository)(nil)
+
+func NewRoleRepository(db *DB) domain.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (repo *roleRepository) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	role, err := scanRole(repo.db.queryRow(ctx, `SELECT name, permissions FROM roles WHERE name = ?`, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Lists every role by name
+func (repo *roleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	rows, err := repo.db.query(ctx, `SELECT name, permissions FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []domain.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+
+	return roles, rows.Err()
+}
+
+// Creates the role or replaces its permissions
+func (repo *roleRepository) SaveRole(ctx context.Context, role *domain.Role) error {
+	_, err := repo.db.exec(ctx, `INSERT INTO roles (name, permissions) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET permissions = excluded.permissions`,
+		role.Name, listToJSON(role.Permissions))
+
+	return err
+}
+
+// Creates the default roles unless they exist, leaving roles an admin changed alone. Safe to call
+// on every start.
+func EnsureDefaultRoles(ctx context.Context, db *DB) error {
+	for _, role := range domain.DefaultRoles() {
+		_, err := db.exec(ctx, `INSERT INTO roles (name, permissions) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`,
+			role.Name, listToJSON(role.Permissions))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanRole(row scanner) (*domain.Role, error) {
+	var role domain.Role
+	var permissions sql.NullString
+	if err := row.Scan(&role.Name, &permissions); err != nil {
+		return nil, err
+	}
+
+	var err error
+	role.Permissions, err = listFromJSON(permissions)
+
+	return &role, err
+}
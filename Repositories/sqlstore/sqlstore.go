@@ -0,0 +1,230 @@
+// Package sqlstore keeps the repositories in a SQL database, SQLite or PostgreSQL, for deployments
+// without MongoDB. Each repository behaves like its MongoDB counterpart in package repositories; the
+// suites in repositorytest are run against both to keep it that way.
+//
+// Queries are written with ? placeholders and rewritten for PostgreSQL. Times are stored as
+// milliseconds since the Unix epoch, in UTC, which is the precision MongoDB keeps them at.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" driver
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// Database driver registered for each dialect
+var drivers = map[string]string{
+	DialectSQLite:   "sqlite3",
+	DialectPostgres: "pgx",
+}
+
+// SQL database the repositories keep their tables in
+type DB struct {
+	db      *sql.DB
+	dialect string
+}
+
+// Opens a database of the given dialect, "sqlite" or "postgres". The tables are only created by
+// Migrate.
+func Open(dialect, dsn string) (*DB, error) {
+	driver, ok := drivers[dialect]
+	if !ok {
+		return nil, fmt.Errorf("unknown SQL dialect %q", dialect)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows one writer at a time; sharing a single connection queues writes instead of
+	// failing them with "database is locked"
+	if dialect == DialectSQLite {
+		db.SetMaxOpenConns(1)
+	}
+
+	return &DB{db: db, dialect: dialect}, nil
+}
+
+func (db *DB) Close() error {
+	return db.db.Close()
+}
+
+// Checks that the database can be reached; usable as a readiness check
+func (db *DB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// Runs statements: the database, or a transaction
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Context key of the transaction started by inTx
+type txKey struct{}
+
+// Runs fn in a transaction, committed if fn returns nil and rolled back otherwise. Repositories
+// called with the context fn is given run their statements in the transaction. Called inside
+// another transaction, fn joins it.
+func (db *DB) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// The transaction of the context, or else the database
+func (db *DB) conn(ctx context.Context) conn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db.db
+}
+
+func (db *DB) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.conn(ctx).ExecContext(ctx, db.rebind(query), args...)
+}
+
+func (db *DB) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.conn(ctx).QueryContext(ctx, db.rebind(query), args...)
+}
+
+func (db *DB) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.conn(ctx).QueryRowContext(ctx, db.rebind(query), args...)
+}
+
+// Rewrites ? placeholders as $1, $2, ... for PostgreSQL
+func (db *DB) rebind(query string) string {
+	if db.dialect != DialectPostgres {
+		return query
+	}
+
+	var rebound strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			rebound.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		rebound.WriteRune(r)
+	}
+
+	return rebound.String()
+}
+
+// Reports whether err is a unique constraint violation, in either dialect
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// Returns notFound unless the statement changed a row
+func requireRowAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// Something rows can be scanned from: *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Zero times are stored as NULL
+func toMillis(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.UnixMilli(), Valid: true}
+}
+
+func toMillisPtr(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+
+	return toMillis(*t)
+}
+
+func fromMillis(ms sql.NullInt64) time.Time {
+	if !ms.Valid {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(ms.Int64).UTC()
+}
+
+func fromMillisPtr(ms sql.NullInt64) *time.Time {
+	if !ms.Valid {
+		return nil
+	}
+
+	t := fromMillis(ms)
+	return &t
+}
+
+// Empty strings are stored as NULL, so unique indexes skip them
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// Lists are stored as JSON text, and nil lists as NULL
+func listToJSON(list []string) sql.NullString {
+	if list == nil {
+		return sql.NullString{}
+	}
+
+	encoded, _ := json.Marshal(list) // Marshalling strings cannot fail
+	return sql.NullString{String: string(encoded), Valid: true}
+}
+
+func listFromJSON(encoded sql.NullString) ([]string, error) {
+	if !encoded.Valid {
+		return nil, nil
+	}
+
+	var list []string
+	err := json.Unmarshal([]byte(encoded.String), &list)
+	return list, err
+}
@@ -0,0 +1,308 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	domain "task_manager/Domain"
+)
+
+const taskColumns = `id, title, description, due_date, status, created_by`
+
+type taskRepository struct {
+	db *DB
+}
+
+var _ domain.TaskRepository = (*taskRepository)(nil)
+
+func NewTaskRepository(db *DB) domain.TaskRepository {
+	return &taskRepository{db: db}
+}
+
+// Gets all Tasks
+func (repo *taskRepository) GetAllTask(ctx context.Context) ([]domain.Task, error) {
+	rows, err := repo.db.query(ctx, `SELECT `+taskColumns+` FROM tasks ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []domain.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// Gets task by ID
+func (repo *taskRepository) GetTaskByID(ctx context.Context, id string) (domain.Task, error) {
+	if _, err := domain.ParseID(id); err != nil {
+		return domain.Task{}, errors.New("invalid task ID format")
+	}
+
+	task, err := scanTask(repo.db.queryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, errors.New("task not found")
+	}
+
+	return task, err
+}
+
+// Sets the fields of updatedTask that are not empty
+func (repo *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask domain.Task) error {
+	if _, err := domain.ParseID(id); err != nil {
+		return err
+	}
+
+	var columns []string
+	var args []any
+	set := func(column string, value any) {
+		columns = append(columns, column+" = ?")
+		args = append(args, value)
+	}
+	if updatedTask.Title != "" {
+		set("title", updatedTask.Title)
+	}
+	if updatedTask.Description != "" {
+		set("description", updatedTask.Description)
+	}
+	if updatedTask.Status != "" {
+		set("status", updatedTask.Status)
+	}
+	if !updatedTask.DueDate.IsZero() {
+		set("due_date", toMillis(updatedTask.DueDate))
+	}
+
+	if len(columns) == 0 {
+		return errors.New("no field provided")
+	}
+
+	result, err := repo.db.exec(ctx, `UPDATE tasks SET `+strings.Join(columns, ", ")+` WHERE id = ?`, append(args, id)...)
+	if err != nil {
+		return err
+	}
+
+	return requireRowAffected(result, errors.New("task not found"))
+}
+
+func (repo *taskRepository) DeleteTask(ctx context.Context, id string) error {
+	if _, err := domain.ParseID(id); err != nil {
+		return err
+	}
+
+	result, err := repo.db.exec(ctx, `DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	return requireRowAffected(result, errors.New("task not found"))
+}
+
+// Deletes every task the user created
+func (repo *taskRepository) DeleteTasksByCreator(ctx context.Context, username string) error {
+	_, err := repo.db.exec(ctx, `DELETE FROM tasks WHERE created_by = ?`, username)
+
+	return err
+}
+
+// Creates a new task, generating its ID unless it has one
+func (repo *taskRepository) NewTask(ctx context.Context, task domain.Task) (domain.Task, error) {
+	if task.ID.IsZero() {
+		task.ID = domain.NewID()
+	}
+
+	_, err := repo.db.exec(ctx, `INSERT INTO tasks (`+taskColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		task.ID.String(), task.Title, task.Description, toMillis(task.DueDate), task.Status, task.CreatedBy)
+	if isUniqueViolation(err) {
+		return domain.Task{}, fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	}
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	task.DueDate = fromMillis(toMillis(task.DueDate))
+
+	return task, nil
+}
+
+// Counts tasks grouped by status
+func (repo *taskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	rows, err := repo.db.query(ctx, `SELECT status, COUNT(*) FROM tasks GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// Tasks matching the filter, ordered by ID
+func (repo *taskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter, limit int) ([]domain.Task, error) {
+	if filter.IDs != nil && len(filter.IDs) == 0 {
+		return nil, nil
+	}
+
+	var conditions []string
+	var args []any
+	if filter.IDs != nil {
+		placeholders := make([]string, len(filter.IDs))
+		for i, id := range filter.IDs {
+			placeholders[i] = "?"
+			args = append(args, id.String())
+		}
+		conditions = append(conditions, "id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.CreatedBy != "" {
+		conditions = append(conditions, "created_by = ?")
+		args = append(args, filter.CreatedBy)
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := repo.db.query(ctx, query+` ORDER BY id LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []domain.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// Aborts the transaction of an atomic bulk write after an operation failed
+var errBulkAborted = errors.New("bulk write aborted")
+
+// Applies the operations one statement at a time, in order. Atomic requests run them in one
+// transaction, which stops at the first failure.
+func (repo *taskRepository) BulkWriteTasks(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool) ([]domain.BulkTaskResult, error) {
+	results := make([]domain.BulkTaskResult, len(operations))
+	apply := func(ctx context.Context) error {
+		failed := false
+		for i, operation := range operations {
+			results[i] = domain.BulkTaskResult{Index: i, Op: operation.Op, ID: operation.ID}
+			if atomic && failed {
+				results[i].Status = domain.BulkStatusSkipped
+				continue
+			}
+
+			id, err := repo.applyBulkOperation(ctx, operation)
+			if err != nil {
+				results[i].Status = domain.BulkStatusFailed
+				results[i].Error = bulkError(err).Error()
+				failed = true
+				continue
+			}
+			results[i].Status = domain.BulkStatusApplied
+			results[i].ID = id
+		}
+
+		if atomic && failed {
+			return errBulkAborted
+		}
+		return nil
+	}
+
+	if !atomic {
+		return results, apply(ctx)
+	}
+
+	err := repo.db.inTx(ctx, apply)
+	if errors.Is(err, errBulkAborted) {
+		for i := range results {
+			if results[i].Status != domain.BulkStatusApplied {
+				continue
+			}
+			results[i].Status = domain.BulkStatusSkipped
+			if results[i].Op == domain.BulkOpCreate {
+				results[i].ID = ""
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Applies one operation, returning the ID of the task it changed
+func (repo *taskRepository) applyBulkOperation(ctx context.Context, operation domain.BulkTaskOperation) (string, error) {
+	switch operation.Op {
+	case domain.BulkOpCreate:
+		created, err := repo.NewTask(ctx, operation.Task)
+		if err != nil {
+			return "", err
+		}
+		return created.ID.String(), nil
+	case domain.BulkOpUpdate:
+		return operation.ID, repo.UpdateTask(ctx, operation.ID, operation.Task)
+	case domain.BulkOpDelete:
+		return operation.ID, repo.DeleteTask(ctx, operation.ID)
+	}
+
+	return "", fmt.Errorf("unknown operation %q", operation.Op)
+}
+
+// The error reported for a failed operation. IDs that cannot be parsed are reported like
+// GetTaskByID does.
+func bulkError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidID):
+		return errors.New("invalid task ID format")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.ErrConflict
+	}
+
+	return err
+}
+
+func scanTask(row scanner) (domain.Task, error) {
+	var task domain.Task
+	var id string
+	var dueDate sql.NullInt64
+	if err := row.Scan(&id, &task.Title, &task.Description, &dueDate, &task.Status, &task.CreatedBy); err != nil {
+		return domain.Task{}, err
+	}
+
+	parsedID, err := domain.ParseID(id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	task.ID = parsedID
+	task.DueDate = fromMillis(dueDate)
+
+	return task, nil
+}
@@ -0,0 +1,21 @@
+package sqlstore
+
+import (
+	"context"
+	domain "task_manager/Domain"
+)
+
+type unitOfWork struct {
+	db *DB
+}
+
+var _ domain.UnitOfWork = (*unitOfWork)(nil)
+
+func NewUnitOfWork(db *DB) domain.UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// Repositories take part through the context, which carries the transaction their statements run in
+func (uow *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return uow.db.inTx(ctx, fn)
+}
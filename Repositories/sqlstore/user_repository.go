@@ -0,0 +1,235 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	domain "task_manager/Domain"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const userColumns = `id, username, password_hash, role, display_name, timezone, token_version, email, email_verified,
+	failed_login_attempts, locked_until, totp_secret, totp_enabled, recovery_code_hashes, oidc_issuer, oidc_subject, totp_last_step`
+
+type userRepository struct {
+	db *DB
+}
+
+var _ domain.UserRepository = (*userRepository)(nil)
+
+func NewUserRepository(db *DB) domain.UserRepository {
+	return &userRepository{db: db}
+}
+
+// Creates a new user. Uniqueness is left to the unique indexes, so concurrent registrations cannot
+// both succeed.
+func (repo *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	id := user.ID
+	if id.IsZero() {
+		id = domain.NewID()
+	}
+
+	_, err := repo.db.exec(ctx, `INSERT INTO users (`+userColumns+`, username_key) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.String(), user.Username, user.PasswordHash, user.Role, user.DisplayName, user.Timezone, user.TokenVersion,
+		nullString(user.Email), user.EmailVerified, user.FailedLoginAttempts, toMillisPtr(user.LockedUntil),
+		user.TOTPSecret, user.TOTPEnabled, listToJSON(user.RecoveryCodeHashes), nullString(user.OIDCIssuer), nullString(user.OIDCSubject),
+		user.TOTPLastStep, usernameKey(user.Username))
+	if isUniqueViolation(err) {
+		// The username is taken regardless of case, or the email or provider account is
+		if repo.usernameTaken(ctx, err, user.Username) {
+			return fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrUserAlreadyExists)
+		}
+		return fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrEmailTaken)
+	}
+	if err != nil {
+		return err
+	}
+
+	user.ID = id
+
+	return nil
+}
+
+// Reports whether a unique violation on insert was the username's. PostgreSQL names the index, and
+// refuses further statements in a transaction after the violation; SQLite does neither, so the
+// username is looked up.
+func (repo *userRepository) usernameTaken(ctx context.Context, violation error, username string) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(violation, &pgErr) {
+		switch pgErr.ConstraintName {
+		case "users_username_key", "users_username_unique_ci", "users_username_key_unique":
+			return true
+		}
+		return false
+	}
+
+	var taken bool
+	err := repo.db.queryRow(ctx, `SELECT COUNT(*) > 0 FROM users WHERE username_key = ?`, usernameKey(username)).Scan(&taken)
+	return err == nil && taken
+}
+
+// The username with every letter folded to one case, so that usernames equal under strings.EqualFold,
+// as they are unique in MongoDB and in memory, have the same key
+func usernameKey(username string) string {
+	return strings.Map(func(r rune) rune {
+		// Each rune's case variants form a cycle under SimpleFold; the smallest stands for them all
+		folded := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			folded = min(folded, f)
+		}
+		return folded
+	}, username)
+}
+
+// Get a user by their ID, which unlike the username is never reused.
+func (repo *userRepository) FindUserByID(ctx context.Context, id domain.ID) (*domain.User, error) {
+	return repo.findUser(ctx, `id = ?`, id.String())
+}
+
+// Get a user by their username.
+func (repo *userRepository) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return repo.findUser(ctx, `username = ?`, username)
+}
+
+// Get a user by their email address, ignoring case.
+func (repo *userRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return repo.findUser(ctx, `lower(email) = lower(?)`, email)
+}
+
+// Get the user linked to an account at an OpenID Connect provider.
+func (repo *userRepository) FindUserByOIDCSubject(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	return repo.findUser(ctx, `oidc_issuer = ? AND oidc_subject = ?`, issuer, subject)
+}
+
+// Saves changes to an existing user, matched by ID. The username never changes, and a linked
+// provider account is never unlinked.
+func (repo *userRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	result, err := repo.db.exec(ctx, `UPDATE users SET password_hash = ?, role = ?, display_name = ?, timezone = ?, token_version = ?,
+		email = ?, email_verified = ?, failed_login_attempts = ?, locked_until = ?, totp_secret = ?, totp_enabled = ?,
+		recovery_code_hashes = ?, oidc_issuer = COALESCE(?, oidc_issuer), oidc_subject = COALESCE(?, oidc_subject)
+		WHERE id = ?`,
+		user.PasswordHash, user.Role, user.DisplayName, user.Timezone, user.TokenVersion,
+		nullString(user.Email), user.EmailVerified, user.FailedLoginAttempts, toMillisPtr(user.LockedUntil), user.TOTPSecret, user.TOTPEnabled,
+		listToJSON(user.RecoveryCodeHashes), linkedIssuer(user), nullString(user.OIDCSubject),
+		user.ID.String())
+	if isUniqueViolation(err) {
+		return domain.ErrEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	return requireRowAffected(result, domain.ErrUserNotFound)
+}
+
+// Removes a user by their username.
+func (repo *userRepository) DeleteUser(ctx context.Context, username string) error {
+	result, err := repo.db.exec(ctx, `DELETE FROM users WHERE username = ?`, username)
+	if err != nil {
+		return err
+	}
+
+	return requireRowAffected(result, domain.ErrUserNotFound)
+}
+
+// Records the TOTP step unless the user already used it or a later one.
+func (repo *userRepository) UseTOTPStep(ctx context.Context, id domain.ID, step int64) error {
+	result, err := repo.db.exec(ctx, `UPDATE users SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?`, step, id.String(), step)
+	if err != nil {
+		return err
+	}
+
+	return requireRowAffected(result, domain.ErrInvalidMFACode)
+}
+
+// Removes the recovery code if the user still has it. The codes are one JSON list, which is only
+// replaced if it has not changed since it was read, so two requests cannot both spend a code.
+func (repo *userRepository) UseRecoveryCode(ctx context.Context, id domain.ID, codeHash string) error {
+	for {
+		var stored sql.NullString
+		err := repo.db.queryRow(ctx, `SELECT recovery_code_hashes FROM users WHERE id = ?`, id.String()).Scan(&stored)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidMFACode
+		}
+		if err != nil {
+			return err
+		}
+
+		hashes, err := listFromJSON(stored)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(hashes, codeHash) {
+			return domain.ErrInvalidMFACode
+		}
+
+		remaining := slices.DeleteFunc(hashes, func(hash string) bool { return hash == codeHash })
+		result, err := repo.db.exec(ctx, `UPDATE users SET recovery_code_hashes = ? WHERE id = ? AND recovery_code_hashes = ?`,
+			listToJSON(remaining), id.String(), stored.String)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected > 0 {
+			return nil
+		}
+		// The codes changed after they were read; look again
+	}
+}
+
+// Returns the user matching the condition, or ErrUserNotFound
+func (repo *userRepository) findUser(ctx context.Context, condition string, args ...any) (*domain.User, error) {
+	user, err := scanUser(repo.db.queryRow(ctx, `SELECT `+userColumns+` FROM users WHERE `+condition, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// The issuer is only saved together with a subject
+func linkedIssuer(user *domain.User) sql.NullString {
+	if user.OIDCSubject == "" {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: user.OIDCIssuer, Valid: true}
+}
+
+func scanUser(row scanner) (*domain.User, error) {
+	var user domain.User
+	var id string
+	var email, recoveryCodeHashes, oidcIssuer, oidcSubject sql.NullString
+	var lockedUntil sql.NullInt64
+	err := row.Scan(&id, &user.Username, &user.PasswordHash, &user.Role, &user.DisplayName, &user.Timezone, &user.TokenVersion,
+		&email, &user.EmailVerified, &user.FailedLoginAttempts, &lockedUntil, &user.TOTPSecret, &user.TOTPEnabled,
+		&recoveryCodeHashes, &oidcIssuer, &oidcSubject, &user.TOTPLastStep)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID, err = domain.ParseID(id); err != nil {
+		return nil, err
+	}
+	if user.RecoveryCodeHashes, err = listFromJSON(recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+	user.Email = email.String
+	user.LockedUntil = fromMillisPtr(lockedUntil)
+	user.OIDCIssuer = oidcIssuer.String
+	user.OIDCSubject = oidcSubject.String
+
+	return &user, nil
+}
@@ -3,15 +3,17 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	domain "task_manager/Domain"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type taskRepository struct {
-	collection *mongo.Collection
+	collection   *mongo.Collection
+	transactions domain.UnitOfWork
 }
 
 // Ensure *taskRepostory implements TaskRepository
@@ -19,7 +21,8 @@ var _ domain.TaskRepository = (*taskRepository)(nil)
 
 func NewTaskRepository(db *mongo.Client, dbName, collectionName string) domain.TaskRepository {
 	return &taskRepository{
-		collection: db.Database(dbName).Collection(collectionName),
+		collection:   openCollection(db, dbName, collectionName),
+		transactions: NewUnitOfWork(db),
 	}
 }
 
@@ -61,8 +64,8 @@ func (repo *taskRepository) GetAllTask(ctx context.Context) ([]domain.Task, erro
 func (repo *taskRepository) GetTaskByID(ctx context.Context, id string) (domain.Task, error) {
 	var findTask domain.Task
 
-	// Convert string id to ObjectID
-	objectID, err := primitive.ObjectIDFromHex(id)
+	// Parse the string id
+	objectID, err := domain.ParseID(id)
 	if err != nil {
 		return domain.Task{}, errors.New("invalid task ID format")
 	}
@@ -81,12 +84,38 @@ func (repo *taskRepository) GetTaskByID(ctx context.Context, id string) (domain.
 
 // Update and existing task
 func (repo *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask domain.Task) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := domain.ParseID(id)
 
 	if err != nil {
 		return err
 	}
 
+	setFields := taskUpdateFields(updatedTask)
+
+	// Confirm that the fields are not empty
+	if len(setFields) == 0 {
+		return errors.New("no field provided")
+	}
+
+	updatingTask := bson.M{"$set": setFields}
+
+	filter := bson.M{"_id": objectID}
+
+	result, err := repo.collection.UpdateOne(ctx, filter, updatingTask)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("task not found")
+	}
+
+	return nil
+}
+
+// Fields of updatedTask that are not empty, by their name in the collection
+func taskUpdateFields(updatedTask domain.Task) bson.M {
 	setFields := bson.M{}
 
 	if updatedTask.Title != "" {
@@ -108,54 +137,294 @@ func (repo *taskRepository) UpdateTask(ctx context.Context, id string, updatedTa
 		setFields["due_date"] = updatedTask.DueDate
 	}
 
-	// Confirm that the fields are not empty
-	if len(setFields) == 0 {
-		return errors.New("no field provided")
-	}
+	return setFields
+}
 
-	updatingTask := bson.M{"$set": setFields}
+func (repo *taskRepository) DeleteTask(ctx context.Context, id string) error {
+	objectID, err := domain.ParseID(id)
+
+	if err != nil {
+		return err
+	}
 
 	filter := bson.M{"_id": objectID}
 
-	result, err := repo.collection.UpdateOne(ctx, filter, updatingTask)
+	result, err := repo.collection.DeleteOne(ctx, filter)
 
 	if err != nil {
 		return err
 	}
 
-	if result.MatchedCount == 0 {
+	// Check if task to be deleted exists.
+	if result.DeletedCount == 0 {
 		return errors.New("task not found")
 	}
 
 	return nil
 }
 
-func (repo *taskRepository) DeleteTask(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
+// Deletes every task the user created
+func (repo *taskRepository) DeleteTasksByCreator(ctx context.Context, username string) error {
+	_, err := repo.collection.DeleteMany(ctx, bson.M{"created_by": username})
+
+	return err
+}
 
+// Creates a new task
+func (repo *taskRepository) NewTask(ctx context.Context, task domain.Task) (domain.Task, error) {
+	result, err := repo.collection.InsertOne(ctx, task)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Task{}, fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	}
 	if err != nil {
-		return err
+		return domain.Task{}, err
 	}
 
-	filter := bson.M{"_id": objectID}
+	task.ID = insertedID(result)
 
-	result, err := repo.collection.DeleteOne(ctx, filter)
+	return task, nil
+}
+
+// Counts tasks grouped by status
+func (repo *taskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
 
+	cursor, err := repo.collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	// Check if task to be deleted exists.
-	if result.DeletedCount == 0 {
-		return errors.New("task not found")
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
 	}
 
-	return nil
+	counts := make(map[string]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+
+	return counts, nil
 }
 
-// Creates a new task
-func (repo *taskRepository) NewTask(ctx context.Context, task domain.Task) (*mongo.InsertOneResult, error) {
-	result, err := repo.collection.InsertOne(ctx, task)
+// Tasks matching the filter, in no particular order
+func (repo *taskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter, limit int) ([]domain.Task, error) {
+	query := bson.M{}
+	if filter.IDs != nil {
+		query["_id"] = bson.M{"$in": filter.IDs}
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.CreatedBy != "" {
+		query["created_by"] = filter.CreatedBy
+	}
+
+	cursor, err := repo.collection.Find(ctx, query, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []domain.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Sends the operations in one BulkWrite. Atomic requests run it ordered, in a transaction, which
+// needs a replica set.
+func (repo *taskRepository) BulkWriteTasks(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool) ([]domain.BulkTaskResult, error) {
+	if !atomic {
+		return repo.bulkWrite(ctx, operations, false)
+	}
+
+	var results []domain.BulkTaskResult
+	err := repo.transactions.Do(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = repo.bulkWrite(ctx, operations, true)
+		if err != nil {
+			return err
+		}
+		if bulkFailed(results) {
+			return errBulkAborted
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkAborted) {
+		return skipApplied(results), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Aborts the transaction of an atomic bulk write after an operation failed
+var errBulkAborted = errors.New("bulk write aborted")
+
+// Writes the operations, reporting those that fail as failed, and the rest as applied. When
+// ordered, nothing is written if an operation cannot be sent, and the first write error stops the
+// rest.
+func (repo *taskRepository) bulkWrite(ctx context.Context, operations []domain.BulkTaskOperation, ordered bool) ([]domain.BulkTaskResult, error) {
+	// A bulk write only counts the documents its updates and deletes matched, so missing tasks are
+	// looked up beforehand
+	existing, err := repo.existingTasks(ctx, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.BulkTaskResult, len(operations))
+	var models []mongo.WriteModel
+	var modelOperations []int // Index of the operation each model was made from
+	for i, operation := range operations {
+		results[i] = domain.BulkTaskResult{Index: i, Op: operation.Op, ID: operation.ID}
+
+		model, id, err := bulkWriteModel(operation, existing)
+		if err != nil {
+			results[i] = failedBulkResult(results[i], err)
+			continue
+		}
+
+		results[i].ID = id.String()
+		models = append(models, model)
+		modelOperations = append(modelOperations, i)
+	}
+	if len(models) == 0 || (ordered && bulkFailed(results)) {
+		return results, nil
+	}
+
+	_, err = repo.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			i := modelOperations[writeErr.Index]
+			results[i] = failedBulkResult(results[i], errors.New(writeErr.Message))
+			if mongo.IsDuplicateKeyError(writeErr.WriteError) {
+				results[i] = failedBulkResult(results[i], domain.ErrConflict)
+			}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = domain.BulkStatusApplied
+		}
+	}
+
+	return results, nil
+}
+
+// IDs of the tasks the updates and deletes name that exist
+func (repo *taskRepository) existingTasks(ctx context.Context, operations []domain.BulkTaskOperation) (map[domain.ID]bool, error) {
+	var ids []domain.ID
+	for _, operation := range operations {
+		if id, err := domain.ParseID(operation.ID); err == nil && operation.Op != domain.BulkOpCreate {
+			ids = append(ids, id)
+		}
+	}
+
+	existing := make(map[domain.ID]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	cursor, err := repo.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var found []struct {
+		ID domain.ID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	for _, task := range found {
+		existing[task.ID] = true
+	}
+
+	return existing, nil
+}
+
+// The write an operation makes, and the ID of the task it changes
+func bulkWriteModel(operation domain.BulkTaskOperation, existing map[domain.ID]bool) (mongo.WriteModel, domain.ID, error) {
+	if operation.Op == domain.BulkOpCreate {
+		task := operation.Task
+		if task.ID.IsZero() {
+			task.ID = domain.NewID()
+		}
+		return mongo.NewInsertOneModel().SetDocument(task), task.ID, nil
+	}
+
+	id, err := domain.ParseID(operation.ID)
+	if err != nil {
+		return nil, domain.ID{}, errors.New("invalid task ID format")
+	}
+
+	switch operation.Op {
+	case domain.BulkOpUpdate:
+		setFields := taskUpdateFields(operation.Task)
+		if len(setFields) == 0 {
+			return nil, domain.ID{}, errors.New("no field provided")
+		}
+		if !existing[id] {
+			return nil, domain.ID{}, errors.New("task not found")
+		}
+		return mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": id}).SetUpdate(bson.M{"$set": setFields}), id, nil
+	case domain.BulkOpDelete:
+		if !existing[id] {
+			return nil, domain.ID{}, errors.New("task not found")
+		}
+		return mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": id}), id, nil
+	}
+
+	return nil, domain.ID{}, fmt.Errorf("unknown operation %q", operation.Op)
+}
+
+// Reports whether an operation failed
+func bulkFailed(results []domain.BulkTaskResult) bool {
+	for _, result := range results {
+		if result.Status == domain.BulkStatusFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Only created tasks report their ID, not creates that failed or were undone
+func failedBulkResult(result domain.BulkTaskResult, err error) domain.BulkTaskResult {
+	result.Status = domain.BulkStatusFailed
+	result.Error = err.Error()
+	if result.Op == domain.BulkOpCreate {
+		result.ID = ""
+	}
+
+	return result
+}
+
+// Marks every operation that did not fail as skipped, once an atomic bulk write was undone
+func skipApplied(results []domain.BulkTaskResult) []domain.BulkTaskResult {
+	for i := range results {
+		if results[i].Status == domain.BulkStatusFailed {
+			continue
+		}
+		results[i].Status = domain.BulkStatusSkipped
+		if results[i].Op == domain.BulkOpCreate {
+			results[i].ID = ""
+		}
+	}
 
-	return result, err
+	return results
 }
@@ -11,7 +11,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -48,11 +47,10 @@ func TestTaskRepository_Integration(t *testing.T) {
 			DueDate:     time.Now().Add(48 * time.Hour).Truncate(time.Millisecond), // Truncate for easier comparison
 		}
 
-		insertResult, err := taskRepo.NewTask(ctx, taskToCreate)
+		created, err := taskRepo.NewTask(ctx, taskToCreate)
 		require.NoError(t, err)
-		require.NotNil(t, insertResult)
-		insertedID, ok := insertResult.InsertedID.(primitive.ObjectID)
-		require.True(t, ok)
+		insertedID := created.ID
+		require.False(t, insertedID.IsZero())
 
 		// Verify in DB
 		var foundTask domain.Task
@@ -70,7 +68,7 @@ func TestTaskRepository_Integration(t *testing.T) {
 	t.Run("GetTaskByID_Success", func(t *testing.T) {
 		taskCollection := getTaskTestCollection(t) // Clean and get
 
-		taskID := primitive.NewObjectID()
+		taskID := domain.NewID()
 		taskToInsert := domain.Task{
 			ID:        taskID,
 			Title:     "Find Me Task",
@@ -80,16 +78,30 @@ func TestTaskRepository_Integration(t *testing.T) {
 		_, err := taskCollection.InsertOne(ctx, taskToInsert)
 		require.NoError(t, err)
 
-		foundTask, err := taskRepo.GetTaskByID(ctx, taskID.Hex())
+		foundTask, err := taskRepo.GetTaskByID(ctx, taskID.String())
 		require.NoError(t, err)
 		assert.Equal(t, taskToInsert.Title, foundTask.Title)
 		assert.Equal(t, taskID, foundTask.ID)
 	})
 
+	t.Run("NewTask_StoresIDAsObjectID", func(t *testing.T) {
+		taskCollection := getTaskTestCollection(t)
+
+		created, err := taskRepo.NewTask(ctx, domain.Task{Title: "Stored Task", Status: "Todo", CreatedBy: defaultUser})
+		require.NoError(t, err)
+
+		// Records written before domain.ID existed hold ObjectIDs, so new ones must too
+		var raw bson.Raw
+		require.NoError(t, taskCollection.FindOne(ctx, bson.M{}).Decode(&raw))
+		storedID, ok := raw.Lookup("_id").ObjectIDOK()
+		require.True(t, ok, "_id should be stored as an ObjectID")
+		assert.Equal(t, created.ID.String(), storedID.Hex())
+	})
+
 	t.Run("GetTaskByID_NotFound", func(t *testing.T) {
 		_ = getTaskTestCollection(t) // Clean
-		nonExistentID := primitive.NewObjectID()
-		_, err := taskRepo.GetTaskByID(ctx, nonExistentID.Hex())
+		nonExistentID := domain.NewID()
+		_, err := taskRepo.GetTaskByID(ctx, nonExistentID.String())
 		require.Error(t, err)
 		assert.EqualError(t, err, "task not found") // Error from your repository
 	})
@@ -104,8 +116,8 @@ func TestTaskRepository_Integration(t *testing.T) {
 	t.Run("GetAllTask_Success", func(t *testing.T) {
 		taskCollection := getTaskTestCollection(t) // Clean and get
 
-		task1 := domain.Task{ID: primitive.NewObjectID(), Title: "Task A", Status: "Todo", CreatedBy: defaultUser}
-		task2 := domain.Task{ID: primitive.NewObjectID(), Title: "Task B", Status: "Done", CreatedBy: defaultUser}
+		task1 := domain.Task{ID: domain.NewID(), Title: "Task A", Status: "Todo", CreatedBy: defaultUser}
+		task2 := domain.Task{ID: domain.NewID(), Title: "Task B", Status: "Done", CreatedBy: defaultUser}
 		_, err := taskCollection.InsertMany(ctx, []interface{}{task1, task2})
 		require.NoError(t, err)
 
@@ -128,7 +140,7 @@ func TestTaskRepository_Integration(t *testing.T) {
 		taskCollection := getTaskTestCollection(t) // Clean and get
 
 		originalTask := domain.Task{
-			ID:        primitive.NewObjectID(),
+			ID:        domain.NewID(),
 			Title:     "Original Title",
 			Status:    "Pending",
 			CreatedBy: defaultUser,
@@ -143,7 +155,7 @@ func TestTaskRepository_Integration(t *testing.T) {
 			DueDate:     time.Now().Add(72 * time.Hour).Truncate(time.Millisecond),
 		}
 
-		err = taskRepo.UpdateTask(ctx, originalTask.ID.Hex(), updateData)
+		err = taskRepo.UpdateTask(ctx, originalTask.ID.String(), updateData)
 		require.NoError(t, err)
 
 		// Verify in DB
@@ -159,21 +171,21 @@ func TestTaskRepository_Integration(t *testing.T) {
 
 	t.Run("UpdateTask_TaskNotFound", func(t *testing.T) {
 		_ = getTaskTestCollection(t) // Clean
-		nonExistentID := primitive.NewObjectID()
+		nonExistentID := domain.NewID()
 		updateData := domain.Task{Title: "Won't Update"}
-		err := taskRepo.UpdateTask(ctx, nonExistentID.Hex(), updateData)
+		err := taskRepo.UpdateTask(ctx, nonExistentID.String(), updateData)
 		require.Error(t, err)
 		assert.EqualError(t, err, "task not found")
 	})
 
 	t.Run("UpdateTask_NoFieldsToUpdate", func(t *testing.T) {
 		taskCollection := getTaskTestCollection(t) // Clean and get
-		taskToUpdate := domain.Task{ID: primitive.NewObjectID(), Title: "A Task"}
+		taskToUpdate := domain.Task{ID: domain.NewID(), Title: "A Task"}
 		_, err := taskCollection.InsertOne(ctx, taskToUpdate)
 		require.NoError(t, err)
 
 		emptyUpdate := domain.Task{} // No fields set
-		err = taskRepo.UpdateTask(ctx, taskToUpdate.ID.Hex(), emptyUpdate)
+		err = taskRepo.UpdateTask(ctx, taskToUpdate.ID.String(), emptyUpdate)
 		require.Error(t, err)
 		assert.EqualError(t, err, "no field provided")
 	})
@@ -181,11 +193,11 @@ func TestTaskRepository_Integration(t *testing.T) {
 	t.Run("DeleteTask_Success", func(t *testing.T) {
 		taskCollection := getTaskTestCollection(t) // Clean and get
 
-		taskToDelete := domain.Task{ID: primitive.NewObjectID(), Title: "To Be Deleted"}
+		taskToDelete := domain.Task{ID: domain.NewID(), Title: "To Be Deleted"}
 		_, err := taskCollection.InsertOne(ctx, taskToDelete)
 		require.NoError(t, err)
 
-		err = taskRepo.DeleteTask(ctx, taskToDelete.ID.Hex())
+		err = taskRepo.DeleteTask(ctx, taskToDelete.ID.String())
 		require.NoError(t, err)
 
 		// Verify in DB
@@ -196,9 +208,24 @@ func TestTaskRepository_Integration(t *testing.T) {
 
 	t.Run("DeleteTask_TaskNotFound", func(t *testing.T) {
 		_ = getTaskTestCollection(t) // Clean
-		nonExistentID := primitive.NewObjectID()
-		err := taskRepo.DeleteTask(ctx, nonExistentID.Hex())
+		nonExistentID := domain.NewID()
+		err := taskRepo.DeleteTask(ctx, nonExistentID.String())
 		require.Error(t, err)
 		assert.EqualError(t, err, "task not found")
 	})
+
+	t.Run("CountTasksByStatus", func(t *testing.T) {
+		taskCollection := getTaskTestCollection(t) // Clean and get
+
+		_, err := taskCollection.InsertMany(ctx, []interface{}{
+			domain.Task{ID: domain.NewID(), Title: "Task A", Status: "Todo", CreatedBy: defaultUser},
+			domain.Task{ID: domain.NewID(), Title: "Task B", Status: "Todo", CreatedBy: defaultUser},
+			domain.Task{ID: domain.NewID(), Title: "Task C", Status: "Done", CreatedBy: defaultUser},
+		})
+		require.NoError(t, err)
+
+		counts, err := taskRepo.CountTasksByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"Todo": 2, "Done": 1}, counts)
+	})
 }
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type unitOfWork struct {
+	client *mongo.Client
+}
+
+var _ domain.UnitOfWork = (*unitOfWork)(nil)
+
+// Runs transactions in a client session. Transactions need a replica set or a sharded cluster;
+// a single node started with --replSet will do.
+func NewUnitOfWork(client *mongo.Client) domain.UnitOfWork {
+	return &unitOfWork{client: client}
+}
+
+// Repositories take part through the context: operations given a session's context run in its
+// transaction, whichever collection they use
+func (uow *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := uow.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessionCtx)
+	})
+	if transactionsUnsupported(err) {
+		return fmt.Errorf("%w: %w", domain.ErrNoTransactions, err)
+	}
+
+	return err
+}
+
+// Standalone servers refuse the first operation of a transaction with IllegalOperation
+func transactionsUnsupported(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(illegalOperationCode)
+}
+
+const illegalOperationCode = 20
@@ -2,14 +2,25 @@ package repositories
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
 	domain "task_manager/Domain"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Unique indexes on username: exact, created by migration 2 and used by lookups, and regardless of case
+const (
+	usernameIndex                = "username_unique"
+	usernameCaseInsensitiveIndex = "username_unique_ci"
+)
+
+// Case-insensitive comparison, shared by the email index and email lookups so the index is used.
+var caseInsensitiveCollation = &options.Collation{Locale: "en", Strength: 2}
+
 type userRepository struct {
 	collection *mongo.Collection
 }
@@ -18,34 +29,48 @@ var _ domain.UserRepository = (*userRepository)(nil)
 
 func NewUserRepository(db *mongo.Client, dbName, collectionName string) domain.UserRepository {
 	return &userRepository{
-		collection: db.Database(dbName).Collection(collectionName),
+		collection: openCollection(db, dbName, collectionName),
 	}
 }
 
 // Creates a new user
-func (repo *userRepository) CreateUser(ctx context.Context, user *domain.User) (*mongo.InsertOneResult, error) {
+// Uniqueness is left to the unique indexes, so concurrent registrations cannot both succeed.
+func (repo *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	result, err := repo.collection.InsertOne(ctx, user)
+	if isDuplicateKeyOn(err, usernameIndex) || isDuplicateKeyOn(err, usernameCaseInsensitiveIndex) {
+		return fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrUserAlreadyExists)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		// The email or the linked provider account is taken
+		return fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrEmailTaken)
+	}
 
-	// Check if user already exists.
-	existingUser := domain.User{}
+	if err != nil {
+		return err
+	}
 
-	err := repo.collection.FindOne(ctx, bson.M{"username": user.Username}).Decode(&existingUser)
+	user.ID = insertedID(result)
 
-	if err == nil {
-		// User found.
-		return nil, errors.New("username is already taken")
-	}
+	return nil
+}
+
+// Get a user by their ID, which unlike the username is never reused.
+func (repo *userRepository) FindUserByID(ctx context.Context, id domain.ID) (*domain.User, error) {
+	var user domain.User
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
 
-	// If no document is returned, it means no user currently have that username.
-	if err != mongo.ErrNoDocuments {
-		return nil, errors.New("database error while checking username")
+	err := repo.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrUserNotFound
 	}
 
-	result, err := repo.collection.InsertOne(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return &user, nil
 }
 
 // Get a user by their username.
@@ -61,7 +86,7 @@ func (repo *userRepository) FindUserByUsername(ctx context.Context, username str
 
 	// User does not exist
 	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("user not found")
+		return nil, domain.ErrUserNotFound
 	}
 
 	if err != nil {
@@ -70,3 +95,187 @@ func (repo *userRepository) FindUserByUsername(ctx context.Context, username str
 
 	return &user, nil
 }
+
+// Get a user by their email address, ignoring case.
+func (repo *userRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.FindOne().SetCollation(caseInsensitiveCollation)
+	err := repo.collection.FindOne(ctx, bson.M{"email": email}, opts).Decode(&user)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Get the user linked to an account at an OpenID Connect provider.
+func (repo *userRepository) FindUserByOIDCSubject(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	var user domain.User
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := repo.collection.FindOne(ctx, bson.M{"oidc_issuer": issuer, "oidc_subject": subject}).Decode(&user)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Saves changes to an existing user, matched by ID.
+func (repo *userRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	setFields := bson.M{
+		"password_hash":         user.PasswordHash,
+		"role":                  user.Role,
+		"display_name":          user.DisplayName,
+		"timezone":              user.Timezone,
+		"token_version":         user.TokenVersion,
+		"email_verified":        user.EmailVerified,
+		"failed_login_attempts": user.FailedLoginAttempts,
+		"locked_until":          user.LockedUntil,
+		"totp_secret":           user.TOTPSecret,
+		"totp_enabled":          user.TOTPEnabled,
+		"recovery_code_hashes":  user.RecoveryCodeHashes,
+	}
+	update := bson.M{"$set": setFields}
+
+	// An empty string would collide with other empty emails in the unique index, so remove the field instead
+	if user.Email != "" {
+		setFields["email"] = user.Email
+	} else {
+		update["$unset"] = bson.M{"email": ""}
+	}
+
+	// A linked provider account is never unlinked by an update
+	if user.OIDCSubject != "" {
+		setFields["oidc_issuer"] = user.OIDCIssuer
+		setFields["oidc_subject"] = user.OIDCSubject
+	}
+
+	result, err := repo.collection.UpdateOne(ctx, bson.M{"_id": user.ID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrEmailTaken
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Removes a user by their username.
+func (repo *userRepository) DeleteUser(ctx context.Context, username string) error {
+	result, err := repo.collection.DeleteOne(ctx, bson.M{"username": username})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Records the TOTP step unless the user already used it or a later one. Users who never used a
+// code have no step stored.
+func (repo *userRepository) UseTOTPStep(ctx context.Context, id domain.ID, step int64) error {
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"totp_last_step": bson.M{"$lt": step}},
+			bson.M{"totp_last_step": bson.M{"$exists": false}},
+		},
+	}
+
+	result, err := repo.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"totp_last_step": step}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// Pulls the recovery code only from a user who still has it, so it is spent once
+func (repo *userRepository) UseRecoveryCode(ctx context.Context, id domain.ID, codeHash string) error {
+	filter := bson.M{"_id": id, "recovery_code_hashes": codeHash}
+
+	result, err := repo.collection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"recovery_code_hashes": codeHash}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// Creates the unique index on username that ignores case, failing while two usernames differ only in case
+func EnsureUsernameIndex(ctx context.Context, db *mongo.Client, dbName, collectionName string) error {
+	_, err := openCollection(db, dbName, collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "username", Value: 1}},
+		Options: options.Index().SetName(usernameCaseInsensitiveIndex).SetUnique(true).SetCollation(caseInsensitiveCollation),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("several usernames differ only in case, rename all but one of each first: %w", err)
+	}
+	return err
+}
+
+// Creates the indexes the user collection relies on. Safe to call on every start-up.
+func EnsureUserIndexes(ctx context.Context, db *mongo.Client, dbName, collectionName string) error {
+	collection := openCollection(db, dbName, collectionName)
+
+	// Unique, case-insensitive email. Accounts created before emails were required have none.
+	emailIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().
+			SetName("email_unique_ci").
+			SetUnique(true).
+			SetCollation(caseInsensitiveCollation).
+			SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string"}}),
+	}
+
+	// A provider account can only be linked to one user
+	oidcIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "oidc_issuer", Value: 1}, {Key: "oidc_subject", Value: 1}},
+		Options: options.Index().
+			SetName("oidc_subject_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"oidc_subject": bson.M{"$type": "string"}}),
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{emailIndex, oidcIndex})
+	return err
+}
+
+// Reports whether err is a duplicate key error raised by the named unique index
+func isDuplicateKeyOn(err error, index string) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), "index: "+index+" ")
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -38,6 +37,10 @@ func TestUserRepository_Integration(t *testing.T) {
 	userRepo := repositories.NewUserRepository(testDBClient, TestDatabaseName, testUserCollectionName)
 	require.NotNil(t, userRepo, "NewUserRepository returned nil")
 
+	// CreateUser relies on the unique indexes to reject taken usernames and emails
+	require.NoError(t, repositories.EnsureUserIndexes(context.Background(), testDBClient, TestDatabaseName, testUserCollectionName))
+	require.NoError(t, repositories.EnsureUsernameIndex(context.Background(), testDBClient, TestDatabaseName, testUserCollectionName))
+
 	ctx := context.Background() // Use a fresh context for operations
 
 	t.Run("CreateUser_Success", func(t *testing.T) {
@@ -49,12 +52,10 @@ func TestUserRepository_Integration(t *testing.T) {
 			Role:         domain.RoleUser,
 		}
 
-		insertResult, err := userRepo.CreateUser(ctx, userToCreate)
+		err := userRepo.CreateUser(ctx, userToCreate)
 		require.NoError(t, err, "CreateUser should not return an error on success")
-		require.NotNil(t, insertResult, "InsertResult should not be nil")
-		require.NotNil(t, insertResult.InsertedID, "InsertedID should not be nil")
-		insertedID, ok := insertResult.InsertedID.(primitive.ObjectID)
-		require.True(t, ok, "InsertedID should be a primitive.ObjectID")
+		insertedID := userToCreate.ID
+		require.False(t, insertedID.IsZero(), "CreateUser should set the ID")
 
 		// Verify directly in the database
 		var foundUser domain.User
@@ -71,16 +72,15 @@ func TestUserRepository_Integration(t *testing.T) {
 		_ = getUserTestCollection(t) // Clean collection
 
 		user1 := &domain.User{Username: "duplicate_integ_user", PasswordHash: "hash1", Role: domain.RoleUser}
-		_, err := userRepo.CreateUser(ctx, user1) // Insert the first user
+		err := userRepo.CreateUser(ctx, user1) // Insert the first user
 		require.NoError(t, err, "First CreateUser call failed unexpectedly")
 
 		user2 := &domain.User{Username: "duplicate_integ_user", PasswordHash: "hash2", Role: domain.RoleUser}
-		insertResult, err := userRepo.CreateUser(ctx, user2) // Attempt to insert user with the same username
+		err = userRepo.CreateUser(ctx, user2) // Attempt to insert user with the same username
 
 		require.Error(t, err, "CreateUser should return an error for duplicate username")
-		assert.Nil(t, insertResult, "InsertResult should be nil on error")
-		// Your repository already checks for this and returns a specific error
-		assert.EqualError(t, err, "username is already taken")
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
 
 		// Verify only one user with that username exists in the DB
 		collection := testDBClient.Database(TestDatabaseName).Collection(testUserCollectionName)
@@ -93,7 +93,7 @@ func TestUserRepository_Integration(t *testing.T) {
 		userCollection := getUserTestCollection(t) // Clean and get collection
 
 		expectedUser := &domain.User{
-			ID:           primitive.NewObjectID(), // Pre-assign ID for exact match if desired
+			ID:           domain.NewID(), // Pre-assign ID for exact match if desired
 			Username:     "findme_integ",
 			PasswordHash: "findmehash_integ",
 			Role:         domain.RoleAdmin,
@@ -128,7 +128,7 @@ func TestUserRepository_Integration(t *testing.T) {
 		// We can test that a short-lived context passed from outside also works.
 
 		userToInsert := &domain.User{Username: "timeout_user_integ", PasswordHash: "hash"}
-		_, err := userRepo.CreateUser(ctx, userToInsert) // Use the main ctx for setup
+		err := userRepo.CreateUser(ctx, userToInsert) // Use the main ctx for setup
 		require.NoError(t, err)
 
 		shortCtx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond) // Extremely short timeout
@@ -142,4 +142,97 @@ func TestUserRepository_Integration(t *testing.T) {
 		assert.True(t, errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || mongo.IsTimeout(err),
 			"Expected context deadline/canceled error or mongo timeout, got: %v", err)
 	})
+
+	t.Run("UpdateUser_Success", func(t *testing.T) {
+		_ = getUserTestCollection(t) // Clean collection
+
+		err := userRepo.CreateUser(ctx, &domain.User{Username: "update_integ_user", PasswordHash: "hash", Role: domain.RoleUser})
+		require.NoError(t, err)
+
+		user, err := userRepo.FindUserByUsername(ctx, "update_integ_user")
+		require.NoError(t, err)
+
+		user.DisplayName = "Updated Name"
+		user.TokenVersion = 4
+		require.NoError(t, userRepo.UpdateUser(ctx, user), "UpdateUser should not return an error for an existing user")
+
+		updatedUser, err := userRepo.FindUserByUsername(ctx, "update_integ_user")
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Name", updatedUser.DisplayName)
+		assert.Equal(t, 4, updatedUser.TokenVersion)
+	})
+
+	t.Run("UpdateUser_Failure_UserDoesNotExist", func(t *testing.T) {
+		_ = getUserTestCollection(t) // Clean collection
+
+		err := userRepo.UpdateUser(ctx, &domain.User{ID: domain.NewID(), Username: "ghost_integ_user"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("DeleteUser_Success", func(t *testing.T) {
+		_ = getUserTestCollection(t) // Clean collection
+
+		err := userRepo.CreateUser(ctx, &domain.User{Username: "delete_integ_user", PasswordHash: "hash"})
+		require.NoError(t, err)
+
+		require.NoError(t, userRepo.DeleteUser(ctx, "delete_integ_user"))
+
+		_, err = userRepo.FindUserByUsername(ctx, "delete_integ_user")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Deleted user should no longer be found")
+
+		err = userRepo.DeleteUser(ctx, "delete_integ_user")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Deleting twice should report the user as missing")
+	})
+
+	t.Run("FindUserByEmail_CaseInsensitive", func(t *testing.T) {
+		_ = getUserTestCollection(t) // Clean collection
+
+		err := userRepo.CreateUser(ctx, &domain.User{Username: "email_integ_user", Email: "email_integ@example.com", PasswordHash: "hash"})
+		require.NoError(t, err)
+
+		foundUser, err := userRepo.FindUserByEmail(ctx, "Email_Integ@Example.COM")
+		require.NoError(t, err, "Email lookup should ignore case")
+		assert.Equal(t, "email_integ_user", foundUser.Username)
+
+		_, err = userRepo.FindUserByEmail(ctx, "nobody@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("EnsureUserIndexes_RejectsDuplicateEmail", func(t *testing.T) {
+		userCollection := getUserTestCollection(t) // Clean collection
+		require.NoError(t, repositories.EnsureUserIndexes(ctx, testDBClient, TestDatabaseName, testUserCollectionName))
+
+		// Users without an email must not collide with each other
+		_, err := userCollection.InsertOne(ctx, &domain.User{Username: "no_email_1"})
+		require.NoError(t, err)
+		_, err = userCollection.InsertOne(ctx, &domain.User{Username: "no_email_2"})
+		require.NoError(t, err)
+
+		_, err = userCollection.InsertOne(ctx, &domain.User{Username: "index_user_1", Email: "dup@example.com"})
+		require.NoError(t, err)
+		_, err = userCollection.InsertOne(ctx, &domain.User{Username: "index_user_2", Email: "DUP@example.com"})
+		assert.True(t, mongo.IsDuplicateKeyError(err), "The email index should be unique regardless of case, got: %v", err)
+	})
+	t.Run("FindUserByOIDCSubject_LinkedByUpdate", func(t *testing.T) {
+		_ = getUserTestCollection(t) // Clean collection
+
+		err := userRepo.CreateUser(ctx, &domain.User{Username: "sso_integ_user", PasswordHash: "hash", Role: domain.RoleUser})
+		require.NoError(t, err)
+
+		_, err = userRepo.FindUserByOIDCSubject(ctx, "https://idp.example.com", "subject-1")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "The user is not linked yet")
+
+		user, err := userRepo.FindUserByUsername(ctx, "sso_integ_user")
+		require.NoError(t, err)
+		user.OIDCIssuer = "https://idp.example.com"
+		user.OIDCSubject = "subject-1"
+		require.NoError(t, userRepo.UpdateUser(ctx, user))
+
+		linkedUser, err := userRepo.FindUserByOIDCSubject(ctx, "https://idp.example.com", "subject-1")
+		require.NoError(t, err)
+		assert.Equal(t, "sso_integ_user", linkedUser.Username)
+
+		_, err = userRepo.FindUserByOIDCSubject(ctx, "https://other-idp.example.com", "subject-1")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "Subjects are only unique per issuer")
+	})
 }
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	domain "task_manager/Domain"
+)
+
+// Events returned by one audit log query, when the filter does not say
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 10000
+)
+
+type auditUsecase struct {
+	auditRepo domain.AuditLogRepository
+}
+
+func NewAuditUsecase(auditRepo domain.AuditLogRepository) domain.AuditUsecase {
+	return &auditUsecase{auditRepo: auditRepo}
+}
+
+func (usecase *auditUsecase) ListEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+
+	return usecase.auditRepo.FindEvents(ctx, filter)
+}
+
+// Audit event of an action by the current user. It succeeded when err is nil.
+func auditEvent(action, targetType, targetID string, err error) domain.AuditEvent {
+	event := domain.AuditEvent{
+		Action:     action,
+		Outcome:    domain.AuditOutcomeSuccess,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
+
+	if err != nil {
+		event.Outcome = domain.AuditOutcomeFailure
+		event.Details = map[string]string{"reason": err.Error()}
+	}
+
+	return event
+}
+
+// Audit event of a login attempt. The user logging in is the actor, as nobody is authenticated yet.
+func loginEvent(username, method string, err error) domain.AuditEvent {
+	event := auditEvent(domain.AuditActionLogin, domain.AuditTargetUser, username, err)
+	event.Actor = username
+
+	if event.Details == nil {
+		event.Details = map[string]string{}
+	}
+	event.Details["method"] = method
+
+	return event
+}
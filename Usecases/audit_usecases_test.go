@@ -0,0 +1,54 @@
+package usecases_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditUsecaseSuite struct {
+	suite.Suite
+	mockAuditRepo *mocks.MockAuditLogRepository
+	auditUsecase  domain.AuditUsecase
+}
+
+func (s *AuditUsecaseSuite) SetupTest() {
+	s.mockAuditRepo = mocks.NewMockAuditLogRepository(s.T())
+	s.auditUsecase = usecases.NewAuditUsecase(s.mockAuditRepo)
+}
+
+func TestAuditUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(AuditUsecaseSuite))
+}
+
+func (s *AuditUsecaseSuite) TestListEvents_PassesFilter() {
+	ctx := context.Background()
+	events := []domain.AuditEvent{{Action: domain.AuditActionTaskDelete, Actor: "alice"}}
+
+	s.mockAuditRepo.EXPECT().
+		FindEvents(ctx, domain.AuditFilter{Actor: "alice", Action: domain.AuditActionTaskDelete, Limit: 20}).
+		Return(events, nil).
+		Once()
+
+	result, err := s.auditUsecase.ListEvents(ctx, domain.AuditFilter{Actor: "alice", Action: domain.AuditActionTaskDelete, Limit: 20})
+
+	s.NoError(err)
+	s.Equal(events, result)
+}
+
+func (s *AuditUsecaseSuite) TestListEvents_DefaultAndMaximumLimit() {
+	ctx := context.Background()
+
+	s.mockAuditRepo.EXPECT().FindEvents(ctx, domain.AuditFilter{Limit: 100}).Return([]domain.AuditEvent{}, nil).Once()
+	s.mockAuditRepo.EXPECT().FindEvents(ctx, domain.AuditFilter{Limit: 10000}).Return([]domain.AuditEvent{}, nil).Once()
+
+	_, err := s.auditUsecase.ListEvents(ctx, domain.AuditFilter{})
+	s.NoError(err)
+
+	_, err = s.auditUsecase.ListEvents(ctx, domain.AuditFilter{Limit: 50000})
+	s.NoError(err)
+}
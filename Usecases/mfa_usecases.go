@@ -0,0 +1,364 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Number of recovery codes issued when two-factor authentication is enabled
+const recoveryCodeCount = 10
+
+// Time allowed between the password step and the second factor of a login
+const mfaTokenTTL = 5 * time.Minute
+
+type mfaUsecase struct {
+	userRepo        domain.UserRepository
+	policyRepo      domain.MFAPolicyRepository
+	roleRepo        domain.RoleRepository
+	tokenRepo       domain.OneTimeTokenRepository
+	passwordService domain.PasswordService
+	jwtService      domain.JWTService
+	totpService     domain.TOTPService
+	loginThrottle   domain.LoginThrottle
+	audit           domain.AuditLogger
+}
+
+func NewMFAUsecase(userRepo domain.UserRepository, policyRepo domain.MFAPolicyRepository, roleRepo domain.RoleRepository, tokenRepo domain.OneTimeTokenRepository, passwordService domain.PasswordService, jwtService domain.JWTService, totpService domain.TOTPService, loginThrottle domain.LoginThrottle, audit domain.AuditLogger) domain.MFAUsecase {
+	return &mfaUsecase{
+		userRepo:        userRepo,
+		policyRepo:      policyRepo,
+		roleRepo:        roleRepo,
+		tokenRepo:       tokenRepo,
+		passwordService: passwordService,
+		jwtService:      jwtService,
+		totpService:     totpService,
+		loginThrottle:   loginThrottle,
+		audit:           audit,
+	}
+}
+
+// Generate a new TOTP secret for the user. It only takes effect once confirmed with a code.
+func (usecase *mfaUsecase) BeginEnrollment(ctx context.Context, username string) (*domain.TOTPEnrollment, error) {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	enrollment, err := usecase.totpService.GenerateSecret(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = enrollment.Secret
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// Enable two-factor authentication once the user proves their app produces valid codes
+func (usecase *mfaUsecase) ConfirmEnrollment(ctx context.Context, username, code string) ([]string, error) {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, domain.ErrMFANotEnrolling
+	}
+
+	if err := usecase.verifyCode(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.RecoveryCodeHashes = hashes
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Turn two-factor authentication off. Needs the password and a current code,
+// and is refused while the user's role requires it.
+func (usecase *mfaUsecase) Disable(ctx context.Context, username, password, code string) error {
+	err := usecase.disable(ctx, username, password, code)
+	usecase.audit.Record(ctx, auditEvent(domain.AuditActionMFADisable, domain.AuditTargetUser, username, err))
+
+	return err
+}
+
+func (usecase *mfaUsecase) disable(ctx context.Context, username, password, code string) error {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return domain.ErrMFANotEnabled
+	}
+
+	if err := usecase.passwordService.ComparePasswords(user.PasswordHash, password); err != nil {
+		return domain.ErrIncorrectPassword
+	}
+
+	policy, err := usecase.policyRepo.GetMFAPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	if policy.RequiresMFA(user.Role) {
+		return domain.ErrMFARequired
+	}
+
+	if err := usecase.verifyCode(ctx, user, code, true); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.RecoveryCodeHashes = nil
+
+	return usecase.userRepo.UpdateUser(ctx, user)
+}
+
+// Replace every recovery code with a fresh set
+func (usecase *mfaUsecase) RegenerateRecoveryCodes(ctx context.Context, username, code string) ([]string, error) {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	// Only an authenticator code will do here, a recovery code cannot mint new ones
+	if err := usecase.verifyCode(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.RecoveryCodeHashes = hashes
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Second step of a login: swap the token from the password step and a code for an access token.
+// Wrong codes count towards the same lockout as wrong passwords, and the token is swapped only once.
+func (usecase *mfaUsecase) CompleteLogin(ctx context.Context, mfaToken, code string) (string, error) {
+	claims, err := usecase.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	token, err := usecase.completeLogin(ctx, claims, code)
+	usecase.audit.Record(ctx, loginEvent(claims.Username, "mfa", err))
+
+	return token, err
+}
+
+func (usecase *mfaUsecase) completeLogin(ctx context.Context, claims *domain.CustomClaims, code string) (string, error) {
+	userID, err := claims.UserID()
+	if err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	user, err := usecase.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	// The password changed or two-factor was turned off since the password step
+	if user.TokenVersion != claims.TokenVersion || !user.TOTPEnabled {
+		return "", domain.ErrInvalidToken
+	}
+
+	// Spare the code if the token was already swapped; consuming it below settles a race
+	if _, err := usecase.tokenRepo.FindToken(ctx, domain.TokenPurposeMFALogin, hashToken(claims.ID)); err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	if err := usecase.verifyCode(ctx, user, code, true); err != nil {
+		return "", err
+	}
+
+	if _, err := usecase.tokenRepo.ConsumeToken(ctx, domain.TokenPurposeMFALogin, hashToken(claims.ID)); err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	return usecase.jwtService.GenerateToken(user)
+}
+
+func (usecase *mfaUsecase) GetPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	return usecase.policyRepo.GetMFAPolicy(ctx)
+}
+
+// Replace the set of roles that must use two-factor authentication. Every role must exist.
+func (usecase *mfaUsecase) SetPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	if policy.RequiredRoles == nil {
+		policy.RequiredRoles = []string{}
+	}
+
+	for _, role := range policy.RequiredRoles {
+		if _, err := usecase.roleRepo.GetRole(ctx, role); err != nil {
+			return err
+		}
+	}
+
+	return usecase.policyRepo.SaveMFAPolicy(ctx, policy)
+}
+
+// Check a code behind the same attempt limit as logins: wrong codes count towards the lockout.
+func (usecase *mfaUsecase) verifyCode(ctx context.Context, user *domain.User, code string, recoveryCodes bool) error {
+	clientIP := domain.RequestMetaFromContext(ctx).ClientIP
+	if wait := usecase.loginThrottle.RetryAfter(ctx, user.Username, clientIP); wait > 0 {
+		return &domain.TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return &domain.TooManyAttemptsError{RetryAfter: time.Until(*user.LockedUntil)}
+	}
+
+	if !usecase.checkCode(ctx, user, code, recoveryCodes) {
+		usecase.recordFailedCode(ctx, user, clientIP)
+		return domain.ErrInvalidMFACode
+	}
+
+	usecase.loginThrottle.RecordSuccess(ctx, user.Username, clientIP)
+
+	return nil
+}
+
+// Accept a TOTP code not used before, or where allowed an unused recovery code. Either is spent
+// atomically, so concurrent requests cannot use the same code twice.
+func (usecase *mfaUsecase) checkCode(ctx context.Context, user *domain.User, code string, recoveryCodes bool) bool {
+	if step, ok := usecase.totpService.ValidateCode(user.TOTPSecret, code); ok {
+		return usecase.spent(ctx, user, "TOTP code", usecase.userRepo.UseTOTPStep(ctx, user.ID, step))
+	}
+
+	if !recoveryCodes {
+		return false
+	}
+
+	codeHash := hashToken(normalizeRecoveryCode(code))
+	if !slices.Contains(user.RecoveryCodeHashes, codeHash) {
+		return false
+	}
+
+	if !usecase.spent(ctx, user, "recovery code", usecase.userRepo.UseRecoveryCode(ctx, user.ID, codeHash)) {
+		return false
+	}
+
+	// Keep the user in step with what is stored, in case it is saved later
+	user.RecoveryCodeHashes = slices.DeleteFunc(slices.Clone(user.RecoveryCodeHashes), func(hash string) bool { return hash == codeHash })
+
+	return true
+}
+
+// Reports whether a code was spent. ErrInvalidMFACode means it already was; other errors are logged.
+func (usecase *mfaUsecase) spent(ctx context.Context, user *domain.User, kind string, err error) bool {
+	if err != nil && !errors.Is(err, domain.ErrInvalidMFACode) {
+		domain.LoggerFromContext(ctx).Error("failed to spend "+kind, slog.String("username", user.Username), slog.Any("error", err))
+	}
+
+	return err == nil
+}
+
+// Same as userUsecase.recordFailedLogin: only a lockout is written to the user.
+func (usecase *mfaUsecase) recordFailedCode(ctx context.Context, user *domain.User, clientIP string) {
+	failures, lockedUntil := usecase.loginThrottle.RecordFailure(ctx, user.Username, clientIP)
+	if lockedUntil.IsZero() {
+		return
+	}
+
+	user.FailedLoginAttempts = failures
+	user.LockedUntil = &lockedUntil
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		domain.LoggerFromContext(ctx).Error("failed to record lockout", slog.String("username", user.Username), slog.Any("error", err))
+	}
+}
+
+// Hand out the token for the second step of a login. Its ID is stored as a one-time token, so the
+// token can be swapped for an access token only once.
+func issueMFAToken(ctx context.Context, tokenRepo domain.OneTimeTokenRepository, jwtService domain.JWTService, user *domain.User) (string, error) {
+	tokenID, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = tokenRepo.CreateToken(ctx, &domain.OneTimeToken{
+		Username:  user.Username,
+		Purpose:   domain.TokenPurposeMFALogin,
+		TokenHash: hashToken(tokenID),
+		CreatedAt: now,
+		ExpiresAt: now.Add(mfaTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return jwtService.GenerateMFAToken(user, tokenID, now.Add(mfaTokenTTL))
+}
+
+// Recovery codes are shown as xxxx-xxxx-xxxx-xxxx; case and separators are ignored when entered
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+
+	return strings.Join(strings.Fields(code), "")
+}
+
+// Generate a set of single-use recovery codes (80 bits each) and their hashes for storage
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		buffer := make([]byte, 10)
+		if _, err := rand.Read(buffer); err != nil {
+			return nil, nil, errors.New("failed to generate recovery codes")
+		}
+
+		raw := strings.ToLower(encoding.EncodeToString(buffer))
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+		hashes = append(hashes, hashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
@@ -0,0 +1,539 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MFAUsecaseSuite struct {
+	suite.Suite
+	mockUserRepo        *mocks.MockUserRepository
+	mockPolicyRepo      *mocks.MockMFAPolicyRepository
+	mockRoleRepo        *mocks.MockRoleRepository
+	mockTokenRepo       *mocks.MockOneTimeTokenRepository
+	mockPasswordService *mocks.MockPasswordService
+	mockJwtService      *mocks.MockJWTService
+	mockTOTPService     *mocks.MockTOTPService
+	mockLoginThrottle   *mocks.MockLoginThrottle
+	mockAudit           *mocks.MockAuditLogger
+	mfaUsecase          domain.MFAUsecase
+}
+
+func (s *MFAUsecaseSuite) SetupTest() {
+	s.mockUserRepo = mocks.NewMockUserRepository(s.T())
+	s.mockPolicyRepo = mocks.NewMockMFAPolicyRepository(s.T())
+	s.mockRoleRepo = mocks.NewMockRoleRepository(s.T())
+	s.mockTokenRepo = mocks.NewMockOneTimeTokenRepository(s.T())
+	s.mockPasswordService = mocks.NewMockPasswordService(s.T())
+	s.mockJwtService = mocks.NewMockJWTService(s.T())
+	s.mockTOTPService = mocks.NewMockTOTPService(s.T())
+	s.mockLoginThrottle = mocks.NewMockLoginThrottle(s.T())
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.mockAudit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	s.mfaUsecase = usecases.NewMFAUsecase(s.mockUserRepo, s.mockPolicyRepo, s.mockRoleRepo, s.mockTokenRepo, s.mockPasswordService, s.mockJwtService, s.mockTOTPService, s.mockLoginThrottle, s.mockAudit)
+}
+
+func TestMFAUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(MFAUsecaseSuite))
+}
+
+// Enables 2FA for a user through the usecase and returns the recovery codes handed out
+func (s *MFAUsecaseSuite) enroll(user *domain.User) []string {
+	ctx := context.Background()
+	user.TOTPSecret = "SECRET"
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, user.Username).Return(user, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, user.Username, "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "123456").Return(int64(1000), true).Once()
+	s.mockUserRepo.EXPECT().UseTOTPStep(ctx, user.ID, int64(1000)).Return(nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, user.Username, "").Once()
+	s.mockUserRepo.EXPECT().UpdateUser(ctx, user).Return(nil).Once()
+
+	codes, err := s.mfaUsecase.ConfirmEnrollment(ctx, user.Username, "123456")
+	s.Require().NoError(err)
+
+	return codes
+}
+
+// ---- Test Enrolment ----
+
+func (s *MFAUsecaseSuite) TestBeginEnrollment_StoresSecret() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser"}
+	enrollment := &domain.TOTPEnrollment{Secret: "SECRET", OTPAuthURI: "otpauth://totp/Task%20Manager:testuser?secret=SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockTOTPService.EXPECT().GenerateSecret("testuser").Return(enrollment, nil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.TOTPSecret == "SECRET" && !user.TOTPEnabled
+		})).
+		Return(nil).
+		Once()
+
+	result, err := s.mfaUsecase.BeginEnrollment(ctx, "testuser")
+
+	s.NoError(err)
+	s.Equal(enrollment, result)
+}
+
+func (s *MFAUsecaseSuite) TestBeginEnrollment_AlreadyEnabled() {
+	ctx := context.Background()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser", TOTPEnabled: true}, nil).Once()
+
+	_, err := s.mfaUsecase.BeginEnrollment(ctx, "testuser")
+
+	s.ErrorIs(err, domain.ErrMFAAlreadyEnabled)
+	s.mockTOTPService.AssertNotCalled(s.T(), "GenerateSecret", mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestConfirmEnrollment_Success() {
+	user := &domain.User{Username: "testuser"}
+
+	codes := s.enroll(user)
+
+	s.True(user.TOTPEnabled)
+	s.Len(codes, 10)
+	s.Len(user.RecoveryCodeHashes, 10)
+	for i, code := range codes {
+		s.Regexp(`^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+		s.NotContains(user.RecoveryCodeHashes[i], strings.ReplaceAll(code, "-", ""), "Recovery codes must only be stored hashed")
+	}
+}
+
+func (s *MFAUsecaseSuite) TestConfirmEnrollment_InvalidCode() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "000000").Return(int64(0), false).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(1, time.Time{}).Once()
+
+	_, err := s.mfaUsecase.ConfirmEnrollment(ctx, "testuser", "000000")
+
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+	s.False(foundUser.TOTPEnabled)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+// Guessing enrolment codes counts towards the same lockout as logins
+func (s *MFAUsecaseSuite) TestConfirmEnrollment_WrongCode_LocksOut() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPSecret: "SECRET"}
+	lockedUntil := time.Now().Add(15 * time.Minute)
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "000000").Return(int64(0), false).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(5, lockedUntil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return !user.TOTPEnabled && user.LockedUntil != nil && user.LockedUntil.Equal(lockedUntil)
+		})).
+		Return(nil).
+		Once()
+
+	_, err := s.mfaUsecase.ConfirmEnrollment(ctx, "testuser", "000000")
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+
+	// The next attempt is refused without checking the code, even a right one
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+
+	_, err = s.mfaUsecase.ConfirmEnrollment(ctx, "testuser", "123456")
+
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	s.False(foundUser.TOTPEnabled)
+}
+
+func (s *MFAUsecaseSuite) TestConfirmEnrollment_Throttled() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Minute).Once()
+
+	_, err := s.mfaUsecase.ConfirmEnrollment(ctx, "testuser", "123456")
+
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	s.mockTOTPService.AssertNotCalled(s.T(), "ValidateCode", mock.Anything, mock.Anything)
+	s.False(foundUser.TOTPEnabled)
+}
+
+func (s *MFAUsecaseSuite) TestConfirmEnrollment_NotStarted() {
+	ctx := context.Background()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser"}, nil).Once()
+
+	_, err := s.mfaUsecase.ConfirmEnrollment(ctx, "testuser", "123456")
+
+	s.ErrorIs(err, domain.ErrMFANotEnrolling)
+}
+
+// ---- Test Disable ----
+
+func (s *MFAUsecaseSuite) TestDisable_Success() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", Role: domain.RoleUser, PasswordHash: "hash", TOTPEnabled: true, TOTPSecret: "SECRET", RecoveryCodeHashes: []string{"h1"}}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "password123").Return(nil).Once()
+	s.mockPolicyRepo.EXPECT().GetMFAPolicy(ctx).Return(&domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin}}, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "123456").Return(int64(1000), true).Once()
+	s.mockUserRepo.EXPECT().UseTOTPStep(ctx, foundUser.ID, int64(1000)).Return(nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "").Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return !user.TOTPEnabled && user.TOTPSecret == "" && len(user.RecoveryCodeHashes) == 0
+		})).
+		Return(nil).
+		Once()
+
+	s.NoError(s.mfaUsecase.Disable(ctx, "testuser", "password123", "123456"))
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionMFADisable && event.Outcome == domain.AuditOutcomeSuccess && event.TargetID == "testuser"
+	}))
+}
+
+func (s *MFAUsecaseSuite) TestDisable_WrongPassword() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "wrongpassword").Return(bcrypt.ErrMismatchedHashAndPassword).Once()
+
+	err := s.mfaUsecase.Disable(ctx, "testuser", "wrongpassword", "123456")
+
+	s.ErrorIs(err, domain.ErrIncorrectPassword)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionMFADisable && event.Outcome == domain.AuditOutcomeFailure
+	}))
+}
+
+func (s *MFAUsecaseSuite) TestDisable_RequiredByPolicy() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "admin", Role: domain.RoleAdmin, PasswordHash: "hash", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "admin").Return(foundUser, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "password123").Return(nil).Once()
+	s.mockPolicyRepo.EXPECT().GetMFAPolicy(ctx).Return(&domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin}}, nil).Once()
+
+	err := s.mfaUsecase.Disable(ctx, "admin", "password123", "123456")
+
+	s.ErrorIs(err, domain.ErrMFARequired)
+	s.True(foundUser.TOTPEnabled)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestDisable_Throttled() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", Role: domain.RoleUser, PasswordHash: "hash", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "password123").Return(nil).Once()
+	s.mockPolicyRepo.EXPECT().GetMFAPolicy(ctx).Return(&domain.MFAPolicy{}, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Minute).Once()
+
+	err := s.mfaUsecase.Disable(ctx, "testuser", "password123", "123456")
+
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	s.mockTOTPService.AssertNotCalled(s.T(), "ValidateCode", mock.Anything, mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+// ---- Test RegenerateRecoveryCodes ----
+
+func (s *MFAUsecaseSuite) TestRegenerateRecoveryCodes_Success() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET", RecoveryCodeHashes: []string{"h1"}}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "123456").Return(int64(1000), true).Once()
+	s.mockUserRepo.EXPECT().UseTOTPStep(ctx, foundUser.ID, int64(1000)).Return(nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "").Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool { return len(user.RecoveryCodeHashes) == 10 })).
+		Return(nil).
+		Once()
+
+	codes, err := s.mfaUsecase.RegenerateRecoveryCodes(ctx, "testuser", "123456")
+
+	s.NoError(err)
+	s.Len(codes, 10)
+}
+
+func (s *MFAUsecaseSuite) TestRegenerateRecoveryCodes_WrongCode_RecordsFailure() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "000000").Return(int64(0), false).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(1, time.Time{}).Once()
+
+	_, err := s.mfaUsecase.RegenerateRecoveryCodes(ctx, "testuser", "000000")
+
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestRegenerateRecoveryCodes_Throttled() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Minute).Once()
+
+	_, err := s.mfaUsecase.RegenerateRecoveryCodes(ctx, "testuser", "123456")
+
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	s.mockTOTPService.AssertNotCalled(s.T(), "ValidateCode", mock.Anything, mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestRegenerateRecoveryCodes_RecoveryCodeNotAccepted() {
+	user := &domain.User{Username: "testuser", TOTPSecret: "SECRET"}
+	codes := s.enroll(user)
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(user, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", codes[0]).Return(int64(0), false).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(1, time.Time{}).Once()
+
+	_, err := s.mfaUsecase.RegenerateRecoveryCodes(ctx, "testuser", codes[0])
+
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UseRecoveryCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ---- Test CompleteLogin ----
+
+// Stored hash of the ID of the token from the password step in these tests
+var mfaTokenIDHash = sha256Hex("mfa-token-id")
+
+func mfaClaims(user *domain.User, tokenVersion int) *domain.CustomClaims {
+	claims := &domain.CustomClaims{Username: user.Username, TokenVersion: tokenVersion}
+	claims.Subject = user.ID.String()
+	claims.ID = "mfa-token-id"
+	return claims
+}
+
+func (s *MFAUsecaseSuite) expectUnusedMFAToken(ctx context.Context) {
+	s.mockTokenRepo.EXPECT().FindToken(ctx, domain.TokenPurposeMFALogin, mfaTokenIDHash).Return(&domain.OneTimeToken{}, nil).Once()
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_TOTPCode() {
+	ctx := domain.WithRequestMeta(context.Background(), domain.RequestMeta{ClientIP: "203.0.113.7"})
+	foundUser := &domain.User{Username: "testuser", TokenVersion: 2, TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 2), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.expectUnusedMFAToken(ctx)
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "203.0.113.7").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "123456").Return(int64(1000), true).Once()
+	s.mockUserRepo.EXPECT().UseTOTPStep(ctx, foundUser.ID, int64(1000)).Return(nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "203.0.113.7").Once()
+	s.mockTokenRepo.EXPECT().ConsumeToken(ctx, domain.TokenPurposeMFALogin, mfaTokenIDHash).Return(&domain.OneTimeToken{}, nil).Once()
+	s.mockJwtService.EXPECT().GenerateToken(foundUser).Return("access.token", nil).Once()
+
+	token, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "123456")
+
+	s.NoError(err)
+	s.Equal("access.token", token)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionLogin && event.Outcome == domain.AuditOutcomeSuccess && event.Details["method"] == "mfa"
+	}))
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_RecoveryCodeWorksOnce() {
+	user := &domain.User{Username: "testuser", TOTPSecret: "SECRET"}
+	codes := s.enroll(user)
+	ctx := context.Background()
+	enteredCode := strings.ToUpper(codes[3]) // Case and dashes do not matter
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(user, 0), nil).Twice()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil).Twice()
+	s.mockTokenRepo.EXPECT().FindToken(ctx, domain.TokenPurposeMFALogin, mfaTokenIDHash).Return(&domain.OneTimeToken{}, nil).Twice()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Twice()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", enteredCode).Return(int64(0), false).Twice()
+	s.mockUserRepo.EXPECT().UseRecoveryCode(ctx, user.ID, user.RecoveryCodeHashes[3]).Return(nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "").Once()
+	s.mockTokenRepo.EXPECT().ConsumeToken(ctx, domain.TokenPurposeMFALogin, mfaTokenIDHash).Return(&domain.OneTimeToken{}, nil).Once()
+	s.mockJwtService.EXPECT().GenerateToken(user).Return("access.token", nil).Once()
+
+	token, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", enteredCode)
+	s.NoError(err)
+	s.Equal("access.token", token)
+	s.Len(user.RecoveryCodeHashes, 9)
+
+	// The same recovery code is rejected the second time
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(1, time.Time{}).Once()
+
+	_, err = s.mfaUsecase.CompleteLogin(ctx, "mfa.token", enteredCode)
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_RecoveryCodeSpentConcurrently() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET", RecoveryCodeHashes: []string{sha256Hex("abcdabcdabcdabcd")}}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 0), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.expectUnusedMFAToken(ctx)
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "abcd-abcd-abcd-abcd").Return(int64(0), false).Once()
+	// Another request spent the code after this one read the user
+	s.mockUserRepo.EXPECT().UseRecoveryCode(ctx, foundUser.ID, sha256Hex("abcdabcdabcdabcd")).Return(domain.ErrInvalidMFACode).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(1, time.Time{}).Once()
+
+	_, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "abcd-abcd-abcd-abcd")
+
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_ReplayedTOTPCode() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 0), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.expectUnusedMFAToken(ctx)
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "123456").Return(int64(1000), true).Once()
+	s.mockUserRepo.EXPECT().UseTOTPStep(ctx, foundUser.ID, int64(1000)).Return(domain.ErrInvalidMFACode).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(1, time.Time{}).Once()
+
+	_, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "123456")
+
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_TokenAlreadySwapped() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 0), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.mockTokenRepo.EXPECT().FindToken(ctx, domain.TokenPurposeMFALogin, mfaTokenIDHash).Return(nil, domain.ErrInvalidToken).Once()
+
+	_, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "123456")
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockTOTPService.AssertNotCalled(s.T(), "ValidateCode", mock.Anything, mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_TokenSwappedConcurrently() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 0), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.expectUnusedMFAToken(ctx)
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "123456").Return(int64(1000), true).Once()
+	s.mockUserRepo.EXPECT().UseTOTPStep(ctx, foundUser.ID, int64(1000)).Return(nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "").Once()
+	s.mockTokenRepo.EXPECT().ConsumeToken(ctx, domain.TokenPurposeMFALogin, mfaTokenIDHash).Return(nil, domain.ErrInvalidToken).Once()
+
+	_, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "123456")
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_WrongCode_RecordsFailure() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 0), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.expectUnusedMFAToken(ctx)
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockTOTPService.EXPECT().ValidateCode("SECRET", "000000").Return(int64(0), false).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(2, time.Time{}).Once()
+
+	token, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "000000")
+
+	s.ErrorIs(err, domain.ErrInvalidMFACode)
+	s.Empty(token)
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionLogin && event.Outcome == domain.AuditOutcomeFailure && event.Actor == "testuser"
+	}))
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_Throttled() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("mfa.token").Return(mfaClaims(foundUser, 0), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, foundUser.ID).Return(foundUser, nil).Once()
+	s.expectUnusedMFAToken(ctx)
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Minute).Once()
+
+	_, err := s.mfaUsecase.CompleteLogin(ctx, "mfa.token", "123456")
+
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	s.mockTOTPService.AssertNotCalled(s.T(), "ValidateCode", mock.Anything, mock.Anything)
+}
+
+func (s *MFAUsecaseSuite) TestCompleteLogin_InvalidOrStaleToken() {
+	ctx := context.Background()
+
+	s.mockJwtService.EXPECT().ValidateMFAToken("bad.token").Return(nil, errors.New("invalid token")).Once()
+	_, err := s.mfaUsecase.CompleteLogin(ctx, "bad.token", "123456")
+	s.ErrorIs(err, domain.ErrInvalidToken)
+
+	// The password was changed after the first step
+	user := &domain.User{ID: domain.NewID(), Username: "testuser", TokenVersion: 2, TOTPEnabled: true}
+	s.mockJwtService.EXPECT().ValidateMFAToken("old.token").Return(mfaClaims(user, 1), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil).Once()
+	_, err = s.mfaUsecase.CompleteLogin(ctx, "old.token", "123456")
+	s.ErrorIs(err, domain.ErrInvalidToken)
+
+	// The account was deleted, even if its username was registered again since
+	s.mockJwtService.EXPECT().ValidateMFAToken("deleted.token").Return(mfaClaims(&domain.User{ID: domain.NewID(), Username: "testuser"}, 2), nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, mock.Anything).Return(nil, domain.ErrUserNotFound).Once()
+	_, err = s.mfaUsecase.CompleteLogin(ctx, "deleted.token", "123456")
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockUserRepo.AssertNotCalled(s.T(), "FindUserByUsername", mock.Anything, mock.Anything)
+}
+
+// ---- Test Policy ----
+
+func (s *MFAUsecaseSuite) TestSetPolicy_NilRolesStoredAsEmpty() {
+	ctx := context.Background()
+	s.mockPolicyRepo.EXPECT().
+		SaveMFAPolicy(ctx, mock.MatchedBy(func(policy *domain.MFAPolicy) bool {
+			return policy.RequiredRoles != nil && len(policy.RequiredRoles) == 0
+		})).
+		Return(nil).
+		Once()
+
+	s.NoError(s.mfaUsecase.SetPolicy(ctx, &domain.MFAPolicy{}))
+}
+
+func (s *MFAUsecaseSuite) TestSetPolicy_UnknownRole() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().GetRole(ctx, domain.RoleAdmin).Return(&domain.Role{Name: domain.RoleAdmin}, nil).Once()
+	s.mockRoleRepo.EXPECT().GetRole(ctx, "superuser").Return(nil, domain.ErrRoleNotFound).Once()
+
+	err := s.mfaUsecase.SetPolicy(ctx, &domain.MFAPolicy{RequiredRoles: []string{domain.RoleAdmin, "superuser"}})
+
+	s.ErrorIs(err, domain.ErrRoleNotFound)
+	s.mockPolicyRepo.AssertNotCalled(s.T(), "SaveMFAPolicy", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Time a user has to finish logging in at the provider
+const oidcLoginTTL = 10 * time.Minute
+
+// Attempts at a free username before giving up, when the provider's suggestion is taken
+const usernameAttempts = 5
+
+// Characters not allowed in usernames derived from the provider's claims
+var usernameDisallowed = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+type oidcUsecase struct {
+	provider   domain.OIDCProvider
+	userRepo   domain.UserRepository
+	tokenRepo  domain.OneTimeTokenRepository
+	jwtService domain.JWTService
+	audit      domain.AuditLogger
+}
+
+func NewOIDCUsecase(provider domain.OIDCProvider, userRepo domain.UserRepository, tokenRepo domain.OneTimeTokenRepository, jwtService domain.JWTService, audit domain.AuditLogger) domain.OIDCUsecase {
+	return &oidcUsecase{
+		provider:   provider,
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		jwtService: jwtService,
+		audit:      audit,
+	}
+}
+
+// Start a login at the provider. The state, nonce and PKCE verifier are kept until the callback.
+func (usecase *oidcUsecase) BeginLogin(ctx context.Context) (string, error) {
+	state, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	codeVerifier, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	pending := domain.OneTimeToken{
+		Purpose:      domain.TokenPurposeOIDCLogin,
+		TokenHash:    hashToken(state),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(oidcLoginTTL),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+	}
+
+	if err := usecase.tokenRepo.CreateToken(ctx, &pending); err != nil {
+		return "", err
+	}
+
+	return usecase.provider.AuthCodeURL(state, nonce, codeVerifier), nil
+}
+
+// Finish a login when the provider redirects back. Each state can only be used once.
+func (usecase *oidcUsecase) CompleteLogin(ctx context.Context, state, code string) (*domain.LoginResult, error) {
+	result, username, err := usecase.completeLogin(ctx, state, code)
+
+	// A login that needs a second factor is recorded when that step finishes
+	if err != nil || !result.MFARequired {
+		usecase.audit.Record(ctx, loginEvent(username, "oidc", err))
+	}
+
+	return result, err
+}
+
+// Also returns the username logged in as, once known
+func (usecase *oidcUsecase) completeLogin(ctx context.Context, state, code string) (*domain.LoginResult, string, error) {
+	pending, err := usecase.tokenRepo.ConsumeToken(ctx, domain.TokenPurposeOIDCLogin, hashToken(state))
+	if err != nil {
+		return nil, "", err
+	}
+
+	identity, err := usecase.provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := usecase.findOrCreateUser(ctx, identity)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Two-factor authentication set up on this service still applies
+	if user.TOTPEnabled {
+		mfaToken, err := issueMFAToken(ctx, usecase.tokenRepo, usecase.jwtService, user)
+		if err != nil {
+			return nil, user.Username, err
+		}
+		return &domain.LoginResult{MFARequired: true, MFAToken: mfaToken}, user.Username, nil
+	}
+
+	token, err := usecase.jwtService.GenerateToken(user)
+	if err != nil {
+		return nil, user.Username, err
+	}
+	return &domain.LoginResult{Token: token}, user.Username, nil
+}
+
+// Return the user linked to the provider account. On first login, link the user with the same
+// email address, or create a new one.
+func (usecase *oidcUsecase) findOrCreateUser(ctx context.Context, identity *domain.OIDCIdentity) (*domain.User, error) {
+	user, err := usecase.userRepo.FindUserByOIDCSubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	email := ""
+	if identity.EmailVerified {
+		email = normalizeEmail(identity.Email)
+	}
+
+	if email != "" {
+		user, err := usecase.userRepo.FindUserByEmail(ctx, email)
+		if err == nil {
+			// Only link when the address was verified here too, otherwise registering someone
+			// else's email would capture their single sign-on
+			if !user.EmailVerified {
+				return nil, domain.ErrEmailTaken
+			}
+
+			user.OIDCIssuer = identity.Issuer
+			user.OIDCSubject = identity.Subject
+			if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	username, err := usecase.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// No password is set; the user can choose one with the password reset flow
+	user = &domain.User{
+		Username:      username,
+		Email:         email,
+		EmailVerified: email != "",
+		Role:          domain.RoleUser,
+		OIDCIssuer:    identity.Issuer,
+		OIDCSubject:   identity.Subject,
+	}
+
+	if err := usecase.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Derive a username from the provider's preferred username or the email address,
+// adding a random suffix if it is already taken
+func (usecase *oidcUsecase) availableUsername(ctx context.Context, identity *domain.OIDCIdentity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	base = usernameDisallowed.ReplaceAllString(base, "")
+	if len(base) < 3 {
+		base = "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < usernameAttempts; attempt++ {
+		_, err := usecase.userRepo.FindUserByUsername(ctx, candidate)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return candidate, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		suffix, err := generateToken()
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "-" + strings.ToLower(suffix[:6])
+	}
+
+	return "", domain.ErrUserAlreadyExists
+}
@@ -0,0 +1,255 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testIssuer = "https://idp.example.com"
+
+type OIDCUsecaseSuite struct {
+	suite.Suite
+	mockProvider   *mocks.MockOIDCProvider
+	mockUserRepo   *mocks.MockUserRepository
+	mockTokenRepo  *mocks.MockOneTimeTokenRepository
+	mockJwtService *mocks.MockJWTService
+	mockAudit      *mocks.MockAuditLogger
+	oidcUsecase    domain.OIDCUsecase
+}
+
+func (s *OIDCUsecaseSuite) SetupTest() {
+	s.mockProvider = mocks.NewMockOIDCProvider(s.T())
+	s.mockUserRepo = mocks.NewMockUserRepository(s.T())
+	s.mockTokenRepo = mocks.NewMockOneTimeTokenRepository(s.T())
+	s.mockJwtService = mocks.NewMockJWTService(s.T())
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.mockAudit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	s.oidcUsecase = usecases.NewOIDCUsecase(s.mockProvider, s.mockUserRepo, s.mockTokenRepo, s.mockJwtService, s.mockAudit)
+}
+
+func TestOIDCUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(OIDCUsecaseSuite))
+}
+
+// Expects the state "state-1" to be redeemed for a login answered with identity
+func (s *OIDCUsecaseSuite) expectCallback(ctx context.Context, identity *domain.OIDCIdentity) {
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(ctx, domain.TokenPurposeOIDCLogin, sha256Hex("state-1")).
+		Return(&domain.OneTimeToken{Purpose: domain.TokenPurposeOIDCLogin, CodeVerifier: "verifier-1", Nonce: "nonce-1"}, nil).
+		Once()
+	s.mockProvider.EXPECT().Exchange(ctx, "code-1", "verifier-1", "nonce-1").Return(identity, nil).Once()
+}
+
+// ---- Test BeginLogin ----
+
+func (s *OIDCUsecaseSuite) TestBeginLogin_StoresStateNonceAndVerifier() {
+	ctx := context.Background()
+	var stored *domain.OneTimeToken
+
+	s.mockTokenRepo.EXPECT().
+		CreateToken(ctx, mock.AnythingOfType("*domain.OneTimeToken")).
+		Run(func(_ context.Context, token *domain.OneTimeToken) { stored = token }).
+		Return(nil).
+		Once()
+
+	s.mockProvider.EXPECT().
+		AuthCodeURL(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(state, nonce, codeVerifier string) string {
+			s.Equal(sha256Hex(state), stored.TokenHash, "Only the hash of the state is stored")
+			s.Equal(nonce, stored.Nonce)
+			s.Equal(codeVerifier, stored.CodeVerifier)
+			s.GreaterOrEqual(len(codeVerifier), 43, "RFC 7636 needs a verifier of at least 43 characters")
+			return "https://idp.example.com/authorize?state=" + state
+		}).
+		Once()
+
+	authURL, err := s.oidcUsecase.BeginLogin(ctx)
+
+	s.NoError(err)
+	s.Contains(authURL, "https://idp.example.com/authorize")
+	s.Equal(domain.TokenPurposeOIDCLogin, stored.Purpose)
+	s.True(stored.ExpiresAt.After(stored.CreatedAt))
+}
+
+// ---- Test CompleteLogin ----
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_LinkedUser() {
+	ctx := context.Background()
+	user := &domain.User{Username: "ssouser", Role: domain.RoleUser, OIDCIssuer: testIssuer, OIDCSubject: "subject-1"}
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1"})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(user, nil).Once()
+	s.mockJwtService.EXPECT().GenerateToken(user).Return("jwt.token", nil).Once()
+
+	result, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.NoError(err)
+	s.Equal(&domain.LoginResult{Token: "jwt.token"}, result)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_InvalidState() {
+	ctx := context.Background()
+	s.mockTokenRepo.EXPECT().ConsumeToken(ctx, domain.TokenPurposeOIDCLogin, sha256Hex("forged")).Return(nil, domain.ErrInvalidToken).Once()
+
+	_, err := s.oidcUsecase.CompleteLogin(ctx, "forged", "code-1")
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockProvider.AssertNotCalled(s.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_ExchangeFails() {
+	ctx := context.Background()
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(ctx, domain.TokenPurposeOIDCLogin, sha256Hex("state-1")).
+		Return(&domain.OneTimeToken{CodeVerifier: "verifier-1", Nonce: "nonce-1"}, nil).
+		Once()
+	s.mockProvider.EXPECT().Exchange(ctx, "code-1", "verifier-1", "nonce-1").Return(nil, domain.ErrSSOFailed).Once()
+
+	_, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.ErrorIs(err, domain.ErrSSOFailed)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_LinksUserWithSameVerifiedEmail() {
+	ctx := context.Background()
+	existing := &domain.User{Username: "localuser", Email: "sso@example.com", EmailVerified: true, Role: domain.RoleUser}
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1", Email: "SSO@example.com", EmailVerified: true})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByEmail(ctx, "sso@example.com").Return(existing, nil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Username == "localuser" && user.OIDCIssuer == testIssuer && user.OIDCSubject == "subject-1"
+		})).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().GenerateToken(existing).Return("jwt.token", nil).Once()
+
+	result, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.NoError(err)
+	s.Equal("jwt.token", result.Token)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_DoesNotLinkUnverifiedLocalEmail() {
+	ctx := context.Background()
+	squatter := &domain.User{Username: "squatter", Email: "sso@example.com", EmailVerified: false}
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1", Email: "sso@example.com", EmailVerified: true})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByEmail(ctx, "sso@example.com").Return(squatter, nil).Once()
+
+	_, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.ErrorIs(err, domain.ErrEmailTaken)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_CreatesUserOnFirstLogin() {
+	ctx := context.Background()
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1", Email: "new.user@example.com", EmailVerified: true, PreferredUsername: "new.user"})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByEmail(ctx, "new.user@example.com").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "new.user").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().
+		CreateUser(ctx, &domain.User{
+			Username:      "new.user",
+			Email:         "new.user@example.com",
+			EmailVerified: true,
+			Role:          domain.RoleUser,
+			OIDCIssuer:    testIssuer,
+			OIDCSubject:   "subject-1",
+		}).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().GenerateToken(mock.AnythingOfType("*domain.User")).Return("jwt.token", nil).Once()
+
+	result, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.NoError(err)
+	s.Equal("jwt.token", result.Token)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_UnverifiedProviderEmailIsNotUsed() {
+	ctx := context.Background()
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1", Email: "victim@example.com", EmailVerified: false})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "victim").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().
+		CreateUser(ctx, mock.MatchedBy(func(user *domain.User) bool { return user.Email == "" && !user.EmailVerified })).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().GenerateToken(mock.AnythingOfType("*domain.User")).Return("jwt.token", nil).Once()
+
+	_, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.NoError(err)
+	s.mockUserRepo.AssertNotCalled(s.T(), "FindUserByEmail", mock.Anything, mock.Anything)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_TakenUsernameGetsSuffix() {
+	ctx := context.Background()
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1", PreferredUsername: "taken"})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "taken").Return(&domain.User{Username: "taken"}, nil).Once()
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, mock.MatchedBy(func(username string) bool { return username != "taken" })).
+		Return(nil, domain.ErrUserNotFound).
+		Once()
+
+	var created *domain.User
+	s.mockUserRepo.EXPECT().
+		CreateUser(ctx, mock.AnythingOfType("*domain.User")).
+		Run(func(_ context.Context, user *domain.User) { created = user }).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().GenerateToken(mock.AnythingOfType("*domain.User")).Return("jwt.token", nil).Once()
+
+	_, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.NoError(err)
+	s.Regexp(`^taken-[a-z0-9_-]{6}$`, created.Username)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_MFAStillRequired() {
+	ctx := context.Background()
+	user := &domain.User{Username: "ssouser", TOTPEnabled: true}
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1"})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(user, nil).Once()
+	s.mockTokenRepo.EXPECT().
+		CreateToken(ctx, mock.MatchedBy(func(token *domain.OneTimeToken) bool {
+			return token.Purpose == domain.TokenPurposeMFALogin && token.Username == "ssouser"
+		})).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().GenerateMFAToken(user, mock.Anything, mock.Anything).Return("mfa.token", nil).Once()
+
+	result, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.NoError(err)
+	s.Equal(&domain.LoginResult{MFARequired: true, MFAToken: "mfa.token"}, result)
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+}
+
+func (s *OIDCUsecaseSuite) TestCompleteLogin_RepositoryError() {
+	ctx := context.Background()
+	repoError := errors.New("database error")
+
+	s.expectCallback(ctx, &domain.OIDCIdentity{Issuer: testIssuer, Subject: "subject-1"})
+	s.mockUserRepo.EXPECT().FindUserByOIDCSubject(ctx, testIssuer, "subject-1").Return(nil, repoError).Once()
+
+	_, err := s.oidcUsecase.CompleteLogin(ctx, "state-1", "code-1")
+
+	s.Equal(repoError, err)
+}
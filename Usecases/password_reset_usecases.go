@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// How long a password reset token stays valid
+const passwordResetTokenTTL = time.Hour
+
+// Time allowed for finding the user, storing the token and sending the email of a reset request
+const passwordResetRequestTimeout = 30 * time.Second
+
+type passwordResetUsecase struct {
+	userRepo        domain.UserRepository
+	tokenRepo       domain.OneTimeTokenRepository
+	accessTokenRepo domain.PersonalAccessTokenRepository
+	passwordService domain.PasswordService
+	validator       domain.PasswordValidator
+	mailer          domain.Mailer
+	audit           domain.AuditLogger
+	pending         sync.WaitGroup // Reset requests being handled in the background
+}
+
+func NewPasswordResetUsecase(userRepo domain.UserRepository, tokenRepo domain.OneTimeTokenRepository, accessTokenRepo domain.PersonalAccessTokenRepository, passwordService domain.PasswordService, validator domain.PasswordValidator, mailer domain.Mailer, audit domain.AuditLogger) domain.PasswordResetUsecase {
+	return &passwordResetUsecase{
+		userRepo:        userRepo,
+		tokenRepo:       tokenRepo,
+		accessTokenRepo: accessTokenRepo,
+		passwordService: passwordService,
+		validator:       validator,
+		mailer:          mailer,
+		audit:           audit,
+	}
+}
+
+// Email a reset token to the user. The work is done in the background, so neither the time taken
+// nor any failure tells the caller whether the username exists; failures are logged and audited instead.
+// Unknown usernames and accounts without an email address are silently ignored.
+func (usecase *passwordResetUsecase) RequestReset(ctx context.Context, username string) {
+	// The request may be answered, and its context cancelled, before the email is sent
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetRequestTimeout)
+	usecase.pending.Add(1)
+	go func() {
+		defer usecase.pending.Done()
+		defer cancel()
+		err := usecase.sendResetToken(ctx, username)
+		if err != nil {
+			domain.LoggerFromContext(ctx).Error("failed to handle password reset request", slog.Any("error", err))
+		}
+		usecase.audit.Record(ctx, auditEvent(domain.AuditActionPasswordResetRequest, domain.AuditTargetUser, username, err))
+	}()
+}
+
+// Waits for the reset requests handled in the background
+func (usecase *passwordResetUsecase) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		usecase.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (usecase *passwordResetUsecase) sendResetToken(ctx context.Context, username string) error {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if errors.Is(err, domain.ErrUserNotFound) || (err == nil && user.Email == "") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	// Only the newest token should work
+	if err := usecase.tokenRepo.DeleteTokensForUser(ctx, user.Username, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	resetToken := domain.OneTimeToken{
+		Username:  user.Username,
+		Purpose:   domain.TokenPurposePasswordReset,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(passwordResetTokenTTL),
+	}
+
+	if err := usecase.tokenRepo.CreateToken(ctx, &resetToken); err != nil {
+		return err
+	}
+
+	message := domain.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your Task Manager password",
+		Body: fmt.Sprintf("Hello %s,\n\nUse the token below to reset your password. It expires in %s and can only be used once.\n\n%s\n\n"+
+			"Send it with your new password to POST /users/password/reset.\n\nIf you did not ask for a reset, you can ignore this email.",
+			user.Username, passwordResetTokenTTL, token),
+	}
+
+	if err := usecase.mailer.Send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	return nil
+}
+
+// Set a new password using a reset token. The token is consumed, and every existing session and
+// personal access token is revoked, as whoever reset the password may be locking out an intruder.
+// A password rejected by the policy leaves the token usable, so the user can try another.
+func (usecase *passwordResetUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	username, err := usecase.resetPassword(ctx, token, newPassword)
+
+	// Whoever holds the token acts as its user, as nobody is authenticated
+	event := auditEvent(domain.AuditActionPasswordReset, domain.AuditTargetUser, username, err)
+	event.Actor = username
+	usecase.audit.Record(ctx, event)
+
+	return err
+}
+
+// Also returns the username the token was for, once known
+func (usecase *passwordResetUsecase) resetPassword(ctx context.Context, token, newPassword string) (string, error) {
+	resetToken, err := usecase.tokenRepo.FindToken(ctx, domain.TokenPurposePasswordReset, hashToken(token))
+	if err != nil {
+		return "", err
+	}
+
+	user, err := usecase.userRepo.FindUserByUsername(ctx, resetToken.Username)
+	if err != nil {
+		return resetToken.Username, domain.ErrInvalidToken
+	}
+
+	if err := usecase.validator.Validate(ctx, newPassword, user.Username); err != nil {
+		return user.Username, err
+	}
+
+	if _, err := usecase.tokenRepo.ConsumeToken(ctx, domain.TokenPurposePasswordReset, hashToken(token)); err != nil {
+		return user.Username, err
+	}
+
+	hashedPassword, err := usecase.passwordService.HashPassword(newPassword)
+	if err != nil {
+		return user.Username, err
+	}
+
+	user.PasswordHash = hashedPassword
+	user.TokenVersion++
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return user.Username, err
+	}
+
+	return user.Username, usecase.accessTokenRepo.DeleteTokensForUser(ctx, user.Username)
+}
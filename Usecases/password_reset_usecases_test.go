@@ -0,0 +1,305 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordResetUsecaseSuite struct {
+	suite.Suite
+	mockUserRepo        *mocks.MockUserRepository
+	mockTokenRepo       *mocks.MockOneTimeTokenRepository
+	mockAccessTokenRepo *mocks.MockPersonalAccessTokenRepository
+	mockPasswordService *mocks.MockPasswordService
+	mockValidator       *mocks.MockPasswordValidator
+	mockMailer          *mocks.MockMailer
+	mockAudit           *mocks.MockAuditLogger
+	auditEvents         chan domain.AuditEvent
+	resetUsecase        domain.PasswordResetUsecase
+}
+
+func (s *PasswordResetUsecaseSuite) SetupTest() {
+	s.mockUserRepo = mocks.NewMockUserRepository(s.T())
+	s.mockTokenRepo = mocks.NewMockOneTimeTokenRepository(s.T())
+	s.mockAccessTokenRepo = mocks.NewMockPersonalAccessTokenRepository(s.T())
+	s.mockPasswordService = mocks.NewMockPasswordService(s.T())
+	s.mockValidator = mocks.NewMockPasswordValidator(s.T())
+	s.mockMailer = mocks.NewMockMailer(s.T())
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.auditEvents = make(chan domain.AuditEvent, 10)
+	s.mockAudit.EXPECT().
+		Record(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, event domain.AuditEvent) { s.auditEvents <- event }).
+		Maybe()
+	s.resetUsecase = usecases.NewPasswordResetUsecase(s.mockUserRepo, s.mockTokenRepo, s.mockAccessTokenRepo, s.mockPasswordService, s.mockValidator, s.mockMailer, s.mockAudit)
+}
+
+func TestPasswordResetUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetUsecaseSuite))
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns the next audit event recorded, which reset requests record in the background
+func (s *PasswordResetUsecaseSuite) nextAuditEvent() domain.AuditEvent {
+	select {
+	case event := <-s.auditEvents:
+		return event
+	case <-time.After(5 * time.Second):
+		s.FailNow("No audit event was recorded")
+		return domain.AuditEvent{}
+	}
+}
+
+// ---- Test RequestReset ----
+
+// Waits for the background work of a reset request to reach the call that closes done
+func (s *PasswordResetUsecaseSuite) waitFor(done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		s.FailNow("The reset request was not handled in the background")
+	}
+}
+
+func (s *PasswordResetUsecaseSuite) TestRequestReset_Success_StoresHashAndMailsToken() {
+	ctx, cancel := context.WithCancel(context.Background())
+	user := &domain.User{Username: "testuser", Email: "testuser@example.com"}
+	var storedToken *domain.OneTimeToken
+	var sentMessage domain.MailMessage
+	sent := make(chan struct{})
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(mock.Anything, "testuser").Return(user, nil).Once()
+	s.mockTokenRepo.EXPECT().DeleteTokensForUser(mock.Anything, "testuser", domain.TokenPurposePasswordReset).Return(nil).Once()
+	s.mockTokenRepo.EXPECT().
+		CreateToken(mock.Anything, mock.AnythingOfType("*domain.OneTimeToken")).
+		Run(func(_ context.Context, token *domain.OneTimeToken) { storedToken = token }).
+		Return(nil).
+		Once()
+	s.mockMailer.EXPECT().
+		Send(mock.Anything, mock.AnythingOfType("domain.MailMessage")).
+		Run(func(ctx context.Context, message domain.MailMessage) {
+			s.NoError(ctx.Err(), "The work outlives the request")
+			sentMessage = message
+			close(sent)
+		}).
+		Return(nil).
+		Once()
+
+	s.resetUsecase.RequestReset(ctx, "testuser")
+	cancel() // The response has been sent
+
+	s.waitFor(sent)
+	s.Require().NotNil(storedToken)
+	s.Equal("testuser@example.com", sentMessage.To)
+	s.Equal(domain.TokenPurposePasswordReset, storedToken.Purpose)
+	s.WithinDuration(time.Now().Add(time.Hour), storedToken.ExpiresAt, 5*time.Second)
+
+	// The mailed token must not be stored in plain text, only its hash
+	rawToken := regexp.MustCompile(`[A-Za-z0-9_-]{43}`).FindString(sentMessage.Body)
+	s.Require().NotEmpty(rawToken, "Mail body should contain the reset token")
+	s.NotContains(storedToken.TokenHash, rawToken)
+	s.Equal(sha256Hex(rawToken), storedToken.TokenHash)
+
+	event := s.nextAuditEvent()
+	s.Equal(domain.AuditActionPasswordResetRequest, event.Action)
+	s.Equal(domain.AuditOutcomeSuccess, event.Outcome)
+	s.Equal("testuser", event.TargetID)
+}
+
+func (s *PasswordResetUsecaseSuite) TestRequestReset_ReturnsBeforeLookingUpTheUser() {
+	looked := make(chan struct{})
+	release := make(chan struct{})
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(mock.Anything, "ghost").
+		Run(func(context.Context, string) {
+			<-release
+			close(looked)
+		}).
+		Return(nil, domain.ErrUserNotFound).
+		Once()
+
+	// Returns while the lookup is still blocked, so existing and unknown usernames take as long
+	s.resetUsecase.RequestReset(context.Background(), "ghost")
+	close(release)
+
+	s.waitFor(looked)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "CreateToken", mock.Anything, mock.Anything)
+	s.mockMailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+
+	// Requests for unknown usernames are recorded too, so probing for accounts shows in the log
+	event := s.nextAuditEvent()
+	s.Equal(domain.AuditActionPasswordResetRequest, event.Action)
+	s.Equal("ghost", event.TargetID)
+}
+
+func (s *PasswordResetUsecaseSuite) TestRequestReset_MailFailure_Logged() {
+	user := &domain.User{Username: "testuser", Email: "testuser@example.com"}
+	logs := &lockedBuffer{}
+	ctx := domain.WithLogger(context.Background(), slog.New(slog.NewTextHandler(logs, nil)))
+	sent := make(chan struct{})
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(mock.Anything, "testuser").Return(user, nil).Once()
+	s.mockTokenRepo.EXPECT().DeleteTokensForUser(mock.Anything, "testuser", domain.TokenPurposePasswordReset).Return(nil).Once()
+	s.mockTokenRepo.EXPECT().CreateToken(mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockMailer.EXPECT().
+		Send(mock.Anything, mock.Anything).
+		Run(func(context.Context, domain.MailMessage) { close(sent) }).
+		Return(errors.New("smtp down")).
+		Once()
+
+	s.resetUsecase.RequestReset(ctx, "testuser")
+
+	s.waitFor(sent)
+	s.Eventually(func() bool { return strings.Contains(logs.String(), "smtp down") }, 5*time.Second, 10*time.Millisecond,
+		"Failures are logged, since the caller is never told")
+
+	event := s.nextAuditEvent()
+	s.Equal(domain.AuditActionPasswordResetRequest, event.Action)
+	s.Equal(domain.AuditOutcomeFailure, event.Outcome)
+	s.Contains(event.Details["reason"], "smtp down")
+}
+
+func (s *PasswordResetUsecaseSuite) TestClose_WaitsForBackgroundRequests() {
+	user := &domain.User{Username: "testuser", Email: "testuser@example.com"}
+	sending := make(chan struct{})
+	release := make(chan struct{})
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(mock.Anything, "testuser").Return(user, nil).Once()
+	s.mockTokenRepo.EXPECT().DeleteTokensForUser(mock.Anything, "testuser", domain.TokenPurposePasswordReset).Return(nil).Once()
+	s.mockTokenRepo.EXPECT().CreateToken(mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockMailer.EXPECT().
+		Send(mock.Anything, mock.Anything).
+		Run(func(context.Context, domain.MailMessage) {
+			close(sending)
+			<-release
+		}).
+		Return(nil).
+		Once()
+
+	s.resetUsecase.RequestReset(context.Background(), "testuser")
+	s.waitFor(sending)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.ErrorIs(s.resetUsecase.Close(ctx), context.DeadlineExceeded, "The email is still being sent")
+
+	close(release)
+	s.Require().NoError(s.resetUsecase.Close(context.Background()))
+	select {
+	case event := <-s.auditEvents:
+		s.Equal(domain.AuditActionPasswordResetRequest, event.Action)
+	default:
+		s.Fail("Close returned before the request was audited")
+	}
+}
+
+// Log output written from the background and read by the test
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// ---- Test ResetPassword ----
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_Success_RevokesSessionsAndAccessTokens() {
+	ctx := context.Background()
+	user := &domain.User{Username: "testuser", PasswordHash: "old_hash", TokenVersion: 1}
+
+	s.mockTokenRepo.EXPECT().
+		FindToken(ctx, domain.TokenPurposePasswordReset, sha256Hex("the-token")).
+		Return(&domain.OneTimeToken{Username: "testuser"}, nil).
+		Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(user, nil).Once()
+	s.mockValidator.EXPECT().Validate(ctx, "newpassword", "testuser").Return(nil).Once()
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(ctx, domain.TokenPurposePasswordReset, sha256Hex("the-token")).
+		Return(&domain.OneTimeToken{Username: "testuser"}, nil).
+		Once()
+	s.mockPasswordService.EXPECT().HashPassword("newpassword").Return("new_hash", nil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.PasswordHash == "new_hash" && user.TokenVersion == 2
+		})).
+		Return(nil).
+		Once()
+	s.mockAccessTokenRepo.EXPECT().DeleteTokensForUser(ctx, "testuser").Return(nil).Once()
+
+	err := s.resetUsecase.ResetPassword(ctx, "the-token", "newpassword")
+
+	s.NoError(err)
+	event := s.nextAuditEvent()
+	s.Equal(domain.AuditActionPasswordReset, event.Action)
+	s.Equal(domain.AuditOutcomeSuccess, event.Outcome)
+	s.Equal("testuser", event.Actor)
+	s.Equal("testuser", event.TargetID)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_InvalidToken() {
+	ctx := context.Background()
+
+	s.mockTokenRepo.EXPECT().
+		FindToken(ctx, domain.TokenPurposePasswordReset, sha256Hex("used-or-expired")).
+		Return(nil, domain.ErrInvalidToken).
+		Once()
+
+	err := s.resetUsecase.ResetPassword(ctx, "used-or-expired", "newpassword")
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockPasswordService.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
+	event := s.nextAuditEvent()
+	s.Equal(domain.AuditActionPasswordReset, event.Action)
+	s.Equal(domain.AuditOutcomeFailure, event.Outcome)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_WeakPassword_KeepsToken() {
+	ctx := context.Background()
+	user := &domain.User{Username: "testuser", PasswordHash: "old_hash", TokenVersion: 1}
+	policyErr := &domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+		{Rule: domain.PasswordRuleCommonPassword, Message: "is too common"},
+	}}
+
+	s.mockTokenRepo.EXPECT().
+		FindToken(ctx, domain.TokenPurposePasswordReset, sha256Hex("the-token")).
+		Return(&domain.OneTimeToken{Username: "testuser"}, nil).
+		Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(user, nil).Once()
+	s.mockValidator.EXPECT().Validate(ctx, "password", "testuser").Return(policyErr).Once()
+
+	err := s.resetUsecase.ResetPassword(ctx, "the-token", "password")
+
+	s.ErrorIs(err, domain.ErrWeakPassword)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "ConsumeToken", mock.Anything, mock.Anything, mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	domain "task_manager/Domain"
+	"time"
+)
+
+// Expiry of a personal access token created without expires_in_days
+const defaultPersonalAccessTokenTTL = 30 * 24 * time.Hour
+
+// Last-used timestamps are only written when older than this, so a busy script does not cause a write per request
+const lastUsedResolution = time.Minute
+
+// Characters of the token kept in clear, after PersonalAccessTokenPrefix
+const visibleTokenChars = 8
+
+type personalAccessTokenUsecase struct {
+	tokenRepo domain.PersonalAccessTokenRepository
+	userRepo  domain.UserRepository
+	roleRepo  domain.RoleRepository
+	audit     domain.AuditLogger
+}
+
+func NewPersonalAccessTokenUsecase(tokenRepo domain.PersonalAccessTokenRepository, userRepo domain.UserRepository, roleRepo domain.RoleRepository, audit domain.AuditLogger) domain.PersonalAccessTokenUsecase {
+	return &personalAccessTokenUsecase{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+		audit:     audit,
+	}
+}
+
+// Create a token for the authenticated user. It needs at least one scope, and its scopes must be
+// permissions the user's role has.
+func (usecase *personalAccessTokenUsecase) CreateToken(ctx context.Context, username string, request domain.CreatePersonalAccessTokenRequest) (*domain.PersonalAccessToken, string, error) {
+	if len(request.Scopes) == 0 {
+		return nil, "", domain.ErrNoScopes
+	}
+
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+
+	role, err := usecase.roleRepo.GetRole(ctx, user.Role)
+	if err != nil {
+		return nil, "", err
+	}
+
+	granted := domain.Principal{Username: user.Username, Permissions: role.Permissions}
+	for _, scope := range request.Scopes {
+		if !granted.Can(scope) {
+			return nil, "", domain.ErrScopeNotAllowed
+		}
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext := domain.PersonalAccessTokenPrefix + secret
+
+	ttl := defaultPersonalAccessTokenTTL
+	if request.ExpiresInDays > 0 {
+		ttl = time.Duration(request.ExpiresInDays) * 24 * time.Hour
+	}
+
+	now := time.Now()
+	token := domain.PersonalAccessToken{
+		Username:  user.Username,
+		UserID:    user.ID,
+		Name:      request.Name,
+		Prefix:    plaintext[:len(domain.PersonalAccessTokenPrefix)+visibleTokenChars],
+		TokenHash: hashToken(plaintext),
+		Scopes:    request.Scopes,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	if err := usecase.tokenRepo.CreateToken(ctx, &token); err != nil {
+		return nil, "", err
+	}
+
+	return &token, plaintext, nil
+}
+
+func (usecase *personalAccessTokenUsecase) ListTokens(ctx context.Context, username string) ([]domain.PersonalAccessToken, error) {
+	return usecase.tokenRepo.ListTokensForUser(ctx, username)
+}
+
+func (usecase *personalAccessTokenUsecase) RevokeToken(ctx context.Context, username, id string) error {
+	if err := usecase.tokenRepo.DeleteToken(ctx, username, id); err != nil {
+		return err
+	}
+
+	usecase.audit.Record(ctx, auditEvent(domain.AuditActionTokenRevoke, domain.AuditTargetPersonalAccessToken, id, nil))
+
+	return nil
+}
+
+// Look up the owner of a token by ID, so a token never passes to someone registering the username
+// of a deleted account. Unknown, expired and orphaned tokens are all ErrInvalidToken.
+func (usecase *personalAccessTokenUsecase) Authenticate(ctx context.Context, token string) (*domain.User, *domain.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	accessToken, err := usecase.tokenRepo.FindTokenByHash(ctx, hashToken(token))
+	if errors.Is(err, domain.ErrTokenNotFound) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !now.Before(accessToken.ExpiresAt) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	user, err := usecase.userRepo.FindUserByID(ctx, accessToken.UserID)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	// A failed timestamp update must not fail the request
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) >= lastUsedResolution {
+		if err := usecase.tokenRepo.UpdateLastUsed(ctx, accessToken.ID.String(), now); err != nil {
+			domain.LoggerFromContext(ctx).Error("failed to record use of token", slog.String("token_prefix", accessToken.Prefix), slog.Any("error", err))
+		} else {
+			accessToken.LastUsedAt = &now
+		}
+	}
+
+	return user, accessToken, nil
+}
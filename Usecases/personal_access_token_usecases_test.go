@@ -0,0 +1,198 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PersonalAccessTokenUsecaseSuite struct {
+	suite.Suite
+	mockTokenRepo *mocks.MockPersonalAccessTokenRepository
+	mockUserRepo  *mocks.MockUserRepository
+	mockRoleRepo  *mocks.MockRoleRepository
+	mockAudit     *mocks.MockAuditLogger
+	tokenUsecase  domain.PersonalAccessTokenUsecase
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) SetupTest() {
+	s.mockTokenRepo = mocks.NewMockPersonalAccessTokenRepository(s.T())
+	s.mockUserRepo = mocks.NewMockUserRepository(s.T())
+	s.mockRoleRepo = mocks.NewMockRoleRepository(s.T())
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.mockAudit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	s.tokenUsecase = usecases.NewPersonalAccessTokenUsecase(s.mockTokenRepo, s.mockUserRepo, s.mockRoleRepo, s.mockAudit)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) expectUserRole() {
+	s.mockRoleRepo.EXPECT().
+		GetRole(mock.Anything, domain.RoleUser).
+		Return(&domain.Role{Name: domain.RoleUser, Permissions: []string{domain.PermissionTasksRead, domain.PermissionTasksWrite}}, nil).
+		Once()
+}
+
+func TestPersonalAccessTokenUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(PersonalAccessTokenUsecaseSuite))
+}
+
+// ---- Test CreateToken ----
+
+func (s *PersonalAccessTokenUsecaseSuite) TestCreateToken_Success() {
+	ctx := context.Background()
+	var stored *domain.PersonalAccessToken
+	user := &domain.User{ID: domain.NewID(), Username: "testuser", Role: domain.RoleUser}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(user, nil).Once()
+	s.expectUserRole()
+	s.mockTokenRepo.EXPECT().
+		CreateToken(ctx, mock.AnythingOfType("*domain.PersonalAccessToken")).
+		Run(func(_ context.Context, token *domain.PersonalAccessToken) { stored = token }).
+		Return(nil).
+		Once()
+
+	request := domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{domain.PermissionTasksRead}, ExpiresInDays: 7}
+	token, plaintext, err := s.tokenUsecase.CreateToken(ctx, "testuser", request)
+
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(plaintext, domain.PersonalAccessTokenPrefix))
+	s.Equal(stored, token)
+	s.Equal("testuser", token.Username)
+	s.Equal(user.ID, token.UserID, "The owner should be stored by ID")
+	s.Equal(sha256Hex(plaintext), token.TokenHash, "Only the hash of the token should be stored")
+	s.NotContains(token.TokenHash, plaintext)
+	s.Equal(plaintext[:len(domain.PersonalAccessTokenPrefix)+8], token.Prefix)
+	s.WithinDuration(time.Now().Add(7*24*time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestCreateToken_DefaultExpiry() {
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser", Role: domain.RoleUser}, nil).Once()
+	s.expectUserRole()
+	s.mockTokenRepo.EXPECT().CreateToken(ctx, mock.Anything).Return(nil).Once()
+
+	token, _, err := s.tokenUsecase.CreateToken(ctx, "testuser", domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{domain.PermissionTasksWrite}})
+
+	s.Require().NoError(err)
+	s.WithinDuration(time.Now().Add(30*24*time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestCreateToken_ScopeOutsideRole() {
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser", Role: domain.RoleUser}, nil).Once()
+	s.expectUserRole()
+
+	_, _, err := s.tokenUsecase.CreateToken(ctx, "testuser", domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{domain.PermissionUsersAdmin}})
+
+	s.ErrorIs(err, domain.ErrScopeNotAllowed)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "CreateToken", mock.Anything, mock.Anything)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestCreateToken_NoScopes() {
+	_, _, err := s.tokenUsecase.CreateToken(context.Background(), "testuser", domain.CreatePersonalAccessTokenRequest{Name: "ci"})
+
+	s.ErrorIs(err, domain.ErrNoScopes)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "CreateToken", mock.Anything, mock.Anything)
+}
+
+// ---- Test Authenticate ----
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_Success_RecordsLastUsed() {
+	ctx := context.Background()
+	plaintext := domain.PersonalAccessTokenPrefix + "secret"
+	user := &domain.User{ID: domain.NewID(), Username: "testuser"}
+	stored := &domain.PersonalAccessToken{ID: domain.NewID(), Username: "testuser", UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+
+	s.mockTokenRepo.EXPECT().FindTokenByHash(ctx, sha256Hex(plaintext)).Return(stored, nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil).Once()
+	s.mockTokenRepo.EXPECT().UpdateLastUsed(ctx, stored.ID.String(), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	foundUser, token, err := s.tokenUsecase.Authenticate(ctx, plaintext)
+
+	s.NoError(err)
+	s.Equal(user, foundUser)
+	s.Require().NotNil(token.LastUsedAt)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_RecentlyUsed_SkipsWrite() {
+	ctx := context.Background()
+	plaintext := domain.PersonalAccessTokenPrefix + "secret"
+	lastUsed := time.Now().Add(-10 * time.Second)
+	stored := &domain.PersonalAccessToken{Username: "testuser", UserID: domain.NewID(), ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: &lastUsed}
+
+	s.mockTokenRepo.EXPECT().FindTokenByHash(ctx, sha256Hex(plaintext)).Return(stored, nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, stored.UserID).Return(&domain.User{ID: stored.UserID, Username: "testuser"}, nil).Once()
+
+	_, _, err := s.tokenUsecase.Authenticate(ctx, plaintext)
+
+	s.NoError(err)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_Expired() {
+	ctx := context.Background()
+	plaintext := domain.PersonalAccessTokenPrefix + "secret"
+	stored := &domain.PersonalAccessToken{Username: "testuser", ExpiresAt: time.Now().Add(-time.Second)}
+
+	s.mockTokenRepo.EXPECT().FindTokenByHash(ctx, sha256Hex(plaintext)).Return(stored, nil).Once()
+
+	_, _, err := s.tokenUsecase.Authenticate(ctx, plaintext)
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockUserRepo.AssertNotCalled(s.T(), "FindUserByID", mock.Anything, mock.Anything)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_OwnerDeleted() {
+	ctx := context.Background()
+	plaintext := domain.PersonalAccessTokenPrefix + "secret"
+	stored := &domain.PersonalAccessToken{Username: "testuser", UserID: domain.NewID(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	// Someone registered the username again after the owner deleted their account
+	s.mockTokenRepo.EXPECT().FindTokenByHash(ctx, sha256Hex(plaintext)).Return(stored, nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByID(ctx, stored.UserID).Return(nil, domain.ErrUserNotFound).Once()
+
+	_, _, err := s.tokenUsecase.Authenticate(ctx, plaintext)
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockUserRepo.AssertNotCalled(s.T(), "FindUserByUsername", mock.Anything, mock.Anything)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_UnknownToken() {
+	ctx := context.Background()
+	plaintext := domain.PersonalAccessTokenPrefix + "unknown"
+
+	s.mockTokenRepo.EXPECT().FindTokenByHash(ctx, sha256Hex(plaintext)).Return(nil, domain.ErrTokenNotFound).Once()
+
+	_, _, err := s.tokenUsecase.Authenticate(ctx, plaintext)
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+}
+
+// ---- Test RevokeToken ----
+
+func (s *PersonalAccessTokenUsecaseSuite) TestRevokeToken_Success_IsAudited() {
+	ctx := context.Background()
+	s.mockTokenRepo.EXPECT().DeleteToken(ctx, "testuser", "someid").Return(nil).Once()
+
+	s.NoError(s.tokenUsecase.RevokeToken(ctx, "testuser", "someid"))
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionTokenRevoke && event.TargetType == domain.AuditTargetPersonalAccessToken && event.TargetID == "someid"
+	}))
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestRevokeToken_NotFound() {
+	ctx := context.Background()
+	s.mockTokenRepo.EXPECT().DeleteToken(ctx, "testuser", "someid").Return(domain.ErrTokenNotFound).Once()
+
+	s.ErrorIs(s.tokenUsecase.RevokeToken(ctx, "testuser", "someid"), domain.ErrTokenNotFound)
+	s.mockAudit.AssertNotCalled(s.T(), "Record", mock.Anything, mock.Anything)
+}
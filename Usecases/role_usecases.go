@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	domain "task_manager/Domain"
+)
+
+type roleUsecase struct {
+	roleRepo domain.RoleRepository
+	userRepo domain.UserRepository
+	audit    domain.AuditLogger
+}
+
+func NewRoleUsecase(roleRepo domain.RoleRepository, userRepo domain.UserRepository, audit domain.AuditLogger) domain.RoleUsecase {
+	return &roleUsecase{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		audit:    audit,
+	}
+}
+
+func (usecase *roleUsecase) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	return usecase.roleRepo.ListRoles(ctx)
+}
+
+// Create a role or replace its permissions. Takes effect on the next request of every user with the role.
+func (usecase *roleUsecase) SaveRole(ctx context.Context, role *domain.Role) error {
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+
+	err := usecase.roleRepo.SaveRole(ctx, role)
+
+	event := auditEvent(domain.AuditActionRoleSave, domain.AuditTargetRole, role.Name, err)
+	if event.Details == nil {
+		event.Details = map[string]string{}
+	}
+	event.Details["permissions"] = strings.Join(role.Permissions, ",")
+	usecase.audit.Record(ctx, event)
+
+	return err
+}
+
+// Give a user a different role
+func (usecase *roleUsecase) AssignRole(ctx context.Context, username, roleName string) error {
+	if _, err := usecase.roleRepo.GetRole(ctx, roleName); err != nil {
+		return err
+	}
+
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	previousRole := user.Role
+	user.Role = roleName
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	event := auditEvent(domain.AuditActionRoleChange, domain.AuditTargetUser, user.Username, nil)
+	event.Details = map[string]string{"from": previousRole, "to": roleName}
+	usecase.audit.Record(ctx, event)
+
+	return nil
+}
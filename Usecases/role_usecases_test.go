@@ -0,0 +1,108 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleUsecaseSuite struct {
+	suite.Suite
+	mockRoleRepo *mocks.MockRoleRepository
+	mockUserRepo *mocks.MockUserRepository
+	mockAudit    *mocks.MockAuditLogger
+	roleUsecase  domain.RoleUsecase
+}
+
+func (s *RoleUsecaseSuite) SetupTest() {
+	s.mockRoleRepo = mocks.NewMockRoleRepository(s.T())
+	s.mockUserRepo = mocks.NewMockUserRepository(s.T())
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.mockAudit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	s.roleUsecase = usecases.NewRoleUsecase(s.mockRoleRepo, s.mockUserRepo, s.mockAudit)
+}
+
+func TestRoleUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(RoleUsecaseSuite))
+}
+
+// ---- Test SaveRole ----
+
+func (s *RoleUsecaseSuite) TestSaveRole_NilPermissionsStoredAsEmpty() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().
+		SaveRole(ctx, mock.MatchedBy(func(role *domain.Role) bool {
+			return role.Name == "viewer" && role.Permissions != nil && len(role.Permissions) == 0
+		})).
+		Return(nil).
+		Once()
+
+	s.NoError(s.roleUsecase.SaveRole(ctx, &domain.Role{Name: "viewer"}))
+}
+
+func (s *RoleUsecaseSuite) TestSaveRole_Audited() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().SaveRole(ctx, mock.Anything).Return(nil).Once()
+
+	s.NoError(s.roleUsecase.SaveRole(ctx, &domain.Role{Name: "viewer", Permissions: []string{domain.PermissionTasksRead, domain.PermissionTasksWrite}}))
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionRoleSave && event.Outcome == domain.AuditOutcomeSuccess &&
+			event.TargetType == domain.AuditTargetRole && event.TargetID == "viewer" &&
+			event.Details["permissions"] == domain.PermissionTasksRead+","+domain.PermissionTasksWrite
+	}))
+}
+
+func (s *RoleUsecaseSuite) TestSaveRole_FailureAudited() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().SaveRole(ctx, mock.Anything).Return(errors.New("database unavailable")).Once()
+
+	s.Error(s.roleUsecase.SaveRole(ctx, &domain.Role{Name: "viewer"}))
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionRoleSave && event.Outcome == domain.AuditOutcomeFailure &&
+			event.Details["reason"] == "database unavailable"
+	}))
+}
+
+// ---- Test AssignRole ----
+
+func (s *RoleUsecaseSuite) TestAssignRole_Success() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().GetRole(ctx, "viewer").Return(&domain.Role{Name: "viewer"}, nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser", Role: domain.RoleUser}, nil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool { return user.Role == "viewer" })).
+		Return(nil).
+		Once()
+
+	s.NoError(s.roleUsecase.AssignRole(ctx, "testuser", "viewer"))
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionRoleChange && event.TargetID == "testuser" &&
+			event.Details["from"] == domain.RoleUser && event.Details["to"] == "viewer"
+	}))
+}
+
+func (s *RoleUsecaseSuite) TestAssignRole_UnknownRole() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().GetRole(ctx, "superuser").Return(nil, domain.ErrRoleNotFound).Once()
+
+	err := s.roleUsecase.AssignRole(ctx, "testuser", "superuser")
+
+	s.ErrorIs(err, domain.ErrRoleNotFound)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func (s *RoleUsecaseSuite) TestAssignRole_UserNotFound() {
+	ctx := context.Background()
+	s.mockRoleRepo.EXPECT().GetRole(ctx, domain.RoleAdmin).Return(&domain.Role{Name: domain.RoleAdmin}, nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "ghost").Return(nil, domain.ErrUserNotFound).Once()
+
+	err := s.roleUsecase.AssignRole(ctx, "ghost", domain.RoleAdmin)
+
+	s.ErrorIs(err, domain.ErrUserNotFound)
+}
@@ -2,19 +2,23 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	domain "task_manager/Domain"
-
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type taskUsecase struct {
-	taskRepo domain.TaskRepository
+	taskRepo     domain.TaskRepository
+	transactions domain.UnitOfWork
+	audit        domain.AuditLogger
 }
 
 // Create a new instance of TaskUsecase
-func NewTaskUsecase(repo domain.TaskRepository) domain.TaskUsecase {
+func NewTaskUsecase(repo domain.TaskRepository, transactions domain.UnitOfWork, audit domain.AuditLogger) domain.TaskUsecase {
 	return &taskUsecase{
-		taskRepo: repo,
+		taskRepo:     repo,
+		transactions: transactions,
+		audit:        audit,
 	}
 }
 
@@ -28,17 +32,277 @@ func (repo *taskUsecase) GetTaskByID(ctx context.Context, id string) (domain.Tas
 	return repo.taskRepo.GetTaskByID(ctx, id)
 }
 
-// Update existing task.
-func (repo *taskUsecase) UpdateTask(ctx context.Context, id string, updatedTask domain.Task) error {
-	return repo.taskRepo.UpdateTask(ctx, id, updatedTask)
+// Update existing task. Only its creator, or someone allowed to change any task, may do so.
+func (repo *taskUsecase) UpdateTask(ctx context.Context, id string, updatedTask domain.Task, principal domain.Principal) error {
+	if err := repo.checkTaskOwner(ctx, id, principal, domain.AuditActionTaskUpdate); err != nil {
+		return err
+	}
+
+	if err := repo.taskRepo.UpdateTask(ctx, id, updatedTask); err != nil {
+		return err
+	}
+
+	repo.audit.Record(ctx, auditEvent(domain.AuditActionTaskUpdate, domain.AuditTargetTask, id, nil))
+
+	return nil
+}
+
+// Delete a task. Only its creator, or someone allowed to delete any task, may do so.
+func (repo *taskUsecase) DeleteTask(ctx context.Context, id string, principal domain.Principal) error {
+	if err := repo.checkTaskOwner(ctx, id, principal, domain.AuditActionTaskDelete); err != nil {
+		return err
+	}
+
+	if err := repo.taskRepo.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+
+	repo.audit.Record(ctx, auditEvent(domain.AuditActionTaskDelete, domain.AuditTargetTask, id, nil))
+
+	return nil
 }
 
-// Delete a task
-func (repo *taskUsecase) DeleteTask(ctx context.Context, id string) error {
-	return repo.taskRepo.DeleteTask(ctx, id)
+// Fails with ErrForbidden, recording the refused action, unless the principal created the task or
+// may change any task
+func (repo *taskUsecase) checkTaskOwner(ctx context.Context, id string, principal domain.Principal, action string) error {
+	if principal.Can(domain.PermissionTasksDeleteAny) {
+		return nil
+	}
+
+	task, err := repo.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if task.CreatedBy != principal.Username {
+		repo.audit.Record(ctx, auditEvent(action, domain.AuditTargetTask, id, domain.ErrForbidden))
+		return domain.ErrForbidden
+	}
+
+	return nil
 }
 
 // Create new task.
-func (repo *taskUsecase) NewTask(ctx context.Context, task domain.Task) (*mongo.InsertOneResult, error) {
-	return repo.taskRepo.NewTask(ctx, task)
+func (repo *taskUsecase) NewTask(ctx context.Context, task domain.Task) (domain.Task, error) {
+	created, err := repo.taskRepo.NewTask(ctx, task)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	repo.audit.Record(ctx, auditEvent(domain.AuditActionTaskCreate, domain.AuditTargetTask, created.ID.String(), nil))
+
+	return created, nil
+}
+
+// Audit action of each kind of bulk operation
+var bulkAuditActions = map[string]string{
+	domain.BulkOpCreate: domain.AuditActionTaskCreate,
+	domain.BulkOpUpdate: domain.AuditActionTaskUpdate,
+	domain.BulkOpDelete: domain.AuditActionTaskDelete,
+}
+
+// Rolls back the transaction of an atomic bulk request once an operation failed
+var errBulkRolledBack = errors.New("bulk request rolled back")
+
+// Apply many changes at once. Operations the principal may not make are reported as failed without
+// reaching the repository; an atomic request then changes nothing.
+func (repo *taskUsecase) BulkTasks(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal) ([]domain.BulkTaskResult, error) {
+	var results []domain.BulkTaskResult
+	var events []domain.AuditEvent
+	var err error
+
+	if request.Atomic {
+		// Tasks are checked in the same transaction that changes them, so a task cannot change hands
+		// in between
+		err = repo.transactions.Do(ctx, func(ctx context.Context) error {
+			var err error
+			results, events, err = repo.bulkTasks(ctx, request, principal)
+			if err == nil && bulkFailed(results) {
+				return errBulkRolledBack
+			}
+			return err
+		})
+		if errors.Is(err, errBulkRolledBack) {
+			err = nil
+		}
+	} else {
+		results, events, err = repo.bulkTasks(ctx, request, principal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Recorded outside the transaction, so that refusals are kept when it rolls back
+	for _, event := range events {
+		repo.audit.Record(ctx, event)
+	}
+
+	return results, nil
+}
+
+// Checks and writes the operations of a bulk request, returning their results and the audit events
+// to record
+func (repo *taskUsecase) bulkTasks(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal) ([]domain.BulkTaskResult, []domain.AuditEvent, error) {
+	operations, err := repo.bulkOperations(ctx, request, principal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creators, err := repo.taskCreators(ctx, operations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]domain.BulkTaskResult, len(operations))
+	var events []domain.AuditEvent
+	var allowed []domain.BulkTaskOperation
+	var allowedIndexes []int // Index in operations of each allowed operation
+	for i, operation := range operations {
+		results[i] = domain.BulkTaskResult{Index: i, Op: operation.Op, ID: operation.ID}
+
+		if err := checkBulkOperation(operation, creators, principal); err != nil {
+			results[i].Status = domain.BulkStatusFailed
+			results[i].Error = err.Error()
+			if errors.Is(err, domain.ErrForbidden) {
+				events = append(events, auditEvent(bulkAuditActions[operation.Op], domain.AuditTargetTask, operation.ID, err))
+			}
+			continue
+		}
+
+		// The repository assigns the ID, so clients cannot pick it
+		if operation.Op == domain.BulkOpCreate {
+			operation.Task.ID = domain.ID{}
+			operation.Task.CreatedBy = principal.Username
+		}
+		allowed = append(allowed, operation)
+		allowedIndexes = append(allowedIndexes, i)
+	}
+
+	if request.Atomic && len(allowed) < len(operations) {
+		for _, i := range allowedIndexes {
+			results[i].Status = domain.BulkStatusSkipped
+		}
+		return results, events, nil
+	}
+	if len(allowed) == 0 {
+		return results, events, nil
+	}
+
+	written, err := repo.taskRepo.BulkWriteTasks(ctx, allowed, request.Atomic)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for j, result := range written {
+		i := allowedIndexes[j]
+		result.Index = i
+		results[i] = result
+
+		if result.Status == domain.BulkStatusApplied {
+			events = append(events, auditEvent(bulkAuditActions[result.Op], domain.AuditTargetTask, result.ID, nil))
+		}
+	}
+
+	return results, events, nil
+}
+
+// The operations of a request, with a filter turned into an update of every task it matches
+func (repo *taskUsecase) bulkOperations(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal) ([]domain.BulkTaskOperation, error) {
+	if request.Filter == nil {
+		if len(request.Operations) == 0 {
+			return nil, domain.ErrInvalidBulkRequest
+		}
+		if len(request.Operations) > domain.MaxBulkTaskOperations {
+			return nil, domain.ErrTooManyBulkTasks
+		}
+		return request.Operations, nil
+	}
+
+	if len(request.Operations) > 0 || (request.Update == domain.Task{}) {
+		return nil, domain.ErrInvalidBulkRequest
+	}
+
+	// A filter only matches the principal's own tasks unless they may change any task
+	filter := *request.Filter
+	if !principal.Can(domain.PermissionTasksDeleteAny) {
+		filter.CreatedBy = principal.Username
+	}
+
+	// One more than allowed tells a filter matching too many tasks apart from one matching just enough
+	tasks, err := repo.taskRepo.FindTasks(ctx, filter, domain.MaxBulkTaskOperations+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > domain.MaxBulkTaskOperations {
+		return nil, domain.ErrTooManyBulkTasks
+	}
+
+	operations := make([]domain.BulkTaskOperation, len(tasks))
+	for i, task := range tasks {
+		operations[i] = domain.BulkTaskOperation{Op: domain.BulkOpUpdate, ID: task.ID.String(), Task: request.Update}
+	}
+
+	return operations, nil
+}
+
+// Who created each task the updates and deletes name, by ID. Tasks that do not exist are left out.
+func (repo *taskUsecase) taskCreators(ctx context.Context, operations []domain.BulkTaskOperation) (map[domain.ID]string, error) {
+	ids := []domain.ID{}
+	for _, operation := range operations {
+		if id, err := domain.ParseID(operation.ID); err == nil && operation.Op != domain.BulkOpCreate {
+			ids = append(ids, id)
+		}
+	}
+
+	creators := make(map[domain.ID]string, len(ids))
+	if len(ids) == 0 {
+		return creators, nil
+	}
+
+	tasks, err := repo.taskRepo.FindTasks(ctx, domain.TaskFilter{IDs: ids}, len(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		creators[task.ID] = task.CreatedBy
+	}
+
+	return creators, nil
+}
+
+// Checks an operation before it is written. Changing another user's task needs PermissionTasksDeleteAny.
+func checkBulkOperation(operation domain.BulkTaskOperation, creators map[domain.ID]string, principal domain.Principal) error {
+	switch operation.Op {
+	case domain.BulkOpCreate:
+		return nil
+	case domain.BulkOpUpdate, domain.BulkOpDelete:
+	default:
+		return fmt.Errorf("unknown operation %q, expected %q, %q or %q", operation.Op, domain.BulkOpCreate, domain.BulkOpUpdate, domain.BulkOpDelete)
+	}
+
+	id, err := domain.ParseID(operation.ID)
+	if err != nil {
+		return errors.New("invalid task ID format")
+	}
+
+	creator, ok := creators[id]
+	if !ok {
+		return errors.New("task not found")
+	}
+	if creator != principal.Username && !principal.Can(domain.PermissionTasksDeleteAny) {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// Reports whether an operation failed
+func bulkFailed(results []domain.BulkTaskResult) bool {
+	for _, result := range results {
+		if result.Status == domain.BulkStatusFailed {
+			return true
+		}
+	}
+
+	return false
 }
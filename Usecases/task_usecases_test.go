@@ -3,28 +3,45 @@ package usecases_test
 import (
 	"context"
 	"errors"
+	"strings"
 	domain "task_manager/Domain"
 	usecases "task_manager/Usecases"
 	"task_manager/mocks"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Define the suite struct
 type TaskUsecaseSuite struct {
 	suite.Suite
-	mockTaskRepo *mocks.MockTaskRepository
-	taskUsecase  domain.TaskUsecase
+	mockTaskRepo     *mocks.MockTaskRepository
+	mockTransactions *mocks.MockUnitOfWork
+	mockAudit        *mocks.MockAuditLogger
+	taskUsecase      domain.TaskUsecase
 }
 
 // Setup runs before each test in the suite
 func (s *TaskUsecaseSuite) SetupTest() {
 	s.mockTaskRepo = mocks.NewMockTaskRepository(s.T())
-	s.taskUsecase = usecases.NewTaskUsecase(s.mockTaskRepo)
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.mockTransactions = mocks.NewMockUnitOfWork(s.T())
+	s.mockAudit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	s.taskUsecase = usecases.NewTaskUsecase(s.mockTaskRepo, s.mockTransactions, s.mockAudit)
+}
+
+// Expects one transaction, running its function like a database would and returning the error
+// the function returned through fnErr
+func (s *TaskUsecaseSuite) expectTransaction(ctx context.Context, fnErr *error) {
+	s.mockTransactions.EXPECT().
+		Do(ctx, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			*fnErr = fn(ctx)
+			return *fnErr
+		}).
+		Once()
 }
 
 // Runs the entire suite
@@ -37,8 +54,8 @@ func TestTaskUsecaseSuite(t *testing.T) {
 func (s *TaskUsecaseSuite) TestGetAllTask_Success() {
 	ctx := context.Background()
 	expectedTasks := []domain.Task{
-		{ID: primitive.NewObjectID(), Title: "Task 1", Status: "Pending"},
-		{ID: primitive.NewObjectID(), Title: "Task 2", Status: "Done"},
+		{ID: domain.NewID(), Title: "Task 1", Status: "Pending"},
+		{ID: domain.NewID(), Title: "Task 2", Status: "Done"},
 	}
 
 	// Arrange
@@ -101,17 +118,17 @@ func (s *TaskUsecaseSuite) TestGetAllTask_NoTasks() {
 
 func (s *TaskUsecaseSuite) TestGetTaskByID_Success() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
 	expectedTasks := domain.Task{ID: taskID, Title: "Specific Task", Status: "In Progress"}
 
 	// Arrange
 	s.mockTaskRepo.EXPECT().
-		GetTaskByID(ctx, taskID.Hex()).
+		GetTaskByID(ctx, taskID.String()).
 		Return(expectedTasks, nil).
 		Once()
 
 	// Act
-	task, err := s.taskUsecase.GetTaskByID(ctx, taskID.Hex())
+	task, err := s.taskUsecase.GetTaskByID(ctx, taskID.String())
 
 	// Assert
 	s.NoError(err)
@@ -121,17 +138,17 @@ func (s *TaskUsecaseSuite) TestGetTaskByID_Success() {
 
 func (s *TaskUsecaseSuite) TestGetTaskByID_NotFound() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
 	notFoundError := errors.New("task not found") // Error returned by repo
 
 	// Arrange
 	s.mockTaskRepo.EXPECT().
-		GetTaskByID(ctx, taskID.Hex()).
+		GetTaskByID(ctx, taskID.String()).
 		Return(domain.Task{}, notFoundError).
 		Once()
 
 	// Act
-	task, err := s.taskUsecase.GetTaskByID(ctx, taskID.Hex())
+	task, err := s.taskUsecase.GetTaskByID(ctx, taskID.String())
 
 	// Assert
 	s.Error(err)
@@ -142,17 +159,17 @@ func (s *TaskUsecaseSuite) TestGetTaskByID_NotFound() {
 
 func (s *TaskUsecaseSuite) TestGetTaskByID_RepositoryError() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
 	repoError := errors.New("internal server error")
 
 	// Arrange
 	s.mockTaskRepo.EXPECT().
-		GetTaskByID(ctx, taskID.Hex()).
+		GetTaskByID(ctx, taskID.String()).
 		Return(domain.Task{}, repoError).
 		Once()
 
 	// Act
-	task, err := s.taskUsecase.GetTaskByID(ctx, taskID.Hex())
+	task, err := s.taskUsecase.GetTaskByID(ctx, taskID.String())
 
 	// Assert
 	s.Error(err)
@@ -165,38 +182,68 @@ func (s *TaskUsecaseSuite) TestGetTaskByID_RepositoryError() {
 
 func (s *TaskUsecaseSuite) TestUpdateTask_Success() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
 	updatedTask := domain.Task{Title: "Updated Title", Status: "Done"}
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
 
 	// Arrange
+	s.mockTaskRepo.EXPECT().
+		GetTaskByID(ctx, taskID.String()).
+		Return(domain.Task{ID: taskID, CreatedBy: "taskowner"}, nil).
+		Once()
+
 	// The mock expects the full updatedTask struct as passed from the usecase
 	s.mockTaskRepo.EXPECT().
-		UpdateTask(ctx, taskID.Hex(), updatedTask).
+		UpdateTask(ctx, taskID.String(), updatedTask).
 		Return(nil).
 		Once()
 
 	// Act
-	err := s.taskUsecase.UpdateTask(ctx, taskID.Hex(), updatedTask)
+	err := s.taskUsecase.UpdateTask(ctx, taskID.String(), updatedTask, owner)
 
 	// Assert
 	s.NoError(err)
 
 }
 
+func (s *TaskUsecaseSuite) TestUpdateTask_Forbidden_SomeoneElsesTask() {
+	ctx := context.Background()
+	taskID := domain.NewID()
+	otherUser := domain.Principal{Username: "intruder", Permissions: []string{domain.PermissionTasksWrite}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		GetTaskByID(ctx, taskID.String()).
+		Return(domain.Task{ID: taskID, CreatedBy: "taskowner"}, nil).
+		Once()
+
+	// Act
+	err := s.taskUsecase.UpdateTask(ctx, taskID.String(), domain.Task{Status: "Done"}, otherUser)
+
+	// Assert
+	s.ErrorIs(err, domain.ErrForbidden)
+	s.mockTaskRepo.AssertNotCalled(s.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionTaskUpdate && event.Outcome == domain.AuditOutcomeFailure && event.TargetID == taskID.String()
+	}))
+
+}
+
 func (s *TaskUsecaseSuite) TestUpdateTask_RepositoryError() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
 	updatedTask := domain.Task{Title: "Updated Title", Status: "Done"}
 	repoError := errors.New("update failed")
+	moderator := domain.Principal{Username: "moderator", Permissions: []string{domain.PermissionTasksDeleteAny}}
 
-	// Arrange
+	// Arrange: no ownership lookup is needed
 	s.mockTaskRepo.EXPECT().
-		UpdateTask(ctx, taskID.Hex(), updatedTask).
+		UpdateTask(ctx, taskID.String(), updatedTask).
 		Return(repoError).
 		Once()
 
 	// Act
-	err := s.taskUsecase.UpdateTask(ctx, taskID.Hex(), updatedTask)
+	err := s.taskUsecase.UpdateTask(ctx, taskID.String(), updatedTask, moderator)
 
 	// Assert
 	s.Error(err)
@@ -205,39 +252,88 @@ func (s *TaskUsecaseSuite) TestUpdateTask_RepositoryError() {
 }
 
 // ---- Test DeleteTask ----
-// This can be improved when authoriaztion is made a requirement for deletion of tasks
 
-func (s *TaskUsecaseSuite) TestDeleteTask_Success() {
+func (s *TaskUsecaseSuite) TestDeleteTask_Success_OwnTask() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
 
 	// Arrange
 	s.mockTaskRepo.EXPECT().
-		DeleteTask(ctx, taskID.Hex()).
+		GetTaskByID(ctx, taskID.String()).
+		Return(domain.Task{ID: taskID, CreatedBy: "taskowner"}, nil).
+		Once()
+
+	s.mockTaskRepo.EXPECT().
+		DeleteTask(ctx, taskID.String()).
 		Return(nil).
 		Once()
 
 	// Act
-	err := s.taskUsecase.DeleteTask(ctx, taskID.Hex())
+	err := s.taskUsecase.DeleteTask(ctx, taskID.String(), owner)
 
 	// Assert
 	s.NoError(err)
 
 }
 
+func (s *TaskUsecaseSuite) TestDeleteTask_Forbidden_SomeoneElsesTask() {
+	ctx := context.Background()
+	taskID := domain.NewID()
+	otherUser := domain.Principal{Username: "intruder", Permissions: []string{domain.PermissionTasksWrite}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		GetTaskByID(ctx, taskID.String()).
+		Return(domain.Task{ID: taskID, CreatedBy: "taskowner"}, nil).
+		Once()
+
+	// Act
+	err := s.taskUsecase.DeleteTask(ctx, taskID.String(), otherUser)
+
+	// Assert
+	s.ErrorIs(err, domain.ErrForbidden)
+	s.mockTaskRepo.AssertNotCalled(s.T(), "DeleteTask", mock.Anything, mock.Anything)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionTaskDelete && event.Outcome == domain.AuditOutcomeFailure && event.TargetID == taskID.String()
+	}))
+
+}
+
+func (s *TaskUsecaseSuite) TestDeleteTask_Success_DeleteAnyPermission() {
+	ctx := context.Background()
+	taskID := domain.NewID()
+	moderator := domain.Principal{Username: "moderator", Permissions: []string{domain.PermissionTasksDeleteAny}}
+
+	// Arrange: no ownership lookup is needed
+	s.mockTaskRepo.EXPECT().
+		DeleteTask(ctx, taskID.String()).
+		Return(nil).
+		Once()
+
+	// Act
+	err := s.taskUsecase.DeleteTask(ctx, taskID.String(), moderator)
+
+	// Assert
+	s.NoError(err)
+	s.mockTaskRepo.AssertNotCalled(s.T(), "GetTaskByID", mock.Anything, mock.Anything)
+
+}
+
 func (s *TaskUsecaseSuite) TestDeleteTask_RepositoryError() {
 	ctx := context.Background()
-	taskID := primitive.NewObjectID()
+	taskID := domain.NewID()
 	repoError := errors.New("deletion failed")
+	moderator := domain.Principal{Username: "moderator", Permissions: []string{domain.PermissionTasksDeleteAny}}
 
 	// Arrange
 	s.mockTaskRepo.EXPECT().
-		DeleteTask(ctx, taskID.Hex()).
+		DeleteTask(ctx, taskID.String()).
 		Return(repoError).
 		Once()
 
 	// Act
-	err := s.taskUsecase.DeleteTask(ctx, taskID.Hex())
+	err := s.taskUsecase.DeleteTask(ctx, taskID.String(), moderator)
 
 	// Assert
 	s.Error(err)
@@ -257,14 +353,15 @@ func (s *TaskUsecaseSuite) TestNewTask_Success() {
 		CreatedBy:   "testuser", // Assuming this is set before calling usecase
 	}
 
-	mockObjectID := primitive.NewObjectID()
-	mockInsertResult := &mongo.InsertOneResult{InsertedID: mockObjectID}
+	mockID := domain.NewID()
+	storedTask := newTask
+	storedTask.ID = mockID
 
 	// Arrange
 	// The exact newTask struct is to be passed to the repository
 	s.mockTaskRepo.EXPECT().
 		NewTask(ctx, newTask).
-		Return(mockInsertResult, nil).
+		Return(storedTask, nil).
 		Once()
 
 	// Act
@@ -272,8 +369,13 @@ func (s *TaskUsecaseSuite) TestNewTask_Success() {
 
 	// Assert
 	s.NoError(err)
-	s.NotNil(result)
-	s.Equal(mockObjectID, result.InsertedID)
+	s.Equal(mockID, result.ID)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, domain.AuditEvent{
+		Action:     domain.AuditActionTaskCreate,
+		Outcome:    domain.AuditOutcomeSuccess,
+		TargetType: domain.AuditTargetTask,
+		TargetID:   mockID.String(),
+	})
 
 }
 
@@ -285,7 +387,7 @@ func (s *TaskUsecaseSuite) TestNewTask_RepositoryError() {
 	// Arrange
 	s.mockTaskRepo.EXPECT().
 		NewTask(ctx, newTask).
-		Return(nil, repoError).
+		Return(domain.Task{}, repoError).
 		Once()
 
 	// Act
@@ -293,7 +395,284 @@ func (s *TaskUsecaseSuite) TestNewTask_RepositoryError() {
 
 	// Assert
 	s.Error(err)
-	s.Nil(result)
+	s.Empty(result)
 	s.Equal(repoError, err)
 
 }
+
+// ---- Test BulkTasks ----
+
+func (s *TaskUsecaseSuite) TestBulkTasks_ChecksOwnershipPerOperation() {
+	ctx := context.Background()
+	ownID, otherID, missingID := domain.NewID(), domain.NewID(), domain.NewID()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
+	request := domain.BulkTaskRequest{Operations: []domain.BulkTaskOperation{
+		{Op: domain.BulkOpCreate, Task: domain.Task{ID: otherID, Title: "New", CreatedBy: "someone_else"}},
+		{Op: domain.BulkOpUpdate, ID: ownID.String(), Task: domain.Task{Status: "Done"}},
+		{Op: domain.BulkOpDelete, ID: otherID.String()},
+		{Op: domain.BulkOpDelete, ID: missingID.String()},
+		{Op: "archive", ID: ownID.String()},
+	}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{ownID, otherID, missingID, ownID}}, 4).
+		Return([]domain.Task{{ID: ownID, CreatedBy: "taskowner"}, {ID: otherID, CreatedBy: "someone_else"}}, nil).
+		Once()
+
+	createdID := domain.NewID()
+	s.mockTaskRepo.EXPECT().
+		BulkWriteTasks(ctx, []domain.BulkTaskOperation{
+			{Op: domain.BulkOpCreate, Task: domain.Task{Title: "New", CreatedBy: "taskowner"}},
+			request.Operations[1],
+		}, false).
+		Return([]domain.BulkTaskResult{
+			{Index: 0, Op: domain.BulkOpCreate, ID: createdID.String(), Status: domain.BulkStatusApplied},
+			{Index: 1, Op: domain.BulkOpUpdate, ID: ownID.String(), Status: domain.BulkStatusApplied},
+		}, nil).
+		Once()
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, request, owner)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(results, 5)
+	s.Equal(domain.BulkTaskResult{Index: 0, Op: domain.BulkOpCreate, ID: createdID.String(), Status: domain.BulkStatusApplied}, results[0])
+	s.Equal(domain.BulkTaskResult{Index: 1, Op: domain.BulkOpUpdate, ID: ownID.String(), Status: domain.BulkStatusApplied}, results[1])
+	s.Equal(domain.BulkTaskResult{Index: 2, Op: domain.BulkOpDelete, ID: otherID.String(), Status: domain.BulkStatusFailed, Error: domain.ErrForbidden.Error()}, results[2])
+	s.Equal("task not found", results[3].Error)
+	s.Equal(domain.BulkStatusFailed, results[4].Status)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionTaskDelete && event.Outcome == domain.AuditOutcomeFailure && event.TargetID == otherID.String()
+	}))
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionTaskCreate && event.Outcome == domain.AuditOutcomeSuccess && event.TargetID == createdID.String()
+	}))
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_DeleteAnyPermissionChangesOtherUsersTasks() {
+	ctx := context.Background()
+	taskID := domain.NewID()
+	moderator := domain.Principal{Username: "moderator", Permissions: []string{domain.PermissionTasksDeleteAny}}
+	operations := []domain.BulkTaskOperation{{Op: domain.BulkOpUpdate, ID: taskID.String(), Task: domain.Task{Status: "Done"}}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{taskID}}, 1).
+		Return([]domain.Task{{ID: taskID, CreatedBy: "taskowner"}}, nil).
+		Once()
+	s.mockTaskRepo.EXPECT().
+		BulkWriteTasks(ctx, operations, true).
+		Return([]domain.BulkTaskResult{{Index: 0, Op: domain.BulkOpUpdate, ID: taskID.String(), Status: domain.BulkStatusApplied}}, nil).
+		Once()
+
+	var fnErr error
+	s.expectTransaction(ctx, &fnErr)
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Operations: operations, Atomic: true}, moderator)
+
+	// Assert
+	s.NoError(err)
+	s.NoError(fnErr, "The transaction should be committed")
+	s.Equal(domain.BulkStatusApplied, results[0].Status)
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_AtomicWritesNothingWhenAnOperationIsRefused() {
+	ctx := context.Background()
+	ownID, otherID := domain.NewID(), domain.NewID()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
+	request := domain.BulkTaskRequest{Atomic: true, Operations: []domain.BulkTaskOperation{
+		{Op: domain.BulkOpDelete, ID: ownID.String()},
+		{Op: domain.BulkOpDelete, ID: otherID.String()},
+	}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, mock.Anything, 2).
+		Return([]domain.Task{{ID: ownID, CreatedBy: "taskowner"}, {ID: otherID, CreatedBy: "someone_else"}}, nil).
+		Once()
+	var fnErr error
+	s.expectTransaction(ctx, &fnErr)
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, request, owner)
+
+	// Assert
+	s.NoError(err)
+	s.Error(fnErr, "The transaction should be rolled back")
+	s.Equal(domain.BulkStatusSkipped, results[0].Status)
+	s.Equal(domain.BulkStatusFailed, results[1].Status)
+	s.mockTaskRepo.AssertNotCalled(s.T(), "BulkWriteTasks", mock.Anything, mock.Anything, mock.Anything)
+	// The refusal is recorded although the transaction rolled back
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Outcome == domain.AuditOutcomeFailure && event.TargetID == otherID.String()
+	}))
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_AtomicRollsBackWhenAWriteFails() {
+	ctx := context.Background()
+	taskID := domain.NewID()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
+	operations := []domain.BulkTaskOperation{
+		{Op: domain.BulkOpCreate, Task: domain.Task{Title: "New", CreatedBy: "taskowner"}},
+		{Op: domain.BulkOpUpdate, ID: taskID.String()},
+	}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{taskID}}, 1).
+		Return([]domain.Task{{ID: taskID, CreatedBy: "taskowner"}}, nil).
+		Once()
+	s.mockTaskRepo.EXPECT().
+		BulkWriteTasks(ctx, operations, true).
+		Return([]domain.BulkTaskResult{
+			{Index: 0, Op: domain.BulkOpCreate, Status: domain.BulkStatusSkipped},
+			{Index: 1, Op: domain.BulkOpUpdate, ID: taskID.String(), Status: domain.BulkStatusFailed, Error: "no field provided"},
+		}, nil).
+		Once()
+	var fnErr error
+	s.expectTransaction(ctx, &fnErr)
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Operations: operations, Atomic: true}, owner)
+
+	// Assert
+	s.NoError(err)
+	s.Error(fnErr, "The transaction should be rolled back")
+	s.Equal(domain.BulkStatusSkipped, results[0].Status)
+	s.Equal("no field provided", results[1].Error)
+	s.mockAudit.AssertNotCalled(s.T(), "Record", mock.Anything, mock.Anything)
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_AtomicTransactionError() {
+	ctx := context.Background()
+	transactionError := errors.New("transactions need a replica set")
+	request := domain.BulkTaskRequest{Atomic: true, Operations: []domain.BulkTaskOperation{{Op: domain.BulkOpCreate, Task: domain.Task{Title: "New"}}}}
+
+	// Arrange
+	s.mockTransactions.EXPECT().
+		Do(ctx, mock.Anything).
+		Return(transactionError).
+		Once()
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, request, domain.Principal{Username: "taskowner"})
+
+	// Assert
+	s.ErrorIs(err, transactionError)
+	s.Nil(results)
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_FilterUpdatesEveryMatchingTask() {
+	ctx := context.Background()
+	first, second := domain.NewID(), domain.NewID()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
+	filter := domain.TaskFilter{Status: "In Review", CreatedBy: "taskowner"}
+	update := domain.Task{Status: "Done"}
+	matched := []domain.Task{{ID: first, CreatedBy: "taskowner"}, {ID: second, CreatedBy: "taskowner"}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, filter, domain.MaxBulkTaskOperations+1).
+		Return(matched, nil).
+		Once()
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{first, second}}, 2).
+		Return(matched, nil).
+		Once()
+	s.mockTaskRepo.EXPECT().
+		BulkWriteTasks(ctx, []domain.BulkTaskOperation{
+			{Op: domain.BulkOpUpdate, ID: first.String(), Task: update},
+			{Op: domain.BulkOpUpdate, ID: second.String(), Task: update},
+		}, false).
+		Return([]domain.BulkTaskResult{
+			{Index: 0, Op: domain.BulkOpUpdate, ID: first.String(), Status: domain.BulkStatusApplied},
+			{Index: 1, Op: domain.BulkOpUpdate, ID: second.String(), Status: domain.BulkStatusApplied},
+		}, nil).
+		Once()
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Filter: &filter, Update: update}, owner)
+
+	// Assert
+	s.NoError(err)
+	s.Len(results, 2)
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_FilterOnlyMatchesOwnTasksWithoutDeleteAny() {
+	ctx := context.Background()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
+	filter := domain.TaskFilter{Status: "In Review"}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{Status: "In Review", CreatedBy: "taskowner"}, domain.MaxBulkTaskOperations+1).
+		Return(nil, nil).
+		Once()
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Filter: &filter, Update: domain.Task{Status: "Done"}}, owner)
+
+	// Assert
+	s.NoError(err)
+	s.Empty(results)
+	s.Empty(filter.CreatedBy, "The request's filter should be left as it was")
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_MatchesIDsRegardlessOfCase() {
+	ctx := context.Background()
+	taskID := domain.NewID()
+	owner := domain.Principal{Username: "taskowner", Permissions: []string{domain.PermissionTasksWrite}}
+	operations := []domain.BulkTaskOperation{{Op: domain.BulkOpDelete, ID: strings.ToUpper(taskID.String())}}
+
+	// Arrange
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{IDs: []domain.ID{taskID}}, 1).
+		Return([]domain.Task{{ID: taskID, CreatedBy: "taskowner"}}, nil).
+		Once()
+	s.mockTaskRepo.EXPECT().
+		BulkWriteTasks(ctx, operations, false).
+		Return([]domain.BulkTaskResult{{Index: 0, Op: domain.BulkOpDelete, ID: operations[0].ID, Status: domain.BulkStatusApplied}}, nil).
+		Once()
+
+	// Act
+	results, err := s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Operations: operations}, owner)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(domain.BulkStatusApplied, results[0].Status)
+
+}
+
+func (s *TaskUsecaseSuite) TestBulkTasks_InvalidRequests() {
+	ctx := context.Background()
+	principal := domain.Principal{Username: "taskowner"}
+	tooMany := make([]domain.BulkTaskOperation, domain.MaxBulkTaskOperations+1)
+
+	// Arrange: a filter matching more tasks than allowed
+	s.mockTaskRepo.EXPECT().
+		FindTasks(ctx, domain.TaskFilter{Status: "Todo", CreatedBy: "taskowner"}, domain.MaxBulkTaskOperations+1).
+		Return(make([]domain.Task, domain.MaxBulkTaskOperations+1), nil).
+		Once()
+
+	// Act & Assert
+	_, err := s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{}, principal)
+	s.ErrorIs(err, domain.ErrInvalidBulkRequest, "Nothing to do")
+	_, err = s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Filter: &domain.TaskFilter{}}, principal)
+	s.ErrorIs(err, domain.ErrInvalidBulkRequest, "A filter needs an update")
+	_, err = s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Operations: tooMany}, principal)
+	s.ErrorIs(err, domain.ErrTooManyBulkTasks)
+	_, err = s.taskUsecase.BulkTasks(ctx, domain.BulkTaskRequest{Filter: &domain.TaskFilter{Status: "Todo"}, Update: domain.Task{Status: "Done"}}, principal)
+	s.ErrorIs(err, domain.ErrTooManyBulkTasks)
+	s.mockTaskRepo.AssertNotCalled(s.T(), "BulkWriteTasks", mock.Anything, mock.Anything, mock.Anything)
+
+}
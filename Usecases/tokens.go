@@ -0,0 +1,26 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// Generate a random, URL-safe token with 256 bits of entropy
+func generateToken() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", errors.New("failed to generate token")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// Hash a token for storage. Tokens are random and long, so a fast hash is enough.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
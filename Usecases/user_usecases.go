@@ -3,31 +3,78 @@ package usecases
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	domain "task_manager/Domain"
-
-	"go.mongodb.org/mongo-driver/mongo"
+	"time"
 )
 
+// How long an email verification token stays valid
+const emailVerificationTokenTTL = 24 * time.Hour
+
+// How recently an account without a password must have signed in to delete itself
+const reauthWindow = 5 * time.Minute
+
 type userUsecase struct {
 	userRepo        domain.UserRepository
 	passwordService domain.PasswordService
+	validator       domain.PasswordValidator
 	jwtService      domain.JWTService
+	tokenRepo       domain.OneTimeTokenRepository
+	accessTokenRepo domain.PersonalAccessTokenRepository
+	taskRepo        domain.TaskRepository
+	transactions    domain.UnitOfWork
+	mailer          domain.Mailer
+	loginThrottle   domain.LoginThrottle
+	audit           domain.AuditLogger
+	metrics         domain.AuthMetrics
+
+	// Hash of a throwaway password, compared against when a username does not exist
+	equaliserOnce sync.Once
+	equaliserHash string
 }
 
-func NewUserUsecase(repo domain.UserRepository, passwordService domain.PasswordService, jwtService domain.JWTService) domain.UserUsecase {
+func NewUserUsecase(repo domain.UserRepository, passwordService domain.PasswordService, validator domain.PasswordValidator, jwtService domain.JWTService, tokenRepo domain.OneTimeTokenRepository, accessTokenRepo domain.PersonalAccessTokenRepository, taskRepo domain.TaskRepository, transactions domain.UnitOfWork, mailer domain.Mailer, loginThrottle domain.LoginThrottle, audit domain.AuditLogger, metrics domain.AuthMetrics) domain.UserUsecase {
 	return &userUsecase{
 		userRepo:        repo,
 		passwordService: passwordService,
+		validator:       validator,
 		jwtService:      jwtService,
+		tokenRepo:       tokenRepo,
+		accessTokenRepo: accessTokenRepo,
+		taskRepo:        taskRepo,
+		transactions:    transactions,
+		mailer:          mailer,
+		loginThrottle:   loginThrottle,
+		audit:           audit,
+		metrics:         metrics,
 	}
 }
 
-func (usecase *userUsecase) Register(ctx context.Context, username, password string) (*mongo.InsertOneResult, error) {
+// Emails are compared case-insensitively, so they are stored lower-cased
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (usecase *userUsecase) Register(ctx context.Context, username, email, password string) (*domain.User, error) {
+	// These checks only spare hashing the password for a taken username or email. They race with
+	// other registrations; CreateUser settles those with ErrConflict.
 	_, err := usecase.userRepo.FindUserByUsername(ctx, username)
 
 	// nil is returned if user already exist, else an error is returned.
 	if err == nil {
-		return nil, errors.New("user already exists")
+		return nil, domain.ErrUserAlreadyExists
+	}
+
+	email = normalizeEmail(email)
+	if _, err := usecase.userRepo.FindUserByEmail(ctx, email); err == nil {
+		return nil, domain.ErrEmailTaken
+	}
+
+	if err := usecase.validator.Validate(ctx, password, username); err != nil {
+		return nil, err
 	}
 
 	// Hash password
@@ -39,31 +86,405 @@ func (usecase *userUsecase) Register(ctx context.Context, username, password str
 	// Create user
 	user := domain.User{
 		Username:     username,
+		Email:        email,
 		PasswordHash: hashedPassword,
 		Role:         domain.RoleUser,
 	}
 
 	// Save tp the database
-	return usecase.userRepo.CreateUser(ctx, &user)
+	if err := usecase.userRepo.CreateUser(ctx, &user); err != nil {
+		return nil, err
+	}
+
+	event := auditEvent(domain.AuditActionRegister, domain.AuditTargetUser, username, nil)
+	event.Actor = username
+	usecase.audit.Record(ctx, event)
+
+	// The account exists either way; the user can ask for another email later
+	if err := usecase.sendVerificationEmail(ctx, &user); err != nil {
+		domain.LoggerFromContext(ctx).Error("failed to send verification email", slog.Any("error", err))
+	}
+
+	return &user, nil
 }
 
-func (usecase *userUsecase) Login(ctx context.Context, username, password string) (string, error) {
-	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+// Created with the current password service on first use, so comparing against it costs as much as a real hash
+func (usecase *userUsecase) timingEqualiserHash(ctx context.Context) string {
+	usecase.equaliserOnce.Do(func() {
+		hash, err := usecase.passwordService.HashPassword("timing-equaliser")
+		if err != nil {
+			domain.LoggerFromContext(ctx).Error("failed to create timing equaliser hash", slog.Any("error", err))
+		}
+		usecase.equaliserHash = hash
+	})
+
+	return usecase.equaliserHash
+}
+
+// Replace the user's password hash if it is outdated. Reports whether the user was changed.
+func (usecase *userUsecase) rehashPassword(ctx context.Context, user *domain.User, password string) bool {
+	if !usecase.passwordService.NeedsRehash(user.PasswordHash) {
+		return false
+	}
+
+	hash, err := usecase.passwordService.HashPassword(password)
+	if err != nil {
+		domain.LoggerFromContext(ctx).Error("failed to rehash password", slog.String("username", user.Username), slog.Any("error", err))
+		return false
+	}
+
+	user.PasswordHash = hash
+	return true
+}
+
+// Look a user up by username, or by email when the identifier looks like one
+func (usecase *userUsecase) findUserByIdentifier(ctx context.Context, identifier string) (*domain.User, error) {
+	if strings.Contains(identifier, "@") {
+		user, err := usecase.userRepo.FindUserByEmail(ctx, normalizeEmail(identifier))
+		if err == nil {
+			return user, nil
+		}
+	}
+
+	return usecase.userRepo.FindUserByUsername(ctx, identifier)
+}
+
+func (usecase *userUsecase) Login(ctx context.Context, identifier, password string) (*domain.LoginResult, error) {
+	result, username, err := usecase.login(ctx, identifier, password)
+
+	// A login that needs a second factor is recorded when that step finishes
+	if err != nil || !result.MFARequired {
+		usecase.audit.Record(ctx, loginEvent(username, "password", err))
+	}
+	usecase.metrics.ObserveLogin("password", loginOutcome(result, err))
+
+	return result, err
+}
+
+// Classifies the password step of a login for metrics
+func loginOutcome(result *domain.LoginResult, err error) string {
+	switch {
+	case err == nil && result.MFARequired:
+		return domain.LoginOutcomeMFARequired
+	case err == nil:
+		return domain.LoginOutcomeSuccess
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return domain.LoginOutcomeInvalidCredentials
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		return domain.LoginOutcomeThrottled
+	default:
+		return domain.LoginOutcomeError
+	}
+}
+
+// Check the password. Also returns the username logged in as, or the identifier when no account matches.
+func (usecase *userUsecase) login(ctx context.Context, identifier, password string) (*domain.LoginResult, string, error) {
+	clientIP := domain.RequestMetaFromContext(ctx).ClientIP
+	user, err := usecase.findUserByIdentifier(ctx, identifier)
+
+	// Failures count against the account, or against the identifier when no account matches
+	throttleKey := identifier
+	if err == nil {
+		throttleKey = user.Username
+	}
+
+	if wait := usecase.loginThrottle.RetryAfter(ctx, throttleKey, clientIP); wait > 0 {
+		return nil, throttleKey, &domain.TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	// Lockouts recorded on the user outlive the in-memory throttle
+	if err == nil && user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return nil, throttleKey, &domain.TooManyAttemptsError{RetryAfter: time.Until(*user.LockedUntil)}
+	}
 
 	// Find user
 	if err != nil {
-		return "", errors.New("invalid username or password")
+		// Compare against a dummy hash so an unknown username takes as long as a wrong password
+		_ = usecase.passwordService.ComparePasswords(usecase.timingEqualiserHash(ctx), password)
+		usecase.loginThrottle.RecordFailure(ctx, throttleKey, clientIP)
+		return nil, throttleKey, domain.ErrInvalidCredentials
 	}
 
 	// Compare password
 	if err := usecase.passwordService.ComparePasswords(user.PasswordHash, password); err != nil {
-		return "", errors.New("invalid username or password")
+		usecase.recordFailedLogin(ctx, user, clientIP)
+		return nil, throttleKey, domain.ErrInvalidCredentials
+	}
+
+	// With two-factor authentication the login only succeeds, and the failure count only resets, after the second step
+	if !user.TOTPEnabled {
+		usecase.loginThrottle.RecordSuccess(ctx, user.Username, clientIP)
+	}
+
+	// Upgrade a hash made with an older algorithm or weaker parameters while the password is at hand
+	rehashed := usecase.rehashPassword(ctx, user, password)
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil || rehashed {
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
+		if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+			return nil, throttleKey, err
+		}
+	}
+
+	if user.TOTPEnabled {
+		mfaToken, err := issueMFAToken(ctx, usecase.tokenRepo, usecase.jwtService, user)
+		if err != nil {
+			return nil, throttleKey, err
+		}
+		return &domain.LoginResult{MFARequired: true, MFAToken: mfaToken}, throttleKey, nil
 	}
 
 	// Generate JWT token
-	token, err := usecase.jwtService.GenerateToken(username, user.Role)
+	token, err := usecase.jwtService.GenerateToken(user)
+	if err != nil {
+		return nil, throttleKey, err
+	}
+	return &domain.LoginResult{Token: token}, throttleKey, nil
+}
+
+// Count a wrong password and record the lockout on the user once one starts.
+// Only lockouts are written, so a wrong password costs no more than an unknown username.
+func (usecase *userUsecase) recordFailedLogin(ctx context.Context, user *domain.User, clientIP string) {
+	failures, lockedUntil := usecase.loginThrottle.RecordFailure(ctx, user.Username, clientIP)
+	if lockedUntil.IsZero() {
+		return
+	}
+
+	user.FailedLoginAttempts = failures
+	user.LockedUntil = &lockedUntil
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		domain.LoggerFromContext(ctx).Error("failed to record lockout", slog.String("username", user.Username), slog.Any("error", err))
+	}
+}
+
+// Lift a login lockout, both the recorded one and the in-memory counters.
+func (usecase *userUsecase) UnlockUser(ctx context.Context, username string) error {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
 	if err != nil {
+		return err
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	usecase.loginThrottle.Reset(ctx, user.Username)
+
+	return nil
+}
+
+// Get the profile of the authenticated user.
+func (usecase *userUsecase) GetProfile(ctx context.Context, username string) (*domain.User, error) {
+	return usecase.userRepo.FindUserByUsername(ctx, username)
+}
+
+// Update the editable profile fields of the authenticated user.
+func (usecase *userUsecase) UpdateProfile(ctx context.Context, username string, update domain.UpdateProfileRequest) (*domain.User, error) {
+	if update.DisplayName == nil && update.Timezone == nil && update.Email == nil {
+		return nil, domain.ErrNoFieldsToUpdate
+	}
+
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.DisplayName != nil {
+		user.DisplayName = *update.DisplayName
+	}
+
+	if update.Timezone != nil {
+		user.Timezone = *update.Timezone
+	}
+
+	// A new address has to be verified again
+	emailChanged := false
+	if update.Email != nil && normalizeEmail(*update.Email) != user.Email {
+		email := normalizeEmail(*update.Email)
+		if existing, err := usecase.userRepo.FindUserByEmail(ctx, email); err == nil && existing.Username != user.Username {
+			return nil, domain.ErrEmailTaken
+		}
+
+		user.Email = email
+		user.EmailVerified = false
+		emailChanged = true
+	}
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if emailChanged {
+		if err := usecase.sendVerificationEmail(ctx, user); err != nil {
+			domain.LoggerFromContext(ctx).Error("failed to send verification email", slog.Any("error", err))
+		}
+	}
+
+	return user, nil
+}
+
+// Change the password of the authenticated user.
+// Every existing session and personal access token is revoked and a fresh token is returned for the caller.
+func (usecase *userUsecase) ChangePassword(ctx context.Context, username, currentPassword, newPassword string) (string, error) {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	if err := usecase.passwordService.ComparePasswords(user.PasswordHash, currentPassword); err != nil {
+		return "", domain.ErrIncorrectPassword
+	}
+
+	if err := usecase.validator.Validate(ctx, newPassword, user.Username); err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := usecase.passwordService.HashPassword(newPassword)
+	if err != nil {
+		return "", err
+	}
+
+	user.PasswordHash = hashedPassword
+	user.TokenVersion++
+
+	if err := usecase.userRepo.UpdateUser(ctx, user); err != nil {
 		return "", err
 	}
-	return token, nil
+
+	// Tokens may have been created by whoever the password is being changed to lock out
+	if err := usecase.accessTokenRepo.DeleteTokensForUser(ctx, user.Username); err != nil {
+		return "", err
+	}
+
+	// Changing the password signs out every other session
+	usecase.audit.Record(ctx, auditEvent(domain.AuditActionPasswordChange, domain.AuditTargetUser, user.Username, nil))
+
+	return usecase.jwtService.GenerateToken(user)
+}
+
+// Permanently delete the authenticated user's account, with their tasks and tokens, after
+// re-checking their password. Accounts without one must have signed in within reauthWindow.
+func (usecase *userUsecase) DeleteAccount(ctx context.Context, username, password string, signedInAt time.Time) error {
+	err := usecase.deleteAccount(ctx, username, password, signedInAt)
+	usecase.audit.Record(ctx, auditEvent(domain.AuditActionAccountDelete, domain.AuditTargetUser, username, err))
+
+	return err
+}
+
+func (usecase *userUsecase) deleteAccount(ctx context.Context, username, password string, signedInAt time.Time) error {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordHash == "" {
+		if time.Since(signedInAt) > reauthWindow {
+			return domain.ErrReauthRequired
+		}
+	} else if err := usecase.passwordService.ComparePasswords(user.PasswordHash, password); err != nil {
+		return domain.ErrIncorrectPassword
+	}
+
+	err = usecase.transactions.Do(ctx, func(ctx context.Context) error {
+		return usecase.removeAccount(ctx, username)
+	})
+	if errors.Is(err, domain.ErrNoTransactions) {
+		// The user goes last, so an account left half-removed can still be deleted again
+		err = usecase.removeAccount(ctx, username)
+	}
+
+	return err
+}
+
+// Deletes the user and everything tied to their username, which may be registered again afterwards
+func (usecase *userUsecase) removeAccount(ctx context.Context, username string) error {
+	if err := usecase.taskRepo.DeleteTasksByCreator(ctx, username); err != nil {
+		return err
+	}
+
+	if err := usecase.accessTokenRepo.DeleteTokensForUser(ctx, username); err != nil {
+		return err
+	}
+
+	for _, purpose := range []string{domain.TokenPurposePasswordReset, domain.TokenPurposeEmailVerification, domain.TokenPurposeMFALogin} {
+		if err := usecase.tokenRepo.DeleteTokensForUser(ctx, username, purpose); err != nil {
+			return err
+		}
+	}
+
+	return usecase.userRepo.DeleteUser(ctx, username)
+}
+
+// Send a new verification email to the authenticated user
+func (usecase *userUsecase) RequestEmailVerification(ctx context.Context, username string) error {
+	user, err := usecase.userRepo.FindUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if user.Email == "" {
+		return domain.ErrNoEmailAddress
+	}
+
+	if user.EmailVerified {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	return usecase.sendVerificationEmail(ctx, user)
+}
+
+// Mark the email address a verification token was sent to as verified
+func (usecase *userUsecase) VerifyEmail(ctx context.Context, token string) error {
+	verificationToken, err := usecase.tokenRepo.ConsumeToken(ctx, domain.TokenPurposeEmailVerification, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	user, err := usecase.userRepo.FindUserByUsername(ctx, verificationToken.Username)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	user.EmailVerified = true
+
+	return usecase.userRepo.UpdateUser(ctx, user)
+}
+
+// Issue a verification token for the user's current email address and mail it.
+// Earlier tokens are discarded so they cannot verify a different address.
+func (usecase *userUsecase) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	if err := usecase.tokenRepo.DeleteTokensForUser(ctx, user.Username, domain.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	verificationToken := domain.OneTimeToken{
+		Username:  user.Username,
+		Purpose:   domain.TokenPurposeEmailVerification,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(emailVerificationTokenTTL),
+	}
+
+	if err := usecase.tokenRepo.CreateToken(ctx, &verificationToken); err != nil {
+		return err
+	}
+
+	return usecase.mailer.Send(ctx, domain.MailMessage{
+		To:      user.Email,
+		Subject: "Verify your Task Manager email address",
+		Body: fmt.Sprintf("Hello %s,\n\nUse the token below to verify your email address. It expires in %s.\n\n%s\n\n"+
+			"Send it to POST /users/email/verify.",
+			user.Username, emailVerificationTokenTTL, token),
+	})
 }
@@ -3,15 +3,18 @@ package usecases_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	domain "task_manager/Domain"
+	"task_manager/Repositories/memory"
 	usecases "task_manager/Usecases"
 	"task_manager/mocks"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,7 +23,16 @@ type UserUsecaseSuite struct {
 	suite.Suite
 	mockUserRepo        *mocks.MockUserRepository
 	mockPasswordService *mocks.MockPasswordService
+	mockValidator       *mocks.MockPasswordValidator
 	mockJwtService      *mocks.MockJWTService
+	mockTokenRepo       *mocks.MockOneTimeTokenRepository
+	mockAccessTokenRepo *mocks.MockPersonalAccessTokenRepository
+	mockTaskRepo        *mocks.MockTaskRepository
+	mockTransactions    *mocks.MockUnitOfWork
+	mockMailer          *mocks.MockMailer
+	mockLoginThrottle   *mocks.MockLoginThrottle
+	mockAudit           *mocks.MockAuditLogger
+	mockMetrics         *mocks.MockAuthMetrics
 	userUsecase         domain.UserUsecase
 }
 
@@ -28,8 +40,19 @@ type UserUsecaseSuite struct {
 func (s *UserUsecaseSuite) SetupTest() {
 	s.mockUserRepo = mocks.NewMockUserRepository(s.T())
 	s.mockPasswordService = mocks.NewMockPasswordService(s.T())
+	s.mockValidator = mocks.NewMockPasswordValidator(s.T())
 	s.mockJwtService = mocks.NewMockJWTService(s.T())
-	s.userUsecase = usecases.NewUserUsecase(s.mockUserRepo, s.mockPasswordService, s.mockJwtService)
+	s.mockTokenRepo = mocks.NewMockOneTimeTokenRepository(s.T())
+	s.mockAccessTokenRepo = mocks.NewMockPersonalAccessTokenRepository(s.T())
+	s.mockTaskRepo = mocks.NewMockTaskRepository(s.T())
+	s.mockTransactions = mocks.NewMockUnitOfWork(s.T())
+	s.mockMailer = mocks.NewMockMailer(s.T())
+	s.mockLoginThrottle = mocks.NewMockLoginThrottle(s.T())
+	s.mockAudit = mocks.NewMockAuditLogger(s.T())
+	s.mockAudit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	s.mockMetrics = mocks.NewMockAuthMetrics(s.T())
+	s.mockMetrics.EXPECT().ObserveLogin(mock.Anything, mock.Anything).Maybe()
+	s.userUsecase = usecases.NewUserUsecase(s.mockUserRepo, s.mockPasswordService, s.mockValidator, s.mockJwtService, s.mockTokenRepo, s.mockAccessTokenRepo, s.mockTaskRepo, s.mockTransactions, s.mockMailer, s.mockLoginThrottle, s.mockAudit, s.mockMetrics)
 }
 
 // Runs the entire suite
@@ -44,34 +67,65 @@ func (s *UserUsecaseSuite) TestRegister_Success() {
 	username := "testuser"
 	password := "password123"
 	hashedPassword := "hashed_password"
-	mockObjectID := primitive.NewObjectID()
-	mockInsertResult := &mongo.InsertOneResult{InsertedID: mockObjectID}
+	mockID := domain.NewID()
+	var sentMessage domain.MailMessage
 
 	// Arrange: setup mok expectations
 	s.mockUserRepo.EXPECT().
 		FindUserByUsername(ctx, username).
-		Return(nil, mongo.ErrNoDocuments).
+		Return(nil, domain.ErrUserNotFound).
 		Once() // Expect user not found initially
 
+	s.mockUserRepo.EXPECT().
+		FindUserByEmail(ctx, "testuser@example.com"). // Email is normalised before lookup
+		Return(nil, domain.ErrUserNotFound).
+		Once()
+
+	s.mockValidator.EXPECT().Validate(ctx, password, username).Return(nil).Once()
+
 	s.mockPasswordService.EXPECT().
 		HashPassword(password).
 		Return(hashedPassword, nil) // Expect password hashing to succeed
 
 	s.mockUserRepo.EXPECT().CreateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
 		return user.Username == username &&
+			user.Email == "testuser@example.com" &&
+			!user.EmailVerified &&
 			user.PasswordHash == hashedPassword &&
 			user.Role == domain.RoleUser
 	})).
-		Return(mockInsertResult, nil).
+		Run(func(_ context.Context, user *domain.User) { user.ID = mockID }).
+		Return(nil).
 		Once() // Expect user creation to succeed.
 
+	// Expect a verification email to be sent
+	s.mockTokenRepo.EXPECT().
+		DeleteTokensForUser(ctx, username, domain.TokenPurposeEmailVerification).
+		Return(nil).
+		Once()
+
+	s.mockTokenRepo.EXPECT().
+		CreateToken(ctx, mock.MatchedBy(func(token *domain.OneTimeToken) bool {
+			return token.Username == username && token.Purpose == domain.TokenPurposeEmailVerification
+		})).
+		Return(nil).
+		Once()
+
+	s.mockMailer.EXPECT().
+		Send(ctx, mock.AnythingOfType("domain.MailMessage")).
+		Run(func(_ context.Context, message domain.MailMessage) { sentMessage = message }).
+		Return(nil).
+		Once()
+
 	// Act: Call the method under test
-	result, err := s.userUsecase.Register(ctx, username, password)
+	result, err := s.userUsecase.Register(ctx, username, " TestUser@Example.com ", password)
 
 	// Assert: Verify the outcomes
 	s.NoError(err)
 	s.NotNil(result)
-	s.Equal(mockObjectID, result.InsertedID)
+	s.Equal(mockID, result.ID)
+	s.Equal("testuser@example.com", result.Email)
+	s.Equal("testuser@example.com", sentMessage.To)
 	// AssertExpectations(s.T()) is automatically called by t.Cleanup
 }
 
@@ -88,7 +142,7 @@ func (s *UserUsecaseSuite) TestRegister_UserAlreadyExists_OnFind() {
 		Once()
 
 	// Act
-	result, err := s.userUsecase.Register(ctx, username, password)
+	result, err := s.userUsecase.Register(ctx, username, "existinguser@example.com", password)
 
 	// Assert
 	s.Error(err)
@@ -108,16 +162,23 @@ func (s *UserUsecaseSuite) TestRegister_PasswordHashingError() {
 	// Arrange
 	s.mockUserRepo.EXPECT().
 		FindUserByUsername(ctx, username).
-		Return(nil, mongo.ErrNoDocuments).
+		Return(nil, domain.ErrUserNotFound).
+		Once()
+
+	s.mockUserRepo.EXPECT().
+		FindUserByEmail(ctx, "testuser@example.com").
+		Return(nil, domain.ErrUserNotFound).
 		Once()
 
+	s.mockValidator.EXPECT().Validate(ctx, password, username).Return(nil).Once()
+
 	s.mockPasswordService.EXPECT().
 		HashPassword(password).
 		Return("", hashError).
 		Once() // Expect hashing to fail
 
 	// Act
-	result, err := s.userUsecase.Register(ctx, username, password)
+	result, err := s.userUsecase.Register(ctx, username, "testuser@example.com", password)
 
 	// Assert
 	s.Error(err)
@@ -137,9 +198,16 @@ func (s *UserUsecaseSuite) TestRegister_CreateUserError() {
 	// Arrange
 	s.mockUserRepo.EXPECT().
 		FindUserByUsername(ctx, username).
-		Return(nil, mongo.ErrNoDocuments).
+		Return(nil, domain.ErrUserNotFound).
+		Once()
+
+	s.mockUserRepo.EXPECT().
+		FindUserByEmail(ctx, "testuser@example.com").
+		Return(nil, domain.ErrUserNotFound).
 		Once()
 
+	s.mockValidator.EXPECT().Validate(ctx, password, username).Return(nil).Once()
+
 	s.mockPasswordService.EXPECT().
 		HashPassword(password).
 		Return(hashedPassword, nil).
@@ -149,11 +217,11 @@ func (s *UserUsecaseSuite) TestRegister_CreateUserError() {
 		CreateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
 			return user.Username == username
 		})).
-		Return(nil, dbError). // Expect user creation to fail
+		Return(dbError). // Expect user creation to fail
 		Once()
 
 	// Act
-	result, err := s.userUsecase.Register(ctx, username, password)
+	result, err := s.userUsecase.Register(ctx, username, "testuser@example.com", password)
 
 	// Assert
 	s.Error(err)
@@ -161,6 +229,29 @@ func (s *UserUsecaseSuite) TestRegister_CreateUserError() {
 	s.Equal(dbError, err)
 }
 
+func (s *UserUsecaseSuite) TestRegister_UsernameTakenConcurrently() {
+	ctx := context.Background()
+	username := "racer"
+	password := "password123"
+
+	// Another registration stores the username between the check and CreateUser
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, username).Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByEmail(ctx, "racer@example.com").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockValidator.EXPECT().Validate(ctx, password, username).Return(nil).Once()
+	s.mockPasswordService.EXPECT().HashPassword(password).Return("hashed_password", nil).Once()
+	s.mockUserRepo.EXPECT().
+		CreateUser(ctx, mock.AnythingOfType("*domain.User")).
+		Return(fmt.Errorf("%w: %w", domain.ErrConflict, domain.ErrUserAlreadyExists)).
+		Once()
+
+	result, err := s.userUsecase.Register(ctx, username, "racer@example.com", password)
+
+	s.Nil(result)
+	s.ErrorIs(err, domain.ErrConflict)
+	s.ErrorIs(err, domain.ErrUserAlreadyExists)
+	s.mockMailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
 // ---- Test Login ----
 
 func (s *UserUsecaseSuite) TestLogin_Success() {
@@ -171,7 +262,7 @@ func (s *UserUsecaseSuite) TestLogin_Success() {
 	role := domain.RoleUser
 	expectedToken := "valid.jwt.token"
 	foundUser := &domain.User{
-		ID:           primitive.NewObjectID(),
+		ID:           domain.NewID(),
 		Username:     username,
 		PasswordHash: hashedPassword,
 		Role:         role,
@@ -183,23 +274,43 @@ func (s *UserUsecaseSuite) TestLogin_Success() {
 		Return(foundUser, nil).
 		Once()
 
+	s.mockLoginThrottle.EXPECT().
+		RetryAfter(ctx, username, "").
+		Return(time.Duration(0)).
+		Once()
+
 	s.mockPasswordService.EXPECT().
 		ComparePasswords(hashedPassword, password).
 		Return(nil). // Expect passwords to match
 		Once()
 
+	s.mockPasswordService.EXPECT().
+		NeedsRehash(hashedPassword).
+		Return(false). // Already hashed with the current parameters
+		Once()
+
+	s.mockLoginThrottle.EXPECT().
+		RecordSuccess(ctx, username, "").
+		Once()
+
 	s.mockJwtService.EXPECT().
-		GenerateToken(username, role).
+		GenerateToken(foundUser).
 		Return(expectedToken, nil). // Expect token generation to succeed
 		Once()
 
 	// Act
-	token, err := s.userUsecase.Login(ctx, username, password)
+	result, err := s.userUsecase.Login(ctx, username, password)
 
 	// Assert
 	s.NoError(err)
-	s.Equal(expectedToken, token)
-
+	s.Require().NotNil(result)
+	s.False(result.MFARequired)
+	s.Equal(expectedToken, result.Token)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionLogin && event.Outcome == domain.AuditOutcomeSuccess &&
+			event.Actor == username && event.Details["method"] == "password"
+	}))
+	s.mockMetrics.AssertCalled(s.T(), "ObserveLogin", "password", domain.LoginOutcomeSuccess)
 }
 
 func (s *UserUsecaseSuite) TestLogin_UserNotFound() {
@@ -210,19 +321,42 @@ func (s *UserUsecaseSuite) TestLogin_UserNotFound() {
 	// Arrange
 	s.mockUserRepo.EXPECT().
 		FindUserByUsername(ctx, username).
-		Return(nil, mongo.ErrNoDocuments). // Expect user not found
+		Return(nil, domain.ErrUserNotFound). // Expect user not found
+		Once()
+
+	s.mockLoginThrottle.EXPECT().
+		RetryAfter(ctx, username, "").
+		Return(time.Duration(0)).
+		Once()
+
+	// A dummy hash from the password service is still compared so unknown usernames are not faster than wrong passwords
+	s.mockPasswordService.EXPECT().
+		HashPassword(mock.AnythingOfType("string")).
+		Return("dummy_hash", nil).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		ComparePasswords("dummy_hash", password).
+		Return(bcrypt.ErrMismatchedHashAndPassword).
+		Once()
+
+	s.mockLoginThrottle.EXPECT().
+		RecordFailure(ctx, username, "").
+		Return(1, time.Time{}).
 		Once()
 
 	// Act
-	token, err := s.userUsecase.Login(ctx, username, password)
+	result, err := s.userUsecase.Login(ctx, username, password)
 
 	// Assert
 	s.Error(err)
-	s.Empty(token)
+	s.Nil(result)
 	s.EqualError(err, "invalid username or password")
-	s.mockPasswordService.AssertNotCalled(s.T(), "ComparePasswords", mock.Anything, mock.Anything)
-	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything, mock.Anything)
-
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionLogin && event.Outcome == domain.AuditOutcomeFailure &&
+			event.Actor == username && event.Details["reason"] == "invalid username or password"
+	}))
 }
 
 func (s *UserUsecaseSuite) TestLogin_IncorrectPassword() {
@@ -232,7 +366,7 @@ func (s *UserUsecaseSuite) TestLogin_IncorrectPassword() {
 	incorrectPassword := "wrongpassword"
 	role := domain.RoleUser
 	foundUser := &domain.User{
-		ID:           primitive.NewObjectID(),
+		ID:           domain.NewID(),
 		Username:     username,
 		PasswordHash: correctPasswordHash,
 		Role:         role,
@@ -244,19 +378,30 @@ func (s *UserUsecaseSuite) TestLogin_IncorrectPassword() {
 		Return(foundUser, nil).
 		Once()
 
+	s.mockLoginThrottle.EXPECT().
+		RetryAfter(ctx, username, "").
+		Return(time.Duration(0)).
+		Once()
+
 	s.mockPasswordService.EXPECT().
 		ComparePasswords(correctPasswordHash, incorrectPassword).
 		Return(bcrypt.ErrMismatchedHashAndPassword). // Expect password comparison to fail.
 		Once()
 
+	s.mockLoginThrottle.EXPECT().
+		RecordFailure(ctx, username, "").
+		Return(1, time.Time{}). // Not locked yet, so nothing is written to the user
+		Once()
+
 	// Act
-	token, err := s.userUsecase.Login(ctx, username, incorrectPassword)
+	result, err := s.userUsecase.Login(ctx, username, incorrectPassword)
 
 	// Assert
 	s.Error(err)
-	s.Empty(token)
+	s.Nil(result)
 	s.EqualError(err, "invalid username or password")
-	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything, mock.Anything)
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+	s.mockMetrics.AssertCalled(s.T(), "ObserveLogin", "password", domain.LoginOutcomeInvalidCredentials)
 
 }
 
@@ -268,7 +413,7 @@ func (s *UserUsecaseSuite) TestLogin_TokenGenerationError() {
 	role := domain.RoleUser
 	tokenError := errors.New("failed to sign token")
 	foundUser := &domain.User{
-		ID:           primitive.NewObjectID(),
+		ID:           domain.NewID(),
 		Username:     username,
 		PasswordHash: hashedPassword,
 		Role:         role,
@@ -280,22 +425,670 @@ func (s *UserUsecaseSuite) TestLogin_TokenGenerationError() {
 		Return(foundUser, nil).
 		Once()
 
+	s.mockLoginThrottle.EXPECT().
+		RetryAfter(ctx, username, "").
+		Return(time.Duration(0)).
+		Once()
+
 	s.mockPasswordService.EXPECT().
 		ComparePasswords(hashedPassword, password).
 		Return(nil).
 		Once()
 
+	s.mockPasswordService.EXPECT().
+		NeedsRehash(hashedPassword).
+		Return(false).
+		Once()
+
+	s.mockLoginThrottle.EXPECT().
+		RecordSuccess(ctx, username, "").
+		Once()
+
 	s.mockJwtService.EXPECT().
-		GenerateToken(username, role).
+		GenerateToken(foundUser).
 		Return("", tokenError).
 		Once()
 
 	// Act
-	token, err := s.userUsecase.Login(ctx, username, password)
+	result, err := s.userUsecase.Login(ctx, username, password)
 
 	// Assert
 	s.Error(err)
-	s.Empty(token)
+	s.Nil(result)
 	s.Equal(tokenError, err)
 
 }
+
+func (s *UserUsecaseSuite) TestLogin_RehashesOutdatedHash() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "$2a$10$legacybcrypthash"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("$2a$10$legacybcrypthash", "password123").Return(nil).Once()
+	s.mockPasswordService.EXPECT().NeedsRehash("$2a$10$legacybcrypthash").Return(true).Once()
+	s.mockPasswordService.EXPECT().HashPassword("password123").Return("$argon2id$new", nil).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "").Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool { return user.PasswordHash == "$argon2id$new" })).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().GenerateToken(foundUser).Return("valid.jwt.token", nil).Once()
+
+	result, err := s.userUsecase.Login(ctx, "testuser", "password123")
+
+	s.NoError(err)
+	s.Equal("valid.jwt.token", result.Token)
+}
+
+func (s *UserUsecaseSuite) TestLogin_RehashFailureDoesNotBlockLogin() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "$2a$10$legacybcrypthash"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("$2a$10$legacybcrypthash", "password123").Return(nil).Once()
+	s.mockPasswordService.EXPECT().NeedsRehash("$2a$10$legacybcrypthash").Return(true).Once()
+	s.mockPasswordService.EXPECT().HashPassword("password123").Return("", errors.New("failed to hash password")).Once()
+	s.mockLoginThrottle.EXPECT().RecordSuccess(ctx, "testuser", "").Once()
+	s.mockJwtService.EXPECT().GenerateToken(foundUser).Return("valid.jwt.token", nil).Once()
+
+	result, err := s.userUsecase.Login(ctx, "testuser", "password123")
+
+	s.NoError(err)
+	s.Equal("valid.jwt.token", result.Token)
+	s.Equal("$2a$10$legacybcrypthash", foundUser.PasswordHash, "The old hash is kept")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+// ---- Test GetProfile ----
+
+func (s *UserUsecaseSuite) TestGetProfile_Success() {
+	ctx := context.Background()
+	foundUser := &domain.User{ID: domain.NewID(), Username: "testuser", DisplayName: "Test User"}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(foundUser, nil).
+		Once()
+
+	user, err := s.userUsecase.GetProfile(ctx, "testuser")
+
+	s.NoError(err)
+	s.Equal(foundUser, user)
+}
+
+// ---- Test UpdateProfile ----
+
+func (s *UserUsecaseSuite) TestUpdateProfile_Success() {
+	ctx := context.Background()
+	displayName := "New Name"
+	foundUser := &domain.User{ID: domain.NewID(), Username: "testuser", Timezone: "UTC"}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(foundUser, nil).
+		Once()
+
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.DisplayName == displayName && user.Timezone == "UTC"
+		})).
+		Return(nil).
+		Once()
+
+	user, err := s.userUsecase.UpdateProfile(ctx, "testuser", domain.UpdateProfileRequest{DisplayName: &displayName})
+
+	s.NoError(err)
+	s.Equal(displayName, user.DisplayName)
+	s.Equal("UTC", user.Timezone, "Fields not in the request should be left untouched")
+}
+
+func (s *UserUsecaseSuite) TestUpdateProfile_NoFields() {
+	ctx := context.Background()
+
+	user, err := s.userUsecase.UpdateProfile(ctx, "testuser", domain.UpdateProfileRequest{})
+
+	s.ErrorIs(err, domain.ErrNoFieldsToUpdate)
+	s.Nil(user)
+	s.mockUserRepo.AssertNotCalled(s.T(), "FindUserByUsername", mock.Anything, mock.Anything)
+}
+
+// ---- Test ChangePassword ----
+
+func (s *UserUsecaseSuite) TestChangePassword_Success_RevokesSessionsAndAccessTokens() {
+	ctx := context.Background()
+	foundUser := &domain.User{
+		ID:           domain.NewID(),
+		Username:     "testuser",
+		PasswordHash: "old_hash",
+		Role:         domain.RoleUser,
+		TokenVersion: 2,
+	}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(foundUser, nil).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		ComparePasswords("old_hash", "oldpassword").
+		Return(nil).
+		Once()
+
+	s.mockValidator.EXPECT().Validate(ctx, "newpassword", "testuser").Return(nil).Once()
+
+	s.mockPasswordService.EXPECT().
+		HashPassword("newpassword").
+		Return("new_hash", nil).
+		Once()
+
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.PasswordHash == "new_hash" && user.TokenVersion == 3
+		})).
+		Return(nil).
+		Once()
+
+	s.mockAccessTokenRepo.EXPECT().DeleteTokensForUser(ctx, "testuser").Return(nil).Once()
+
+	s.mockJwtService.EXPECT().
+		GenerateToken(mock.MatchedBy(func(user *domain.User) bool {
+			return user.TokenVersion == 3
+		})).
+		Return("fresh.jwt.token", nil).
+		Once()
+
+	token, err := s.userUsecase.ChangePassword(ctx, "testuser", "oldpassword", "newpassword")
+
+	s.NoError(err)
+	s.Equal("fresh.jwt.token", token)
+}
+
+func (s *UserUsecaseSuite) TestChangePassword_IncorrectCurrentPassword() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "old_hash"}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(foundUser, nil).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		ComparePasswords("old_hash", "wrongpassword").
+		Return(bcrypt.ErrMismatchedHashAndPassword).
+		Once()
+
+	token, err := s.userUsecase.ChangePassword(ctx, "testuser", "wrongpassword", "newpassword")
+
+	s.ErrorIs(err, domain.ErrIncorrectPassword)
+	s.Empty(token)
+	s.mockPasswordService.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestChangePassword_WeakPassword() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "old_hash"}
+	policyErr := &domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+		{Rule: domain.PasswordRuleContainsUsername, Message: "must not contain the username"},
+	}}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("old_hash", "oldpassword").Return(nil).Once()
+	s.mockValidator.EXPECT().Validate(ctx, "testuser2024", "testuser").Return(policyErr).Once()
+
+	token, err := s.userUsecase.ChangePassword(ctx, "testuser", "oldpassword", "testuser2024")
+
+	var violations *domain.PasswordPolicyError
+	s.Require().ErrorAs(err, &violations)
+	s.Equal(domain.PasswordRuleContainsUsername, violations.Violations[0].Rule)
+	s.Empty(token)
+	s.mockPasswordService.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+// ---- Test DeleteAccount ----
+
+// Expects one transaction, running its function like a database would
+func (s *UserUsecaseSuite) expectTransaction(ctx context.Context) {
+	s.mockTransactions.EXPECT().
+		Do(ctx, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).
+		Once()
+}
+
+// Expects everything tied to the username to be deleted, the user last
+func (s *UserUsecaseSuite) expectAccountRemoved(ctx context.Context, username string) {
+	s.mockTaskRepo.EXPECT().DeleteTasksByCreator(ctx, username).Return(nil).Once()
+	s.mockAccessTokenRepo.EXPECT().DeleteTokensForUser(ctx, username).Return(nil).Once()
+	for _, purpose := range []string{domain.TokenPurposePasswordReset, domain.TokenPurposeEmailVerification, domain.TokenPurposeMFALogin} {
+		s.mockTokenRepo.EXPECT().DeleteTokensForUser(ctx, username, purpose).Return(nil).Once()
+	}
+	s.mockUserRepo.EXPECT().DeleteUser(ctx, username).Return(nil).Once()
+}
+
+func (s *UserUsecaseSuite) TestDeleteAccount_Success() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash"}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(foundUser, nil).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		ComparePasswords("hash", "password123").
+		Return(nil).
+		Once()
+
+	s.expectTransaction(ctx)
+	s.expectAccountRemoved(ctx, "testuser")
+
+	err := s.userUsecase.DeleteAccount(ctx, "testuser", "password123", time.Time{})
+
+	s.NoError(err)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionAccountDelete && event.Outcome == domain.AuditOutcomeSuccess && event.TargetID == "testuser"
+	}))
+}
+
+func (s *UserUsecaseSuite) TestDeleteAccount_IncorrectPassword() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash"}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(foundUser, nil).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		ComparePasswords("hash", "wrongpassword").
+		Return(bcrypt.ErrMismatchedHashAndPassword).
+		Once()
+
+	// A recent sign-in does not stand in for the password of an account that has one
+	err := s.userUsecase.DeleteAccount(ctx, "testuser", "wrongpassword", time.Now())
+
+	s.ErrorIs(err, domain.ErrIncorrectPassword)
+	s.mockTransactions.AssertNotCalled(s.T(), "Do", mock.Anything, mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "DeleteUser", mock.Anything, mock.Anything)
+	s.mockAudit.AssertCalled(s.T(), "Record", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+		return event.Action == domain.AuditActionAccountDelete && event.Outcome == domain.AuditOutcomeFailure
+	}))
+}
+
+func (s *UserUsecaseSuite) TestDeleteAccount_PartialFailureRolledBack() {
+	ctx := context.Background()
+	removalError := errors.New("connection reset")
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser", PasswordHash: "hash"}, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "password123").Return(nil).Once()
+	s.expectTransaction(ctx)
+	s.mockTaskRepo.EXPECT().DeleteTasksByCreator(ctx, "testuser").Return(nil).Once()
+	s.mockAccessTokenRepo.EXPECT().DeleteTokensForUser(ctx, "testuser").Return(removalError).Once()
+
+	err := s.userUsecase.DeleteAccount(ctx, "testuser", "password123", time.Time{})
+
+	s.ErrorIs(err, removalError)
+	s.mockUserRepo.AssertNotCalled(s.T(), "DeleteUser", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestDeleteAccount_WithoutTransactions() {
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(&domain.User{Username: "testuser", PasswordHash: "hash"}, nil).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "password123").Return(nil).Once()
+	s.mockTransactions.EXPECT().Do(ctx, mock.Anything).Return(domain.ErrNoTransactions).Once()
+	s.expectAccountRemoved(ctx, "testuser")
+
+	err := s.userUsecase.DeleteAccount(ctx, "testuser", "password123", time.Time{})
+
+	s.NoError(err, "Databases without transactions should still delete accounts")
+}
+
+func (s *UserUsecaseSuite) TestDeleteAccount_WithoutPassword_RecentSignIn() {
+	ctx := context.Background()
+
+	// Signed up through single sign-on, so there is no password to check
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "ssouser").Return(&domain.User{Username: "ssouser"}, nil).Once()
+	s.expectTransaction(ctx)
+	s.expectAccountRemoved(ctx, "ssouser")
+
+	err := s.userUsecase.DeleteAccount(ctx, "ssouser", "", time.Now().Add(-time.Minute))
+
+	s.NoError(err)
+	s.mockPasswordService.AssertNotCalled(s.T(), "ComparePasswords", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestDeleteAccount_WithoutPassword_StaleSignIn() {
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "ssouser").Return(&domain.User{Username: "ssouser"}, nil).Twice()
+
+	err := s.userUsecase.DeleteAccount(ctx, "ssouser", "anything", time.Now().Add(-time.Hour))
+	s.ErrorIs(err, domain.ErrReauthRequired)
+
+	// Personal access tokens carry no sign-in time
+	err = s.userUsecase.DeleteAccount(ctx, "ssouser", "", time.Time{})
+	s.ErrorIs(err, domain.ErrReauthRequired)
+
+	s.mockTransactions.AssertNotCalled(s.T(), "Do", mock.Anything, mock.Anything)
+	s.mockPasswordService.AssertNotCalled(s.T(), "ComparePasswords", mock.Anything, mock.Anything)
+}
+
+// The account is deleted with everything tied to its username, so someone registering the
+// username again starts afresh, and tokens of the deleted account do not carry over.
+func TestDeleteAccount_UsernameRegisteredAgain(t *testing.T) {
+	ctx := context.Background()
+	userRepo := memory.NewUserRepository()
+	taskRepo := memory.NewTaskRepository()
+	tokenRepo := memory.NewOneTimeTokenRepository()
+	accessTokenRepo := memory.NewPersonalAccessTokenRepository()
+	passwordService := mocks.NewMockPasswordService(t)
+	passwordService.EXPECT().HashPassword(mock.Anything).Return("hash", nil)
+	passwordService.EXPECT().ComparePasswords("hash", "password123").Return(nil)
+	validator := mocks.NewMockPasswordValidator(t)
+	validator.EXPECT().Validate(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mailer := mocks.NewMockMailer(t)
+	mailer.EXPECT().Send(mock.Anything, mock.Anything).Return(nil).Maybe()
+	audit := mocks.NewMockAuditLogger(t)
+	audit.EXPECT().Record(mock.Anything, mock.Anything).Maybe()
+	userUsecase := usecases.NewUserUsecase(userRepo, passwordService, validator, mocks.NewMockJWTService(t), tokenRepo, accessTokenRepo, taskRepo,
+		memory.NewUnitOfWork(), mailer, mocks.NewMockLoginThrottle(t), audit, mocks.NewMockAuthMetrics(t))
+
+	deleted, err := userUsecase.Register(ctx, "testuser", "first@example.com", "password123")
+	require.NoError(t, err)
+	_, err = taskRepo.NewTask(ctx, domain.Task{Title: "Private task", CreatedBy: "testuser"})
+	require.NoError(t, err)
+	accessTokenUsecase := usecases.NewPersonalAccessTokenUsecase(accessTokenRepo, userRepo, memory.NewRoleRepository(), audit)
+	_, accessToken, err := accessTokenUsecase.CreateToken(ctx, "testuser", domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{domain.PermissionTasksRead}})
+	require.NoError(t, err)
+
+	require.NoError(t, userUsecase.DeleteAccount(ctx, "testuser", "password123", time.Time{}))
+
+	registered, err := userUsecase.Register(ctx, "testuser", "second@example.com", "password123")
+	require.NoError(t, err)
+	assert.NotEqual(t, deleted.ID, registered.ID)
+
+	// Sessions name the user by ID, which now finds no one
+	_, err = userRepo.FindUserByID(ctx, deleted.ID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	tasks, err := taskRepo.FindTasks(ctx, domain.TaskFilter{CreatedBy: "testuser"}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "Tasks of the deleted account must not pass to the new one")
+
+	_, _, err = accessTokenUsecase.Authenticate(ctx, accessToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken, "Personal access tokens of the deleted account must be revoked")
+}
+
+// ---- Test Email ----
+
+func (s *UserUsecaseSuite) TestRegister_EmailAlreadyRegistered() {
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "newuser").
+		Return(nil, domain.ErrUserNotFound).
+		Once()
+
+	s.mockUserRepo.EXPECT().
+		FindUserByEmail(ctx, "taken@example.com").
+		Return(&domain.User{Username: "someoneelse"}, nil).
+		Once()
+
+	result, err := s.userUsecase.Register(ctx, "newuser", "Taken@Example.com", "password123")
+
+	s.ErrorIs(err, domain.ErrEmailTaken)
+	s.Nil(result)
+	s.mockPasswordService.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestLogin_Success_WithEmail() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", Email: "testuser@example.com", PasswordHash: "hash", Role: domain.RoleUser}
+
+	s.mockUserRepo.EXPECT().
+		FindUserByEmail(ctx, "testuser@example.com").
+		Return(foundUser, nil).
+		Once()
+
+	s.mockLoginThrottle.EXPECT().
+		RetryAfter(ctx, "testuser", ""). // Throttled by username whichever identifier is used
+		Return(time.Duration(0)).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		ComparePasswords("hash", "password123").
+		Return(nil).
+		Once()
+
+	s.mockPasswordService.EXPECT().
+		NeedsRehash("hash").
+		Return(false).
+		Once()
+
+	s.mockLoginThrottle.EXPECT().
+		RecordSuccess(ctx, "testuser", "").
+		Once()
+
+	s.mockJwtService.EXPECT().
+		GenerateToken(foundUser).
+		Return("valid.jwt.token", nil).
+		Once()
+
+	result, err := s.userUsecase.Login(ctx, "TestUser@example.com", "password123")
+
+	s.NoError(err)
+	s.Equal("valid.jwt.token", result.Token)
+	s.mockUserRepo.AssertNotCalled(s.T(), "FindUserByUsername", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestUpdateProfile_EmailChange_RequiresVerification() {
+	ctx := context.Background()
+	newEmail := "new@example.com"
+	foundUser := &domain.User{Username: "testuser", Email: "old@example.com", EmailVerified: true}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockUserRepo.EXPECT().FindUserByEmail(ctx, newEmail).Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Email == newEmail && !user.EmailVerified
+		})).
+		Return(nil).
+		Once()
+	s.mockTokenRepo.EXPECT().DeleteTokensForUser(ctx, "testuser", domain.TokenPurposeEmailVerification).Return(nil).Once()
+	s.mockTokenRepo.EXPECT().CreateToken(ctx, mock.Anything).Return(nil).Once()
+	s.mockMailer.EXPECT().
+		Send(ctx, mock.MatchedBy(func(message domain.MailMessage) bool { return message.To == newEmail })).
+		Return(nil).
+		Once()
+
+	user, err := s.userUsecase.UpdateProfile(ctx, "testuser", domain.UpdateProfileRequest{Email: &newEmail})
+
+	s.NoError(err)
+	s.False(user.EmailVerified)
+}
+
+func (s *UserUsecaseSuite) TestRequestEmailVerification_AlreadyVerified() {
+	ctx := context.Background()
+
+	s.mockUserRepo.EXPECT().
+		FindUserByUsername(ctx, "testuser").
+		Return(&domain.User{Username: "testuser", Email: "testuser@example.com", EmailVerified: true}, nil).
+		Once()
+
+	err := s.userUsecase.RequestEmailVerification(ctx, "testuser")
+
+	s.ErrorIs(err, domain.ErrEmailAlreadyVerified)
+	s.mockMailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestVerifyEmail_Success() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", Email: "testuser@example.com"}
+
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(ctx, domain.TokenPurposeEmailVerification, mock.AnythingOfType("string")).
+		Return(&domain.OneTimeToken{Username: "testuser"}, nil).
+		Once()
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool { return user.EmailVerified })).
+		Return(nil).
+		Once()
+
+	err := s.userUsecase.VerifyEmail(ctx, "verification-token")
+
+	s.NoError(err)
+}
+
+func (s *UserUsecaseSuite) TestVerifyEmail_InvalidToken() {
+	ctx := context.Background()
+
+	s.mockTokenRepo.EXPECT().
+		ConsumeToken(ctx, domain.TokenPurposeEmailVerification, mock.AnythingOfType("string")).
+		Return(nil, domain.ErrInvalidToken).
+		Once()
+
+	err := s.userUsecase.VerifyEmail(ctx, "bad-token")
+
+	s.ErrorIs(err, domain.ErrInvalidToken)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything)
+}
+
+// ---- Test Lockout ----
+
+func (s *UserUsecaseSuite) TestLogin_Throttled_ReturnsRetryAfter() {
+	ctx := domain.WithRequestMeta(context.Background(), domain.RequestMeta{ClientIP: "203.0.113.7"})
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().
+		RetryAfter(ctx, "testuser", "203.0.113.7").
+		Return(90 * time.Second).
+		Once()
+
+	result, err := s.userUsecase.Login(ctx, "testuser", "password123")
+
+	s.Nil(result)
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	var tooManyAttempts *domain.TooManyAttemptsError
+	s.Require().ErrorAs(err, &tooManyAttempts)
+	s.Equal(90*time.Second, tooManyAttempts.RetryAfter)
+	s.mockPasswordService.AssertNotCalled(s.T(), "ComparePasswords", mock.Anything, mock.Anything)
+	s.mockMetrics.AssertCalled(s.T(), "ObserveLogin", "password", domain.LoginOutcomeThrottled)
+}
+
+func (s *UserUsecaseSuite) TestLogin_LockedOnUser_SurvivesRestart() {
+	ctx := context.Background()
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash", LockedUntil: &lockedUntil}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+
+	_, err := s.userUsecase.Login(ctx, "testuser", "password123")
+
+	s.ErrorIs(err, domain.ErrTooManyAttempts)
+	s.mockPasswordService.AssertNotCalled(s.T(), "ComparePasswords", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestLogin_IncorrectPassword_RecordsLockout() {
+	ctx := context.Background()
+	lockedUntil := time.Now().Add(time.Minute)
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "wrongpassword").Return(bcrypt.ErrMismatchedHashAndPassword).Once()
+	s.mockLoginThrottle.EXPECT().RecordFailure(ctx, "testuser", "").Return(5, lockedUntil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.FailedLoginAttempts == 5 && user.LockedUntil != nil && user.LockedUntil.Equal(lockedUntil)
+		})).
+		Return(nil).
+		Once()
+
+	_, err := s.userUsecase.Login(ctx, "testuser", "wrongpassword")
+
+	s.ErrorIs(err, domain.ErrInvalidCredentials)
+}
+
+func (s *UserUsecaseSuite) TestUnlockUser_Success() {
+	ctx := context.Background()
+	lockedUntil := time.Now().Add(time.Hour)
+	foundUser := &domain.User{Username: "testuser", FailedLoginAttempts: 7, LockedUntil: &lockedUntil}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockUserRepo.EXPECT().
+		UpdateUser(ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.FailedLoginAttempts == 0 && user.LockedUntil == nil
+		})).
+		Return(nil).
+		Once()
+	s.mockLoginThrottle.EXPECT().Reset(ctx, "testuser").Once()
+
+	err := s.userUsecase.UnlockUser(ctx, "testuser")
+
+	s.NoError(err)
+}
+
+func (s *UserUsecaseSuite) TestLogin_MFAEnabled_ReturnsMFAToken() {
+	ctx := context.Background()
+	foundUser := &domain.User{Username: "testuser", PasswordHash: "hash", TOTPEnabled: true, TOTPSecret: "SECRET"}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(foundUser, nil).Once()
+	s.mockLoginThrottle.EXPECT().RetryAfter(ctx, "testuser", "").Return(time.Duration(0)).Once()
+	s.mockPasswordService.EXPECT().ComparePasswords("hash", "password123").Return(nil).Once()
+	s.mockPasswordService.EXPECT().NeedsRehash("hash").Return(false).Once()
+	var storedToken *domain.OneTimeToken
+	s.mockTokenRepo.EXPECT().
+		CreateToken(ctx, mock.AnythingOfType("*domain.OneTimeToken")).
+		Run(func(_ context.Context, token *domain.OneTimeToken) { storedToken = token }).
+		Return(nil).
+		Once()
+	s.mockJwtService.EXPECT().
+		GenerateMFAToken(foundUser, mock.Anything, mock.Anything).
+		Run(func(_ *domain.User, tokenID string, expiresAt time.Time) {
+			s.Equal(sha256Hex(tokenID), storedToken.TokenHash, "The token ID is tracked as a one-time token")
+			s.Equal(storedToken.ExpiresAt, expiresAt)
+		}).
+		Return("mfa.pending.token", nil).
+		Once()
+
+	result, err := s.userUsecase.Login(ctx, "testuser", "password123")
+
+	s.NoError(err)
+	s.Require().NotNil(result)
+	s.True(result.MFARequired)
+	s.Equal("mfa.pending.token", result.MFAToken)
+	s.Equal(domain.TokenPurposeMFALogin, storedToken.Purpose)
+	s.Equal("testuser", storedToken.Username)
+	s.Empty(result.Token, "No access token before the second factor")
+	s.mockJwtService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+	// The failure count must survive until the second factor succeeds
+	s.mockLoginThrottle.AssertNotCalled(s.T(), "RecordSuccess", mock.Anything, mock.Anything, mock.Anything)
+	s.mockAudit.AssertNotCalled(s.T(), "Record", mock.Anything, mock.Anything)
+	s.mockMetrics.AssertCalled(s.T(), "ObserveLogin", "password", domain.LoginOutcomeMFARequired)
+}
+
+func (s *UserUsecaseSuite) TestRegister_WeakPassword() {
+	ctx := context.Background()
+	policyErr := &domain.PasswordPolicyError{Violations: []domain.PasswordViolation{
+		{Rule: domain.PasswordRuleMinLength, Message: "must be at least 8 characters long"},
+		{Rule: domain.PasswordRuleCommonPassword, Message: "is too common"},
+	}}
+
+	s.mockUserRepo.EXPECT().FindUserByUsername(ctx, "testuser").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockUserRepo.EXPECT().FindUserByEmail(ctx, "testuser@example.com").Return(nil, domain.ErrUserNotFound).Once()
+	s.mockValidator.EXPECT().Validate(ctx, "123456", "testuser").Return(policyErr).Once()
+
+	result, err := s.userUsecase.Register(ctx, "testuser", "testuser@example.com", "123456")
+
+	s.ErrorIs(err, domain.ErrWeakPassword)
+	s.Nil(result)
+	s.mockPasswordService.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "CreateUser", mock.Anything, mock.Anything)
+}
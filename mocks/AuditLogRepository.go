@@ -0,0 +1,142 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockAuditLogRepository creates a new instance of MockAuditLogRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditLogRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditLogRepository {
+	mock := &MockAuditLogRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuditLogRepository is an autogenerated mock type for the AuditLogRepository type
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+type MockAuditLogRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditLogRepository) EXPECT() *MockAuditLogRepository_Expecter {
+	return &MockAuditLogRepository_Expecter{mock: &_m.Mock}
+}
+
+// AppendEvent provides a mock function for the type MockAuditLogRepository
+func (_mock *MockAuditLogRepository) AppendEvent(ctx context.Context, event *domain.AuditEvent) error {
+	ret := _mock.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditEvent) error); ok {
+		r0 = returnFunc(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuditLogRepository_AppendEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppendEvent'
+type MockAuditLogRepository_AppendEvent_Call struct {
+	*mock.Call
+}
+
+// AppendEvent is a helper method to define mock.On call
+//   - ctx
+//   - event
+func (_e *MockAuditLogRepository_Expecter) AppendEvent(ctx interface{}, event interface{}) *MockAuditLogRepository_AppendEvent_Call {
+	return &MockAuditLogRepository_AppendEvent_Call{Call: _e.mock.On("AppendEvent", ctx, event)}
+}
+
+func (_c *MockAuditLogRepository_AppendEvent_Call) Run(run func(ctx context.Context, event *domain.AuditEvent)) *MockAuditLogRepository_AppendEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.AuditEvent))
+	})
+	return _c
+}
+
+func (_c *MockAuditLogRepository_AppendEvent_Call) Return(err error) *MockAuditLogRepository_AppendEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuditLogRepository_AppendEvent_Call) RunAndReturn(run func(ctx context.Context, event *domain.AuditEvent) error) *MockAuditLogRepository_AppendEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindEvents provides a mock function for the type MockAuditLogRepository
+func (_mock *MockAuditLogRepository) FindEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEvents")
+	}
+
+	var r0 []domain.AuditEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) ([]domain.AuditEvent, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) []domain.AuditEvent); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuditFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAuditLogRepository_FindEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindEvents'
+type MockAuditLogRepository_FindEvents_Call struct {
+	*mock.Call
+}
+
+// FindEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockAuditLogRepository_Expecter) FindEvents(ctx interface{}, filter interface{}) *MockAuditLogRepository_FindEvents_Call {
+	return &MockAuditLogRepository_FindEvents_Call{Call: _e.mock.On("FindEvents", ctx, filter)}
+}
+
+func (_c *MockAuditLogRepository_FindEvents_Call) Run(run func(ctx context.Context, filter domain.AuditFilter)) *MockAuditLogRepository_FindEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.AuditFilter))
+	})
+	return _c
+}
+
+func (_c *MockAuditLogRepository_FindEvents_Call) Return(auditEvents []domain.AuditEvent, err error) *MockAuditLogRepository_FindEvents_Call {
+	_c.Call.Return(auditEvents, err)
+	return _c
+}
+
+func (_c *MockAuditLogRepository_FindEvents_Call) RunAndReturn(run func(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)) *MockAuditLogRepository_FindEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
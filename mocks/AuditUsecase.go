@@ -0,0 +1,96 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockAuditUsecase creates a new instance of MockAuditUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditUsecase {
+	mock := &MockAuditUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuditUsecase is an autogenerated mock type for the AuditUsecase type
+type MockAuditUsecase struct {
+	mock.Mock
+}
+
+type MockAuditUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditUsecase) EXPECT() *MockAuditUsecase_Expecter {
+	return &MockAuditUsecase_Expecter{mock: &_m.Mock}
+}
+
+// ListEvents provides a mock function for the type MockAuditUsecase
+func (_mock *MockAuditUsecase) ListEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEvents")
+	}
+
+	var r0 []domain.AuditEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) ([]domain.AuditEvent, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) []domain.AuditEvent); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuditFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAuditUsecase_ListEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEvents'
+type MockAuditUsecase_ListEvents_Call struct {
+	*mock.Call
+}
+
+// ListEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockAuditUsecase_Expecter) ListEvents(ctx interface{}, filter interface{}) *MockAuditUsecase_ListEvents_Call {
+	return &MockAuditUsecase_ListEvents_Call{Call: _e.mock.On("ListEvents", ctx, filter)}
+}
+
+func (_c *MockAuditUsecase_ListEvents_Call) Run(run func(ctx context.Context, filter domain.AuditFilter)) *MockAuditUsecase_ListEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.AuditFilter))
+	})
+	return _c
+}
+
+func (_c *MockAuditUsecase_ListEvents_Call) Return(auditEvents []domain.AuditEvent, err error) *MockAuditUsecase_ListEvents_Call {
+	_c.Call.Return(auditEvents, err)
+	return _c
+}
+
+func (_c *MockAuditUsecase_ListEvents_Call) RunAndReturn(run func(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)) *MockAuditUsecase_ListEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
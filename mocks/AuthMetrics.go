@@ -0,0 +1,71 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockAuthMetrics creates a new instance of MockAuthMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthMetrics {
+	mock := &MockAuthMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuthMetrics is an autogenerated mock type for the AuthMetrics type
+type MockAuthMetrics struct {
+	mock.Mock
+}
+
+type MockAuthMetrics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuthMetrics) EXPECT() *MockAuthMetrics_Expecter {
+	return &MockAuthMetrics_Expecter{mock: &_m.Mock}
+}
+
+// ObserveLogin provides a mock function for the type MockAuthMetrics
+func (_mock *MockAuthMetrics) ObserveLogin(method string, outcome string) {
+	_mock.Called(method, outcome)
+	return
+}
+
+// MockAuthMetrics_ObserveLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveLogin'
+type MockAuthMetrics_ObserveLogin_Call struct {
+	*mock.Call
+}
+
+// ObserveLogin is a helper method to define mock.On call
+//   - method
+//   - outcome
+func (_e *MockAuthMetrics_Expecter) ObserveLogin(method interface{}, outcome interface{}) *MockAuthMetrics_ObserveLogin_Call {
+	return &MockAuthMetrics_ObserveLogin_Call{Call: _e.mock.On("ObserveLogin", method, outcome)}
+}
+
+func (_c *MockAuthMetrics_ObserveLogin_Call) Run(run func(method string, outcome string)) *MockAuthMetrics_ObserveLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthMetrics_ObserveLogin_Call) Return() *MockAuthMetrics_ObserveLogin_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuthMetrics_ObserveLogin_Call) RunAndReturn(run func(method string, outcome string)) *MockAuthMetrics_ObserveLogin_Call {
+	_c.Run(run)
+	return _c
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockBreachedPasswordChecker creates a new instance of MockBreachedPasswordChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBreachedPasswordChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBreachedPasswordChecker {
+	mock := &MockBreachedPasswordChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockBreachedPasswordChecker is an autogenerated mock type for the BreachedPasswordChecker type
+type MockBreachedPasswordChecker struct {
+	mock.Mock
+}
+
+type MockBreachedPasswordChecker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBreachedPasswordChecker) EXPECT() *MockBreachedPasswordChecker_Expecter {
+	return &MockBreachedPasswordChecker_Expecter{mock: &_m.Mock}
+}
+
+// IsBreached provides a mock function for the type MockBreachedPasswordChecker
+func (_mock *MockBreachedPasswordChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	ret := _mock.Called(ctx, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsBreached")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, password)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBreachedPasswordChecker_IsBreached_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsBreached'
+type MockBreachedPasswordChecker_IsBreached_Call struct {
+	*mock.Call
+}
+
+// IsBreached is a helper method to define mock.On call
+//   - ctx
+//   - password
+func (_e *MockBreachedPasswordChecker_Expecter) IsBreached(ctx interface{}, password interface{}) *MockBreachedPasswordChecker_IsBreached_Call {
+	return &MockBreachedPasswordChecker_IsBreached_Call{Call: _e.mock.On("IsBreached", ctx, password)}
+}
+
+func (_c *MockBreachedPasswordChecker_IsBreached_Call) Run(run func(ctx context.Context, password string)) *MockBreachedPasswordChecker_IsBreached_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockBreachedPasswordChecker_IsBreached_Call) Return(b bool, err error) *MockBreachedPasswordChecker_IsBreached_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockBreachedPasswordChecker_IsBreached_Call) RunAndReturn(run func(ctx context.Context, password string) (bool, error)) *MockBreachedPasswordChecker_IsBreached_Call {
+	_c.Call.Return(run)
+	return _c
+}
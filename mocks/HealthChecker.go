@@ -0,0 +1,152 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockHealthChecker creates a new instance of MockHealthChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockHealthChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockHealthChecker {
+	mock := &MockHealthChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockHealthChecker is an autogenerated mock type for the HealthChecker type
+type MockHealthChecker struct {
+	mock.Mock
+}
+
+type MockHealthChecker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockHealthChecker) EXPECT() *MockHealthChecker_Expecter {
+	return &MockHealthChecker_Expecter{mock: &_m.Mock}
+}
+
+// Readiness provides a mock function for the type MockHealthChecker
+func (_mock *MockHealthChecker) Readiness(ctx context.Context) domain.HealthReport {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Readiness")
+	}
+
+	var r0 domain.HealthReport
+	if returnFunc, ok := ret.Get(0).(func(context.Context) domain.HealthReport); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(domain.HealthReport)
+	}
+	return r0
+}
+
+// MockHealthChecker_Readiness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Readiness'
+type MockHealthChecker_Readiness_Call struct {
+	*mock.Call
+}
+
+// Readiness is a helper method to define mock.On call
+//   - ctx
+func (_e *MockHealthChecker_Expecter) Readiness(ctx interface{}) *MockHealthChecker_Readiness_Call {
+	return &MockHealthChecker_Readiness_Call{Call: _e.mock.On("Readiness", ctx)}
+}
+
+func (_c *MockHealthChecker_Readiness_Call) Run(run func(ctx context.Context)) *MockHealthChecker_Readiness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockHealthChecker_Readiness_Call) Return(healthReport domain.HealthReport) *MockHealthChecker_Readiness_Call {
+	_c.Call.Return(healthReport)
+	return _c
+}
+
+func (_c *MockHealthChecker_Readiness_Call) RunAndReturn(run func(ctx context.Context) domain.HealthReport) *MockHealthChecker_Readiness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Register provides a mock function for the type MockHealthChecker
+func (_mock *MockHealthChecker) Register(name string, check domain.HealthCheck) {
+	_mock.Called(name, check)
+	return
+}
+
+// MockHealthChecker_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type MockHealthChecker_Register_Call struct {
+	*mock.Call
+}
+
+// Register is a helper method to define mock.On call
+//   - name
+//   - check
+func (_e *MockHealthChecker_Expecter) Register(name interface{}, check interface{}) *MockHealthChecker_Register_Call {
+	return &MockHealthChecker_Register_Call{Call: _e.mock.On("Register", name, check)}
+}
+
+func (_c *MockHealthChecker_Register_Call) Run(run func(name string, check domain.HealthCheck)) *MockHealthChecker_Register_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.HealthCheck))
+	})
+	return _c
+}
+
+func (_c *MockHealthChecker_Register_Call) Return() *MockHealthChecker_Register_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockHealthChecker_Register_Call) RunAndReturn(run func(name string, check domain.HealthCheck)) *MockHealthChecker_Register_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetShuttingDown provides a mock function for the type MockHealthChecker
+func (_mock *MockHealthChecker) SetShuttingDown() {
+	_mock.Called()
+	return
+}
+
+// MockHealthChecker_SetShuttingDown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetShuttingDown'
+type MockHealthChecker_SetShuttingDown_Call struct {
+	*mock.Call
+}
+
+// SetShuttingDown is a helper method to define mock.On call
+func (_e *MockHealthChecker_Expecter) SetShuttingDown() *MockHealthChecker_SetShuttingDown_Call {
+	return &MockHealthChecker_SetShuttingDown_Call{Call: _e.mock.On("SetShuttingDown")}
+}
+
+func (_c *MockHealthChecker_SetShuttingDown_Call) Run(run func()) *MockHealthChecker_SetShuttingDown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockHealthChecker_SetShuttingDown_Call) Return() *MockHealthChecker_SetShuttingDown_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockHealthChecker_SetShuttingDown_Call) RunAndReturn(run func()) *MockHealthChecker_SetShuttingDown_Call {
+	_c.Run(run)
+	return _c
+}
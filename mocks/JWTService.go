@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"task_manager/Domain"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,9 +38,65 @@ func (_m *MockJWTService) EXPECT() *MockJWTService_Expecter {
 	return &MockJWTService_Expecter{mock: &_m.Mock}
 }
 
+// GenerateMFAToken provides a mock function for the type MockJWTService
+func (_mock *MockJWTService) GenerateMFAToken(user *domain.User, tokenID string, expiresAt time.Time) (string, error) {
+	ret := _mock.Called(user, tokenID, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateMFAToken")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*domain.User, string, time.Time) (string, error)); ok {
+		return returnFunc(user, tokenID, expiresAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*domain.User, string, time.Time) string); ok {
+		r0 = returnFunc(user, tokenID, expiresAt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*domain.User, string, time.Time) error); ok {
+		r1 = returnFunc(user, tokenID, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJWTService_GenerateMFAToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateMFAToken'
+type MockJWTService_GenerateMFAToken_Call struct {
+	*mock.Call
+}
+
+// GenerateMFAToken is a helper method to define mock.On call
+//   - user
+//   - tokenID
+//   - expiresAt
+func (_e *MockJWTService_Expecter) GenerateMFAToken(user interface{}, tokenID interface{}, expiresAt interface{}) *MockJWTService_GenerateMFAToken_Call {
+	return &MockJWTService_GenerateMFAToken_Call{Call: _e.mock.On("GenerateMFAToken", user, tokenID, expiresAt)}
+}
+
+func (_c *MockJWTService_GenerateMFAToken_Call) Run(run func(user *domain.User, tokenID string, expiresAt time.Time)) *MockJWTService_GenerateMFAToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*domain.User), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockJWTService_GenerateMFAToken_Call) Return(s string, err error) *MockJWTService_GenerateMFAToken_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockJWTService_GenerateMFAToken_Call) RunAndReturn(run func(user *domain.User, tokenID string, expiresAt time.Time) (string, error)) *MockJWTService_GenerateMFAToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GenerateToken provides a mock function for the type MockJWTService
-func (_mock *MockJWTService) GenerateToken(username string, role string) (string, error) {
-	ret := _mock.Called(username, role)
+func (_mock *MockJWTService) GenerateToken(user *domain.User) (string, error) {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateToken")
@@ -47,16 +104,16 @@ func (_mock *MockJWTService) GenerateToken(username string, role string) (string
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, string) (string, error)); ok {
-		return returnFunc(username, role)
+	if returnFunc, ok := ret.Get(0).(func(*domain.User) (string, error)); ok {
+		return returnFunc(user)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = returnFunc(username, role)
+	if returnFunc, ok := ret.Get(0).(func(*domain.User) string); ok {
+		r0 = returnFunc(user)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = returnFunc(username, role)
+	if returnFunc, ok := ret.Get(1).(func(*domain.User) error); ok {
+		r1 = returnFunc(user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,15 +126,14 @@ type MockJWTService_GenerateToken_Call struct {
 }
 
 // GenerateToken is a helper method to define mock.On call
-//   - username
-//   - role
-func (_e *MockJWTService_Expecter) GenerateToken(username interface{}, role interface{}) *MockJWTService_GenerateToken_Call {
-	return &MockJWTService_GenerateToken_Call{Call: _e.mock.On("GenerateToken", username, role)}
+//   - user
+func (_e *MockJWTService_Expecter) GenerateToken(user interface{}) *MockJWTService_GenerateToken_Call {
+	return &MockJWTService_GenerateToken_Call{Call: _e.mock.On("GenerateToken", user)}
 }
 
-func (_c *MockJWTService_GenerateToken_Call) Run(run func(username string, role string)) *MockJWTService_GenerateToken_Call {
+func (_c *MockJWTService_GenerateToken_Call) Run(run func(user *domain.User)) *MockJWTService_GenerateToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(*domain.User))
 	})
 	return _c
 }
@@ -87,7 +143,63 @@ func (_c *MockJWTService_GenerateToken_Call) Return(s string, err error) *MockJW
 	return _c
 }
 
-func (_c *MockJWTService_GenerateToken_Call) RunAndReturn(run func(username string, role string) (string, error)) *MockJWTService_GenerateToken_Call {
+func (_c *MockJWTService_GenerateToken_Call) RunAndReturn(run func(user *domain.User) (string, error)) *MockJWTService_GenerateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateMFAToken provides a mock function for the type MockJWTService
+func (_mock *MockJWTService) ValidateMFAToken(token string) (*domain.CustomClaims, error) {
+	ret := _mock.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateMFAToken")
+	}
+
+	var r0 *domain.CustomClaims
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (*domain.CustomClaims, error)); ok {
+		return returnFunc(token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) *domain.CustomClaims); ok {
+		r0 = returnFunc(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CustomClaims)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJWTService_ValidateMFAToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateMFAToken'
+type MockJWTService_ValidateMFAToken_Call struct {
+	*mock.Call
+}
+
+// ValidateMFAToken is a helper method to define mock.On call
+//   - token
+func (_e *MockJWTService_Expecter) ValidateMFAToken(token interface{}) *MockJWTService_ValidateMFAToken_Call {
+	return &MockJWTService_ValidateMFAToken_Call{Call: _e.mock.On("ValidateMFAToken", token)}
+}
+
+func (_c *MockJWTService_ValidateMFAToken_Call) Run(run func(token string)) *MockJWTService_ValidateMFAToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockJWTService_ValidateMFAToken_Call) Return(customClaims *domain.CustomClaims, err error) *MockJWTService_ValidateMFAToken_Call {
+	_c.Call.Return(customClaims, err)
+	return _c
+}
+
+func (_c *MockJWTService_ValidateMFAToken_Call) RunAndReturn(run func(token string) (*domain.CustomClaims, error)) *MockJWTService_ValidateMFAToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,213 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockLoginThrottle creates a new instance of MockLoginThrottle. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginThrottle(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginThrottle {
+	mock := &MockLoginThrottle{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLoginThrottle is an autogenerated mock type for the LoginThrottle type
+type MockLoginThrottle struct {
+	mock.Mock
+}
+
+type MockLoginThrottle_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLoginThrottle) EXPECT() *MockLoginThrottle_Expecter {
+	return &MockLoginThrottle_Expecter{mock: &_m.Mock}
+}
+
+// RecordFailure provides a mock function for the type MockLoginThrottle
+func (_mock *MockLoginThrottle) RecordFailure(ctx context.Context, username string, clientIP string) (int, time.Time) {
+	ret := _mock.Called(ctx, username, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailure")
+	}
+
+	var r0 int
+	var r1 time.Time
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (int, time.Time)); ok {
+		return returnFunc(ctx, username, clientIP)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = returnFunc(ctx, username, clientIP)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) time.Time); ok {
+		r1 = returnFunc(ctx, username, clientIP)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+	return r0, r1
+}
+
+// MockLoginThrottle_RecordFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordFailure'
+type MockLoginThrottle_RecordFailure_Call struct {
+	*mock.Call
+}
+
+// RecordFailure is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - clientIP
+func (_e *MockLoginThrottle_Expecter) RecordFailure(ctx interface{}, username interface{}, clientIP interface{}) *MockLoginThrottle_RecordFailure_Call {
+	return &MockLoginThrottle_RecordFailure_Call{Call: _e.mock.On("RecordFailure", ctx, username, clientIP)}
+}
+
+func (_c *MockLoginThrottle_RecordFailure_Call) Run(run func(ctx context.Context, username string, clientIP string)) *MockLoginThrottle_RecordFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginThrottle_RecordFailure_Call) Return(n int, time1 time.Time) *MockLoginThrottle_RecordFailure_Call {
+	_c.Call.Return(n, time1)
+	return _c
+}
+
+func (_c *MockLoginThrottle_RecordFailure_Call) RunAndReturn(run func(ctx context.Context, username string, clientIP string) (int, time.Time)) *MockLoginThrottle_RecordFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordSuccess provides a mock function for the type MockLoginThrottle
+func (_mock *MockLoginThrottle) RecordSuccess(ctx context.Context, username string, clientIP string) {
+	_mock.Called(ctx, username, clientIP)
+	return
+}
+
+// MockLoginThrottle_RecordSuccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordSuccess'
+type MockLoginThrottle_RecordSuccess_Call struct {
+	*mock.Call
+}
+
+// RecordSuccess is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - clientIP
+func (_e *MockLoginThrottle_Expecter) RecordSuccess(ctx interface{}, username interface{}, clientIP interface{}) *MockLoginThrottle_RecordSuccess_Call {
+	return &MockLoginThrottle_RecordSuccess_Call{Call: _e.mock.On("RecordSuccess", ctx, username, clientIP)}
+}
+
+func (_c *MockLoginThrottle_RecordSuccess_Call) Run(run func(ctx context.Context, username string, clientIP string)) *MockLoginThrottle_RecordSuccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginThrottle_RecordSuccess_Call) Return() *MockLoginThrottle_RecordSuccess_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLoginThrottle_RecordSuccess_Call) RunAndReturn(run func(ctx context.Context, username string, clientIP string)) *MockLoginThrottle_RecordSuccess_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Reset provides a mock function for the type MockLoginThrottle
+func (_mock *MockLoginThrottle) Reset(ctx context.Context, username string) {
+	_mock.Called(ctx, username)
+	return
+}
+
+// MockLoginThrottle_Reset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reset'
+type MockLoginThrottle_Reset_Call struct {
+	*mock.Call
+}
+
+// Reset is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockLoginThrottle_Expecter) Reset(ctx interface{}, username interface{}) *MockLoginThrottle_Reset_Call {
+	return &MockLoginThrottle_Reset_Call{Call: _e.mock.On("Reset", ctx, username)}
+}
+
+func (_c *MockLoginThrottle_Reset_Call) Run(run func(ctx context.Context, username string)) *MockLoginThrottle_Reset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginThrottle_Reset_Call) Return() *MockLoginThrottle_Reset_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLoginThrottle_Reset_Call) RunAndReturn(run func(ctx context.Context, username string)) *MockLoginThrottle_Reset_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RetryAfter provides a mock function for the type MockLoginThrottle
+func (_mock *MockLoginThrottle) RetryAfter(ctx context.Context, username string, clientIP string) time.Duration {
+	ret := _mock.Called(ctx, username, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryAfter")
+	}
+
+	var r0 time.Duration
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) time.Duration); ok {
+		r0 = returnFunc(ctx, username, clientIP)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+	return r0
+}
+
+// MockLoginThrottle_RetryAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryAfter'
+type MockLoginThrottle_RetryAfter_Call struct {
+	*mock.Call
+}
+
+// RetryAfter is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - clientIP
+func (_e *MockLoginThrottle_Expecter) RetryAfter(ctx interface{}, username interface{}, clientIP interface{}) *MockLoginThrottle_RetryAfter_Call {
+	return &MockLoginThrottle_RetryAfter_Call{Call: _e.mock.On("RetryAfter", ctx, username, clientIP)}
+}
+
+func (_c *MockLoginThrottle_RetryAfter_Call) Run(run func(ctx context.Context, username string, clientIP string)) *MockLoginThrottle_RetryAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginThrottle_RetryAfter_Call) Return(duration time.Duration) *MockLoginThrottle_RetryAfter_Call {
+	_c.Call.Return(duration)
+	return _c
+}
+
+func (_c *MockLoginThrottle_RetryAfter_Call) RunAndReturn(run func(ctx context.Context, username string, clientIP string) time.Duration) *MockLoginThrottle_RetryAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,141 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockMFAPolicyRepository creates a new instance of MockMFAPolicyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMFAPolicyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMFAPolicyRepository {
+	mock := &MockMFAPolicyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMFAPolicyRepository is an autogenerated mock type for the MFAPolicyRepository type
+type MockMFAPolicyRepository struct {
+	mock.Mock
+}
+
+type MockMFAPolicyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMFAPolicyRepository) EXPECT() *MockMFAPolicyRepository_Expecter {
+	return &MockMFAPolicyRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetMFAPolicy provides a mock function for the type MockMFAPolicyRepository
+func (_mock *MockMFAPolicyRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMFAPolicy")
+	}
+
+	var r0 *domain.MFAPolicy
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*domain.MFAPolicy, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *domain.MFAPolicy); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MFAPolicy)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMFAPolicyRepository_GetMFAPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMFAPolicy'
+type MockMFAPolicyRepository_GetMFAPolicy_Call struct {
+	*mock.Call
+}
+
+// GetMFAPolicy is a helper method to define mock.On call
+//   - ctx
+func (_e *MockMFAPolicyRepository_Expecter) GetMFAPolicy(ctx interface{}) *MockMFAPolicyRepository_GetMFAPolicy_Call {
+	return &MockMFAPolicyRepository_GetMFAPolicy_Call{Call: _e.mock.On("GetMFAPolicy", ctx)}
+}
+
+func (_c *MockMFAPolicyRepository_GetMFAPolicy_Call) Run(run func(ctx context.Context)) *MockMFAPolicyRepository_GetMFAPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockMFAPolicyRepository_GetMFAPolicy_Call) Return(mFAPolicy *domain.MFAPolicy, err error) *MockMFAPolicyRepository_GetMFAPolicy_Call {
+	_c.Call.Return(mFAPolicy, err)
+	return _c
+}
+
+func (_c *MockMFAPolicyRepository_GetMFAPolicy_Call) RunAndReturn(run func(ctx context.Context) (*domain.MFAPolicy, error)) *MockMFAPolicyRepository_GetMFAPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMFAPolicy provides a mock function for the type MockMFAPolicyRepository
+func (_mock *MockMFAPolicyRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	ret := _mock.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMFAPolicy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.MFAPolicy) error); ok {
+		r0 = returnFunc(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMFAPolicyRepository_SaveMFAPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMFAPolicy'
+type MockMFAPolicyRepository_SaveMFAPolicy_Call struct {
+	*mock.Call
+}
+
+// SaveMFAPolicy is a helper method to define mock.On call
+//   - ctx
+//   - policy
+func (_e *MockMFAPolicyRepository_Expecter) SaveMFAPolicy(ctx interface{}, policy interface{}) *MockMFAPolicyRepository_SaveMFAPolicy_Call {
+	return &MockMFAPolicyRepository_SaveMFAPolicy_Call{Call: _e.mock.On("SaveMFAPolicy", ctx, policy)}
+}
+
+func (_c *MockMFAPolicyRepository_SaveMFAPolicy_Call) Run(run func(ctx context.Context, policy *domain.MFAPolicy)) *MockMFAPolicyRepository_SaveMFAPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.MFAPolicy))
+	})
+	return _c
+}
+
+func (_c *MockMFAPolicyRepository_SaveMFAPolicy_Call) Return(err error) *MockMFAPolicyRepository_SaveMFAPolicy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMFAPolicyRepository_SaveMFAPolicy_Call) RunAndReturn(run func(ctx context.Context, policy *domain.MFAPolicy) error) *MockMFAPolicyRepository_SaveMFAPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,418 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockMFAUsecase creates a new instance of MockMFAUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMFAUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMFAUsecase {
+	mock := &MockMFAUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMFAUsecase is an autogenerated mock type for the MFAUsecase type
+type MockMFAUsecase struct {
+	mock.Mock
+}
+
+type MockMFAUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMFAUsecase) EXPECT() *MockMFAUsecase_Expecter {
+	return &MockMFAUsecase_Expecter{mock: &_m.Mock}
+}
+
+// BeginEnrollment provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) BeginEnrollment(ctx context.Context, username string) (*domain.TOTPEnrollment, error) {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginEnrollment")
+	}
+
+	var r0 *domain.TOTPEnrollment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.TOTPEnrollment, error)); ok {
+		return returnFunc(ctx, username)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.TOTPEnrollment); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TOTPEnrollment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMFAUsecase_BeginEnrollment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginEnrollment'
+type MockMFAUsecase_BeginEnrollment_Call struct {
+	*mock.Call
+}
+
+// BeginEnrollment is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockMFAUsecase_Expecter) BeginEnrollment(ctx interface{}, username interface{}) *MockMFAUsecase_BeginEnrollment_Call {
+	return &MockMFAUsecase_BeginEnrollment_Call{Call: _e.mock.On("BeginEnrollment", ctx, username)}
+}
+
+func (_c *MockMFAUsecase_BeginEnrollment_Call) Run(run func(ctx context.Context, username string)) *MockMFAUsecase_BeginEnrollment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_BeginEnrollment_Call) Return(tOTPEnrollment *domain.TOTPEnrollment, err error) *MockMFAUsecase_BeginEnrollment_Call {
+	_c.Call.Return(tOTPEnrollment, err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_BeginEnrollment_Call) RunAndReturn(run func(ctx context.Context, username string) (*domain.TOTPEnrollment, error)) *MockMFAUsecase_BeginEnrollment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteLogin provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) CompleteLogin(ctx context.Context, mfaToken string, code string) (string, error) {
+	ret := _mock.Called(ctx, mfaToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteLogin")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return returnFunc(ctx, mfaToken, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = returnFunc(ctx, mfaToken, code)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, mfaToken, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMFAUsecase_CompleteLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteLogin'
+type MockMFAUsecase_CompleteLogin_Call struct {
+	*mock.Call
+}
+
+// CompleteLogin is a helper method to define mock.On call
+//   - ctx
+//   - mfaToken
+//   - code
+func (_e *MockMFAUsecase_Expecter) CompleteLogin(ctx interface{}, mfaToken interface{}, code interface{}) *MockMFAUsecase_CompleteLogin_Call {
+	return &MockMFAUsecase_CompleteLogin_Call{Call: _e.mock.On("CompleteLogin", ctx, mfaToken, code)}
+}
+
+func (_c *MockMFAUsecase_CompleteLogin_Call) Run(run func(ctx context.Context, mfaToken string, code string)) *MockMFAUsecase_CompleteLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_CompleteLogin_Call) Return(s string, err error) *MockMFAUsecase_CompleteLogin_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_CompleteLogin_Call) RunAndReturn(run func(ctx context.Context, mfaToken string, code string) (string, error)) *MockMFAUsecase_CompleteLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmEnrollment provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) ConfirmEnrollment(ctx context.Context, username string, code string) ([]string, error) {
+	ret := _mock.Called(ctx, username, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEnrollment")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return returnFunc(ctx, username, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = returnFunc(ctx, username, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, username, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMFAUsecase_ConfirmEnrollment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmEnrollment'
+type MockMFAUsecase_ConfirmEnrollment_Call struct {
+	*mock.Call
+}
+
+// ConfirmEnrollment is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - code
+func (_e *MockMFAUsecase_Expecter) ConfirmEnrollment(ctx interface{}, username interface{}, code interface{}) *MockMFAUsecase_ConfirmEnrollment_Call {
+	return &MockMFAUsecase_ConfirmEnrollment_Call{Call: _e.mock.On("ConfirmEnrollment", ctx, username, code)}
+}
+
+func (_c *MockMFAUsecase_ConfirmEnrollment_Call) Run(run func(ctx context.Context, username string, code string)) *MockMFAUsecase_ConfirmEnrollment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_ConfirmEnrollment_Call) Return(strings []string, err error) *MockMFAUsecase_ConfirmEnrollment_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_ConfirmEnrollment_Call) RunAndReturn(run func(ctx context.Context, username string, code string) ([]string, error)) *MockMFAUsecase_ConfirmEnrollment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Disable provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) Disable(ctx context.Context, username string, password string, code string) error {
+	ret := _mock.Called(ctx, username, password, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, username, password, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMFAUsecase_Disable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Disable'
+type MockMFAUsecase_Disable_Call struct {
+	*mock.Call
+}
+
+// Disable is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - password
+//   - code
+func (_e *MockMFAUsecase_Expecter) Disable(ctx interface{}, username interface{}, password interface{}, code interface{}) *MockMFAUsecase_Disable_Call {
+	return &MockMFAUsecase_Disable_Call{Call: _e.mock.On("Disable", ctx, username, password, code)}
+}
+
+func (_c *MockMFAUsecase_Disable_Call) Run(run func(ctx context.Context, username string, password string, code string)) *MockMFAUsecase_Disable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_Disable_Call) Return(err error) *MockMFAUsecase_Disable_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_Disable_Call) RunAndReturn(run func(ctx context.Context, username string, password string, code string) error) *MockMFAUsecase_Disable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPolicy provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) GetPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicy")
+	}
+
+	var r0 *domain.MFAPolicy
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*domain.MFAPolicy, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *domain.MFAPolicy); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MFAPolicy)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMFAUsecase_GetPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPolicy'
+type MockMFAUsecase_GetPolicy_Call struct {
+	*mock.Call
+}
+
+// GetPolicy is a helper method to define mock.On call
+//   - ctx
+func (_e *MockMFAUsecase_Expecter) GetPolicy(ctx interface{}) *MockMFAUsecase_GetPolicy_Call {
+	return &MockMFAUsecase_GetPolicy_Call{Call: _e.mock.On("GetPolicy", ctx)}
+}
+
+func (_c *MockMFAUsecase_GetPolicy_Call) Run(run func(ctx context.Context)) *MockMFAUsecase_GetPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_GetPolicy_Call) Return(mFAPolicy *domain.MFAPolicy, err error) *MockMFAUsecase_GetPolicy_Call {
+	_c.Call.Return(mFAPolicy, err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_GetPolicy_Call) RunAndReturn(run func(ctx context.Context) (*domain.MFAPolicy, error)) *MockMFAUsecase_GetPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegenerateRecoveryCodes provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) RegenerateRecoveryCodes(ctx context.Context, username string, code string) ([]string, error) {
+	ret := _mock.Called(ctx, username, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return returnFunc(ctx, username, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = returnFunc(ctx, username, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, username, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMFAUsecase_RegenerateRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegenerateRecoveryCodes'
+type MockMFAUsecase_RegenerateRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// RegenerateRecoveryCodes is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - code
+func (_e *MockMFAUsecase_Expecter) RegenerateRecoveryCodes(ctx interface{}, username interface{}, code interface{}) *MockMFAUsecase_RegenerateRecoveryCodes_Call {
+	return &MockMFAUsecase_RegenerateRecoveryCodes_Call{Call: _e.mock.On("RegenerateRecoveryCodes", ctx, username, code)}
+}
+
+func (_c *MockMFAUsecase_RegenerateRecoveryCodes_Call) Run(run func(ctx context.Context, username string, code string)) *MockMFAUsecase_RegenerateRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_RegenerateRecoveryCodes_Call) Return(strings []string, err error) *MockMFAUsecase_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_RegenerateRecoveryCodes_Call) RunAndReturn(run func(ctx context.Context, username string, code string) ([]string, error)) *MockMFAUsecase_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPolicy provides a mock function for the type MockMFAUsecase
+func (_mock *MockMFAUsecase) SetPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	ret := _mock.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPolicy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.MFAPolicy) error); ok {
+		r0 = returnFunc(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMFAUsecase_SetPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPolicy'
+type MockMFAUsecase_SetPolicy_Call struct {
+	*mock.Call
+}
+
+// SetPolicy is a helper method to define mock.On call
+//   - ctx
+//   - policy
+func (_e *MockMFAUsecase_Expecter) SetPolicy(ctx interface{}, policy interface{}) *MockMFAUsecase_SetPolicy_Call {
+	return &MockMFAUsecase_SetPolicy_Call{Call: _e.mock.On("SetPolicy", ctx, policy)}
+}
+
+func (_c *MockMFAUsecase_SetPolicy_Call) Run(run func(ctx context.Context, policy *domain.MFAPolicy)) *MockMFAUsecase_SetPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.MFAPolicy))
+	})
+	return _c
+}
+
+func (_c *MockMFAUsecase_SetPolicy_Call) Return(err error) *MockMFAUsecase_SetPolicy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMFAUsecase_SetPolicy_Call) RunAndReturn(run func(ctx context.Context, policy *domain.MFAPolicy) error) *MockMFAUsecase_SetPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockOIDCProvider creates a new instance of MockOIDCProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOIDCProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOIDCProvider {
+	mock := &MockOIDCProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockOIDCProvider is an autogenerated mock type for the OIDCProvider type
+type MockOIDCProvider struct {
+	mock.Mock
+}
+
+type MockOIDCProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOIDCProvider) EXPECT() *MockOIDCProvider_Expecter {
+	return &MockOIDCProvider_Expecter{mock: &_m.Mock}
+}
+
+// AuthCodeURL provides a mock function for the type MockOIDCProvider
+func (_mock *MockOIDCProvider) AuthCodeURL(state string, nonce string, codeVerifier string) string {
+	ret := _mock.Called(state, nonce, codeVerifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthCodeURL")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = returnFunc(state, nonce, codeVerifier)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockOIDCProvider_AuthCodeURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthCodeURL'
+type MockOIDCProvider_AuthCodeURL_Call struct {
+	*mock.Call
+}
+
+// AuthCodeURL is a helper method to define mock.On call
+//   - state
+//   - nonce
+//   - codeVerifier
+func (_e *MockOIDCProvider_Expecter) AuthCodeURL(state interface{}, nonce interface{}, codeVerifier interface{}) *MockOIDCProvider_AuthCodeURL_Call {
+	return &MockOIDCProvider_AuthCodeURL_Call{Call: _e.mock.On("AuthCodeURL", state, nonce, codeVerifier)}
+}
+
+func (_c *MockOIDCProvider_AuthCodeURL_Call) Run(run func(state string, nonce string, codeVerifier string)) *MockOIDCProvider_AuthCodeURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCProvider_AuthCodeURL_Call) Return(s string) *MockOIDCProvider_AuthCodeURL_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockOIDCProvider_AuthCodeURL_Call) RunAndReturn(run func(state string, nonce string, codeVerifier string) string) *MockOIDCProvider_AuthCodeURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Exchange provides a mock function for the type MockOIDCProvider
+func (_mock *MockOIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*domain.OIDCIdentity, error) {
+	ret := _mock.Called(ctx, code, codeVerifier, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 *domain.OIDCIdentity
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.OIDCIdentity, error)); ok {
+		return returnFunc(ctx, code, codeVerifier, nonce)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.OIDCIdentity); ok {
+		r0 = returnFunc(ctx, code, codeVerifier, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCIdentity)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, code, codeVerifier, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOIDCProvider_Exchange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exchange'
+type MockOIDCProvider_Exchange_Call struct {
+	*mock.Call
+}
+
+// Exchange is a helper method to define mock.On call
+//   - ctx
+//   - code
+//   - codeVerifier
+//   - nonce
+func (_e *MockOIDCProvider_Expecter) Exchange(ctx interface{}, code interface{}, codeVerifier interface{}, nonce interface{}) *MockOIDCProvider_Exchange_Call {
+	return &MockOIDCProvider_Exchange_Call{Call: _e.mock.On("Exchange", ctx, code, codeVerifier, nonce)}
+}
+
+func (_c *MockOIDCProvider_Exchange_Call) Run(run func(ctx context.Context, code string, codeVerifier string, nonce string)) *MockOIDCProvider_Exchange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCProvider_Exchange_Call) Return(oIDCIdentity *domain.OIDCIdentity, err error) *MockOIDCProvider_Exchange_Call {
+	_c.Call.Return(oIDCIdentity, err)
+	return _c
+}
+
+func (_c *MockOIDCProvider_Exchange_Call) RunAndReturn(run func(ctx context.Context, code string, codeVerifier string, nonce string) (*domain.OIDCIdentity, error)) *MockOIDCProvider_Exchange_Call {
+	_c.Call.Return(run)
+	return _c
+}
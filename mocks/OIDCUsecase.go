@@ -0,0 +1,151 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockOIDCUsecase creates a new instance of MockOIDCUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOIDCUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOIDCUsecase {
+	mock := &MockOIDCUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockOIDCUsecase is an autogenerated mock type for the OIDCUsecase type
+type MockOIDCUsecase struct {
+	mock.Mock
+}
+
+type MockOIDCUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOIDCUsecase) EXPECT() *MockOIDCUsecase_Expecter {
+	return &MockOIDCUsecase_Expecter{mock: &_m.Mock}
+}
+
+// BeginLogin provides a mock function for the type MockOIDCUsecase
+func (_mock *MockOIDCUsecase) BeginLogin(ctx context.Context) (string, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginLogin")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOIDCUsecase_BeginLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginLogin'
+type MockOIDCUsecase_BeginLogin_Call struct {
+	*mock.Call
+}
+
+// BeginLogin is a helper method to define mock.On call
+//   - ctx
+func (_e *MockOIDCUsecase_Expecter) BeginLogin(ctx interface{}) *MockOIDCUsecase_BeginLogin_Call {
+	return &MockOIDCUsecase_BeginLogin_Call{Call: _e.mock.On("BeginLogin", ctx)}
+}
+
+func (_c *MockOIDCUsecase_BeginLogin_Call) Run(run func(ctx context.Context)) *MockOIDCUsecase_BeginLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockOIDCUsecase_BeginLogin_Call) Return(s string, err error) *MockOIDCUsecase_BeginLogin_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockOIDCUsecase_BeginLogin_Call) RunAndReturn(run func(ctx context.Context) (string, error)) *MockOIDCUsecase_BeginLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteLogin provides a mock function for the type MockOIDCUsecase
+func (_mock *MockOIDCUsecase) CompleteLogin(ctx context.Context, state string, code string) (*domain.LoginResult, error) {
+	ret := _mock.Called(ctx, state, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteLogin")
+	}
+
+	var r0 *domain.LoginResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.LoginResult, error)); ok {
+		return returnFunc(ctx, state, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.LoginResult); ok {
+		r0 = returnFunc(ctx, state, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, state, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOIDCUsecase_CompleteLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteLogin'
+type MockOIDCUsecase_CompleteLogin_Call struct {
+	*mock.Call
+}
+
+// CompleteLogin is a helper method to define mock.On call
+//   - ctx
+//   - state
+//   - code
+func (_e *MockOIDCUsecase_Expecter) CompleteLogin(ctx interface{}, state interface{}, code interface{}) *MockOIDCUsecase_CompleteLogin_Call {
+	return &MockOIDCUsecase_CompleteLogin_Call{Call: _e.mock.On("CompleteLogin", ctx, state, code)}
+}
+
+func (_c *MockOIDCUsecase_CompleteLogin_Call) Run(run func(ctx context.Context, state string, code string)) *MockOIDCUsecase_CompleteLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCUsecase_CompleteLogin_Call) Return(loginResult *domain.LoginResult, err error) *MockOIDCUsecase_CompleteLogin_Call {
+	_c.Call.Return(loginResult, err)
+	return _c
+}
+
+func (_c *MockOIDCUsecase_CompleteLogin_Call) RunAndReturn(run func(ctx context.Context, state string, code string) (*domain.LoginResult, error)) *MockOIDCUsecase_CompleteLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
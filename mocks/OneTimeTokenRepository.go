@@ -0,0 +1,248 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockOneTimeTokenRepository creates a new instance of MockOneTimeTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOneTimeTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOneTimeTokenRepository {
+	mock := &MockOneTimeTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockOneTimeTokenRepository is an autogenerated mock type for the OneTimeTokenRepository type
+type MockOneTimeTokenRepository struct {
+	mock.Mock
+}
+
+type MockOneTimeTokenRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOneTimeTokenRepository) EXPECT() *MockOneTimeTokenRepository_Expecter {
+	return &MockOneTimeTokenRepository_Expecter{mock: &_m.Mock}
+}
+
+// ConsumeToken provides a mock function for the type MockOneTimeTokenRepository
+func (_mock *MockOneTimeTokenRepository) ConsumeToken(ctx context.Context, purpose string, tokenHash string) (*domain.OneTimeToken, error) {
+	ret := _mock.Called(ctx, purpose, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeToken")
+	}
+
+	var r0 *domain.OneTimeToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.OneTimeToken, error)); ok {
+		return returnFunc(ctx, purpose, tokenHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.OneTimeToken); ok {
+		r0 = returnFunc(ctx, purpose, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OneTimeToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, purpose, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOneTimeTokenRepository_ConsumeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeToken'
+type MockOneTimeTokenRepository_ConsumeToken_Call struct {
+	*mock.Call
+}
+
+// ConsumeToken is a helper method to define mock.On call
+//   - ctx
+//   - purpose
+//   - tokenHash
+func (_e *MockOneTimeTokenRepository_Expecter) ConsumeToken(ctx interface{}, purpose interface{}, tokenHash interface{}) *MockOneTimeTokenRepository_ConsumeToken_Call {
+	return &MockOneTimeTokenRepository_ConsumeToken_Call{Call: _e.mock.On("ConsumeToken", ctx, purpose, tokenHash)}
+}
+
+func (_c *MockOneTimeTokenRepository_ConsumeToken_Call) Run(run func(ctx context.Context, purpose string, tokenHash string)) *MockOneTimeTokenRepository_ConsumeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_ConsumeToken_Call) Return(oneTimeToken *domain.OneTimeToken, err error) *MockOneTimeTokenRepository_ConsumeToken_Call {
+	_c.Call.Return(oneTimeToken, err)
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_ConsumeToken_Call) RunAndReturn(run func(ctx context.Context, purpose string, tokenHash string) (*domain.OneTimeToken, error)) *MockOneTimeTokenRepository_ConsumeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateToken provides a mock function for the type MockOneTimeTokenRepository
+func (_mock *MockOneTimeTokenRepository) CreateToken(ctx context.Context, token *domain.OneTimeToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.OneTimeToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockOneTimeTokenRepository_CreateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateToken'
+type MockOneTimeTokenRepository_CreateToken_Call struct {
+	*mock.Call
+}
+
+// CreateToken is a helper method to define mock.On call
+//   - ctx
+//   - token
+func (_e *MockOneTimeTokenRepository_Expecter) CreateToken(ctx interface{}, token interface{}) *MockOneTimeTokenRepository_CreateToken_Call {
+	return &MockOneTimeTokenRepository_CreateToken_Call{Call: _e.mock.On("CreateToken", ctx, token)}
+}
+
+func (_c *MockOneTimeTokenRepository_CreateToken_Call) Run(run func(ctx context.Context, token *domain.OneTimeToken)) *MockOneTimeTokenRepository_CreateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.OneTimeToken))
+	})
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_CreateToken_Call) Return(err error) *MockOneTimeTokenRepository_CreateToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_CreateToken_Call) RunAndReturn(run func(ctx context.Context, token *domain.OneTimeToken) error) *MockOneTimeTokenRepository_CreateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTokensForUser provides a mock function for the type MockOneTimeTokenRepository
+func (_mock *MockOneTimeTokenRepository) DeleteTokensForUser(ctx context.Context, username string, purpose string) error {
+	ret := _mock.Called(ctx, username, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTokensForUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, username, purpose)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockOneTimeTokenRepository_DeleteTokensForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTokensForUser'
+type MockOneTimeTokenRepository_DeleteTokensForUser_Call struct {
+	*mock.Call
+}
+
+// DeleteTokensForUser is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - purpose
+func (_e *MockOneTimeTokenRepository_Expecter) DeleteTokensForUser(ctx interface{}, username interface{}, purpose interface{}) *MockOneTimeTokenRepository_DeleteTokensForUser_Call {
+	return &MockOneTimeTokenRepository_DeleteTokensForUser_Call{Call: _e.mock.On("DeleteTokensForUser", ctx, username, purpose)}
+}
+
+func (_c *MockOneTimeTokenRepository_DeleteTokensForUser_Call) Run(run func(ctx context.Context, username string, purpose string)) *MockOneTimeTokenRepository_DeleteTokensForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_DeleteTokensForUser_Call) Return(err error) *MockOneTimeTokenRepository_DeleteTokensForUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_DeleteTokensForUser_Call) RunAndReturn(run func(ctx context.Context, username string, purpose string) error) *MockOneTimeTokenRepository_DeleteTokensForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindToken provides a mock function for the type MockOneTimeTokenRepository
+func (_mock *MockOneTimeTokenRepository) FindToken(ctx context.Context, purpose string, tokenHash string) (*domain.OneTimeToken, error) {
+	ret := _mock.Called(ctx, purpose, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindToken")
+	}
+
+	var r0 *domain.OneTimeToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.OneTimeToken, error)); ok {
+		return returnFunc(ctx, purpose, tokenHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.OneTimeToken); ok {
+		r0 = returnFunc(ctx, purpose, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OneTimeToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, purpose, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOneTimeTokenRepository_FindToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindToken'
+type MockOneTimeTokenRepository_FindToken_Call struct {
+	*mock.Call
+}
+
+// FindToken is a helper method to define mock.On call
+//   - ctx
+//   - purpose
+//   - tokenHash
+func (_e *MockOneTimeTokenRepository_Expecter) FindToken(ctx interface{}, purpose interface{}, tokenHash interface{}) *MockOneTimeTokenRepository_FindToken_Call {
+	return &MockOneTimeTokenRepository_FindToken_Call{Call: _e.mock.On("FindToken", ctx, purpose, tokenHash)}
+}
+
+func (_c *MockOneTimeTokenRepository_FindToken_Call) Run(run func(ctx context.Context, purpose string, tokenHash string)) *MockOneTimeTokenRepository_FindToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_FindToken_Call) Return(oneTimeToken *domain.OneTimeToken, err error) *MockOneTimeTokenRepository_FindToken_Call {
+	_c.Call.Return(oneTimeToken, err)
+	return _c
+}
+
+func (_c *MockOneTimeTokenRepository_FindToken_Call) RunAndReturn(run func(ctx context.Context, purpose string, tokenHash string) (*domain.OneTimeToken, error)) *MockOneTimeTokenRepository_FindToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
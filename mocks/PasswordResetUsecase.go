@@ -0,0 +1,165 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockPasswordResetUsecase creates a new instance of MockPasswordResetUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasswordResetUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasswordResetUsecase {
+	mock := &MockPasswordResetUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPasswordResetUsecase is an autogenerated mock type for the PasswordResetUsecase type
+type MockPasswordResetUsecase struct {
+	mock.Mock
+}
+
+type MockPasswordResetUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPasswordResetUsecase) EXPECT() *MockPasswordResetUsecase_Expecter {
+	return &MockPasswordResetUsecase_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function for the type MockPasswordResetUsecase
+func (_mock *MockPasswordResetUsecase) Close(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordResetUsecase_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockPasswordResetUsecase_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+//   - ctx
+func (_e *MockPasswordResetUsecase_Expecter) Close(ctx interface{}) *MockPasswordResetUsecase_Close_Call {
+	return &MockPasswordResetUsecase_Close_Call{Call: _e.mock.On("Close", ctx)}
+}
+
+func (_c *MockPasswordResetUsecase_Close_Call) Run(run func(ctx context.Context)) *MockPasswordResetUsecase_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockPasswordResetUsecase_Close_Call) Return(err error) *MockPasswordResetUsecase_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordResetUsecase_Close_Call) RunAndReturn(run func(ctx context.Context) error) *MockPasswordResetUsecase_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestReset provides a mock function for the type MockPasswordResetUsecase
+func (_mock *MockPasswordResetUsecase) RequestReset(ctx context.Context, username string) {
+	_mock.Called(ctx, username)
+	return
+}
+
+// MockPasswordResetUsecase_RequestReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestReset'
+type MockPasswordResetUsecase_RequestReset_Call struct {
+	*mock.Call
+}
+
+// RequestReset is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockPasswordResetUsecase_Expecter) RequestReset(ctx interface{}, username interface{}) *MockPasswordResetUsecase_RequestReset_Call {
+	return &MockPasswordResetUsecase_RequestReset_Call{Call: _e.mock.On("RequestReset", ctx, username)}
+}
+
+func (_c *MockPasswordResetUsecase_RequestReset_Call) Run(run func(ctx context.Context, username string)) *MockPasswordResetUsecase_RequestReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordResetUsecase_RequestReset_Call) Return() *MockPasswordResetUsecase_RequestReset_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockPasswordResetUsecase_RequestReset_Call) RunAndReturn(run func(ctx context.Context, username string)) *MockPasswordResetUsecase_RequestReset_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type MockPasswordResetUsecase
+func (_mock *MockPasswordResetUsecase) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	ret := _mock.Called(ctx, token, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, token, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordResetUsecase_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockPasswordResetUsecase_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx
+//   - token
+//   - newPassword
+func (_e *MockPasswordResetUsecase_Expecter) ResetPassword(ctx interface{}, token interface{}, newPassword interface{}) *MockPasswordResetUsecase_ResetPassword_Call {
+	return &MockPasswordResetUsecase_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, token, newPassword)}
+}
+
+func (_c *MockPasswordResetUsecase_ResetPassword_Call) Run(run func(ctx context.Context, token string, newPassword string)) *MockPasswordResetUsecase_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordResetUsecase_ResetPassword_Call) Return(err error) *MockPasswordResetUsecase_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordResetUsecase_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, token string, newPassword string) error) *MockPasswordResetUsecase_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -134,3 +134,48 @@ func (_c *MockPasswordService_HashPassword_Call) RunAndReturn(run func(password
 	_c.Call.Return(run)
 	return _c
 }
+
+// NeedsRehash provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) NeedsRehash(hashedPassword string) bool {
+	ret := _mock.Called(hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NeedsRehash")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = returnFunc(hashedPassword)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockPasswordService_NeedsRehash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NeedsRehash'
+type MockPasswordService_NeedsRehash_Call struct {
+	*mock.Call
+}
+
+// NeedsRehash is a helper method to define mock.On call
+//   - hashedPassword
+func (_e *MockPasswordService_Expecter) NeedsRehash(hashedPassword interface{}) *MockPasswordService_NeedsRehash_Call {
+	return &MockPasswordService_NeedsRehash_Call{Call: _e.mock.On("NeedsRehash", hashedPassword)}
+}
+
+func (_c *MockPasswordService_NeedsRehash_Call) Run(run func(hashedPassword string)) *MockPasswordService_NeedsRehash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_NeedsRehash_Call) Return(b bool) *MockPasswordService_NeedsRehash_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockPasswordService_NeedsRehash_Call) RunAndReturn(run func(hashedPassword string) bool) *MockPasswordService_NeedsRehash_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockPasswordValidator creates a new instance of MockPasswordValidator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasswordValidator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasswordValidator {
+	mock := &MockPasswordValidator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPasswordValidator is an autogenerated mock type for the PasswordValidator type
+type MockPasswordValidator struct {
+	mock.Mock
+}
+
+type MockPasswordValidator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPasswordValidator) EXPECT() *MockPasswordValidator_Expecter {
+	return &MockPasswordValidator_Expecter{mock: &_m.Mock}
+}
+
+// Validate provides a mock function for the type MockPasswordValidator
+func (_mock *MockPasswordValidator) Validate(ctx context.Context, password string, username string) error {
+	ret := _mock.Called(ctx, password, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, password, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordValidator_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type MockPasswordValidator_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - ctx
+//   - password
+//   - username
+func (_e *MockPasswordValidator_Expecter) Validate(ctx interface{}, password interface{}, username interface{}) *MockPasswordValidator_Validate_Call {
+	return &MockPasswordValidator_Validate_Call{Call: _e.mock.On("Validate", ctx, password, username)}
+}
+
+func (_c *MockPasswordValidator_Validate_Call) Run(run func(ctx context.Context, password string, username string)) *MockPasswordValidator_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordValidator_Validate_Call) Return(err error) *MockPasswordValidator_Validate_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordValidator_Validate_Call) RunAndReturn(run func(ctx context.Context, password string, username string) error) *MockPasswordValidator_Validate_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,340 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+	"time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockPersonalAccessTokenRepository creates a new instance of MockPersonalAccessTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPersonalAccessTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPersonalAccessTokenRepository {
+	mock := &MockPersonalAccessTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPersonalAccessTokenRepository is an autogenerated mock type for the PersonalAccessTokenRepository type
+type MockPersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+type MockPersonalAccessTokenRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPersonalAccessTokenRepository) EXPECT() *MockPersonalAccessTokenRepository_Expecter {
+	return &MockPersonalAccessTokenRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateToken provides a mock function for the type MockPersonalAccessTokenRepository
+func (_mock *MockPersonalAccessTokenRepository) CreateToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.PersonalAccessToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPersonalAccessTokenRepository_CreateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateToken'
+type MockPersonalAccessTokenRepository_CreateToken_Call struct {
+	*mock.Call
+}
+
+// CreateToken is a helper method to define mock.On call
+//   - ctx
+//   - token
+func (_e *MockPersonalAccessTokenRepository_Expecter) CreateToken(ctx interface{}, token interface{}) *MockPersonalAccessTokenRepository_CreateToken_Call {
+	return &MockPersonalAccessTokenRepository_CreateToken_Call{Call: _e.mock.On("CreateToken", ctx, token)}
+}
+
+func (_c *MockPersonalAccessTokenRepository_CreateToken_Call) Run(run func(ctx context.Context, token *domain.PersonalAccessToken)) *MockPersonalAccessTokenRepository_CreateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.PersonalAccessToken))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_CreateToken_Call) Return(err error) *MockPersonalAccessTokenRepository_CreateToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_CreateToken_Call) RunAndReturn(run func(ctx context.Context, token *domain.PersonalAccessToken) error) *MockPersonalAccessTokenRepository_CreateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteToken provides a mock function for the type MockPersonalAccessTokenRepository
+func (_mock *MockPersonalAccessTokenRepository) DeleteToken(ctx context.Context, username string, id string) error {
+	ret := _mock.Called(ctx, username, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, username, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPersonalAccessTokenRepository_DeleteToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteToken'
+type MockPersonalAccessTokenRepository_DeleteToken_Call struct {
+	*mock.Call
+}
+
+// DeleteToken is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - id
+func (_e *MockPersonalAccessTokenRepository_Expecter) DeleteToken(ctx interface{}, username interface{}, id interface{}) *MockPersonalAccessTokenRepository_DeleteToken_Call {
+	return &MockPersonalAccessTokenRepository_DeleteToken_Call{Call: _e.mock.On("DeleteToken", ctx, username, id)}
+}
+
+func (_c *MockPersonalAccessTokenRepository_DeleteToken_Call) Run(run func(ctx context.Context, username string, id string)) *MockPersonalAccessTokenRepository_DeleteToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_DeleteToken_Call) Return(err error) *MockPersonalAccessTokenRepository_DeleteToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_DeleteToken_Call) RunAndReturn(run func(ctx context.Context, username string, id string) error) *MockPersonalAccessTokenRepository_DeleteToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTokensForUser provides a mock function for the type MockPersonalAccessTokenRepository
+func (_mock *MockPersonalAccessTokenRepository) DeleteTokensForUser(ctx context.Context, username string) error {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTokensForUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPersonalAccessTokenRepository_DeleteTokensForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTokensForUser'
+type MockPersonalAccessTokenRepository_DeleteTokensForUser_Call struct {
+	*mock.Call
+}
+
+// DeleteTokensForUser is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockPersonalAccessTokenRepository_Expecter) DeleteTokensForUser(ctx interface{}, username interface{}) *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call {
+	return &MockPersonalAccessTokenRepository_DeleteTokensForUser_Call{Call: _e.mock.On("DeleteTokensForUser", ctx, username)}
+}
+
+func (_c *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call) Run(run func(ctx context.Context, username string)) *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call) Return(err error) *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call) RunAndReturn(run func(ctx context.Context, username string) error) *MockPersonalAccessTokenRepository_DeleteTokensForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTokenByHash provides a mock function for the type MockPersonalAccessTokenRepository
+func (_mock *MockPersonalAccessTokenRepository) FindTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	ret := _mock.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTokenByHash")
+	}
+
+	var r0 *domain.PersonalAccessToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.PersonalAccessToken, error)); ok {
+		return returnFunc(ctx, tokenHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.PersonalAccessToken); ok {
+		r0 = returnFunc(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPersonalAccessTokenRepository_FindTokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTokenByHash'
+type MockPersonalAccessTokenRepository_FindTokenByHash_Call struct {
+	*mock.Call
+}
+
+// FindTokenByHash is a helper method to define mock.On call
+//   - ctx
+//   - tokenHash
+func (_e *MockPersonalAccessTokenRepository_Expecter) FindTokenByHash(ctx interface{}, tokenHash interface{}) *MockPersonalAccessTokenRepository_FindTokenByHash_Call {
+	return &MockPersonalAccessTokenRepository_FindTokenByHash_Call{Call: _e.mock.On("FindTokenByHash", ctx, tokenHash)}
+}
+
+func (_c *MockPersonalAccessTokenRepository_FindTokenByHash_Call) Run(run func(ctx context.Context, tokenHash string)) *MockPersonalAccessTokenRepository_FindTokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_FindTokenByHash_Call) Return(personalAccessToken *domain.PersonalAccessToken, err error) *MockPersonalAccessTokenRepository_FindTokenByHash_Call {
+	_c.Call.Return(personalAccessToken, err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_FindTokenByHash_Call) RunAndReturn(run func(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)) *MockPersonalAccessTokenRepository_FindTokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTokensForUser provides a mock function for the type MockPersonalAccessTokenRepository
+func (_mock *MockPersonalAccessTokenRepository) ListTokensForUser(ctx context.Context, username string) ([]domain.PersonalAccessToken, error) {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTokensForUser")
+	}
+
+	var r0 []domain.PersonalAccessToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]domain.PersonalAccessToken, error)); ok {
+		return returnFunc(ctx, username)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []domain.PersonalAccessToken); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPersonalAccessTokenRepository_ListTokensForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTokensForUser'
+type MockPersonalAccessTokenRepository_ListTokensForUser_Call struct {
+	*mock.Call
+}
+
+// ListTokensForUser is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockPersonalAccessTokenRepository_Expecter) ListTokensForUser(ctx interface{}, username interface{}) *MockPersonalAccessTokenRepository_ListTokensForUser_Call {
+	return &MockPersonalAccessTokenRepository_ListTokensForUser_Call{Call: _e.mock.On("ListTokensForUser", ctx, username)}
+}
+
+func (_c *MockPersonalAccessTokenRepository_ListTokensForUser_Call) Run(run func(ctx context.Context, username string)) *MockPersonalAccessTokenRepository_ListTokensForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_ListTokensForUser_Call) Return(personalAccessTokens []domain.PersonalAccessToken, err error) *MockPersonalAccessTokenRepository_ListTokensForUser_Call {
+	_c.Call.Return(personalAccessTokens, err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_ListTokensForUser_Call) RunAndReturn(run func(ctx context.Context, username string) ([]domain.PersonalAccessToken, error)) *MockPersonalAccessTokenRepository_ListTokensForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateLastUsed provides a mock function for the type MockPersonalAccessTokenRepository
+func (_mock *MockPersonalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	ret := _mock.Called(ctx, id, lastUsedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLastUsed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, lastUsedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPersonalAccessTokenRepository_UpdateLastUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateLastUsed'
+type MockPersonalAccessTokenRepository_UpdateLastUsed_Call struct {
+	*mock.Call
+}
+
+// UpdateLastUsed is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - lastUsedAt
+func (_e *MockPersonalAccessTokenRepository_Expecter) UpdateLastUsed(ctx interface{}, id interface{}, lastUsedAt interface{}) *MockPersonalAccessTokenRepository_UpdateLastUsed_Call {
+	return &MockPersonalAccessTokenRepository_UpdateLastUsed_Call{Call: _e.mock.On("UpdateLastUsed", ctx, id, lastUsedAt)}
+}
+
+func (_c *MockPersonalAccessTokenRepository_UpdateLastUsed_Call) Run(run func(ctx context.Context, id string, lastUsedAt time.Time)) *MockPersonalAccessTokenRepository_UpdateLastUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_UpdateLastUsed_Call) Return(err error) *MockPersonalAccessTokenRepository_UpdateLastUsed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenRepository_UpdateLastUsed_Call) RunAndReturn(run func(ctx context.Context, id string, lastUsedAt time.Time) error) *MockPersonalAccessTokenRepository_UpdateLastUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
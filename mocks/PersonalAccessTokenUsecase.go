@@ -0,0 +1,272 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockPersonalAccessTokenUsecase creates a new instance of MockPersonalAccessTokenUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPersonalAccessTokenUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPersonalAccessTokenUsecase {
+	mock := &MockPersonalAccessTokenUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPersonalAccessTokenUsecase is an autogenerated mock type for the PersonalAccessTokenUsecase type
+type MockPersonalAccessTokenUsecase struct {
+	mock.Mock
+}
+
+type MockPersonalAccessTokenUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPersonalAccessTokenUsecase) EXPECT() *MockPersonalAccessTokenUsecase_Expecter {
+	return &MockPersonalAccessTokenUsecase_Expecter{mock: &_m.Mock}
+}
+
+// Authenticate provides a mock function for the type MockPersonalAccessTokenUsecase
+func (_mock *MockPersonalAccessTokenUsecase) Authenticate(ctx context.Context, token string) (*domain.User, *domain.PersonalAccessToken, error) {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *domain.User
+	var r1 *domain.PersonalAccessToken
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.User, *domain.PersonalAccessToken, error)); ok {
+		return returnFunc(ctx, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) *domain.PersonalAccessToken); ok {
+		r1 = returnFunc(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = returnFunc(ctx, token)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockPersonalAccessTokenUsecase_Authenticate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authenticate'
+type MockPersonalAccessTokenUsecase_Authenticate_Call struct {
+	*mock.Call
+}
+
+// Authenticate is a helper method to define mock.On call
+//   - ctx
+//   - token
+func (_e *MockPersonalAccessTokenUsecase_Expecter) Authenticate(ctx interface{}, token interface{}) *MockPersonalAccessTokenUsecase_Authenticate_Call {
+	return &MockPersonalAccessTokenUsecase_Authenticate_Call{Call: _e.mock.On("Authenticate", ctx, token)}
+}
+
+func (_c *MockPersonalAccessTokenUsecase_Authenticate_Call) Run(run func(ctx context.Context, token string)) *MockPersonalAccessTokenUsecase_Authenticate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_Authenticate_Call) Return(user *domain.User, personalAccessToken *domain.PersonalAccessToken, err error) *MockPersonalAccessTokenUsecase_Authenticate_Call {
+	_c.Call.Return(user, personalAccessToken, err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_Authenticate_Call) RunAndReturn(run func(ctx context.Context, token string) (*domain.User, *domain.PersonalAccessToken, error)) *MockPersonalAccessTokenUsecase_Authenticate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateToken provides a mock function for the type MockPersonalAccessTokenUsecase
+func (_mock *MockPersonalAccessTokenUsecase) CreateToken(ctx context.Context, username string, request domain.CreatePersonalAccessTokenRequest) (*domain.PersonalAccessToken, string, error) {
+	ret := _mock.Called(ctx, username, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateToken")
+	}
+
+	var r0 *domain.PersonalAccessToken
+	var r1 string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.CreatePersonalAccessTokenRequest) (*domain.PersonalAccessToken, string, error)); ok {
+		return returnFunc(ctx, username, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.CreatePersonalAccessTokenRequest) *domain.PersonalAccessToken); ok {
+		r0 = returnFunc(ctx, username, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, domain.CreatePersonalAccessTokenRequest) string); ok {
+		r1 = returnFunc(ctx, username, request)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, domain.CreatePersonalAccessTokenRequest) error); ok {
+		r2 = returnFunc(ctx, username, request)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockPersonalAccessTokenUsecase_CreateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateToken'
+type MockPersonalAccessTokenUsecase_CreateToken_Call struct {
+	*mock.Call
+}
+
+// CreateToken is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - request
+func (_e *MockPersonalAccessTokenUsecase_Expecter) CreateToken(ctx interface{}, username interface{}, request interface{}) *MockPersonalAccessTokenUsecase_CreateToken_Call {
+	return &MockPersonalAccessTokenUsecase_CreateToken_Call{Call: _e.mock.On("CreateToken", ctx, username, request)}
+}
+
+func (_c *MockPersonalAccessTokenUsecase_CreateToken_Call) Run(run func(ctx context.Context, username string, request domain.CreatePersonalAccessTokenRequest)) *MockPersonalAccessTokenUsecase_CreateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.CreatePersonalAccessTokenRequest))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_CreateToken_Call) Return(personalAccessToken *domain.PersonalAccessToken, s string, err error) *MockPersonalAccessTokenUsecase_CreateToken_Call {
+	_c.Call.Return(personalAccessToken, s, err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_CreateToken_Call) RunAndReturn(run func(ctx context.Context, username string, request domain.CreatePersonalAccessTokenRequest) (*domain.PersonalAccessToken, string, error)) *MockPersonalAccessTokenUsecase_CreateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTokens provides a mock function for the type MockPersonalAccessTokenUsecase
+func (_mock *MockPersonalAccessTokenUsecase) ListTokens(ctx context.Context, username string) ([]domain.PersonalAccessToken, error) {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTokens")
+	}
+
+	var r0 []domain.PersonalAccessToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]domain.PersonalAccessToken, error)); ok {
+		return returnFunc(ctx, username)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []domain.PersonalAccessToken); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPersonalAccessTokenUsecase_ListTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTokens'
+type MockPersonalAccessTokenUsecase_ListTokens_Call struct {
+	*mock.Call
+}
+
+// ListTokens is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockPersonalAccessTokenUsecase_Expecter) ListTokens(ctx interface{}, username interface{}) *MockPersonalAccessTokenUsecase_ListTokens_Call {
+	return &MockPersonalAccessTokenUsecase_ListTokens_Call{Call: _e.mock.On("ListTokens", ctx, username)}
+}
+
+func (_c *MockPersonalAccessTokenUsecase_ListTokens_Call) Run(run func(ctx context.Context, username string)) *MockPersonalAccessTokenUsecase_ListTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_ListTokens_Call) Return(personalAccessTokens []domain.PersonalAccessToken, err error) *MockPersonalAccessTokenUsecase_ListTokens_Call {
+	_c.Call.Return(personalAccessTokens, err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_ListTokens_Call) RunAndReturn(run func(ctx context.Context, username string) ([]domain.PersonalAccessToken, error)) *MockPersonalAccessTokenUsecase_ListTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function for the type MockPersonalAccessTokenUsecase
+func (_mock *MockPersonalAccessTokenUsecase) RevokeToken(ctx context.Context, username string, id string) error {
+	ret := _mock.Called(ctx, username, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, username, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPersonalAccessTokenUsecase_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type MockPersonalAccessTokenUsecase_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - id
+func (_e *MockPersonalAccessTokenUsecase_Expecter) RevokeToken(ctx interface{}, username interface{}, id interface{}) *MockPersonalAccessTokenUsecase_RevokeToken_Call {
+	return &MockPersonalAccessTokenUsecase_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, username, id)}
+}
+
+func (_c *MockPersonalAccessTokenUsecase_RevokeToken_Call) Run(run func(ctx context.Context, username string, id string)) *MockPersonalAccessTokenUsecase_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_RevokeToken_Call) Return(err error) *MockPersonalAccessTokenUsecase_RevokeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPersonalAccessTokenUsecase_RevokeToken_Call) RunAndReturn(run func(ctx context.Context, username string, id string) error) *MockPersonalAccessTokenUsecase_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
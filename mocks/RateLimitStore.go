@@ -0,0 +1,97 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockRateLimitStore creates a new instance of MockRateLimitStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRateLimitStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRateLimitStore {
+	mock := &MockRateLimitStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRateLimitStore is an autogenerated mock type for the RateLimitStore type
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+type MockRateLimitStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRateLimitStore) EXPECT() *MockRateLimitStore_Expecter {
+	return &MockRateLimitStore_Expecter{mock: &_m.Mock}
+}
+
+// Take provides a mock function for the type MockRateLimitStore
+func (_mock *MockRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error) {
+	ret := _mock.Called(ctx, key, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Take")
+	}
+
+	var r0 *domain.RateLimitDecision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.RateLimit) (*domain.RateLimitDecision, error)); ok {
+		return returnFunc(ctx, key, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.RateLimit) *domain.RateLimitDecision); ok {
+		r0 = returnFunc(ctx, key, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RateLimitDecision)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, domain.RateLimit) error); ok {
+		r1 = returnFunc(ctx, key, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRateLimitStore_Take_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Take'
+type MockRateLimitStore_Take_Call struct {
+	*mock.Call
+}
+
+// Take is a helper method to define mock.On call
+//   - ctx
+//   - key
+//   - limit
+func (_e *MockRateLimitStore_Expecter) Take(ctx interface{}, key interface{}, limit interface{}) *MockRateLimitStore_Take_Call {
+	return &MockRateLimitStore_Take_Call{Call: _e.mock.On("Take", ctx, key, limit)}
+}
+
+func (_c *MockRateLimitStore_Take_Call) Run(run func(ctx context.Context, key string, limit domain.RateLimit)) *MockRateLimitStore_Take_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.RateLimit))
+	})
+	return _c
+}
+
+func (_c *MockRateLimitStore_Take_Call) Return(rateLimitDecision *domain.RateLimitDecision, err error) *MockRateLimitStore_Take_Call {
+	_c.Call.Return(rateLimitDecision, err)
+	return _c
+}
+
+func (_c *MockRateLimitStore_Take_Call) RunAndReturn(run func(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error)) *MockRateLimitStore_Take_Call {
+	_c.Call.Return(run)
+	return _c
+}
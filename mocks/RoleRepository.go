@@ -0,0 +1,198 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockRoleRepository creates a new instance of MockRoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRoleRepository {
+	mock := &MockRoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRoleRepository is an autogenerated mock type for the RoleRepository type
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+type MockRoleRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRoleRepository) EXPECT() *MockRoleRepository_Expecter {
+	return &MockRoleRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetRole provides a mock function for the type MockRoleRepository
+func (_mock *MockRoleRepository) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRole")
+	}
+
+	var r0 *domain.Role
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.Role, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.Role); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Role)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRoleRepository_GetRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRole'
+type MockRoleRepository_GetRole_Call struct {
+	*mock.Call
+}
+
+// GetRole is a helper method to define mock.On call
+//   - ctx
+//   - name
+func (_e *MockRoleRepository_Expecter) GetRole(ctx interface{}, name interface{}) *MockRoleRepository_GetRole_Call {
+	return &MockRoleRepository_GetRole_Call{Call: _e.mock.On("GetRole", ctx, name)}
+}
+
+func (_c *MockRoleRepository_GetRole_Call) Run(run func(ctx context.Context, name string)) *MockRoleRepository_GetRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_GetRole_Call) Return(role *domain.Role, err error) *MockRoleRepository_GetRole_Call {
+	_c.Call.Return(role, err)
+	return _c
+}
+
+func (_c *MockRoleRepository_GetRole_Call) RunAndReturn(run func(ctx context.Context, name string) (*domain.Role, error)) *MockRoleRepository_GetRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRoles provides a mock function for the type MockRoleRepository
+func (_mock *MockRoleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoles")
+	}
+
+	var r0 []domain.Role
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.Role, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.Role); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRoleRepository_ListRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRoles'
+type MockRoleRepository_ListRoles_Call struct {
+	*mock.Call
+}
+
+// ListRoles is a helper method to define mock.On call
+//   - ctx
+func (_e *MockRoleRepository_Expecter) ListRoles(ctx interface{}) *MockRoleRepository_ListRoles_Call {
+	return &MockRoleRepository_ListRoles_Call{Call: _e.mock.On("ListRoles", ctx)}
+}
+
+func (_c *MockRoleRepository_ListRoles_Call) Run(run func(ctx context.Context)) *MockRoleRepository_ListRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_ListRoles_Call) Return(roles []domain.Role, err error) *MockRoleRepository_ListRoles_Call {
+	_c.Call.Return(roles, err)
+	return _c
+}
+
+func (_c *MockRoleRepository_ListRoles_Call) RunAndReturn(run func(ctx context.Context) ([]domain.Role, error)) *MockRoleRepository_ListRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveRole provides a mock function for the type MockRoleRepository
+func (_mock *MockRoleRepository) SaveRole(ctx context.Context, role *domain.Role) error {
+	ret := _mock.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Role) error); ok {
+		r0 = returnFunc(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRoleRepository_SaveRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveRole'
+type MockRoleRepository_SaveRole_Call struct {
+	*mock.Call
+}
+
+// SaveRole is a helper method to define mock.On call
+//   - ctx
+//   - role
+func (_e *MockRoleRepository_Expecter) SaveRole(ctx interface{}, role interface{}) *MockRoleRepository_SaveRole_Call {
+	return &MockRoleRepository_SaveRole_Call{Call: _e.mock.On("SaveRole", ctx, role)}
+}
+
+func (_c *MockRoleRepository_SaveRole_Call) Run(run func(ctx context.Context, role *domain.Role)) *MockRoleRepository_SaveRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Role))
+	})
+	return _c
+}
+
+func (_c *MockRoleRepository_SaveRole_Call) Return(err error) *MockRoleRepository_SaveRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRoleRepository_SaveRole_Call) RunAndReturn(run func(ctx context.Context, role *domain.Role) error) *MockRoleRepository_SaveRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
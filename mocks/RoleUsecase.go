@@ -0,0 +1,188 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"context"
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockRoleUsecase creates a new instance of MockRoleUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRoleUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRoleUsecase {
+	mock := &MockRoleUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRoleUsecase is an autogenerated mock type for the RoleUsecase type
+type MockRoleUsecase struct {
+	mock.Mock
+}
+
+type MockRoleUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRoleUsecase) EXPECT() *MockRoleUsecase_Expecter {
+	return &MockRoleUsecase_Expecter{mock: &_m.Mock}
+}
+
+// AssignRole provides a mock function for the type MockRoleUsecase
+func (_mock *MockRoleUsecase) AssignRole(ctx context.Context, username string, role string) error {
+	ret := _mock.Called(ctx, username, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, username, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRoleUsecase_AssignRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AssignRole'
+type MockRoleUsecase_AssignRole_Call struct {
+	*mock.Call
+}
+
+// AssignRole is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - role
+func (_e *MockRoleUsecase_Expecter) AssignRole(ctx interface{}, username interface{}, role interface{}) *MockRoleUsecase_AssignRole_Call {
+	return &MockRoleUsecase_AssignRole_Call{Call: _e.mock.On("AssignRole", ctx, username, role)}
+}
+
+func (_c *MockRoleUsecase_AssignRole_Call) Run(run func(ctx context.Context, username string, role string)) *MockRoleUsecase_AssignRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockRoleUsecase_AssignRole_Call) Return(err error) *MockRoleUsecase_AssignRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRoleUsecase_AssignRole_Call) RunAndReturn(run func(ctx context.Context, username string, role string) error) *MockRoleUsecase_AssignRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRoles provides a mock function for the type MockRoleUsecase
+func (_mock *MockRoleUsecase) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoles")
+	}
+
+	var r0 []domain.Role
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.Role, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.Role); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRoleUsecase_ListRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRoles'
+type MockRoleUsecase_ListRoles_Call struct {
+	*mock.Call
+}
+
+// ListRoles is a helper method to define mock.On call
+//   - ctx
+func (_e *MockRoleUsecase_Expecter) ListRoles(ctx interface{}) *MockRoleUsecase_ListRoles_Call {
+	return &MockRoleUsecase_ListRoles_Call{Call: _e.mock.On("ListRoles", ctx)}
+}
+
+func (_c *MockRoleUsecase_ListRoles_Call) Run(run func(ctx context.Context)) *MockRoleUsecase_ListRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRoleUsecase_ListRoles_Call) Return(roles []domain.Role, err error) *MockRoleUsecase_ListRoles_Call {
+	_c.Call.Return(roles, err)
+	return _c
+}
+
+func (_c *MockRoleUsecase_ListRoles_Call) RunAndReturn(run func(ctx context.Context) ([]domain.Role, error)) *MockRoleUsecase_ListRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveRole provides a mock function for the type MockRoleUsecase
+func (_mock *MockRoleUsecase) SaveRole(ctx context.Context, role *domain.Role) error {
+	ret := _mock.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Role) error); ok {
+		r0 = returnFunc(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRoleUsecase_SaveRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveRole'
+type MockRoleUsecase_SaveRole_Call struct {
+	*mock.Call
+}
+
+// SaveRole is a helper method to define mock.On call
+//   - ctx
+//   - role
+func (_e *MockRoleUsecase_Expecter) SaveRole(ctx interface{}, role interface{}) *MockRoleUsecase_SaveRole_Call {
+	return &MockRoleUsecase_SaveRole_Call{Call: _e.mock.On("SaveRole", ctx, role)}
+}
+
+func (_c *MockRoleUsecase_SaveRole_Call) Run(run func(ctx context.Context, role *domain.Role)) *MockRoleUsecase_SaveRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Role))
+	})
+	return _c
+}
+
+func (_c *MockRoleUsecase_SaveRole_Call) Return(err error) *MockRoleUsecase_SaveRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRoleUsecase_SaveRole_Call) RunAndReturn(run func(ctx context.Context, role *domain.Role) error) *MockRoleUsecase_SaveRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mocks
+
+import (
+	"task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockTOTPService creates a new instance of MockTOTPService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTOTPService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTOTPService {
+	mock := &MockTOTPService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTOTPService is an autogenerated mock type for the TOTPService type
+type MockTOTPService struct {
+	mock.Mock
+}
+
+type MockTOTPService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTOTPService) EXPECT() *MockTOTPService_Expecter {
+	return &MockTOTPService_Expecter{mock: &_m.Mock}
+}
+
+// GenerateSecret provides a mock function for the type MockTOTPService
+func (_mock *MockTOTPService) GenerateSecret(accountName string) (*domain.TOTPEnrollment, error) {
+	ret := _mock.Called(accountName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateSecret")
+	}
+
+	var r0 *domain.TOTPEnrollment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (*domain.TOTPEnrollment, error)); ok {
+		return returnFunc(accountName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) *domain.TOTPEnrollment); ok {
+		r0 = returnFunc(accountName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TOTPEnrollment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(accountName)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTOTPService_GenerateSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateSecret'
+type MockTOTPService_GenerateSecret_Call struct {
+	*mock.Call
+}
+
+// GenerateSecret is a helper method to define mock.On call
+//   - accountName
+func (_e *MockTOTPService_Expecter) GenerateSecret(accountName interface{}) *MockTOTPService_GenerateSecret_Call {
+	return &MockTOTPService_GenerateSecret_Call{Call: _e.mock.On("GenerateSecret", accountName)}
+}
+
+func (_c *MockTOTPService_GenerateSecret_Call) Run(run func(accountName string)) *MockTOTPService_GenerateSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockTOTPService_GenerateSecret_Call) Return(tOTPEnrollment *domain.TOTPEnrollment, err error) *MockTOTPService_GenerateSecret_Call {
+	_c.Call.Return(tOTPEnrollment, err)
+	return _c
+}
+
+func (_c *MockTOTPService_GenerateSecret_Call) RunAndReturn(run func(accountName string) (*domain.TOTPEnrollment, error)) *MockTOTPService_GenerateSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateCode provides a mock function for the type MockTOTPService
+func (_mock *MockTOTPService) ValidateCode(secret string, code string) (int64, bool) {
+	ret := _mock.Called(secret, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateCode")
+	}
+
+	var r0 int64
+	var r1 bool
+	if returnFunc, ok := ret.Get(0).(func(string, string) (int64, bool)); ok {
+		return returnFunc(secret, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) int64); ok {
+		r0 = returnFunc(secret, code)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) bool); ok {
+		r1 = returnFunc(secret, code)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	return r0, r1
+}
+
+// MockTOTPService_ValidateCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateCode'
+type MockTOTPService_ValidateCode_Call struct {
+	*mock.Call
+}
+
+// ValidateCode is a helper method to define mock.On call
+//   - secret
+//   - code
+func (_e *MockTOTPService_Expecter) ValidateCode(secret interface{}, code interface{}) *MockTOTPService_ValidateCode_Call {
+	return &MockTOTPService_ValidateCode_Call{Call: _e.mock.On("ValidateCode", secret, code)}
+}
+
+func (_c *MockTOTPService_ValidateCode_Call) Run(run func(secret string, code string)) *MockTOTPService_ValidateCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTOTPService_ValidateCode_Call) Return(n int64, b bool) *MockTOTPService_ValidateCode_Call {
+	_c.Call.Return(n, b)
+	return _c
+}
+
+func (_c *MockTOTPService_ValidateCode_Call) RunAndReturn(run func(secret string, code string) (int64, bool)) *MockTOTPService_ValidateCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -9,7 +9,6 @@ import (
 	"task_manager/Domain"
 
 	mock "github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // NewMockTaskRepository creates a new instance of MockTaskRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -39,6 +38,120 @@ func (_m *MockTaskRepository) EXPECT() *MockTaskRepository_Expecter {
 	return &MockTaskRepository_Expecter{mock: &_m.Mock}
 }
 
+// BulkWriteTasks provides a mock function for the type MockTaskRepository
+func (_mock *MockTaskRepository) BulkWriteTasks(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool) ([]domain.BulkTaskResult, error) {
+	ret := _mock.Called(ctx, operations, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkWriteTasks")
+	}
+
+	var r0 []domain.BulkTaskResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.BulkTaskOperation, bool) ([]domain.BulkTaskResult, error)); ok {
+		return returnFunc(ctx, operations, atomic)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.BulkTaskOperation, bool) []domain.BulkTaskResult); ok {
+		r0 = returnFunc(ctx, operations, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.BulkTaskResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []domain.BulkTaskOperation, bool) error); ok {
+		r1 = returnFunc(ctx, operations, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTaskRepository_BulkWriteTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkWriteTasks'
+type MockTaskRepository_BulkWriteTasks_Call struct {
+	*mock.Call
+}
+
+// BulkWriteTasks is a helper method to define mock.On call
+//   - ctx
+//   - operations
+//   - atomic
+func (_e *MockTaskRepository_Expecter) BulkWriteTasks(ctx interface{}, operations interface{}, atomic interface{}) *MockTaskRepository_BulkWriteTasks_Call {
+	return &MockTaskRepository_BulkWriteTasks_Call{Call: _e.mock.On("BulkWriteTasks", ctx, operations, atomic)}
+}
+
+func (_c *MockTaskRepository_BulkWriteTasks_Call) Run(run func(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool)) *MockTaskRepository_BulkWriteTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]domain.BulkTaskOperation), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *MockTaskRepository_BulkWriteTasks_Call) Return(bulkTaskResults []domain.BulkTaskResult, err error) *MockTaskRepository_BulkWriteTasks_Call {
+	_c.Call.Return(bulkTaskResults, err)
+	return _c
+}
+
+func (_c *MockTaskRepository_BulkWriteTasks_Call) RunAndReturn(run func(ctx context.Context, operations []domain.BulkTaskOperation, atomic bool) ([]domain.BulkTaskResult, error)) *MockTaskRepository_BulkWriteTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountTasksByStatus provides a mock function for the type MockTaskRepository
+func (_mock *MockTaskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTasksByStatus")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTaskRepository_CountTasksByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountTasksByStatus'
+type MockTaskRepository_CountTasksByStatus_Call struct {
+	*mock.Call
+}
+
+// CountTasksByStatus is a helper method to define mock.On call
+//   - ctx
+func (_e *MockTaskRepository_Expecter) CountTasksByStatus(ctx interface{}) *MockTaskRepository_CountTasksByStatus_Call {
+	return &MockTaskRepository_CountTasksByStatus_Call{Call: _e.mock.On("CountTasksByStatus", ctx)}
+}
+
+func (_c *MockTaskRepository_CountTasksByStatus_Call) Run(run func(ctx context.Context)) *MockTaskRepository_CountTasksByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockTaskRepository_CountTasksByStatus_Call) Return(stringToInt64 map[string]int64, err error) *MockTaskRepository_CountTasksByStatus_Call {
+	_c.Call.Return(stringToInt64, err)
+	return _c
+}
+
+func (_c *MockTaskRepository_CountTasksByStatus_Call) RunAndReturn(run func(ctx context.Context) (map[string]int64, error)) *MockTaskRepository_CountTasksByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteTask provides a mock function for the type MockTaskRepository
 func (_mock *MockTaskRepository) DeleteTask(ctx context.Context, id string) error {
 	ret := _mock.Called(ctx, id)
@@ -85,6 +198,110 @@ func (_c *MockTaskRepository_DeleteTask_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// DeleteTasksByCreator provides a mock function for the type MockTaskRepository
+func (_mock *MockTaskRepository) DeleteTasksByCreator(ctx context.Context, username string) error {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTasksByCreator")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTaskRepository_DeleteTasksByCreator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTasksByCreator'
+type MockTaskRepository_DeleteTasksByCreator_Call struct {
+	*mock.Call
+}
+
+// DeleteTasksByCreator is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockTaskRepository_Expecter) DeleteTasksByCreator(ctx interface{}, username interface{}) *MockTaskRepository_DeleteTasksByCreator_Call {
+	return &MockTaskRepository_DeleteTasksByCreator_Call{Call: _e.mock.On("DeleteTasksByCreator", ctx, username)}
+}
+
+func (_c *MockTaskRepository_DeleteTasksByCreator_Call) Run(run func(ctx context.Context, username string)) *MockTaskRepository_DeleteTasksByCreator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTaskRepository_DeleteTasksByCreator_Call) Return(err error) *MockTaskRepository_DeleteTasksByCreator_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTaskRepository_DeleteTasksByCreator_Call) RunAndReturn(run func(ctx context.Context, username string) error) *MockTaskRepository_DeleteTasksByCreator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTasks provides a mock function for the type MockTaskRepository
+func (_mock *MockTaskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter, limit int) ([]domain.Task, error) {
+	ret := _mock.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.TaskFilter, int) ([]domain.Task, error)); ok {
+		return returnFunc(ctx, filter, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.TaskFilter, int) []domain.Task); ok {
+		r0 = returnFunc(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.TaskFilter, int) error); ok {
+		r1 = returnFunc(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTaskRepository_FindTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTasks'
+type MockTaskRepository_FindTasks_Call struct {
+	*mock.Call
+}
+
+// FindTasks is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - limit
+func (_e *MockTaskRepository_Expecter) FindTasks(ctx interface{}, filter interface{}, limit interface{}) *MockTaskRepository_FindTasks_Call {
+	return &MockTaskRepository_FindTasks_Call{Call: _e.mock.On("FindTasks", ctx, filter, limit)}
+}
+
+func (_c *MockTaskRepository_FindTasks_Call) Run(run func(ctx context.Context, filter domain.TaskFilter, limit int)) *MockTaskRepository_FindTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.TaskFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockTaskRepository_FindTasks_Call) Return(tasks []domain.Task, err error) *MockTaskRepository_FindTasks_Call {
+	_c.Call.Return(tasks, err)
+	return _c
+}
+
+func (_c *MockTaskRepository_FindTasks_Call) RunAndReturn(run func(ctx context.Context, filter domain.TaskFilter, limit int) ([]domain.Task, error)) *MockTaskRepository_FindTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetAllTask provides a mock function for the type MockTaskRepository
 func (_mock *MockTaskRepository) GetAllTask(ctx context.Context) ([]domain.Task, error) {
 	ret := _mock.Called(ctx)
@@ -197,24 +414,22 @@ func (_c *MockTaskRepository_GetTaskByID_Call) RunAndReturn(run func(ctx context
 }
 
 // NewTask provides a mock function for the type MockTaskRepository
-func (_mock *MockTaskRepository) NewTask(ctx context.Context, task domain.Task) (*mongo.InsertOneResult, error) {
+func (_mock *MockTaskRepository) NewTask(ctx context.Context, task domain.Task) (domain.Task, error) {
 	ret := _mock.Called(ctx, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewTask")
 	}
 
-	var r0 *mongo.InsertOneResult
+	var r0 domain.Task
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) (*mongo.InsertOneResult, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) (domain.Task, error)); ok {
 		return returnFunc(ctx, task)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) *mongo.InsertOneResult); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) domain.Task); ok {
 		r0 = returnFunc(ctx, task)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.InsertOneResult)
-		}
+		r0 = ret.Get(0).(domain.Task)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.Task) error); ok {
 		r1 = returnFunc(ctx, task)
@@ -243,12 +458,12 @@ func (_c *MockTaskRepository_NewTask_Call) Run(run func(ctx context.Context, tas
 	return _c
 }
 
-func (_c *MockTaskRepository_NewTask_Call) Return(insertOneResult *mongo.InsertOneResult, err error) *MockTaskRepository_NewTask_Call {
-	_c.Call.Return(insertOneResult, err)
+func (_c *MockTaskRepository_NewTask_Call) Return(task1 domain.Task, err error) *MockTaskRepository_NewTask_Call {
+	_c.Call.Return(task1, err)
 	return _c
 }
 
-func (_c *MockTaskRepository_NewTask_Call) RunAndReturn(run func(ctx context.Context, task domain.Task) (*mongo.InsertOneResult, error)) *MockTaskRepository_NewTask_Call {
+func (_c *MockTaskRepository_NewTask_Call) RunAndReturn(run func(ctx context.Context, task domain.Task) (domain.Task, error)) *MockTaskRepository_NewTask_Call {
 	_c.Call.Return(run)
 	return _c
 }
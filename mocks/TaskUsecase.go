@@ -9,7 +9,6 @@ import (
 	"task_manager/Domain"
 
 	mock "github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // NewMockTaskUsecase creates a new instance of MockTaskUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -39,17 +38,75 @@ func (_m *MockTaskUsecase) EXPECT() *MockTaskUsecase_Expecter {
 	return &MockTaskUsecase_Expecter{mock: &_m.Mock}
 }
 
+// BulkTasks provides a mock function for the type MockTaskUsecase
+func (_mock *MockTaskUsecase) BulkTasks(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal) ([]domain.BulkTaskResult, error) {
+	ret := _mock.Called(ctx, request, principal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkTasks")
+	}
+
+	var r0 []domain.BulkTaskResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.BulkTaskRequest, domain.Principal) ([]domain.BulkTaskResult, error)); ok {
+		return returnFunc(ctx, request, principal)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.BulkTaskRequest, domain.Principal) []domain.BulkTaskResult); ok {
+		r0 = returnFunc(ctx, request, principal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.BulkTaskResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.BulkTaskRequest, domain.Principal) error); ok {
+		r1 = returnFunc(ctx, request, principal)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTaskUsecase_BulkTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkTasks'
+type MockTaskUsecase_BulkTasks_Call struct {
+	*mock.Call
+}
+
+// BulkTasks is a helper method to define mock.On call
+//   - ctx
+//   - request
+//   - principal
+func (_e *MockTaskUsecase_Expecter) BulkTasks(ctx interface{}, request interface{}, principal interface{}) *MockTaskUsecase_BulkTasks_Call {
+	return &MockTaskUsecase_BulkTasks_Call{Call: _e.mock.On("BulkTasks", ctx, request, principal)}
+}
+
+func (_c *MockTaskUsecase_BulkTasks_Call) Run(run func(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal)) *MockTaskUsecase_BulkTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.BulkTaskRequest), args[2].(domain.Principal))
+	})
+	return _c
+}
+
+func (_c *MockTaskUsecase_BulkTasks_Call) Return(bulkTaskResults []domain.BulkTaskResult, err error) *MockTaskUsecase_BulkTasks_Call {
+	_c.Call.Return(bulkTaskResults, err)
+	return _c
+}
+
+func (_c *MockTaskUsecase_BulkTasks_Call) RunAndReturn(run func(ctx context.Context, request domain.BulkTaskRequest, principal domain.Principal) ([]domain.BulkTaskResult, error)) *MockTaskUsecase_BulkTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteTask provides a mock function for the type MockTaskUsecase
-func (_mock *MockTaskUsecase) DeleteTask(ctx context.Context, id string) error {
-	ret := _mock.Called(ctx, id)
+func (_mock *MockTaskUsecase) DeleteTask(ctx context.Context, id string, principal domain.Principal) error {
+	ret := _mock.Called(ctx, id, principal)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.Principal) error); ok {
+		r0 = returnFunc(ctx, id, principal)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -64,13 +121,14 @@ type MockTaskUsecase_DeleteTask_Call struct {
 // DeleteTask is a helper method to define mock.On call
 //   - ctx
 //   - id
-func (_e *MockTaskUsecase_Expecter) DeleteTask(ctx interface{}, id interface{}) *MockTaskUsecase_DeleteTask_Call {
-	return &MockTaskUsecase_DeleteTask_Call{Call: _e.mock.On("DeleteTask", ctx, id)}
+//   - principal
+func (_e *MockTaskUsecase_Expecter) DeleteTask(ctx interface{}, id interface{}, principal interface{}) *MockTaskUsecase_DeleteTask_Call {
+	return &MockTaskUsecase_DeleteTask_Call{Call: _e.mock.On("DeleteTask", ctx, id, principal)}
 }
 
-func (_c *MockTaskUsecase_DeleteTask_Call) Run(run func(ctx context.Context, id string)) *MockTaskUsecase_DeleteTask_Call {
+func (_c *MockTaskUsecase_DeleteTask_Call) Run(run func(ctx context.Context, id string, principal domain.Principal)) *MockTaskUsecase_DeleteTask_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.Principal))
 	})
 	return _c
 }
@@ -80,7 +138,7 @@ func (_c *MockTaskUsecase_DeleteTask_Call) Return(err error) *MockTaskUsecase_De
 	return _c
 }
 
-func (_c *MockTaskUsecase_DeleteTask_Call) RunAndReturn(run func(ctx context.Context, id string) error) *MockTaskUsecase_DeleteTask_Call {
+func (_c *MockTaskUsecase_DeleteTask_Call) RunAndReturn(run func(ctx context.Context, id string, principal domain.Principal) error) *MockTaskUsecase_DeleteTask_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -197,24 +255,22 @@ func (_c *MockTaskUsecase_GetTaskByID_Call) RunAndReturn(run func(ctx context.Co
 }
 
 // NewTask provides a mock function for the type MockTaskUsecase
-func (_mock *MockTaskUsecase) NewTask(ctx context.Context, task domain.Task) (*mongo.InsertOneResult, error) {
+func (_mock *MockTaskUsecase) NewTask(ctx context.Context, task domain.Task) (domain.Task, error) {
 	ret := _mock.Called(ctx, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewTask")
 	}
 
-	var r0 *mongo.InsertOneResult
+	var r0 domain.Task
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) (*mongo.InsertOneResult, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) (domain.Task, error)); ok {
 		return returnFunc(ctx, task)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) *mongo.InsertOneResult); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Task) domain.Task); ok {
 		r0 = returnFunc(ctx, task)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.InsertOneResult)
-		}
+		r0 = ret.Get(0).(domain.Task)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.Task) error); ok {
 		r1 = returnFunc(ctx, task)
@@ -243,27 +299,27 @@ func (_c *MockTaskUsecase_NewTask_Call) Run(run func(ctx context.Context, task d
 	return _c
 }
 
-func (_c *MockTaskUsecase_NewTask_Call) Return(insertOneResult *mongo.InsertOneResult, err error) *MockTaskUsecase_NewTask_Call {
-	_c.Call.Return(insertOneResult, err)
+func (_c *MockTaskUsecase_NewTask_Call) Return(task1 domain.Task, err error) *MockTaskUsecase_NewTask_Call {
+	_c.Call.Return(task1, err)
 	return _c
 }
 
-func (_c *MockTaskUsecase_NewTask_Call) RunAndReturn(run func(ctx context.Context, task domain.Task) (*mongo.InsertOneResult, error)) *MockTaskUsecase_NewTask_Call {
+func (_c *MockTaskUsecase_NewTask_Call) RunAndReturn(run func(ctx context.Context, task domain.Task) (domain.Task, error)) *MockTaskUsecase_NewTask_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // UpdateTask provides a mock function for the type MockTaskUsecase
-func (_mock *MockTaskUsecase) UpdateTask(ctx context.Context, id string, updatedTask domain.Task) error {
-	ret := _mock.Called(ctx, id, updatedTask)
+func (_mock *MockTaskUsecase) UpdateTask(ctx context.Context, id string, updatedTask domain.Task, principal domain.Principal) error {
+	ret := _mock.Called(ctx, id, updatedTask, principal)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTask")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.Task) error); ok {
-		r0 = returnFunc(ctx, id, updatedTask)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.Task, domain.Principal) error); ok {
+		r0 = returnFunc(ctx, id, updatedTask, principal)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -279,13 +335,14 @@ type MockTaskUsecase_UpdateTask_Call struct {
 //   - ctx
 //   - id
 //   - updatedTask
-func (_e *MockTaskUsecase_Expecter) UpdateTask(ctx interface{}, id interface{}, updatedTask interface{}) *MockTaskUsecase_UpdateTask_Call {
-	return &MockTaskUsecase_UpdateTask_Call{Call: _e.mock.On("UpdateTask", ctx, id, updatedTask)}
+//   - principal
+func (_e *MockTaskUsecase_Expecter) UpdateTask(ctx interface{}, id interface{}, updatedTask interface{}, principal interface{}) *MockTaskUsecase_UpdateTask_Call {
+	return &MockTaskUsecase_UpdateTask_Call{Call: _e.mock.On("UpdateTask", ctx, id, updatedTask, principal)}
 }
 
-func (_c *MockTaskUsecase_UpdateTask_Call) Run(run func(ctx context.Context, id string, updatedTask domain.Task)) *MockTaskUsecase_UpdateTask_Call {
+func (_c *MockTaskUsecase_UpdateTask_Call) Run(run func(ctx context.Context, id string, updatedTask domain.Task, principal domain.Principal)) *MockTaskUsecase_UpdateTask_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(domain.Task))
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.Task), args[3].(domain.Principal))
 	})
 	return _c
 }
@@ -295,7 +352,7 @@ func (_c *MockTaskUsecase_UpdateTask_Call) Return(err error) *MockTaskUsecase_Up
 	return _c
 }
 
-func (_c *MockTaskUsecase_UpdateTask_Call) RunAndReturn(run func(ctx context.Context, id string, updatedTask domain.Task) error) *MockTaskUsecase_UpdateTask_Call {
+func (_c *MockTaskUsecase_UpdateTask_Call) RunAndReturn(run func(ctx context.Context, id string, updatedTask domain.Task, principal domain.Principal) error) *MockTaskUsecase_UpdateTask_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -9,7 +9,6 @@ import (
 	"task_manager/Domain"
 
 	mock "github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -40,31 +39,20 @@ func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
 }
 
 // CreateUser provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) CreateUser(ctx context.Context, user *domain.User) (*mongo.InsertOneResult, error) {
+func (_mock *MockUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	ret := _mock.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
 	}
 
-	var r0 *mongo.InsertOneResult
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User) (*mongo.InsertOneResult, error)); ok {
-		return returnFunc(ctx, user)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User) *mongo.InsertOneResult); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
 		r0 = returnFunc(ctx, user)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.InsertOneResult)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
-		r1 = returnFunc(ctx, user)
-	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
 // MockUserRepository_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
@@ -86,12 +74,230 @@ func (_c *MockUserRepository_CreateUser_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockUserRepository_CreateUser_Call) Return(insertOneResult *mongo.InsertOneResult, err error) *MockUserRepository_CreateUser_Call {
-	_c.Call.Return(insertOneResult, err)
+func (_c *MockUserRepository_CreateUser_Call) Return(err error) *MockUserRepository_CreateUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_CreateUser_Call) RunAndReturn(run func(ctx context.Context, user *domain.User) error) *MockUserRepository_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) DeleteUser(ctx context.Context, username string) error {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockUserRepository_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockUserRepository_Expecter) DeleteUser(ctx interface{}, username interface{}) *MockUserRepository_DeleteUser_Call {
+	return &MockUserRepository_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, username)}
+}
+
+func (_c *MockUserRepository_DeleteUser_Call) Run(run func(ctx context.Context, username string)) *MockUserRepository_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteUser_Call) Return(err error) *MockUserRepository_DeleteUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, username string) error) *MockUserRepository_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByEmail provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByEmail")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_FindUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByEmail'
+type MockUserRepository_FindUserByEmail_Call struct {
+	*mock.Call
+}
+
+// FindUserByEmail is a helper method to define mock.On call
+//   - ctx
+//   - email
+func (_e *MockUserRepository_Expecter) FindUserByEmail(ctx interface{}, email interface{}) *MockUserRepository_FindUserByEmail_Call {
+	return &MockUserRepository_FindUserByEmail_Call{Call: _e.mock.On("FindUserByEmail", ctx, email)}
+}
+
+func (_c *MockUserRepository_FindUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockUserRepository_FindUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_FindUserByEmail_Call) Return(user *domain.User, err error) *MockUserRepository_FindUserByEmail_Call {
+	_c.Call.Return(user, err)
 	return _c
 }
 
-func (_c *MockUserRepository_CreateUser_Call) RunAndReturn(run func(ctx context.Context, user *domain.User) (*mongo.InsertOneResult, error)) *MockUserRepository_CreateUser_Call {
+func (_c *MockUserRepository_FindUserByEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*domain.User, error)) *MockUserRepository_FindUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByID provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) FindUserByID(ctx context.Context, id domain.ID) (*domain.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ID) (*domain.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ID) *domain.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.ID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_FindUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByID'
+type MockUserRepository_FindUserByID_Call struct {
+	*mock.Call
+}
+
+// FindUserByID is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserRepository_Expecter) FindUserByID(ctx interface{}, id interface{}) *MockUserRepository_FindUserByID_Call {
+	return &MockUserRepository_FindUserByID_Call{Call: _e.mock.On("FindUserByID", ctx, id)}
+}
+
+func (_c *MockUserRepository_FindUserByID_Call) Run(run func(ctx context.Context, id domain.ID)) *MockUserRepository_FindUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.ID))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_FindUserByID_Call) Return(user *domain.User, err error) *MockUserRepository_FindUserByID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserRepository_FindUserByID_Call) RunAndReturn(run func(ctx context.Context, id domain.ID) (*domain.User, error)) *MockUserRepository_FindUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByOIDCSubject provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) FindUserByOIDCSubject(ctx context.Context, issuer string, subject string) (*domain.User, error) {
+	ret := _mock.Called(ctx, issuer, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByOIDCSubject")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, issuer, subject)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.User); ok {
+		r0 = returnFunc(ctx, issuer, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, issuer, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_FindUserByOIDCSubject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByOIDCSubject'
+type MockUserRepository_FindUserByOIDCSubject_Call struct {
+	*mock.Call
+}
+
+// FindUserByOIDCSubject is a helper method to define mock.On call
+//   - ctx
+//   - issuer
+//   - subject
+func (_e *MockUserRepository_Expecter) FindUserByOIDCSubject(ctx interface{}, issuer interface{}, subject interface{}) *MockUserRepository_FindUserByOIDCSubject_Call {
+	return &MockUserRepository_FindUserByOIDCSubject_Call{Call: _e.mock.On("FindUserByOIDCSubject", ctx, issuer, subject)}
+}
+
+func (_c *MockUserRepository_FindUserByOIDCSubject_Call) Run(run func(ctx context.Context, issuer string, subject string)) *MockUserRepository_FindUserByOIDCSubject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_FindUserByOIDCSubject_Call) Return(user *domain.User, err error) *MockUserRepository_FindUserByOIDCSubject_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserRepository_FindUserByOIDCSubject_Call) RunAndReturn(run func(ctx context.Context, issuer string, subject string) (*domain.User, error)) *MockUserRepository_FindUserByOIDCSubject_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -152,3 +358,143 @@ func (_c *MockUserRepository_FindUserByUsername_Call) RunAndReturn(run func(ctx
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateUser provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type MockUserRepository_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx
+//   - user
+func (_e *MockUserRepository_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockUserRepository_UpdateUser_Call {
+	return &MockUserRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+}
+
+func (_c *MockUserRepository_UpdateUser_Call) Run(run func(ctx context.Context, user *domain.User)) *MockUserRepository_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.User))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUser_Call) Return(err error) *MockUserRepository_UpdateUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUser_Call) RunAndReturn(run func(ctx context.Context, user *domain.User) error) *MockUserRepository_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UseRecoveryCode(ctx context.Context, id domain.ID, codeHash string) error {
+	ret := _mock.Called(ctx, id, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ID, string) error); ok {
+		r0 = returnFunc(ctx, id, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockUserRepository_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - codeHash
+func (_e *MockUserRepository_Expecter) UseRecoveryCode(ctx interface{}, id interface{}, codeHash interface{}) *MockUserRepository_UseRecoveryCode_Call {
+	return &MockUserRepository_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", ctx, id, codeHash)}
+}
+
+func (_c *MockUserRepository_UseRecoveryCode_Call) Run(run func(ctx context.Context, id domain.ID, codeHash string)) *MockUserRepository_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.ID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UseRecoveryCode_Call) Return(err error) *MockUserRepository_UseRecoveryCode_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_UseRecoveryCode_Call) RunAndReturn(run func(ctx context.Context, id domain.ID, codeHash string) error) *MockUserRepository_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UseTOTPStep(ctx context.Context, id domain.ID, step int64) error {
+	ret := _mock.Called(ctx, id, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ID, int64) error); ok {
+		r0 = returnFunc(ctx, id, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type MockUserRepository_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - step
+func (_e *MockUserRepository_Expecter) UseTOTPStep(ctx interface{}, id interface{}, step interface{}) *MockUserRepository_UseTOTPStep_Call {
+	return &MockUserRepository_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", ctx, id, step)}
+}
+
+func (_c *MockUserRepository_UseTOTPStep_Call) Run(run func(ctx context.Context, id domain.ID, step int64)) *MockUserRepository_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.ID), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UseTOTPStep_Call) Return(err error) *MockUserRepository_UseTOTPStep_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_UseTOTPStep_Call) RunAndReturn(run func(ctx context.Context, id domain.ID, step int64) error) *MockUserRepository_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
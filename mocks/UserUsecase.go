@@ -6,9 +6,10 @@ package mocks
 
 import (
 	"context"
+	"task_manager/Domain"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // NewMockUserUsecase creates a new instance of MockUserUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -38,26 +39,190 @@ func (_m *MockUserUsecase) EXPECT() *MockUserUsecase_Expecter {
 	return &MockUserUsecase_Expecter{mock: &_m.Mock}
 }
 
-// Login provides a mock function for the type MockUserUsecase
-func (_mock *MockUserUsecase) Login(ctx context.Context, username string, password string) (string, error) {
-	ret := _mock.Called(ctx, username, password)
+// ChangePassword provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) ChangePassword(ctx context.Context, username string, currentPassword string, newPassword string) (string, error) {
+	ret := _mock.Called(ctx, username, currentPassword, newPassword)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for ChangePassword")
 	}
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return returnFunc(ctx, username, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return returnFunc(ctx, username, currentPassword, newPassword)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = returnFunc(ctx, username, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = returnFunc(ctx, username, currentPassword, newPassword)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, username, currentPassword, newPassword)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserUsecase_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockUserUsecase_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - currentPassword
+//   - newPassword
+func (_e *MockUserUsecase_Expecter) ChangePassword(ctx interface{}, username interface{}, currentPassword interface{}, newPassword interface{}) *MockUserUsecase_ChangePassword_Call {
+	return &MockUserUsecase_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, username, currentPassword, newPassword)}
+}
+
+func (_c *MockUserUsecase_ChangePassword_Call) Run(run func(ctx context.Context, username string, currentPassword string, newPassword string)) *MockUserUsecase_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_ChangePassword_Call) Return(s string, err error) *MockUserUsecase_ChangePassword_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockUserUsecase_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, username string, currentPassword string, newPassword string) (string, error)) *MockUserUsecase_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAccount provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) DeleteAccount(ctx context.Context, username string, password string, signedInAt time.Time) error {
+	ret := _mock.Called(ctx, username, password, signedInAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, username, password, signedInAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserUsecase_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type MockUserUsecase_DeleteAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteAccount is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - password
+//   - signedInAt
+func (_e *MockUserUsecase_Expecter) DeleteAccount(ctx interface{}, username interface{}, password interface{}, signedInAt interface{}) *MockUserUsecase_DeleteAccount_Call {
+	return &MockUserUsecase_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount", ctx, username, password, signedInAt)}
+}
+
+func (_c *MockUserUsecase_DeleteAccount_Call) Run(run func(ctx context.Context, username string, password string, signedInAt time.Time)) *MockUserUsecase_DeleteAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_DeleteAccount_Call) Return(err error) *MockUserUsecase_DeleteAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserUsecase_DeleteAccount_Call) RunAndReturn(run func(ctx context.Context, username string, password string, signedInAt time.Time) error) *MockUserUsecase_DeleteAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProfile provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) GetProfile(ctx context.Context, username string) (*domain.User, error) {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, username)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserUsecase_GetProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProfile'
+type MockUserUsecase_GetProfile_Call struct {
+	*mock.Call
+}
+
+// GetProfile is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockUserUsecase_Expecter) GetProfile(ctx interface{}, username interface{}) *MockUserUsecase_GetProfile_Call {
+	return &MockUserUsecase_GetProfile_Call{Call: _e.mock.On("GetProfile", ctx, username)}
+}
+
+func (_c *MockUserUsecase_GetProfile_Call) Run(run func(ctx context.Context, username string)) *MockUserUsecase_GetProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_GetProfile_Call) Return(user *domain.User, err error) *MockUserUsecase_GetProfile_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserUsecase_GetProfile_Call) RunAndReturn(run func(ctx context.Context, username string) (*domain.User, error)) *MockUserUsecase_GetProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) Login(ctx context.Context, identifier string, password string) (*domain.LoginResult, error) {
+	ret := _mock.Called(ctx, identifier, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *domain.LoginResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.LoginResult, error)); ok {
+		return returnFunc(ctx, identifier, password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.LoginResult); ok {
+		r0 = returnFunc(ctx, identifier, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginResult)
+		}
+	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = returnFunc(ctx, username, password)
+		r1 = returnFunc(ctx, identifier, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,51 +236,51 @@ type MockUserUsecase_Login_Call struct {
 
 // Login is a helper method to define mock.On call
 //   - ctx
-//   - username
+//   - identifier
 //   - password
-func (_e *MockUserUsecase_Expecter) Login(ctx interface{}, username interface{}, password interface{}) *MockUserUsecase_Login_Call {
-	return &MockUserUsecase_Login_Call{Call: _e.mock.On("Login", ctx, username, password)}
+func (_e *MockUserUsecase_Expecter) Login(ctx interface{}, identifier interface{}, password interface{}) *MockUserUsecase_Login_Call {
+	return &MockUserUsecase_Login_Call{Call: _e.mock.On("Login", ctx, identifier, password)}
 }
 
-func (_c *MockUserUsecase_Login_Call) Run(run func(ctx context.Context, username string, password string)) *MockUserUsecase_Login_Call {
+func (_c *MockUserUsecase_Login_Call) Run(run func(ctx context.Context, identifier string, password string)) *MockUserUsecase_Login_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserUsecase_Login_Call) Return(s string, err error) *MockUserUsecase_Login_Call {
-	_c.Call.Return(s, err)
+func (_c *MockUserUsecase_Login_Call) Return(loginResult *domain.LoginResult, err error) *MockUserUsecase_Login_Call {
+	_c.Call.Return(loginResult, err)
 	return _c
 }
 
-func (_c *MockUserUsecase_Login_Call) RunAndReturn(run func(ctx context.Context, username string, password string) (string, error)) *MockUserUsecase_Login_Call {
+func (_c *MockUserUsecase_Login_Call) RunAndReturn(run func(ctx context.Context, identifier string, password string) (*domain.LoginResult, error)) *MockUserUsecase_Login_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Register provides a mock function for the type MockUserUsecase
-func (_mock *MockUserUsecase) Register(ctx context.Context, username string, password string) (*mongo.InsertOneResult, error) {
-	ret := _mock.Called(ctx, username, password)
+func (_mock *MockUserUsecase) Register(ctx context.Context, username string, email string, password string) (*domain.User, error) {
+	ret := _mock.Called(ctx, username, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
 	}
 
-	var r0 *mongo.InsertOneResult
+	var r0 *domain.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*mongo.InsertOneResult, error)); ok {
-		return returnFunc(ctx, username, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, username, email, password)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *mongo.InsertOneResult); ok {
-		r0 = returnFunc(ctx, username, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.User); ok {
+		r0 = returnFunc(ctx, username, email, password)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.InsertOneResult)
+			r0 = ret.Get(0).(*domain.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = returnFunc(ctx, username, password)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, username, email, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -130,24 +295,221 @@ type MockUserUsecase_Register_Call struct {
 // Register is a helper method to define mock.On call
 //   - ctx
 //   - username
+//   - email
 //   - password
-func (_e *MockUserUsecase_Expecter) Register(ctx interface{}, username interface{}, password interface{}) *MockUserUsecase_Register_Call {
-	return &MockUserUsecase_Register_Call{Call: _e.mock.On("Register", ctx, username, password)}
+func (_e *MockUserUsecase_Expecter) Register(ctx interface{}, username interface{}, email interface{}, password interface{}) *MockUserUsecase_Register_Call {
+	return &MockUserUsecase_Register_Call{Call: _e.mock.On("Register", ctx, username, email, password)}
 }
 
-func (_c *MockUserUsecase_Register_Call) Run(run func(ctx context.Context, username string, password string)) *MockUserUsecase_Register_Call {
+func (_c *MockUserUsecase_Register_Call) Run(run func(ctx context.Context, username string, email string, password string)) *MockUserUsecase_Register_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_Register_Call) Return(user *domain.User, err error) *MockUserUsecase_Register_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserUsecase_Register_Call) RunAndReturn(run func(ctx context.Context, username string, email string, password string) (*domain.User, error)) *MockUserUsecase_Register_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestEmailVerification provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) RequestEmailVerification(ctx context.Context, username string) error {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestEmailVerification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserUsecase_RequestEmailVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestEmailVerification'
+type MockUserUsecase_RequestEmailVerification_Call struct {
+	*mock.Call
+}
+
+// RequestEmailVerification is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockUserUsecase_Expecter) RequestEmailVerification(ctx interface{}, username interface{}) *MockUserUsecase_RequestEmailVerification_Call {
+	return &MockUserUsecase_RequestEmailVerification_Call{Call: _e.mock.On("RequestEmailVerification", ctx, username)}
+}
+
+func (_c *MockUserUsecase_RequestEmailVerification_Call) Run(run func(ctx context.Context, username string)) *MockUserUsecase_RequestEmailVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_RequestEmailVerification_Call) Return(err error) *MockUserUsecase_RequestEmailVerification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserUsecase_RequestEmailVerification_Call) RunAndReturn(run func(ctx context.Context, username string) error) *MockUserUsecase_RequestEmailVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockUser provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) UnlockUser(ctx context.Context, username string) error {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserUsecase_UnlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockUser'
+type MockUserUsecase_UnlockUser_Call struct {
+	*mock.Call
+}
+
+// UnlockUser is a helper method to define mock.On call
+//   - ctx
+//   - username
+func (_e *MockUserUsecase_Expecter) UnlockUser(ctx interface{}, username interface{}) *MockUserUsecase_UnlockUser_Call {
+	return &MockUserUsecase_UnlockUser_Call{Call: _e.mock.On("UnlockUser", ctx, username)}
+}
+
+func (_c *MockUserUsecase_UnlockUser_Call) Run(run func(ctx context.Context, username string)) *MockUserUsecase_UnlockUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_UnlockUser_Call) Return(err error) *MockUserUsecase_UnlockUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserUsecase_UnlockUser_Call) RunAndReturn(run func(ctx context.Context, username string) error) *MockUserUsecase_UnlockUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProfile provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) UpdateProfile(ctx context.Context, username string, update domain.UpdateProfileRequest) (*domain.User, error) {
+	ret := _mock.Called(ctx, username, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.UpdateProfileRequest) (*domain.User, error)); ok {
+		return returnFunc(ctx, username, update)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.UpdateProfileRequest) *domain.User); ok {
+		r0 = returnFunc(ctx, username, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, domain.UpdateProfileRequest) error); ok {
+		r1 = returnFunc(ctx, username, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserUsecase_UpdateProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProfile'
+type MockUserUsecase_UpdateProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateProfile is a helper method to define mock.On call
+//   - ctx
+//   - username
+//   - update
+func (_e *MockUserUsecase_Expecter) UpdateProfile(ctx interface{}, username interface{}, update interface{}) *MockUserUsecase_UpdateProfile_Call {
+	return &MockUserUsecase_UpdateProfile_Call{Call: _e.mock.On("UpdateProfile", ctx, username, update)}
+}
+
+func (_c *MockUserUsecase_UpdateProfile_Call) Run(run func(ctx context.Context, username string, update domain.UpdateProfileRequest)) *MockUserUsecase_UpdateProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.UpdateProfileRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserUsecase_UpdateProfile_Call) Return(user *domain.User, err error) *MockUserUsecase_UpdateProfile_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserUsecase_UpdateProfile_Call) RunAndReturn(run func(ctx context.Context, username string, update domain.UpdateProfileRequest) (*domain.User, error)) *MockUserUsecase_UpdateProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function for the type MockUserUsecase
+func (_mock *MockUserUsecase) VerifyEmail(ctx context.Context, token string) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserUsecase_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type MockUserUsecase_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx
+//   - token
+func (_e *MockUserUsecase_Expecter) VerifyEmail(ctx interface{}, token interface{}) *MockUserUsecase_VerifyEmail_Call {
+	return &MockUserUsecase_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, token)}
+}
+
+func (_c *MockUserUsecase_VerifyEmail_Call) Run(run func(ctx context.Context, token string)) *MockUserUsecase_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserUsecase_Register_Call) Return(insertOneResult *mongo.InsertOneResult, err error) *MockUserUsecase_Register_Call {
-	_c.Call.Return(insertOneResult, err)
+func (_c *MockUserUsecase_VerifyEmail_Call) Return(err error) *MockUserUsecase_VerifyEmail_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserUsecase_Register_Call) RunAndReturn(run func(ctx context.Context, username string, password string) (*mongo.InsertOneResult, error)) *MockUserUsecase_Register_Call {
+func (_c *MockUserUsecase_VerifyEmail_Call) RunAndReturn(run func(ctx context.Context, token string) error) *MockUserUsecase_VerifyEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }